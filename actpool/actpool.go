@@ -7,7 +7,9 @@
 package actpool
 
 import (
+	"container/heap"
 	"fmt"
+	"math/big"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -18,6 +20,7 @@ import (
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/proto"
 )
 
@@ -51,7 +54,7 @@ var (
 type ActPool interface {
 	// Reset resets actpool state
 	Reset()
-	// PickActs returns all currently accepted transfers and votes in actpool
+	// PickActs returns currently accepted transfers, votes and executions in actpool, ordered by gas price
 	PickActs() ([]*action.Transfer, []*action.Vote, []*action.Execution)
 	// AddTsf adds an transfer into the pool after passing validation
 	AddTsf(tsf *action.Transfer) error
@@ -59,6 +62,10 @@ type ActPool interface {
 	AddVote(vote *action.Vote) error
 	// AddExecution adds an execution into the pool after passing validation
 	AddExecution(execution *action.Execution) error
+	// AddEvidence adds an evidence of a delegate's equivocation into the pool after passing validation
+	AddEvidence(evidence *action.Evidence) error
+	// PickEvidences returns currently accepted evidences in actpool, at most one against each offender
+	PickEvidences() []*action.Evidence
 	// GetPendingNonce returns pending nonce in pool given an account address
 	GetPendingNonce(addr string) (uint64, error)
 	// GetUnconfirmedActs returns unconfirmed actions in pool given an account address
@@ -69,6 +76,11 @@ type ActPool interface {
 	GetSize() uint64
 	// GetCapacity returns the act pool capacity
 	GetCapacity() uint64
+	// SubscribeNewAction sends every action accepted into the pool to the channel. An action is dropped for the
+	// channel if it is full, so that a slow subscriber never stalls adding actions
+	SubscribeNewAction(ch chan *iproto.ActionPb) error
+	// UnsubscribeNewAction stops sending the accepted actions to the channel
+	UnsubscribeNewAction(ch chan *iproto.ActionPb) error
 }
 
 // actPool implements ActPool interface
@@ -78,6 +90,8 @@ type actPool struct {
 	bc          blockchain.Blockchain
 	accountActs map[string]ActQueue
 	allActions  map[hash.Hash32B]*iproto.ActionPb
+	evidences   map[hash.Hash32B]*action.Evidence
+	listeners   []chan *iproto.ActionPb
 }
 
 // NewActPool constructs a new actpool
@@ -90,6 +104,7 @@ func NewActPool(bc blockchain.Blockchain, cfg config.ActPool) (ActPool, error) {
 		bc:          bc,
 		accountActs: make(map[string]ActQueue),
 		allActions:  make(map[hash.Hash32B]*iproto.ActionPb),
+		evidences:   make(map[hash.Hash32B]*action.Evidence),
 	}
 	return ap, nil
 }
@@ -108,6 +123,7 @@ func (ap *actPool) Reset() {
 
 	// Remove confirmed actions in actpool
 	ap.removeConfirmedActs()
+	ap.removeInvalidEvidences()
 	for from, queue := range ap.accountActs {
 		// Reset pending balance for each account
 		balance, err := ap.bc.Balance(from)
@@ -116,6 +132,7 @@ func (ap *actPool) Reset() {
 			return
 		}
 		queue.SetPendingBalance(balance)
+		queue.SetChargeGasFee(ap.chargeGasFee())
 
 		// Reset pending nonce and remove invalid actions for each account
 		confirmedNonce, err := ap.bc.Nonce(from)
@@ -130,40 +147,62 @@ func (ap *actPool) Reset() {
 	}
 }
 
-// PickActs returns all currently accepted transfers and votes for all accounts
+// PickActs returns currently accepted transfers, votes and executions for all accounts
+// Actions paying higher gas price are picked first, while the actions of each account are picked in nonce order. An
+// account is skipped once its next action does not fit into the remaining block gas
 func (ap *actPool) PickActs() ([]*action.Transfer, []*action.Vote, []*action.Execution) {
 	ap.mutex.Lock()
 	defer ap.mutex.Unlock()
 
 	numActs := uint64(0)
+	remainingGas := blockchain.GasLimit
 	transfers := make([]*action.Transfer, 0)
 	votes := make([]*action.Vote, 0)
 	executions := make([]*action.Execution, 0)
+	priced := actPriceQueue{}
 	for _, queue := range ap.accountActs {
-		for _, act := range queue.PendingActs() {
-			switch {
-			case act.GetTransfer() != nil:
-				tsf := action.Transfer{}
-				tsf.ConvertFromActionPb(act)
-				transfers = append(transfers, &tsf)
-				numActs++
-			case act.GetVote() != nil:
-				vote := action.Vote{}
-				vote.ConvertFromActionPb(act)
-				votes = append(votes, &vote)
-				numActs++
-			case act.GetExecution() != nil:
-				execution := action.Execution{}
-				execution.ConvertFromActionPb(act)
-				executions = append(executions, &execution)
-				numActs++
-			}
-			if ap.cfg.MaxNumActsToPick > 0 && numActs >= ap.cfg.MaxNumActsToPick {
-				logger.Debug().
-					Uint64("limit", ap.cfg.MaxNumActsToPick).
-					Msg("reach the max number of actions to pick")
-				return transfers, votes, executions
-			}
+		if acts := queue.PendingActs(); len(acts) > 0 {
+			priced = append(priced, acts)
+		}
+	}
+	heap.Init(&priced)
+	for priced.Len() > 0 {
+		acts := priced[0]
+		act := acts[0]
+		if act.GasLimit > remainingGas {
+			// the subsequent actions of the account cannot be picked either
+			heap.Pop(&priced)
+			continue
+		}
+		if len(acts) > 1 {
+			priced[0] = acts[1:]
+			heap.Fix(&priced, 0)
+		} else {
+			heap.Pop(&priced)
+		}
+		remainingGas -= act.GasLimit
+		switch {
+		case act.GetTransfer() != nil:
+			tsf := action.Transfer{}
+			tsf.ConvertFromActionPb(act)
+			transfers = append(transfers, &tsf)
+			numActs++
+		case act.GetVote() != nil:
+			vote := action.Vote{}
+			vote.ConvertFromActionPb(act)
+			votes = append(votes, &vote)
+			numActs++
+		case act.GetExecution() != nil:
+			execution := action.Execution{}
+			execution.ConvertFromActionPb(act)
+			executions = append(executions, &execution)
+			numActs++
+		}
+		if ap.cfg.MaxNumActsToPick > 0 && numActs >= ap.cfg.MaxNumActsToPick {
+			logger.Debug().
+				Uint64("limit", ap.cfg.MaxNumActsToPick).
+				Msg("reach the max number of actions to pick")
+			return transfers, votes, executions
 		}
 	}
 	return transfers, votes, executions
@@ -270,6 +309,69 @@ func (ap *actPool) AddExecution(exec *action.Execution) error {
 	return ap.addAction(exec.Executor, action, hash, exec.Nonce)
 }
 
+// AddEvidence inserts a new evidence into the pool if it passes validation
+func (ap *actPool) AddEvidence(evidence *action.Evidence) error {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	hash := evidence.Hash()
+	// Reject evidence if it already exists in pool
+	if ap.evidences[hash] != nil {
+		logger.Error().
+			Hex("hash", hash[:]).
+			Msg("Rejecting existed evidence")
+		return fmt.Errorf("existed evidence: %x", hash)
+	}
+	// Reject evidence if it fails validation
+	if err := ap.validateEvidence(evidence); err != nil {
+		logger.Error().
+			Hex("hash", hash[:]).
+			Err(err).
+			Msg("Rejecting invalid evidence")
+		return err
+	}
+	// Reject evidence if there is already one against the offender in pool, since an offender is only slashed once for
+	// its equivocation
+	for _, e := range ap.evidences {
+		if e.Offender() == evidence.Offender() {
+			logger.Error().
+				Hex("hash", hash[:]).
+				Msg("Rejecting evidence against an offender with evidence in pool")
+			return errors.Wrapf(action.ErrEvidence, "evidence against %s exists", evidence.Offender())
+		}
+	}
+	// Reject evidence if pool space is full
+	if uint64(len(ap.evidences)) >= ap.cfg.MaxNumActsPerPool {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Msg("Rejecting evidence due to insufficient space")
+		return errors.Wrapf(ErrActPool, "insufficient space for evidence")
+	}
+	ap.evidences[hash] = evidence
+	ap.emitToSubscribers(evidence.ConvertToActionPb(), hash)
+	return nil
+}
+
+// PickEvidences returns currently accepted evidences, at most one against each offender, since an offender is only
+// slashed once for its equivocation. The evidences no longer valid at the next height are removed from pool instead of
+// being picked, so that they never make the minted block invalid
+func (ap *actPool) PickEvidences() []*action.Evidence {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+
+	ap.removeInvalidEvidences()
+	evidences := make([]*action.Evidence, 0)
+	offenders := make(map[string]bool)
+	for _, evidence := range ap.evidences {
+		if offenders[evidence.Offender()] {
+			continue
+		}
+		offenders[evidence.Offender()] = true
+		evidences = append(evidences, evidence)
+	}
+	return evidences
+}
+
 // GetPendingNonce returns pending nonce in pool or confirmed nonce given an account address
 func (ap *actPool) GetPendingNonce(addr string) (uint64, error) {
 	ap.mutex.Lock()
@@ -314,6 +416,35 @@ func (ap *actPool) GetSize() uint64 {
 	return uint64(len(ap.allActions))
 }
 
+// SubscribeNewAction sends every action accepted into the pool to the channel
+func (ap *actPool) SubscribeNewAction(ch chan *iproto.ActionPb) error {
+	if ch == nil {
+		return errors.New("subscriber channel cannot be nil")
+	}
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	for _, listener := range ap.listeners {
+		if listener == ch {
+			return errors.New("channel has already subscribed new actions")
+		}
+	}
+	ap.listeners = append(ap.listeners, ch)
+	return nil
+}
+
+// UnsubscribeNewAction stops sending the accepted actions to the channel
+func (ap *actPool) UnsubscribeNewAction(ch chan *iproto.ActionPb) error {
+	ap.mutex.Lock()
+	defer ap.mutex.Unlock()
+	for i, listener := range ap.listeners {
+		if listener == ch {
+			ap.listeners = append(ap.listeners[:i], ap.listeners[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("channel has not subscribed new actions")
+}
+
 // GetCapacity returns the act pool capacity
 func (ap *actPool) GetCapacity() uint64 {
 	return ap.cfg.MaxNumActsPerPool
@@ -361,6 +492,25 @@ func (ap *actPool) validateTsf(tsf *action.Transfer) error {
 		logger.Error().Err(err).Msg("Error when validating transfer's signature")
 		return errors.Wrapf(err, "failed to verify Transfer signature")
 	}
+	// Reject transfer signed for another chain
+	if err := blockchain.VerifyChainID(tsf.ChainID, ap.bc.TipHeight()+1, ap.bc.Genesis(), ap.bc.Upgrades()); err != nil {
+		logger.Error().Err(err).Msg("Error when validating transfer's chain ID")
+		return err
+	}
+	// Reject transfer of too high or insufficient gas
+	if tsf.GasLimit > blockchain.GasLimit {
+		logger.Error().Msg("Rejecting transfer due to high gas")
+		return errors.Wrapf(ErrGasHigherThanLimit, "gas is higher than gas limit")
+	}
+	intrinsicGas, err := tsf.IntrinsicGas()
+	if intrinsicGas > tsf.GasLimit || err != nil {
+		logger.Error().Msg("Rejecting transfer due to insufficient gas")
+		return errors.Wrapf(ErrInsufficientGas, "insufficient gas for transfer")
+	}
+	if err := blockchain.VerifyGasPrice(tsf.GasPrice, ap.bc.TipHeight()+1, ap.bc.Genesis(), ap.bc.Upgrades()); err != nil {
+		logger.Error().Err(err).Msg("Error when validating transfer's gas price")
+		return err
+	}
 	// Reject transfer if nonce is too low
 	confirmedNonce, err := ap.bc.Nonce(tsf.Sender)
 	if err != nil {
@@ -415,6 +565,15 @@ func (ap *actPool) validateExecution(exec *action.Execution) error {
 		logger.Error().Err(err).Msg("Error when validating execution's signature")
 		return errors.Wrapf(err, "failed to verify Execution signature")
 	}
+	// Reject execution signed for another chain
+	if err := blockchain.VerifyChainID(exec.ChainID, ap.bc.TipHeight()+1, ap.bc.Genesis(), ap.bc.Upgrades()); err != nil {
+		logger.Error().Err(err).Msg("Error when validating execution's chain ID")
+		return err
+	}
+	if err := blockchain.VerifyGasPrice(exec.GasPrice, ap.bc.TipHeight()+1, ap.bc.Genesis(), ap.bc.Upgrades()); err != nil {
+		logger.Error().Err(err).Msg("Error when validating execution's gas price")
+		return err
+	}
 	// Reject transfer if nonce is too low
 	confirmedNonce, err := ap.bc.Nonce(executor.RawAddress)
 	if err != nil {
@@ -465,6 +624,25 @@ func (ap *actPool) validateVote(vote *action.Vote) error {
 		logger.Error().Err(err).Msg("Error when validating vote's signature")
 		return errors.Wrapf(err, "failed to verify Vote signature")
 	}
+	// Reject vote signed for another chain
+	if err := blockchain.VerifyChainID(vote.ChainID, ap.bc.TipHeight()+1, ap.bc.Genesis(), ap.bc.Upgrades()); err != nil {
+		logger.Error().Err(err).Msg("Error when validating vote's chain ID")
+		return err
+	}
+	// Reject vote of too high or insufficient gas
+	if vote.GasLimit > blockchain.GasLimit {
+		logger.Error().Msg("Rejecting vote due to high gas")
+		return errors.Wrapf(ErrGasHigherThanLimit, "gas is higher than gas limit")
+	}
+	if vote.IntrinsicGas() > vote.GasLimit {
+		logger.Error().Msg("Rejecting vote due to insufficient gas")
+		return errors.Wrapf(ErrInsufficientGas, "insufficient gas for vote")
+	}
+	gasPrice := new(big.Int).SetBytes(vote.GasPrice)
+	if err := blockchain.VerifyGasPrice(gasPrice, ap.bc.TipHeight()+1, ap.bc.Genesis(), ap.bc.Upgrades()); err != nil {
+		logger.Error().Err(err).Msg("Error when validating vote's gas price")
+		return err
+	}
 
 	// Reject vote if nonce is too low
 	confirmedNonce, err := ap.bc.Nonce(voter.RawAddress)
@@ -498,6 +676,70 @@ func (ap *actPool) validateVote(vote *action.Vote) error {
 	return nil
 }
 
+// validateEvidence checks whether an evidence is valid
+func (ap *actPool) validateEvidence(evidence *action.Evidence) error {
+	height := ap.bc.TipHeight() + 1
+	// Reject evidence before the slashing upgrade
+	if height < ap.bc.Upgrades().SlashingHeight {
+		logger.Error().Msg("Error when validating evidence before the slashing upgrade")
+		return errors.Wrapf(action.ErrEvidence, "no evidence is accepted before height %d", ap.bc.Upgrades().SlashingHeight)
+	}
+	// Reject evidence of the equivocation at height 0 or a future height
+	if evidence.Height() == 0 || evidence.Height() > height {
+		logger.Error().Msg("Error when validating evidence's height")
+		return errors.Wrapf(action.ErrEvidence, "evidence at future height %d", evidence.Height())
+	}
+	// Verify evidence using offender's public key as a candidate
+	offender := evidence.Offender()
+	var pkBytes []byte
+	_, candidates := ap.bc.Candidates()
+	for _, candidate := range candidates {
+		if candidate.Address == offender {
+			pkBytes = candidate.PubKey
+			break
+		}
+	}
+	if pkBytes == nil {
+		logger.Error().Str("offender", offender).Msg("Error when validating evidence's offender")
+		return errors.Wrapf(action.ErrEvidence, "offender %s is not a candidate", offender)
+	}
+	pubKey, err := keypair.BytesToPublicKey(pkBytes)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error when validating offender's public key")
+		return errors.Wrapf(err, "failed to get the public key of offender %s", offender)
+	}
+	if err := evidence.Verify(pubKey); err != nil {
+		logger.Error().Err(err).Msg("Error when validating evidence's signatures")
+		return errors.Wrapf(err, "failed to verify evidence against offender %s", offender)
+	}
+	// Reject evidence if the offender has been slashed for the equivocation
+	slashed, err := ap.isSlashed(evidence)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error when validating offender's state")
+		return err
+	}
+	if slashed {
+		logger.Error().Str("offender", offender).Msg("Error when validating evidence of slashed offender")
+		return errors.Wrapf(action.ErrEvidence, "offender %s has been slashed", offender)
+	}
+	return nil
+}
+
+// chargeGasFee returns whether the intrinsic gas fees of transfers and votes are charged at the next height
+func (ap *actPool) chargeGasFee() bool {
+	return ap.bc.TipHeight()+1 >= ap.bc.Upgrades().GasFeeHeight
+}
+
+// isSlashed returns whether the offender of the evidence has been jailed since the height of the equivocation, in
+// which case the evidence is either committed already or not acceptable any more
+func (ap *actPool) isSlashed(evidence *action.Evidence) (bool, error) {
+	state, err := ap.bc.StateByAddr(evidence.Offender())
+	if err != nil {
+		return false, errors.Wrapf(err, "cannot find offender's state: %s", evidence.Offender())
+	}
+	return evidence.Height() < state.JailedUntil, nil
+}
+
 func (ap *actPool) addAction(sender string, act *iproto.ActionPb, hash hash.Hash32B, actNonce uint64) error {
 	queue := ap.accountActs[sender]
 	if queue == nil {
@@ -519,13 +761,15 @@ func (ap *actPool) addAction(sender string, act *iproto.ActionPb, hash hash.Hash
 			return err
 		}
 		queue.SetPendingBalance(balance)
+		queue.SetChargeGasFee(ap.chargeGasFee())
 	}
 	if queue.Overlaps(act) {
 		// Nonce already exists
-		logger.Error().
-			Hex("hash", hash[:]).
-			Msg("Rejecting action because replacement action is not supported")
-		return errors.Wrapf(ErrNonce, "duplicate nonce")
+		if err := ap.replaceAction(sender, act, hash); err != nil {
+			return err
+		}
+		ap.emitToSubscribers(act, hash)
+		return nil
 	}
 
 	if actNonce-queue.StartNonce() >= ap.cfg.MaxNumActsPerAcct {
@@ -538,9 +782,7 @@ func (ap *actPool) addAction(sender string, act *iproto.ActionPb, hash hash.Hash
 	}
 
 	if transfer := act.GetTransfer(); transfer != nil {
-		tsf := &action.Transfer{}
-		tsf.ConvertFromActionPb(act)
-		if queue.PendingBalance().Cmp(tsf.Amount) < 0 {
+		if queue.PendingBalance().Cmp(actCost(act, ap.chargeGasFee())) < 0 {
 			// Pending balance is insufficient
 			logger.Warn().
 				Hex("hash", hash[:]).
@@ -574,6 +816,78 @@ func (ap *actPool) addAction(sender string, act *iproto.ActionPb, hash hash.Hash
 	if actNonce == nonce {
 		ap.updateAccount(sender)
 	}
+	ap.emitToSubscribers(act, hash)
+	return nil
+}
+
+// emitToSubscribers sends the action to the subscribers without blocking, and drops the action for the subscribers not
+// keeping up with the pool
+func (ap *actPool) emitToSubscribers(act *iproto.ActionPb, hash hash.Hash32B) {
+	for _, listener := range ap.listeners {
+		select {
+		case listener <- act:
+		default:
+			logger.Warn().Hex("hash", hash[:]).Msg("Drop the action for a slow subscriber")
+		}
+	}
+}
+
+// replaceAction replaces the action of the same nonce in account queue if the new action pays enough higher gas price
+func (ap *actPool) replaceAction(sender string, act *iproto.ActionPb, hash hash.Hash32B) error {
+	queue := ap.accountActs[sender]
+	if err := ap.validateReplacement(sender, act); err != nil {
+		logger.Warn().
+			Hex("hash", hash[:]).
+			Err(err).
+			Msg("Rejecting replacement action")
+		return err
+	}
+	replaced, err := queue.Replace(act, ap.cfg.ReplacementPriceBump)
+	if err != nil {
+		logger.Error().
+			Hex("hash", hash[:]).
+			Err(err).
+			Msg("Rejecting replacement action")
+		return err
+	}
+	ap.removeInvalidActs([]*iproto.ActionPb{replaced})
+	ap.allActions[hash] = act
+	// The replacement may cost a different balance, so reevaluate the queue starting from the confirmed state
+	balance, err := ap.bc.Balance(sender)
+	if err != nil {
+		logger.Error().Err(err).Msg("Error when replacing action")
+		return err
+	}
+	queue.SetPendingBalance(balance)
+	queue.SetPendingNonce(queue.StartNonce())
+	ap.updateAccount(sender)
+	return nil
+}
+
+// validateReplacement checks whether the sender affords the replacement action with the balance left by the actions
+// before it, as a new action is checked against the pending balance
+func (ap *actPool) validateReplacement(sender string, act *iproto.ActionPb) error {
+	queue := ap.accountActs[sender]
+	chargeGasFee := ap.chargeGasFee()
+	balance := new(big.Int).Set(queue.PendingBalance())
+	if act.Nonce < queue.PendingNonce() {
+		// The replaced action is pending, so the replacement is paid by the confirmed balance less the costs of the
+		// pending actions before it
+		confirmedBalance, err := ap.bc.Balance(sender)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the balance of %s", sender)
+		}
+		balance.Set(confirmedBalance)
+		for _, pending := range queue.PendingActs() {
+			if pending.Nonce >= act.Nonce {
+				break
+			}
+			balance.Sub(balance, actCost(pending, chargeGasFee))
+		}
+	}
+	if balance.Cmp(actCost(act, chargeGasFee)) < 0 {
+		return errors.Wrapf(ErrBalance, "insufficient balance for replacement action")
+	}
 	return nil
 }
 
@@ -597,6 +911,19 @@ func (ap *actPool) removeConfirmedActs() {
 	}
 }
 
+// removeInvalidEvidences removes the evidences failing validation at the next height from pool, including the ones
+// whose offenders have been slashed
+func (ap *actPool) removeInvalidEvidences() {
+	for hash, evidence := range ap.evidences {
+		if err := ap.validateEvidence(evidence); err != nil {
+			logger.Debug().
+				Hex("hash", hash[:]).
+				Msg("Removed invalidated evidence")
+			delete(ap.evidences, hash)
+		}
+	}
+}
+
 func (ap *actPool) removeInvalidActs(acts []*iproto.ActionPb) {
 	for _, act := range acts {
 		var hash hash.Hash32B
@@ -20,8 +20,11 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
 	"github.com/iotexproject/iotex-core/testutil"
 )
@@ -54,11 +57,12 @@ var (
 
 func TestActPool_validateTsf(t *testing.T) {
 	require := require.New(t)
-	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	cfg := config.Default
+	bc := blockchain.NewBlockchain(&cfg, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
 	require.NoError(bc.Start(context.Background()))
 	_, err := bc.CreateState(addr1.RawAddress, uint64(100))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
 	require.NoError(err)
@@ -92,12 +96,38 @@ func TestActPool_validateTsf(t *testing.T) {
 	prevTsf, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(50), []byte{}, uint64(100000), big.NewInt(10))
 	err = ap.AddTsf(prevTsf)
 	require.NoError(err)
-	err = bc.CommitStateChanges(0, []*action.Transfer{prevTsf}, nil, nil)
+	err = bc.CommitStateChanges(0, []*action.Transfer{prevTsf}, nil, nil, nil)
 	require.NoError(err)
 	ap.Reset()
 	nTsf, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(60), []byte{}, uint64(100000), big.NewInt(10))
 	err = ap.validateTsf(nTsf)
 	require.Equal(ErrNonce, errors.Cause(err))
+	// Case VII: Insufficient gas
+	lowGasTsf, _ := signedTransfer(addr1, addr1, uint64(2), big.NewInt(1), []byte{}, uint64(100), big.NewInt(0))
+	err = ap.validateTsf(lowGasTsf)
+	require.Equal(ErrInsufficientGas, errors.Cause(err))
+	// Case VIII: Signed for another chain
+	otherChainTsf, err := action.NewTransfer(uint64(2), big.NewInt(1), addr1.RawAddress, addr1.RawAddress, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	otherChainTsf.ChainID = blockchain.Gen.ChainID + 1
+	otherChainTsf, err = otherChainTsf.Sign(addr1)
+	require.NoError(err)
+	err = ap.validateTsf(otherChainTsf)
+	require.Equal(blockchain.ErrChainID, errors.Cause(err))
+	// Case IX: Signed without the chain ID once the chain ID is required
+	cfg.Chain.Upgrades.ChainIDHeight = 0
+	legacyTsf, _ := signedTransfer(addr1, addr1, uint64(2), big.NewInt(1), []byte{}, uint64(100000), big.NewInt(0))
+	err = ap.validateTsf(legacyTsf)
+	require.Equal(blockchain.ErrChainID, errors.Cause(err))
+	// Case X: Gas price is lower than the minimum once the gas fee is charged
+	cfg.Chain.Upgrades.GasFeeHeight = 0
+	freeTsf, err := action.NewTransfer(uint64(2), big.NewInt(1), addr1.RawAddress, addr1.RawAddress, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	freeTsf.ChainID = blockchain.Gen.ChainID
+	freeTsf, err = freeTsf.Sign(addr1)
+	require.NoError(err)
+	err = ap.validateTsf(freeTsf)
+	require.Equal(blockchain.ErrGasPrice, errors.Cause(err))
 }
 
 func TestActPool_validateVote(t *testing.T) {
@@ -108,7 +138,7 @@ func TestActPool_validateVote(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(addr2.RawAddress, uint64(100))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
 	require.NoError(err)
@@ -163,7 +193,7 @@ func TestActPool_validateVote(t *testing.T) {
 	prevTsf, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(50), []byte{}, uint64(100000), big.NewInt(10))
 	err = ap.AddTsf(prevTsf)
 	require.NoError(err)
-	err = bc.CommitStateChanges(0, []*action.Transfer{prevTsf}, nil, nil)
+	err = bc.CommitStateChanges(0, []*action.Transfer{prevTsf}, nil, nil, nil)
 	require.NoError(err)
 	ap.Reset()
 	nVote, _ := signedVote(addr1, addr1, uint64(1), uint64(100000), big.NewInt(10))
@@ -173,6 +203,53 @@ func TestActPool_validateVote(t *testing.T) {
 	vote2, _ := signedVote(addr1, addr2, uint64(2), uint64(100000), big.NewInt(10))
 	err = ap.validateVote(vote2)
 	require.Equal(ErrVotee, errors.Cause(err))
+	// Case VII: Insufficient gas
+	lowGasVote, _ := signedVote(addr1, addr1, uint64(2), uint64(100), big.NewInt(0))
+	err = ap.validateVote(lowGasVote)
+	require.Equal(ErrInsufficientGas, errors.Cause(err))
+}
+
+func TestActPool_Evidence(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	upgrades := config.Default.Chain.Upgrades
+	upgrades.SlashingHeight = 0
+	offenderState := &state.State{Balance: big.NewInt(100)}
+	mockBC := mock_blockchain.NewMockBlockchain(ctrl)
+	mockBC.EXPECT().TipHeight().Return(uint64(5)).AnyTimes()
+	mockBC.EXPECT().Upgrades().Return(upgrades).AnyTimes()
+	mockBC.EXPECT().Candidates().Return(uint64(5), []*state.Candidate{
+		{Address: addr1.RawAddress, PubKey: addr1.PublicKey[:]},
+	}).AnyTimes()
+	mockBC.EXPECT().StateByAddr(addr1.RawAddress).Return(offenderState, nil).AnyTimes()
+	Ap, err := NewActPool(mockBC, getActPoolCfg())
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+
+	// Case I: Equivocation at height 0
+	evidence, err := equivocation(addr1, 0)
+	require.NoError(err)
+	require.Equal(action.ErrEvidence, errors.Cause(ap.AddEvidence(evidence)))
+	// Case II: Equivocation at a future height
+	evidence, err = equivocation(addr1, 7)
+	require.NoError(err)
+	require.Equal(action.ErrEvidence, errors.Cause(ap.AddEvidence(evidence)))
+	// Case III: Valid evidence
+	evidence, err = equivocation(addr1, 5)
+	require.NoError(err)
+	require.NoError(ap.AddEvidence(evidence))
+	// Case IV: Another evidence against the same offender
+	evidence, err = equivocation(addr1, 4)
+	require.NoError(err)
+	require.Equal(action.ErrEvidence, errors.Cause(ap.AddEvidence(evidence)))
+	require.Equal(1, len(ap.PickEvidences()))
+	// Case V: The evidence failing validation at mint time is removed instead of being picked
+	offenderState.JailedUntil = 10
+	require.Equal(0, len(ap.PickEvidences()))
+	require.Equal(0, len(ap.evidences))
 }
 
 func TestActPool_AddActs(t *testing.T) {
@@ -185,7 +262,7 @@ func TestActPool_AddActs(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(addr2.RawAddress, uint64(10))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 	// Create actpool
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
@@ -253,6 +330,9 @@ func TestActPool_AddActs(t *testing.T) {
 		nAction := nTsf.ConvertToActionPb()
 		ap2.allActions[nTsf.Hash()] = nAction
 	}
+	mockBC.EXPECT().TipHeight().Times(4).Return(uint64(0))
+	mockBC.EXPECT().Upgrades().Times(4).Return(config.Default.Chain.Upgrades)
+	mockBC.EXPECT().Genesis().Times(4).Return(blockchain.Gen)
 	mockBC.EXPECT().Nonce(gomock.Any()).Times(2).Return(uint64(0), nil)
 	mockBC.EXPECT().StateByAddr(gomock.Any()).Times(1).Return(nil, nil)
 	err = ap2.AddTsf(tsf1)
@@ -298,7 +378,7 @@ func TestActPool_PickActs(t *testing.T) {
 		require.NoError(err)
 		_, err = bc.CreateState(addr2.RawAddress, uint64(10))
 		require.NoError(err)
-		require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+		require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 		// Create actpool
 		Ap, err := NewActPool(bc, cfg)
 		require.NoError(err)
@@ -365,13 +445,82 @@ func TestActPool_PickActs(t *testing.T) {
 	})
 }
 
+func TestActPool_PickActsByGasPrice(t *testing.T) {
+	createActPool := func() *actPool {
+		require := require.New(t)
+		bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+		require.NoError(bc.Start(context.Background()))
+		_, err := bc.CreateState(addr1.RawAddress, uint64(1000000000))
+		require.NoError(err)
+		_, err = bc.CreateState(addr2.RawAddress, uint64(1000000000))
+		require.NoError(err)
+		require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
+		Ap, err := NewActPool(bc, getActPoolCfg())
+		require.NoError(err)
+		ap, ok := Ap.(*actPool)
+		require.True(ok)
+		return ap
+	}
+
+	t.Run("price-order", func(t *testing.T) {
+		require := require.New(t)
+		ap := createActPool()
+		tsf1, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(1))
+		tsf2, _ := signedTransfer(addr1, addr1, uint64(2), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(5))
+		tsf3, _ := signedTransfer(addr2, addr2, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(3))
+		tsf4, _ := signedTransfer(addr2, addr2, uint64(2), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(2))
+		require.NoError(ap.AddTsf(tsf1))
+		require.NoError(ap.AddTsf(tsf2))
+		require.NoError(ap.AddTsf(tsf3))
+		require.NoError(ap.AddTsf(tsf4))
+		// tsf2 pays the most but has to wait for tsf1 of the same sender
+		pickedTsfs, _, _ := ap.PickActs()
+		require.Equal([]*action.Transfer{tsf3, tsf4, tsf1, tsf2}, pickedTsfs)
+	})
+	t.Run("block-gas-limit", func(t *testing.T) {
+		require := require.New(t)
+		ap := createActPool()
+		tsf1, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(10), []byte{}, blockchain.GasLimit, big.NewInt(1))
+		tsf2, _ := signedTransfer(addr2, addr2, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(2))
+		require.NoError(ap.AddTsf(tsf1))
+		require.NoError(ap.AddTsf(tsf2))
+		pickedTsfs, _, _ := ap.PickActs()
+		require.Equal([]*action.Transfer{tsf2}, pickedTsfs)
+	})
+	t.Run("replacement", func(t *testing.T) {
+		require := require.New(t)
+		ap := createActPool()
+		tsf1, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(10))
+		require.NoError(ap.AddTsf(tsf1))
+		// the gas price is not bumped enough
+		tsf2, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(20), []byte{}, uint64(100000), big.NewInt(10))
+		require.Equal(ErrNonce, errors.Cause(ap.AddTsf(tsf2)))
+		// the sender cannot afford the replacement
+		overBalTsf, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(2000000000), []byte{}, uint64(100000), big.NewInt(20))
+		require.Equal(ErrBalance, errors.Cause(ap.AddTsf(overBalTsf)))
+		_, err := ap.GetActionByHash(tsf1.Hash())
+		require.NoError(err)
+		// replace the transfer
+		tsf3, _ := signedTransfer(addr1, addr1, uint64(1), big.NewInt(30), []byte{}, uint64(100000), big.NewInt(11))
+		require.NoError(ap.AddTsf(tsf3))
+		_, err = ap.GetActionByHash(tsf1.Hash())
+		require.Equal(ErrHash, errors.Cause(err))
+		_, err = ap.GetActionByHash(tsf3.Hash())
+		require.NoError(err)
+		require.Equal(uint64(1), ap.GetSize())
+		require.Equal(uint64(2), ap.accountActs[addr1.RawAddress].PendingNonce())
+		pickedTsfs, _, _ := ap.PickActs()
+		require.Equal([]*action.Transfer{tsf3}, pickedTsfs)
+	})
+}
+
 func TestActPool_removeConfirmedActs(t *testing.T) {
 	require := require.New(t)
 	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
 	require.NoError(bc.Start(context.Background()))
 	_, err := bc.CreateState(addr1.RawAddress, uint64(100))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 	// Create actpool
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
@@ -395,7 +544,7 @@ func TestActPool_removeConfirmedActs(t *testing.T) {
 
 	require.Equal(4, len(ap.allActions))
 	require.NotNil(ap.accountActs[addr1.RawAddress])
-	err = bc.CommitStateChanges(0, []*action.Transfer{tsf1, tsf2, tsf3}, []*action.Vote{vote4}, []*action.Execution{})
+	err = bc.CommitStateChanges(0, []*action.Transfer{tsf1, tsf2, tsf3}, []*action.Vote{vote4}, []*action.Execution{}, nil)
 	require.NoError(err)
 	ap.removeConfirmedActs()
 	require.Equal(0, len(ap.allActions))
@@ -413,7 +562,7 @@ func TestActPool_Reset(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(addr3.RawAddress, uint64(300))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 
 	apConfig := getActPoolCfg()
 	Ap1, err := NewActPool(bc, apConfig)
@@ -515,7 +664,7 @@ func TestActPool_Reset(t *testing.T) {
 	// Let ap1 be BP's actpool
 	pickedTsfs, pickedVotes, pickedExecutions := ap1.PickActs()
 	// ap1 commits update of accounts to trie
-	err = bc.CommitStateChanges(0, pickedTsfs, pickedVotes, pickedExecutions)
+	err = bc.CommitStateChanges(0, pickedTsfs, pickedVotes, pickedExecutions, nil)
 	require.NoError(err)
 	//Reset
 	ap1.Reset()
@@ -611,7 +760,7 @@ func TestActPool_Reset(t *testing.T) {
 	// Let ap2 be BP's actpool
 	pickedTsfs, pickedVotes, pickedExecutions = ap2.PickActs()
 	// ap2 commits update of accounts to trie
-	err = bc.CommitStateChanges(0, pickedTsfs, pickedVotes, pickedExecutions)
+	err = bc.CommitStateChanges(0, pickedTsfs, pickedVotes, pickedExecutions, nil)
 	require.NoError(err)
 	//Reset
 	ap1.Reset()
@@ -655,7 +804,7 @@ func TestActPool_Reset(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(addr5.RawAddress, uint64(20))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(1, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(1, nil, nil, nil, nil))
 	tsf21, _ := signedTransfer(addr4, addr5, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(10))
 	vote22, _ := signedVote(addr4, addr4, uint64(2), uint64(100000), big.NewInt(10))
 	vote23, _ := action.NewVote(3, addr4.RawAddress, "", uint64(100000), big.NewInt(10))
@@ -692,7 +841,7 @@ func TestActPool_Reset(t *testing.T) {
 	// Let ap1 be BP's actpool
 	pickedTsfs, pickedVotes, pickedExecutions = ap1.PickActs()
 	// ap1 commits update of accounts to trie
-	err = bc.CommitStateChanges(0, pickedTsfs, pickedVotes, pickedExecutions)
+	err = bc.CommitStateChanges(0, pickedTsfs, pickedVotes, pickedExecutions, nil)
 	require.NoError(err)
 	//Reset
 	ap1.Reset()
@@ -716,7 +865,7 @@ func TestActPool_removeInvalidActs(t *testing.T) {
 	require.NoError(bc.Start(context.Background()))
 	_, err := bc.CreateState(addr1.RawAddress, uint64(100))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 	// Create actpool
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
@@ -758,7 +907,7 @@ func TestActPool_GetPendingNonce(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(addr2.RawAddress, uint64(100))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 	// Create actpool
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
@@ -794,7 +943,7 @@ func TestActPool_GetUnconfirmedActs(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(addr2.RawAddress, uint64(100))
 	require.NoError(err)
-	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
 	// Create actpool
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
@@ -877,7 +1026,7 @@ func TestActPool_GetSize(t *testing.T) {
 	require.NoError(bc.Start(context.Background()))
 	_, err := bc.CreateState(addr1.RawAddress, uint64(100))
 	require.NoError(err)
-	require.NoError(bc.CommitStateChanges(0, nil, nil, nil))
+	require.NoError(bc.CommitStateChanges(0, nil, nil, nil, nil))
 	// Create actpool
 	apConfig := getActPoolCfg()
 	Ap, err := NewActPool(bc, apConfig)
@@ -901,11 +1050,56 @@ func TestActPool_GetSize(t *testing.T) {
 	require.Equal(uint64(4), ap.GetSize())
 
 	require.NoError(bc.CommitStateChanges(0,
-		[]*action.Transfer{tsf1, tsf2, tsf3}, []*action.Vote{vote4}, nil))
+		[]*action.Transfer{tsf1, tsf2, tsf3}, []*action.Vote{vote4}, nil, nil))
 	ap.removeConfirmedActs()
 	require.Equal(uint64(0), ap.GetSize())
 }
 
+func TestActPool_SubscribeNewAction(t *testing.T) {
+	require := require.New(t)
+	bc := blockchain.NewBlockchain(&config.Default, blockchain.InMemStateFactoryOption(), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	_, err := bc.CreateState(addr1.RawAddress, uint64(100))
+	require.NoError(err)
+	require.NoError(bc.CommitStateChanges(0, nil, nil, nil, nil))
+	// Create actpool
+	apConfig := getActPoolCfg()
+	Ap, err := NewActPool(bc, apConfig)
+	require.NoError(err)
+	ap, ok := Ap.(*actPool)
+	require.True(ok)
+
+	ch := make(chan *iproto.ActionPb, 1)
+	require.NoError(ap.SubscribeNewAction(ch))
+	require.Error(ap.SubscribeNewAction(ch))
+	require.Error(ap.SubscribeNewAction(nil))
+
+	tsf1, err := signedTransfer(addr1, addr1, uint64(1), big.NewInt(10), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf2, err := signedTransfer(addr1, addr1, uint64(2), big.NewInt(20), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf3, err := signedTransfer(addr1, addr1, uint64(3), big.NewInt(30), []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	require.NoError(ap.AddTsf(tsf1))
+	// the channel is full, so the action is dropped for it without blocking
+	require.NoError(ap.AddTsf(tsf2))
+	act := <-ch
+	require.Equal(tsf1.ConvertToActionPb(), act)
+	select {
+	case <-ch:
+		require.Fail("action should have been dropped")
+	default:
+	}
+	// a rejected action is not sent
+	require.Error(ap.AddTsf(tsf1))
+	require.Equal(0, len(ch))
+
+	require.NoError(ap.UnsubscribeNewAction(ch))
+	require.Error(ap.UnsubscribeNewAction(ch))
+	require.NoError(ap.AddTsf(tsf3))
+	require.Equal(0, len(ch))
+}
+
 // Helper function to return the correct pending nonce just in case of empty queue
 func (ap *actPool) getPendingNonce(addr string) (uint64, error) {
 	if queue, ok := ap.accountActs[addr]; ok {
@@ -944,7 +1138,25 @@ func signedVote(voter *iotxaddress.Address, votee *iotxaddress.Address, nonce ui
 
 func getActPoolCfg() config.ActPool {
 	return config.ActPool{
-		MaxNumActsPerPool: maxNumActsPerPool,
-		MaxNumActsPerAcct: maxNumActsPerAcct,
+		MaxNumActsPerPool:    maxNumActsPerPool,
+		MaxNumActsPerAcct:    maxNumActsPerAcct,
+		ReplacementPriceBump: 10,
+	}
+}
+
+func equivocation(offender *iotxaddress.Address, height uint64) (*action.Evidence, error) {
+	msgs := make([]*iproto.ViewChangeMsg, 0, 2)
+	blkHashes := []hash.Hash32B{{1}, {2}}
+	for i := range blkHashes {
+		h := action.ViewChangeHash(height, 0, iproto.ViewChangeMsg_PREVOTE, blkHashes[i], true)
+		msgs = append(msgs, &iproto.ViewChangeMsg{
+			Vctype:     iproto.ViewChangeMsg_PREVOTE,
+			BlockHash:  blkHashes[i][:],
+			SenderAddr: offender.RawAddress,
+			Decision:   true,
+			Height:     height,
+			Signature:  crypto.EC283.Sign(offender.PrivateKey, h[:]),
+		})
 	}
+	return action.NewEvidence(msgs[0], msgs[1])
 }
@@ -39,10 +39,36 @@ func (h *noncePriorityQueue) Pop() interface{} {
 	return x
 }
 
+// actPriceQueue is a max heap of accounts' consecutive pending actions, ordered by the gas price of the first action
+type actPriceQueue [][]*iproto.ActionPb
+
+func (h actPriceQueue) Len() int { return len(h) }
+func (h actPriceQueue) Less(i, j int) bool {
+	return new(big.Int).SetBytes(h[i][0].GasPrice).Cmp(new(big.Int).SetBytes(h[j][0].GasPrice)) > 0
+}
+func (h actPriceQueue) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *actPriceQueue) Push(x interface{}) {
+	in, ok := x.([]*iproto.ActionPb)
+	if !ok {
+		return
+	}
+	*h = append(*h, in)
+}
+
+func (h *actPriceQueue) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
 // ActQueue is the interface of actQueue
 type ActQueue interface {
 	Overlaps(*iproto.ActionPb) bool
 	Put(*iproto.ActionPb) error
+	Replace(*iproto.ActionPb, uint64) (*iproto.ActionPb, error)
 	FilterNonce(uint64) []*iproto.ActionPb
 	SetStartNonce(uint64)
 	StartNonce() uint64
@@ -51,6 +77,7 @@ type ActQueue interface {
 	PendingNonce() uint64
 	SetPendingBalance(*big.Int)
 	PendingBalance() *big.Int
+	SetChargeGasFee(bool)
 	Len() int
 	Empty() bool
 	PendingActs() []*iproto.ActionPb
@@ -69,6 +96,8 @@ type actQueue struct {
 	pendingNonce uint64
 	// Current pending balance for the account
 	pendingBalance *big.Int
+	// Whether the intrinsic gas fees of transfers and votes are charged at the next height
+	chargeGasFee bool
 }
 
 // NewActQueue create a new action queue
@@ -98,6 +127,25 @@ func (q *actQueue) Put(act *iproto.ActionPb) error {
 	return nil
 }
 
+// Replace replaces the action of the same nonce in the map if the gas price of the new action is higher by at least
+// the given percentage, and returns the replaced action
+func (q *actQueue) Replace(act *iproto.ActionPb, priceBump uint64) (*iproto.ActionPb, error) {
+	nonce := act.Nonce
+	prev := q.items[nonce]
+	if prev == nil {
+		return nil, errors.Wrapf(ErrNonce, "no action to replace")
+	}
+	prevPrice := new(big.Int).SetBytes(prev.GasPrice)
+	price := new(big.Int).SetBytes(act.GasPrice)
+	threshold := new(big.Int).Mul(prevPrice, new(big.Int).SetUint64(100+priceBump))
+	threshold.Div(threshold, big.NewInt(100))
+	if price.Cmp(prevPrice) <= 0 || price.Cmp(threshold) < 0 {
+		return nil, errors.Wrapf(ErrNonce, "replacement action is underpriced")
+	}
+	q.items[nonce] = act
+	return prev, nil
+}
+
 // FilterNonce removes all actions from the map with a nonce lower than the given threshold
 func (q *actQueue) FilterNonce(threshold uint64) []*iproto.ActionPb {
 	var removed []*iproto.ActionPb
@@ -115,25 +163,11 @@ func (q *actQueue) UpdateQueue(nonce uint64) []*iproto.ActionPb {
 	// First, starting from the current pending nonce, incrementally find the next pending nonce
 	// while updating pending balance if transfers are payable
 	for ; q.items[nonce] != nil; nonce++ {
-		if q.items[nonce].GetVote() != nil {
-			continue
-		}
-		if q.items[nonce].GetTransfer() != nil {
-			tsf := &action.Transfer{}
-			tsf.ConvertFromActionPb(q.items[nonce])
-			if q.pendingBalance.Cmp(tsf.Amount) < 0 {
-				break
-			}
-			q.pendingBalance.Sub(q.pendingBalance, tsf.Amount)
-		}
-		if q.items[nonce].GetExecution() != nil {
-			execution := &action.Execution{}
-			execution.ConvertFromActionPb(q.items[nonce])
-			if q.pendingBalance.Cmp(execution.Amount) < 0 {
-				break
-			}
-			q.pendingBalance.Sub(q.pendingBalance, execution.Amount)
+		cost := actCost(q.items[nonce], q.chargeGasFee)
+		if q.pendingBalance.Cmp(cost) < 0 {
+			break
 		}
+		q.pendingBalance.Sub(q.pendingBalance, cost)
 	}
 	q.pendingNonce = nonce
 
@@ -158,9 +192,7 @@ func (q *actQueue) UpdateQueue(nonce uint64) []*iproto.ActionPb {
 	for ; i < q.index.Len(); i++ {
 		nonce = q.index[i]
 		if act := q.items[nonce]; act.GetTransfer() != nil {
-			tsf := &action.Transfer{}
-			tsf.ConvertFromActionPb(act)
-			if q.pendingBalance.Cmp(tsf.Amount) < 0 {
+			if q.pendingBalance.Cmp(actCost(act, q.chargeGasFee)) < 0 {
 				break
 			}
 		}
@@ -198,6 +230,11 @@ func (q *actQueue) PendingBalance() *big.Int {
 	return q.pendingBalance
 }
 
+// SetChargeGasFee sets whether the pending balance pays the intrinsic gas fees of transfers and votes
+func (q *actQueue) SetChargeGasFee(chargeGasFee bool) {
+	q.chargeGasFee = chargeGasFee
+}
+
 // Len returns the length of the action map
 func (q *actQueue) Len() int {
 	return len(q.items)
@@ -245,3 +282,36 @@ func (q *actQueue) removeActs(idx int) []*iproto.ActionPb {
 	heap.Init(&q.index)
 	return removedFromQueue
 }
+
+// actCost returns the balance needed by an action, i.e. the amount of transfer or execution plus the intrinsic gas fee
+// of transfer or vote, or the gas deposit of execution, if charged
+func actCost(act *iproto.ActionPb, chargeGasFee bool) *big.Int {
+	cost := big.NewInt(0)
+	switch {
+	case act.GetTransfer() != nil:
+		tsf := &action.Transfer{}
+		tsf.ConvertFromActionPb(act)
+		if chargeGasFee {
+			// the intrinsic gas of the transfer has been verified in validation
+			gas, _ := tsf.IntrinsicGas()
+			cost.Mul(new(big.Int).SetUint64(gas), tsf.GasPrice)
+		}
+		cost.Add(cost, tsf.Amount)
+	case act.GetVote() != nil:
+		if !chargeGasFee {
+			break
+		}
+		vote := &action.Vote{}
+		vote.ConvertFromActionPb(act)
+		cost.Mul(new(big.Int).SetUint64(vote.IntrinsicGas()), new(big.Int).SetBytes(vote.GasPrice))
+	case act.GetExecution() != nil:
+		execution := &action.Execution{}
+		execution.ConvertFromActionPb(act)
+		if chargeGasFee {
+			// the gas limit is deposited up front, and the gas left is refunded after the execution
+			cost.Mul(new(big.Int).SetUint64(execution.GasLimit), execution.GasPrice)
+		}
+		cost.Add(cost, execution.Amount)
+	}
+	return cost
+}
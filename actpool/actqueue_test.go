@@ -218,3 +218,13 @@ func TestActQueue_removeActs(t *testing.T) {
 	require.Equal(1, len(q.items))
 	require.Equal([]*pb.ActionPb{action5, action6}, removed)
 }
+
+func TestActCost(t *testing.T) {
+	require := require.New(t)
+	execution, err := action.NewExecution("executor", "contract", uint64(1), big.NewInt(100), uint64(1000), big.NewInt(10), []byte{})
+	require.NoError(err)
+	act := execution.ConvertToActionPb()
+	require.Equal(uint64(100), actCost(act, false).Uint64())
+	// the gas deposit of the execution is needed once the gas fees are charged
+	require.Equal(uint64(10100), actCost(act, true).Uint64())
+}
@@ -17,4 +17,15 @@ const (
 	BooleanSizeInBytes = 1
 	// GasSizeInBytes defines the size of gas in byte uints
 	GasSizeInBytes = 8
+	// ChainIDSizeInBytes defines the size of chain ID in byte units
+	ChainIDSizeInBytes = 4
+)
+
+const (
+	// TransferBaseIntrinsicGas represents the base intrinsic gas for transfer
+	TransferBaseIntrinsicGas = uint64(10000)
+	// TransferPayloadGas represents the transfer payload gas per uint
+	TransferPayloadGas = uint64(100)
+	// VoteIntrinsicGas represents the intrinsic gas for vote
+	VoteIntrinsicGas = uint64(10000)
 )
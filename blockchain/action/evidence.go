@@ -0,0 +1,213 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/pkg/version"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+var (
+	// ErrEvidence indicates error for an evidence action
+	ErrEvidence = errors.New("invalid evidence")
+)
+
+// Evidence defines the struct of the evidence of a delegate's equivocation, which consists of two conflicting view
+// change messages signed by the delegate at the same height and round. An evidence is included in a block by the block
+// producer, and has no nonce, gas or signature of its own, since it is proven by the signatures of the offender
+type Evidence struct {
+	*iproto.ActionPb
+}
+
+// NewEvidence returns an Evidence instance of the two conflicting messages. The messages only carry the hashes of the
+// blocks they are about, and are ordered by their content hashes, so that the same equivocation always has the same
+// evidence no matter which message is seen first
+func NewEvidence(first *iproto.ViewChangeMsg, second *iproto.ViewChangeMsg) (*Evidence, error) {
+	if err := verifyConflict(first, second); err != nil {
+		return nil, err
+	}
+	h1 := viewChangeMsgHash(first)
+	h2 := viewChangeMsgHash(second)
+	if bytes.Compare(h1[:], h2[:]) > 0 {
+		first, second = second, first
+	}
+	pbEvidence := &iproto.ActionPb{
+		Action: &iproto.ActionPb_Evidence{
+			Evidence: &iproto.EvidencePb{
+				First:  compactViewChangeMsg(first),
+				Second: compactViewChangeMsg(second),
+			},
+		},
+		Version: version.ProtocolVersion,
+	}
+	return &Evidence{pbEvidence}, nil
+}
+
+// Offender returns the address of the delegate who signed the conflicting messages
+func (e *Evidence) Offender() string {
+	return e.GetEvidence().GetFirst().GetSenderAddr()
+}
+
+// Height returns the height the conflicting messages are signed at
+func (e *Evidence) Height() uint64 {
+	return e.GetEvidence().GetFirst().GetHeight()
+}
+
+// TotalSize returns the total size of this Evidence
+func (e *Evidence) TotalSize() uint32 {
+	size := VersionSizeInBytes
+	for _, msg := range []*iproto.ViewChangeMsg{e.GetEvidence().GetFirst(), e.GetEvidence().GetSecond()} {
+		size += len(msg.GetSenderAddr())
+		size += len(msg.GetBlockHash())
+		size += len(msg.GetSignature())
+	}
+	return uint32(size)
+}
+
+// ByteStream returns a raw byte stream of this Evidence
+func (e *Evidence) ByteStream() []byte {
+	stream := byteutil.Uint32ToBytes(e.Version)
+	for _, msg := range []*iproto.ViewChangeMsg{e.GetEvidence().GetFirst(), e.GetEvidence().GetSecond()} {
+		h := viewChangeMsgHash(msg)
+		stream = append(stream, msg.GetSenderAddr()...)
+		stream = append(stream, h[:]...)
+		stream = append(stream, msg.GetSignature()...)
+	}
+	return stream
+}
+
+// ConvertToActionPb converts Evidence to protobuf's ActionPb
+func (e *Evidence) ConvertToActionPb() *iproto.ActionPb {
+	return e.ActionPb
+}
+
+// ConvertFromActionPb converts a protobuf's ActionPb to Evidence
+func (e *Evidence) ConvertFromActionPb(pbAct *iproto.ActionPb) {
+	e.ActionPb = pbAct
+}
+
+// Serialize returns a serialized byte stream for the Evidence
+func (e *Evidence) Serialize() ([]byte, error) {
+	return proto.Marshal(e.ConvertToActionPb())
+}
+
+// Deserialize parse the byte stream into Evidence
+func (e *Evidence) Deserialize(buf []byte) error {
+	pbEvidence := &iproto.ActionPb{}
+	if err := proto.Unmarshal(buf, pbEvidence); err != nil {
+		return err
+	}
+	e.ConvertFromActionPb(pbEvidence)
+	return nil
+}
+
+// Hash returns the hash of the Evidence
+func (e *Evidence) Hash() hash.Hash32B {
+	return blake2b.Sum256(e.ByteStream())
+}
+
+// Verify verifies the messages of the Evidence conflict with each other, and are both signed by the offender's key
+func (e *Evidence) Verify(offenderPubKey keypair.PublicKey) error {
+	first := e.GetEvidence().GetFirst()
+	second := e.GetEvidence().GetSecond()
+	if err := verifyConflict(first, second); err != nil {
+		return err
+	}
+	for _, msg := range []*iproto.ViewChangeMsg{first, second} {
+		h := viewChangeMsgHash(msg)
+		if !crypto.EC283.Verify(offenderPubKey, h[:], msg.Signature) {
+			return errors.Wrapf(ErrEvidence, "failed to verify the signature of %s over %s", msg.SenderAddr, msg.Vctype)
+		}
+	}
+	return nil
+}
+
+// ViewChangeHash returns the hash of the content of a consensus view change message, which is signed by the delegate
+// sending it
+func ViewChangeHash(
+	height uint64,
+	round uint32,
+	vctype iproto.ViewChangeMsg_ViewChangeType,
+	blkHash hash.Hash32B,
+	decision bool,
+) hash.Hash32B {
+	stream := byteutil.Uint64ToBytes(height)
+	stream = append(stream, byteutil.Uint32ToBytes(round)...)
+	stream = append(stream, byteutil.Uint32ToBytes(uint32(vctype))...)
+	stream = append(stream, blkHash[:]...)
+	if decision {
+		stream = append(stream, 1)
+	} else {
+		stream = append(stream, 0)
+	}
+	return byteutil.BytesTo32B(hash.Hash256b(stream))
+}
+
+//======================================
+// private functions
+//======================================
+
+// verifyConflict verifies the messages are sent by the same delegate at the same height and round for the same step
+// of the consensus, but about different blocks or with different decisions
+func verifyConflict(first *iproto.ViewChangeMsg, second *iproto.ViewChangeMsg) error {
+	if first == nil || second == nil {
+		return errors.Wrap(ErrEvidence, "the evidence misses a message")
+	}
+	if first.SenderAddr != second.SenderAddr {
+		return errors.Wrapf(ErrEvidence, "messages from %s and %s", first.SenderAddr, second.SenderAddr)
+	}
+	if first.Height != second.Height || first.Round != second.Round || first.Vctype != second.Vctype {
+		return errors.Wrapf(
+			ErrEvidence,
+			"messages of %s at height %d round %d and %s at height %d round %d",
+			first.Vctype,
+			first.Height,
+			first.Round,
+			second.Vctype,
+			second.Height,
+			second.Round,
+		)
+	}
+	if viewChangeMsgHash(first) == viewChangeMsgHash(second) {
+		return errors.Wrapf(ErrEvidence, "messages of %s at height %d don't conflict", first.Vctype, first.Height)
+	}
+	return nil
+}
+
+// viewChangeMsgHash returns the hash signed by the sender of the message
+func viewChangeMsgHash(msg *iproto.ViewChangeMsg) hash.Hash32B {
+	return ViewChangeHash(
+		msg.Height,
+		msg.Round,
+		msg.Vctype,
+		byteutil.BytesTo32B(msg.BlockHash),
+		msg.Decision,
+	)
+}
+
+// compactViewChangeMsg returns a copy of the message without the block, which is not needed to verify the signature
+func compactViewChangeMsg(msg *iproto.ViewChangeMsg) *iproto.ViewChangeMsg {
+	return &iproto.ViewChangeMsg{
+		Vctype:     msg.Vctype,
+		BlockHash:  msg.BlockHash,
+		SenderAddr: msg.SenderAddr,
+		Decision:   msg.Decision,
+		Height:     msg.Height,
+		Round:      msg.Round,
+		Signature:  msg.Signature,
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package action
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+func signedPrevote(signer *iotxaddress.Address, blkHash hash.Hash32B, decision bool) *iproto.ViewChangeMsg {
+	h := ViewChangeHash(5, 1, iproto.ViewChangeMsg_PREVOTE, blkHash, decision)
+	return &iproto.ViewChangeMsg{
+		Vctype:     iproto.ViewChangeMsg_PREVOTE,
+		BlockHash:  blkHash[:],
+		SenderAddr: signer.RawAddress,
+		Decision:   decision,
+		Height:     5,
+		Round:      1,
+		Signature:  crypto.EC283.Sign(signer.PrivateKey, h[:]),
+	}
+}
+
+func TestEvidence(t *testing.T) {
+	require := require.New(t)
+	offender, err := iotxaddress.NewAddress(true, chainid)
+	require.NoError(err)
+	other, err := iotxaddress.NewAddress(true, chainid)
+	require.NoError(err)
+
+	first := signedPrevote(offender, hash.Hash32B{1}, true)
+	second := signedPrevote(offender, hash.Hash32B{2}, true)
+	e, err := NewEvidence(first, second)
+	require.NoError(err)
+	require.Equal(offender.RawAddress, e.Offender())
+	require.Equal(uint64(5), e.Height())
+	require.NoError(e.Verify(offender.PublicKey))
+	require.Equal(ErrEvidence, errors.Cause(e.Verify(other.PublicKey)))
+
+	// The same equivocation has the same evidence no matter which message is seen first
+	reversed, err := NewEvidence(second, first)
+	require.NoError(err)
+	require.Equal(e.Hash(), reversed.Hash())
+
+	// Messages with different decisions conflict too
+	_, err = NewEvidence(first, signedPrevote(offender, hash.Hash32B{1}, false))
+	require.NoError(err)
+
+	// Messages not conflicting
+	_, err = NewEvidence(first, first)
+	require.Equal(ErrEvidence, errors.Cause(err))
+	_, err = NewEvidence(first, signedPrevote(other, hash.Hash32B{2}, true))
+	require.Equal(ErrEvidence, errors.Cause(err))
+	vote := signedPrevote(offender, hash.Hash32B{2}, true)
+	vote.Vctype = iproto.ViewChangeMsg_VOTE
+	_, err = NewEvidence(first, vote)
+	require.Equal(ErrEvidence, errors.Cause(err))
+}
+
+func TestEvidenceSerializeDeserialize(t *testing.T) {
+	require := require.New(t)
+	offender, err := iotxaddress.NewAddress(true, chainid)
+	require.NoError(err)
+
+	e, err := NewEvidence(
+		signedPrevote(offender, hash.Hash32B{1}, true),
+		signedPrevote(offender, hash.Hash32B{2}, true),
+	)
+	require.NoError(err)
+	s, err := e.Serialize()
+	require.NoError(err)
+
+	newE := &Evidence{}
+	require.NoError(newE.Deserialize(s))
+	require.Equal(e.Hash(), newE.Hash())
+	require.Equal(e.Offender(), newE.Offender())
+	require.NoError(newE.Verify(offender.PublicKey))
+}
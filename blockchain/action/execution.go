@@ -46,6 +46,8 @@ type Execution struct {
 	GasPrice       *big.Int
 	Signature      []byte
 	Data           []byte
+	// ChainID is the ID of the chain the execution is signed for, which is 0 if it is signed without the chain ID
+	ChainID uint32
 }
 
 // NewExecution returns a Execution instance
@@ -82,6 +84,9 @@ func (ex *Execution) TotalSize() uint32 {
 		size += len(ex.GasPrice.Bytes())
 	}
 	size += len(ex.Data)
+	if ex.ChainID != 0 {
+		size += ChainIDSizeInBytes
+	}
 	return uint32(size)
 }
 
@@ -106,6 +111,12 @@ func (ex *Execution) ByteStream() []byte {
 	}
 	// Signature = Sign(hash(ByteStream())), so not included
 	stream = append(stream, ex.Data...)
+	// the chain ID is only signed if set, so the actions signed without it keep their hashes
+	if ex.ChainID != 0 {
+		temp = make([]byte, ChainIDSizeInBytes)
+		enc.MachineEndian.PutUint32(temp, ex.ChainID)
+		stream = append(stream, temp...)
+	}
 	return stream
 }
 
@@ -124,6 +135,7 @@ func (ex *Execution) ConvertToActionPb() *iproto.ActionPb {
 		Nonce:     ex.Nonce,
 		GasLimit:  ex.GasLimit,
 		Signature: ex.Signature,
+		ChainID:   ex.ChainID,
 	}
 	if ex.Amount != nil && len(ex.Amount.Bytes()) > 0 {
 		act.GetExecution().Amount = ex.Amount.Bytes()
@@ -145,6 +157,7 @@ func (ex *Execution) ToJSON() (*explorer.Execution, error) {
 		GasLimit:       int64(ex.GasLimit),
 		Data:           hex.EncodeToString(ex.Data),
 		Signature:      hex.EncodeToString(ex.Signature),
+		ChainID:        int64(ex.ChainID),
 	}
 	if ex.Amount != nil && len(ex.Amount.Bytes()) > 0 {
 		execution.Amount = ex.Amount.Int64()
@@ -166,6 +179,7 @@ func (ex *Execution) ConvertFromActionPb(pbAct *iproto.ActionPb) {
 	ex.Nonce = pbAct.GetNonce()
 	ex.GasLimit = pbAct.GetGasLimit()
 	ex.Signature = pbAct.GetSignature()
+	ex.ChainID = pbAct.GetChainID()
 	pbExecution := pbAct.GetExecution()
 	ex.Executor = pbExecution.Executor
 	ex.Contract = pbExecution.GetContract()
@@ -213,6 +227,7 @@ func NewExecutionFromJSON(jsonExecution *explorer.Execution) (*Execution, error)
 		return nil, err
 	}
 	ex.Signature = signature
+	ex.ChainID = uint32(jsonExecution.ChainID)
 
 	return ex, nil
 }
@@ -9,6 +9,7 @@ package action
 import (
 	"bytes"
 	"encoding/hex"
+	"math"
 	"math/big"
 
 	"github.com/golang/protobuf/proto"
@@ -48,6 +49,8 @@ type (
 		GasPrice        *big.Int
 		Signature       []byte
 		IsCoinbase      bool
+		// ChainID is the ID of the chain the transfer is signed for, which is 0 if it is signed without the chain ID
+		ChainID uint32
 		// Coinbase transfer is not expected to be received from the network but can only be generated by block producer
 	}
 )
@@ -109,9 +112,24 @@ func (tsf *Transfer) TotalSize() uint32 {
 	}
 	size += len(tsf.SenderPublicKey)
 	size += len(tsf.Signature)
+	if tsf.ChainID != 0 {
+		size += ChainIDSizeInBytes
+	}
 	return uint32(size)
 }
 
+// IntrinsicGas returns the intrinsic gas of this Transfer, which is free for coinbase transfer
+func (tsf *Transfer) IntrinsicGas() (uint64, error) {
+	if tsf.IsCoinbase {
+		return 0, nil
+	}
+	payloadSize := uint64(len(tsf.Payload))
+	if (math.MaxUint64-TransferBaseIntrinsicGas)/TransferPayloadGas < payloadSize {
+		return 0, errors.Wrap(ErrTransferError, "intrinsic gas overflows")
+	}
+	return payloadSize*TransferPayloadGas + TransferBaseIntrinsicGas, nil
+}
+
 // ByteStream returns a raw byte stream of this Transfer
 func (tsf *Transfer) ByteStream() []byte {
 	stream := make([]byte, 4)
@@ -138,6 +156,12 @@ func (tsf *Transfer) ByteStream() []byte {
 	} else {
 		stream = append(stream, 0)
 	}
+	// the chain ID is only signed if set, so the actions signed without it keep their hashes
+	if tsf.ChainID != 0 {
+		temp = make([]byte, ChainIDSizeInBytes)
+		enc.MachineEndian.PutUint32(temp, tsf.ChainID)
+		stream = append(stream, temp...)
+	}
 	return stream
 }
 
@@ -158,6 +182,7 @@ func (tsf *Transfer) ConvertToActionPb() *iproto.ActionPb {
 		Nonce:     tsf.Nonce,
 		GasLimit:  tsf.GasLimit,
 		Signature: tsf.Signature,
+		ChainID:   tsf.ChainID,
 	}
 
 	if tsf.Amount != nil && len(tsf.Amount.Bytes()) > 0 {
@@ -182,6 +207,7 @@ func (tsf *Transfer) ToJSON() *explorer.Transfer {
 		GasLimit:     int64(tsf.GasLimit),
 		Signature:    hex.EncodeToString(tsf.Signature),
 		IsCoinbase:   tsf.IsCoinbase,
+		ChainID:      int64(tsf.ChainID),
 	}
 
 	if tsf.Amount != nil && len(tsf.Amount.Bytes()) > 0 {
@@ -225,6 +251,7 @@ func (tsf *Transfer) ConvertFromActionPb(pbAct *iproto.ActionPb) {
 	copy(tsf.SenderPublicKey[:], pbTsf.SenderPubKey)
 	tsf.Signature = pbAct.Signature
 	tsf.IsCoinbase = pbTsf.IsCoinbase
+	tsf.ChainID = pbAct.GetChainID()
 }
 
 // NewTransferFromJSON creates a new Transfer from TransferJSON
@@ -257,6 +284,7 @@ func NewTransferFromJSON(jsonTsf *explorer.Transfer) (*Transfer, error) {
 	}
 	tsf.Signature = signature
 	tsf.IsCoinbase = jsonTsf.IsCoinbase
+	tsf.ChainID = uint32(jsonTsf.ChainID)
 
 	return tsf, nil
 }
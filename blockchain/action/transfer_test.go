@@ -105,3 +105,55 @@ func TestCoinbaseTsf(t *testing.T) {
 	require.NotNil(t, coinbaseTsf)
 	require.True(t, coinbaseTsf.IsCoinbase)
 }
+
+func TestTransferIntrinsicGas(t *testing.T) {
+	require := require.New(t)
+
+	tsf, err := NewTransfer(0, big.NewInt(10), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	gas, err := tsf.IntrinsicGas()
+	require.NoError(err)
+	require.Equal(TransferBaseIntrinsicGas, gas)
+
+	tsf.Payload = []byte("payload")
+	gas, err = tsf.IntrinsicGas()
+	require.NoError(err)
+	require.Equal(TransferBaseIntrinsicGas+7*TransferPayloadGas, gas)
+
+	coinbaseTsf := NewCoinBaseTransfer(big.NewInt(int64(5)), ta.Addrinfo["producer"].RawAddress)
+	gas, err = coinbaseTsf.IntrinsicGas()
+	require.NoError(err)
+	require.Equal(uint64(0), gas)
+}
+
+func TestTransferChainID(t *testing.T) {
+	require := require.New(t)
+	sender, err := iotxaddress.NewAddress(true, chainid)
+	require.NoError(err)
+	recipient, err := iotxaddress.NewAddress(true, chainid)
+	require.NoError(err)
+
+	tsf, err := NewTransfer(0, big.NewInt(10), sender.RawAddress, recipient.RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	legacyHash := tsf.Hash()
+	tsf.ChainID = 1
+	require.NotEqual(legacyHash, tsf.Hash())
+	tsf, err = tsf.Sign(sender)
+	require.NoError(err)
+
+	// the chain ID survives the round trips
+	raw, err := tsf.Serialize()
+	require.NoError(err)
+	newtsf := &Transfer{}
+	require.NoError(newtsf.Deserialize(raw))
+	require.Equal(uint32(1), newtsf.ChainID)
+	require.Equal(tsf.Hash(), newtsf.Hash())
+	require.NoError(newtsf.Verify(sender))
+	newtsf, err = NewTransferFromJSON(tsf.ToJSON())
+	require.NoError(err)
+	require.Equal(tsf.Hash(), newtsf.Hash())
+
+	// the signature does not verify on another chain
+	newtsf.ChainID = 2
+	require.Error(newtsf.Verify(sender))
+}
@@ -76,9 +76,17 @@ func (v *Vote) TotalSize() uint32 {
 	size += GasSizeInBytes
 	size += len(v.GasPrice)
 	size += len(v.Signature)
+	if v.ChainID != 0 {
+		size += ChainIDSizeInBytes
+	}
 	return uint32(size)
 }
 
+// IntrinsicGas returns the intrinsic gas of this Vote
+func (v *Vote) IntrinsicGas() uint64 {
+	return VoteIntrinsicGas
+}
+
 // ByteStream returns a raw byte stream of this Transfer
 func (v *Vote) ByteStream() []byte {
 	stream := make([]byte, TimestampSizeInBytes)
@@ -98,6 +106,12 @@ func (v *Vote) ByteStream() []byte {
 	stream = append(stream, temp...)
 	stream = append(stream, v.GasPrice...)
 	// Signature = Sign(hash(ByteStream())), so not included
+	// the chain ID is only signed if set, so the actions signed without it keep their hashes
+	if v.ChainID != 0 {
+		temp = make([]byte, ChainIDSizeInBytes)
+		enc.MachineEndian.PutUint32(temp, v.ChainID)
+		stream = append(stream, temp...)
+	}
 	return stream
 }
 
@@ -123,6 +137,7 @@ func (v *Vote) ToJSON() (*explorer.Vote, error) {
 		GasLimit:    int64(v.GasLimit),
 		GasPrice:    big.NewInt(0).SetBytes(v.GasPrice).Int64(),
 		Signature:   hex.EncodeToString(v.Signature),
+		ChainID:     int64(v.ChainID),
 	}
 	return vote, nil
 }
@@ -164,6 +179,7 @@ func NewVoteFromJSON(jsonVote *explorer.Vote) (*Vote, error) {
 		GasLimit:  uint64(jsonVote.GasLimit),
 		GasPrice:  big.NewInt(jsonVote.GasPrice).Bytes(),
 		Signature: signature,
+		ChainID:   uint32(jsonVote.ChainID),
 	}
 	return &Vote{pbVote}, nil
 }
@@ -73,3 +73,29 @@ func TestVoteToJSONFromJSON(t *testing.T) {
 	require.Equal(v.Hash(), newv.Hash())
 	require.Equal(v.TotalSize(), newv.TotalSize())
 }
+
+func TestVoteChainID(t *testing.T) {
+	require := require.New(t)
+	sender, err := iotxaddress.NewAddress(true, chainid)
+	require.NoError(err)
+	recipient, err := iotxaddress.NewAddress(true, chainid)
+	require.NoError(err)
+
+	v, err := NewVote(0, sender.RawAddress, recipient.RawAddress, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	legacyHash := v.Hash()
+	v.ChainID = 1
+	require.NotEqual(legacyHash, v.Hash())
+	v, err = v.Sign(sender)
+	require.NoError(err)
+
+	expv, err := v.ToJSON()
+	require.NoError(err)
+	newv, err := NewVoteFromJSON(expv)
+	require.NoError(err)
+	require.Equal(uint32(1), newv.ChainID)
+	require.NoError(newv.Verify(sender))
+
+	newv.ChainID = 2
+	require.Error(newv.Verify(sender))
+}
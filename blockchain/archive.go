@@ -0,0 +1,124 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+// maxArchivedBlockSize is the maximum size of a serialized block in the archive
+const maxArchivedBlockSize = 1 << 28
+
+// ErrInvalidArchive indicates the block archive is corrupted or does not match the blockchain
+var ErrInvalidArchive = errors.New("invalid block archive")
+
+// gzipMagic is the leading bytes of a gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ExportBlocks writes the blocks in [start, end] into w. Every block is serialized as iproto.BlockPb and prefixed
+// by its length in 4-byte big endian. The whole stream is compressed by gzip if compress is true
+func ExportBlocks(bc Blockchain, w io.Writer, start, end uint64, compress bool) error {
+	if end > bc.TipHeight() {
+		return errors.Wrapf(ErrInvalidArchive, "end height %d is higher than tip height %d", end, bc.TipHeight())
+	}
+	if start > end {
+		return errors.Wrapf(ErrInvalidArchive, "start height %d is higher than end height %d", start, end)
+	}
+	var zw *gzip.Writer
+	if compress {
+		zw = gzip.NewWriter(w)
+		w = zw
+	}
+	bw := bufio.NewWriter(w)
+	lenBuf := make([]byte, 4)
+	for height := start; height <= end; height++ {
+		blk, err := bc.GetBlockByHeight(height)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get block on height %d", height)
+		}
+		data, err := blk.Serialize()
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize block on height %d", height)
+		}
+		binary.BigEndian.PutUint32(lenBuf, uint32(len(data)))
+		if _, err := bw.Write(lenBuf); err != nil {
+			return errors.Wrapf(err, "failed to write block on height %d", height)
+		}
+		if _, err := bw.Write(data); err != nil {
+			return errors.Wrapf(err, "failed to write block on height %d", height)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "failed to flush blocks")
+	}
+	if zw != nil {
+		return zw.Close()
+	}
+	return nil
+}
+
+// ImportBlocks reads the blocks written by ExportBlocks from r, validates and commits them to the blockchain in
+// order. Blocks no higher than the tip are skipped if they are already in the blockchain, so an interrupted import
+// can be resumed. It returns the number of blocks committed
+func ImportBlocks(bc Blockchain, r io.Reader) (uint64, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(len(gzipMagic)); err == nil && magic[0] == gzipMagic[0] && magic[1] == gzipMagic[1] {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to open compressed archive")
+		}
+		defer zr.Close()
+		br = bufio.NewReader(zr)
+	}
+	var imported uint64
+	lenBuf := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(br, lenBuf); err != nil {
+			if err == io.EOF {
+				return imported, nil
+			}
+			return imported, errors.Wrap(ErrInvalidArchive, "failed to read block length")
+		}
+		size := binary.BigEndian.Uint32(lenBuf)
+		if size > maxArchivedBlockSize {
+			return imported, errors.Wrapf(ErrInvalidArchive, "block size %d exceeds limit", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return imported, errors.Wrap(ErrInvalidArchive, "failed to read block")
+		}
+		blk := &Block{}
+		if err := blk.Deserialize(data); err != nil {
+			return imported, errors.Wrapf(ErrInvalidArchive, "failed to deserialize block: %v", err)
+		}
+		if blk.Height() <= bc.TipHeight() {
+			existing, err := bc.GetHashByHeight(blk.Height())
+			if err != nil {
+				return imported, errors.Wrapf(err, "failed to get block hash on height %d", blk.Height())
+			}
+			if existing != blk.HashBlock() {
+				return imported, errors.Wrapf(ErrInvalidArchive, "block on height %d does not match blockchain", blk.Height())
+			}
+			continue
+		}
+		if err := bc.ValidateBlock(blk); err != nil {
+			return imported, errors.Wrapf(err, "failed to validate block on height %d", blk.Height())
+		}
+		if err := bc.CommitBlock(blk); err != nil {
+			return imported, errors.Wrapf(err, "failed to commit block on height %d", blk.Height())
+		}
+		imported++
+		logger.Debug().Uint64("height", blk.Height()).Msg("Imported block")
+	}
+}
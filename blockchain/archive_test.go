@@ -0,0 +1,93 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/state"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestExportImportBlocks(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+	newChain := func() Blockchain {
+		sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+		require.Nil(err)
+		require.NoError(sf.Start(context.Background()))
+		_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
+		require.NoError(err)
+		bc := NewBlockchain(&cfg, PrecreatedStateFactoryOption(sf), InMemDaoOption())
+		require.NotNil(bc)
+		require.NoError(bc.Start(context.Background()))
+		return bc
+	}
+	bc := newChain()
+	defer func() {
+		require.NoError(bc.Stop(context.Background()))
+	}()
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(uint64(4), bc.TipHeight())
+
+	var buf bytes.Buffer
+	require.Equal(ErrInvalidArchive, errors.Cause(ExportBlocks(bc, &buf, 1, 5, false)))
+	require.Equal(ErrInvalidArchive, errors.Cause(ExportBlocks(bc, &buf, 3, 2, false)))
+
+	for _, compress := range []bool{false, true} {
+		// export in two ranges
+		var first, second bytes.Buffer
+		require.NoError(ExportBlocks(bc, &first, 1, 2, compress))
+		require.NoError(ExportBlocks(bc, &second, 1, 4, compress))
+
+		bc2 := newChain()
+		imported, err := ImportBlocks(bc2, &first)
+		require.NoError(err)
+		require.Equal(uint64(2), imported)
+		require.Equal(uint64(2), bc2.TipHeight())
+		// blocks already in the blockchain are skipped
+		imported, err = ImportBlocks(bc2, &second)
+		require.NoError(err)
+		require.Equal(uint64(2), imported)
+		require.Equal(bc.TipHeight(), bc2.TipHeight())
+		require.Equal(bc.TipHash(), bc2.TipHash())
+		for _, name := range []string{"producer", "alfa", "bravo", "charlie", "delta", "echo", "foxtrot"} {
+			b1, err := bc.Balance(ta.Addrinfo[name].RawAddress)
+			require.NoError(err)
+			b2, err := bc2.Balance(ta.Addrinfo[name].RawAddress)
+			require.NoError(err)
+			require.Equal(b1, b2)
+		}
+		require.NoError(bc2.Stop(context.Background()))
+	}
+
+	// truncated archive
+	require.NoError(ExportBlocks(bc, &buf, 1, 4, false))
+	data := buf.Bytes()
+	bc3 := newChain()
+	defer func() {
+		require.NoError(bc3.Stop(context.Background()))
+	}()
+	imported, err := ImportBlocks(bc3, bytes.NewReader(data[:len(data)-1]))
+	require.Equal(ErrInvalidArchive, errors.Cause(err))
+	require.Equal(uint64(3), imported)
+
+	// resume the import
+	imported, err = ImportBlocks(bc3, bytes.NewReader(data))
+	require.NoError(err)
+	require.Equal(uint64(1), imported)
+	require.Equal(bc.TipHash(), bc3.TipHash())
+}
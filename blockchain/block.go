@@ -26,6 +26,9 @@ import (
 	"github.com/iotexproject/iotex-core/proto"
 )
 
+// ErrActionNotInBlock indicates the action is not included in the block
+var ErrActionNotInBlock = errors.New("action is not in the block")
+
 // Payee defines the struct of payee
 type Payee struct {
 	Address string
@@ -61,6 +64,10 @@ type Block struct {
 	Transfers  []*action.Transfer
 	Votes      []*action.Vote
 	Executions []*action.Execution
+	// Evidences are the evidences of the delegates' equivocations, which are included by the block producer
+	Evidences []*action.Evidence
+	// CommitCert is the commit certificate of the block, which is not covered by the block hash
+	CommitCert *CommitCert
 	receipts   map[hash.Hash32B]*Receipt
 }
 
@@ -150,6 +157,9 @@ func (b *Block) ByteStream() []byte {
 	for _, e := range b.Executions {
 		stream = append(stream, e.ByteStream()...)
 	}
+	for _, e := range b.Evidences {
+		stream = append(stream, e.ByteStream()...)
+	}
 	return stream
 }
 
@@ -185,7 +195,14 @@ func (b *Block) ConvertToBlockPb() *iproto.BlockPb {
 	for _, execution := range b.Executions {
 		actions = append(actions, execution.ConvertToActionPb())
 	}
-	return &iproto.BlockPb{Header: b.ConvertToBlockHeaderPb(), Actions: actions}
+	for _, evidence := range b.Evidences {
+		actions = append(actions, evidence.ConvertToActionPb())
+	}
+	pbBlock := &iproto.BlockPb{Header: b.ConvertToBlockHeaderPb(), Actions: actions}
+	if b.CommitCert != nil {
+		pbBlock.CommitCert = b.CommitCert.ConvertToCommitCertPb()
+	}
+	return pbBlock
 }
 
 // Serialize returns the serialized byte stream of the block
@@ -219,6 +236,7 @@ func (b *Block) ConvertFromBlockPb(pbBlock *iproto.BlockPb) {
 	b.Transfers = []*action.Transfer{}
 	b.Votes = []*action.Vote{}
 	b.Executions = []*action.Execution{}
+	b.Evidences = nil
 
 	for _, act := range pbBlock.Actions {
 		if tfPb := act.GetTransfer(); tfPb != nil {
@@ -233,10 +251,20 @@ func (b *Block) ConvertFromBlockPb(pbBlock *iproto.BlockPb) {
 			execution := &action.Execution{}
 			execution.ConvertFromActionPb(act)
 			b.Executions = append(b.Executions, execution)
+		} else if evidencePb := act.GetEvidence(); evidencePb != nil {
+			evidence := &action.Evidence{}
+			evidence.ConvertFromActionPb(act)
+			b.Evidences = append(b.Evidences, evidence)
 		} else {
 			logger.Fatal().Msg("unexpected action")
 		}
 	}
+
+	b.CommitCert = nil
+	if pbCert := pbBlock.GetCommitCert(); pbCert != nil {
+		b.CommitCert = &CommitCert{}
+		b.CommitCert.ConvertFromCommitCertPb(pbCert)
+	}
 }
 
 // Deserialize parses the byte stream into a Block
@@ -258,6 +286,52 @@ func (b *Block) Deserialize(buf []byte) error {
 
 // TxRoot returns the Merkle root of all txs and actions in this block.
 func (b *Block) TxRoot() hash.Hash32B {
+	h := b.actionHashes()
+	if len(h) == 0 {
+		return hash.ZeroHash32B
+	}
+	return crypto.NewMerkleTree(h).HashTree()
+}
+
+// NumActions returns the number of actions in the block, which is the number of leaves of the Merkle tree of TxRoot
+func (b *Block) NumActions() int {
+	return len(b.Transfers) + len(b.Votes) + len(b.Executions) + len(b.Evidences)
+}
+
+// TxMerklePath returns the index of the action in the Merkle tree of TxRoot and its audit path, which can be
+// verified by crypto.VerifyAuditPath with NumActions()
+func (b *Block) TxMerklePath(actHash hash.Hash32B) (int, []hash.Hash32B, error) {
+	h := b.actionHashes()
+	for i, ah := range h {
+		if ah != actHash {
+			continue
+		}
+		path, err := crypto.NewMerkleTree(h).AuditPath(i)
+		if err != nil {
+			return 0, nil, err
+		}
+		return i, path, nil
+	}
+	return 0, nil, ErrActionNotInBlock
+}
+
+// ReceiptRoot returns the Merkle root of the receipts of the actions in this block, in the same order as TxRoot. The
+// receipts are generated by executing the block, and an action without receipt is not included
+func (b *Block) ReceiptRoot() hash.Hash32B {
+	var h []hash.Hash32B
+	for _, ah := range b.actionHashes() {
+		if receipt, ok := b.receipts[ah]; ok {
+			h = append(h, blake2b.Sum256(receipt.ByteStream()))
+		}
+	}
+	if len(h) == 0 {
+		return hash.ZeroHash32B
+	}
+	return crypto.NewMerkleTree(h).HashTree()
+}
+
+// actionHashes returns the hashes of transfers, votes, executions and evidences, which are the leaves of TxRoot
+func (b *Block) actionHashes() []hash.Hash32B {
 	var h []hash.Hash32B
 	for _, t := range b.Transfers {
 		h = append(h, t.Hash())
@@ -268,10 +342,10 @@ func (b *Block) TxRoot() hash.Hash32B {
 	for _, e := range b.Executions {
 		h = append(h, e.Hash())
 	}
-	if len(h) == 0 {
-		return hash.ZeroHash32B
+	for _, e := range b.Evidences {
+		h = append(h, e.Hash())
 	}
-	return crypto.NewMerkleTree(h).HashTree()
+	return h
 }
 
 // HashBlock return the hash of this block (actually hash of block header)
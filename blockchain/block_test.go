@@ -130,6 +130,21 @@ func TestMerkle(t *testing.T) {
 	require.Equal(hash07[:], hash[:])
 
 	t.Log("Merkle root match pass\n")
+
+	// verify merkle path of the 5th tx
+	index, path, err := block.TxMerklePath(cbtsf4.Hash())
+	require.NoError(err)
+	require.Equal(4, index)
+	require.Equal(3, len(path))
+	require.Equal(hash4, path[0][:])
+	require.Equal(hash45[:], path[1][:])
+	require.Equal(hash03[:], path[2][:])
+	require.Equal(5, block.NumActions())
+	require.True(crypto.VerifyAuditPath(hash, cbtsf4.Hash(), index, block.NumActions(), path))
+	// the copy of the 5th tx padding the tree is not in the block
+	require.False(crypto.VerifyAuditPath(hash, cbtsf4.Hash(), index+1, block.NumActions(), path))
+	_, _, err = block.TxMerklePath(hash01)
+	require.Equal(ErrActionNotInBlock, errors.Cause(err))
 }
 
 func TestConvertFromBlockPb(t *testing.T) {
@@ -190,7 +205,7 @@ func TestConvertFromBlockPb(t *testing.T) {
 
 func TestWrongRootHash(t *testing.T) {
 	require := require.New(t)
-	val := validator{nil}
+	val := validator{}
 	tsf1, err := action.NewTransfer(1, big.NewInt(20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	require.NoError(err)
 	tsf1, err = tsf1.Sign(ta.Addrinfo["producer"])
@@ -211,7 +226,7 @@ func TestWrongRootHash(t *testing.T) {
 
 func TestSignBlock(t *testing.T) {
 	require := require.New(t)
-	val := validator{nil}
+	val := validator{}
 	tsf1, err := action.NewTransfer(1, big.NewInt(20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	require.NoError(err)
 	tsf1, err = tsf1.Sign(ta.Addrinfo["producer"])
@@ -239,8 +254,8 @@ func TestWrongNonce(t *testing.T) {
 	require.NoError(sf.Start(context.Background()))
 	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
 	assert.NoError(t, err)
-	val := validator{sf}
-	require.Nil(sf.CommitStateChanges(0, nil, nil, nil))
+	val := validator{sf: sf, genesis: Gen, upgrades: cfg.Chain.Upgrades}
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
 
 	// correct nonce
 	coinbaseTsf := action.NewCoinBaseTransfer(big.NewInt(int64(Gen.BlockReward)), ta.Addrinfo["producer"].RawAddress)
@@ -253,7 +268,7 @@ func TestWrongNonce(t *testing.T) {
 	err = blk.SignBlock(ta.Addrinfo["producer"])
 	require.NoError(err)
 	require.Nil(val.Validate(blk, 2, hash))
-	require.NoError(sf.CommitStateChanges(1, []*action.Transfer{tsf1}, nil, nil))
+	require.NoError(sf.CommitStateChanges(1, []*action.Transfer{tsf1}, nil, nil, nil))
 
 	// low nonce
 	tsf2, err := action.NewTransfer(1, big.NewInt(30), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["bravo"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
@@ -346,6 +361,88 @@ func TestWrongNonce(t *testing.T) {
 	require.Equal(ErrActionNonce, errors.Cause(err))
 }
 
+func TestWrongChainID(t *testing.T) {
+	cfg := &config.Default
+	testutil.CleanupPath(t, cfg.Chain.TrieDBPath)
+	defer testutil.CleanupPath(t, cfg.Chain.TrieDBPath)
+	testutil.CleanupPath(t, cfg.Chain.ChainDBPath)
+	defer testutil.CleanupPath(t, cfg.Chain.ChainDBPath)
+	require := require.New(t)
+	sf, err := state.NewFactory(cfg, state.DefaultTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	val := validator{sf: sf, genesis: Gen}
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+
+	// signed with and without the chain ID before the chain ID is required
+	coinbaseTsf := action.NewCoinBaseTransfer(big.NewInt(int64(Gen.BlockReward)), ta.Addrinfo["producer"].RawAddress)
+	tsf1, err := action.NewTransfer(1, big.NewInt(20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	tsf1.ChainID = Gen.ChainID
+	tsf1, err = tsf1.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	vote, err := action.NewVote(2, ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["producer"].RawAddress, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	vote, err = vote.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	hash := tsf1.Hash()
+	blk := NewBlock(1, 3, hash, clock.New(), []*action.Transfer{coinbaseTsf, tsf1}, []*action.Vote{vote}, nil)
+	require.NoError(blk.SignBlock(ta.Addrinfo["producer"]))
+	val.upgrades.ChainIDHeight = 4
+	require.NoError(val.Validate(blk, 2, hash))
+
+	// signed without the chain ID once the chain ID is required
+	val.upgrades.ChainIDHeight = 3
+	err = val.Validate(blk, 2, hash)
+	require.Equal(ErrChainID, errors.Cause(err))
+
+	// signed for another chain
+	tsf2, err := action.NewTransfer(1, big.NewInt(20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
+	require.NoError(err)
+	tsf2.ChainID = Gen.ChainID + 1
+	tsf2, err = tsf2.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	blk = NewBlock(1, 3, hash, clock.New(), []*action.Transfer{coinbaseTsf, tsf2}, nil, nil)
+	require.NoError(blk.SignBlock(ta.Addrinfo["producer"]))
+	err = val.Validate(blk, 2, hash)
+	require.Equal(ErrChainID, errors.Cause(err))
+}
+
+func TestLowGasPrice(t *testing.T) {
+	cfg := &config.Default
+	testutil.CleanupPath(t, cfg.Chain.TrieDBPath)
+	defer testutil.CleanupPath(t, cfg.Chain.TrieDBPath)
+	testutil.CleanupPath(t, cfg.Chain.ChainDBPath)
+	defer testutil.CleanupPath(t, cfg.Chain.ChainDBPath)
+	require := require.New(t)
+	sf, err := state.NewFactory(cfg, state.DefaultTrieOption())
+	require.NoError(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	val := validator{sf: sf, genesis: Gen, upgrades: cfg.Chain.Upgrades}
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+
+	// the action paying no gas is valid before the gas fee upgrade
+	coinbaseTsf := action.NewCoinBaseTransfer(big.NewInt(int64(Gen.BlockReward)), ta.Addrinfo["producer"].RawAddress)
+	vote, err := action.NewVote(1, ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["producer"].RawAddress, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	vote, err = vote.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	hash := vote.Hash()
+	blk := NewBlock(1, 3, hash, clock.New(), []*action.Transfer{coinbaseTsf}, []*action.Vote{vote}, nil)
+	require.NoError(blk.SignBlock(ta.Addrinfo["producer"]))
+	val.upgrades.GasFeeHeight = 4
+	require.NoError(val.Validate(blk, 2, hash))
+
+	// but pays lower than the minimum gas price from the upgrade
+	val.upgrades.GasFeeHeight = 3
+	err = val.Validate(blk, 2, hash)
+	require.Equal(ErrGasPrice, errors.Cause(err))
+}
+
 func TestWrongCoinbaseTsf(t *testing.T) {
 	cfg := &config.Default
 	testutil.CleanupPath(t, cfg.Chain.TrieDBPath)
@@ -358,8 +455,8 @@ func TestWrongCoinbaseTsf(t *testing.T) {
 	require.NoError(sf.Start(context.Background()))
 	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
 	assert.NoError(t, err)
-	val := validator{sf}
-	require.Nil(sf.CommitStateChanges(0, nil, nil, nil))
+	val := validator{sf: sf, genesis: Gen, upgrades: cfg.Chain.Upgrades}
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
 
 	// no coinbase tsf
 	coinbaseTsf := action.NewCoinBaseTransfer(big.NewInt(int64(Gen.BlockReward)), ta.Addrinfo["producer"].RawAddress)
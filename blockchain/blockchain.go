@@ -38,7 +38,13 @@ type Blockchain interface {
 	// CreateState adds a new State with initial balance to the factory
 	CreateState(addr string, init uint64) (*state.State, error)
 	// CommitStateChanges updates a State from the given actions
-	CommitStateChanges(chainHeight uint64, tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution) error
+	CommitStateChanges(
+		chainHeight uint64,
+		tsf []*action.Transfer,
+		vote []*action.Vote,
+		executions []*action.Execution,
+		evidences []*action.Evidence,
+	) error
 	// Candidates returns the candidate list
 	Candidates() (uint64, []*state.Candidate)
 	// CandidatesByHeight returns the candidate list by a given height
@@ -84,6 +90,12 @@ type Blockchain interface {
 	GetBlockHashByExecutionHash(h hash.Hash32B) (hash.Hash32B, error)
 	// GetReceiptByExecutionHash returns the receipt by execution hash
 	GetReceiptByExecutionHash(h hash.Hash32B) (*Receipt, error)
+	// GetReceiptByActionHash returns the receipt by the hash of a transfer, vote or execution
+	GetReceiptByActionHash(h hash.Hash32B) (*Receipt, error)
+	// GetLogs returns the logs matching the filter, skipping the first offset logs on the from height and returning at
+	// most limit logs. To resume after the last log returned, set the from height to its height and the offset to the
+	// number of the logs on that height returned so far
+	GetLogs(filter *LogFilter, offset uint64, limit uint64) ([]*Log, error)
 	// GetFactory returns the State Factory
 	GetFactory() state.Factory
 	// TipHash returns tip block's hash
@@ -92,11 +104,24 @@ type Blockchain interface {
 	TipHeight() uint64
 	// StateByAddr returns state of a given address
 	StateByAddr(address string) (*state.State, error)
+	// StateByAddrAtHeight returns state of a given address at a given height
+	StateByAddrAtHeight(address string, height uint64) (*state.State, error)
+	// Upgrades returns the heights the protocol upgrades are activated on
+	Upgrades() config.Upgrades
+	// Genesis returns the genesis of the chain
+	Genesis() *Genesis
 
 	// For block operations
 	// MintNewBlock creates a new block with given actions
 	// Note: the coinbase transfer will be added to the given transfers when minting a new block
-	MintNewBlock(tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution, address *iotxaddress.Address, data string) (*Block, error)
+	MintNewBlock(
+		tsf []*action.Transfer,
+		vote []*action.Vote,
+		executions []*action.Execution,
+		evidences []*action.Evidence,
+		address *iotxaddress.Address,
+		data string,
+	) (*Block, error)
 	// TODO: Merge the MintNewDKGBlock into MintNewBlock
 	// MintNewDKGBlock creates a new block with given actions and dkg keys
 	MintNewDKGBlock(tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution,
@@ -107,6 +132,8 @@ type Blockchain interface {
 	CommitBlock(blk *Block) error
 	// ValidateBlock validates a new block before adding it to the blockchain
 	ValidateBlock(blk *Block) error
+	// ValidateProposal validates a block proposed in consensus, which does not carry its commit certificate yet
+	ValidateProposal(blk *Block) error
 
 	// For action operations
 	// Validator returns the current validator object
@@ -118,6 +145,20 @@ type Blockchain interface {
 	// ExecuteContractRead runs a read-only smart contract operation, this is done off the network since it does not
 	// cause any state change
 	ExecuteContractRead(*action.Execution) ([]byte, error)
+	// ExecuteContractReadAtHeight runs a read-only smart contract operation on the states at a given height
+	ExecuteContractReadAtHeight(*action.Execution, uint64) ([]byte, error)
+	// EstimateGasForExecution returns the minimal gas limit with which the execution succeeds on the states of the tip,
+	// or the reason why it never succeeds
+	EstimateGasForExecution(*action.Execution) (uint64, string, error)
+	// TraceExecution replays a committed execution on the states of its parent block, and returns the trace of the EVM
+	TraceExecution(h hash.Hash32B) (*ExecutionTrace, error)
+
+	// For subscription operations
+	// SubscribeBlockCreation sends every block committed to the chain to the channel. A block is dropped for the
+	// channel if it is full, so that a slow subscriber never stalls committing blocks
+	SubscribeBlockCreation(ch chan *Block) error
+	// UnsubscribeBlockCreation stops sending the committed blocks to the channel
+	UnsubscribeBlockCreation(ch chan *Block) error
 }
 
 // blockchain implements the Blockchain interface
@@ -135,6 +176,9 @@ type blockchain struct {
 
 	// used by account-based model
 	sf state.Factory
+
+	listenerMu     sync.RWMutex // mutex to protect blockListeners
+	blockListeners []chan *Block
 }
 
 // Option sets blockchain construction parameter
@@ -219,10 +263,16 @@ func ClockOption(clk clock.Clock) Option {
 
 // NewBlockchain creates a new blockchain and DB instance
 func NewBlockchain(cfg *config.Config, opts ...Option) Blockchain {
+	genesis, err := NewGenesis(cfg)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to load genesis")
+		return nil
+	}
 	// create the Blockchain
 	chain := &blockchain{
 		config:  cfg,
-		genesis: Gen,
+		genesis: genesis,
+		chainID: genesis.ChainID,
 		clk:     clock.New(),
 	}
 	for _, opt := range opts {
@@ -241,7 +291,19 @@ func NewBlockchain(cfg *config.Config, opts ...Option) Blockchain {
 	return chain
 }
 
-func (bc *blockchain) initValidator() { bc.validator = &validator{sf: bc.sf} }
+func (bc *blockchain) initValidator() {
+	// the commit certificates are verified against the delegates of the chain whatever the consensus scheme of the node
+	// is, so that the full nodes syncing the blocks verify them as well
+	consensus := bc.config.Consensus
+	bc.genesis.OverrideConsensus(&consensus)
+	bc.validator = &validator{
+		sf:       bc.sf,
+		bc:       bc,
+		genesis:  bc.genesis,
+		upgrades: bc.config.Chain.Upgrades,
+		rollDPoS: &consensus.RollDPoS,
+	}
+}
 
 // Start starts the blockchain
 func (bc *blockchain) Start(ctx context.Context) (err error) {
@@ -255,6 +317,9 @@ func (bc *blockchain) Start(ctx context.Context) (err error) {
 		return err
 	}
 	if bc.tipHeight == 0 {
+		if len(bc.config.Chain.SnapshotPath) > 0 {
+			return bc.startFromSnapshot(bc.config.Chain.SnapshotPath)
+		}
 		return bc.startEmptyBlockchain()
 	}
 	// get blockchain tip hash
@@ -266,18 +331,18 @@ func (bc *blockchain) Start(ctx context.Context) (err error) {
 }
 
 func (bc *blockchain) startEmptyBlockchain() error {
-	genesis := NewGenesisBlock(bc.config)
-	if genesis == nil {
-		return errors.New("cannot create genesis block")
+	genesis, err := bc.genesis.Block()
+	if err != nil {
+		return errors.Wrap(err, "cannot create genesis block")
 	}
 	// Genesis block has height 0
 	if genesis.Header.height != 0 {
 		return errors.New(fmt.Sprintf("genesis block has height %d but expects 0", genesis.Height()))
 	}
-	// add producer into Trie
+	// add creator, initial balances and contracts into Trie
 	if bc.sf != nil {
-		if _, err := bc.sf.LoadOrCreateState(Gen.CreatorAddr, Gen.TotalSupply); err != nil {
-			return errors.Wrap(err, "failed to add Creator into StateFactory")
+		if err := bc.genesis.createState(bc.sf); err != nil {
+			return errors.Wrap(err, "failed to add genesis state into StateFactory")
 		}
 	}
 	// add Genesis block as very first block
@@ -299,9 +364,9 @@ func (bc *blockchain) startExistingBlockchain(recoveryHeight uint64) error {
 		}
 		startHeight = factoryHeight + 1
 	}
-	// If restarting factory from fresh db, first create creator's state
+	// If restarting factory from fresh db, first create genesis state
 	if startHeight == 0 {
-		if _, err := bc.sf.LoadOrCreateState(Gen.CreatorAddr, Gen.TotalSupply); err != nil {
+		if err := bc.genesis.createState(bc.sf); err != nil {
 			return err
 		}
 	}
@@ -351,8 +416,14 @@ func (bc *blockchain) CreateState(addr string, init uint64) (*state.State, error
 }
 
 // CommitStateChanges updates a State from the given actions
-func (bc *blockchain) CommitStateChanges(blockHeight uint64, tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution) error {
-	return bc.sf.CommitStateChanges(blockHeight, tsf, vote, executions)
+func (bc *blockchain) CommitStateChanges(
+	blockHeight uint64,
+	tsf []*action.Transfer,
+	vote []*action.Vote,
+	executions []*action.Execution,
+	evidences []*action.Evidence,
+) error {
+	return bc.sf.CommitStateChanges(blockHeight, tsf, vote, executions, evidences)
 }
 
 // Candidates returns the candidate list
@@ -553,7 +624,27 @@ func (bc *blockchain) GetReceiptByExecutionHash(h hash.Hash32B) (*Receipt, error
 	if !bc.config.Explorer.Enabled {
 		return nil, errors.New("explorer not enabled")
 	}
-	return bc.dao.getReceiptByExecutionHash(h)
+	return bc.dao.getReceiptByActionHash(h)
+}
+
+// GetReceiptByActionHash returns the receipt by the hash of a transfer, vote or execution
+func (bc *blockchain) GetReceiptByActionHash(h hash.Hash32B) (*Receipt, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, errors.New("explorer not enabled")
+	}
+	return bc.dao.getReceiptByActionHash(h)
+}
+
+// GetLogs returns the logs matching the filter, skipping the first offset logs on the from height and returning at most
+// limit logs
+func (bc *blockchain) GetLogs(filter *LogFilter, offset uint64, limit uint64) ([]*Log, error) {
+	if !bc.config.Explorer.Enabled {
+		return nil, errors.New("explorer not enabled")
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return bc.dao.getLogs(filter, offset, limit)
 }
 
 // GetFactory returns the State Factory
@@ -579,20 +670,32 @@ func (bc *blockchain) TipHeight() uint64 {
 func (bc *blockchain) ValidateBlock(blk *Block) error {
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
-	return bc.validateBlock(blk)
+	return bc.validateBlock(blk, true)
+}
+
+// ValidateProposal validates a block proposed in consensus, which does not carry its commit certificate yet
+func (bc *blockchain) ValidateProposal(blk *Block) error {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.validateBlock(blk, false)
 }
 
 // MintNewBlock creates a new block with given actions
 // Note: the coinbase transfer will be added to the given transfers
 // when minting a new block
 func (bc *blockchain) MintNewBlock(tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution,
-	producer *iotxaddress.Address, data string) (*Block, error) {
+	evidences []*action.Evidence, producer *iotxaddress.Address, data string) (*Block, error) {
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
 
-	tsf = append(tsf, action.NewCoinBaseTransfer(big.NewInt(int64(bc.genesis.BlockReward)), producer.RawAddress))
+	reward := new(big.Int).SetUint64(bc.genesis.BlockRewardAt(bc.tipHeight + 1))
+	tsf = append(tsf, action.NewCoinBaseTransfer(reward, producer.RawAddress))
 
 	blk := NewBlock(bc.chainID, bc.tipHeight+1, bc.tipHash, bc.clk, tsf, vote, executions)
+	if len(evidences) > 0 {
+		blk.Evidences = evidences
+		blk.Header.txRoot = blk.TxRoot()
+	}
 	if producer.PrivateKey == keypair.ZeroPrivateKey {
 		logger.Warn().Msg("Unsigned block...")
 		return blk, nil
@@ -603,6 +706,9 @@ func (bc *blockchain) MintNewBlock(tsf []*action.Transfer, vote []*action.Vote,
 	blk.Header.DKGBlockSig = []byte{}
 
 	blk.Header.Pubkey = producer.PublicKey
+	if err := bc.fillRoots(blk); err != nil {
+		return nil, err
+	}
 	blkHash := blk.HashBlock()
 	blk.Header.blockSig = crypto.EC283.Sign(producer.PrivateKey, blkHash[:])
 	return blk, nil
@@ -616,7 +722,8 @@ func (bc *blockchain) MintNewDKGBlock(tsf []*action.Transfer, vote []*action.Vot
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
 
-	tsf = append(tsf, action.NewCoinBaseTransfer(big.NewInt(int64(bc.genesis.BlockReward)), producer.RawAddress))
+	reward := new(big.Int).SetUint64(bc.genesis.BlockRewardAt(bc.tipHeight + 1))
+	tsf = append(tsf, action.NewCoinBaseTransfer(reward, producer.RawAddress))
 
 	blk := NewBlock(bc.chainID, bc.tipHeight+1, bc.tipHash, bc.clk, tsf, vote, executions)
 	if producer.PrivateKey == keypair.ZeroPrivateKey {
@@ -637,6 +744,9 @@ func (bc *blockchain) MintNewDKGBlock(tsf []*action.Transfer, vote []*action.Vot
 	}
 
 	blk.Header.Pubkey = producer.PublicKey
+	if err := bc.fillRoots(blk); err != nil {
+		return nil, err
+	}
 	blkHash := blk.HashBlock()
 	blk.Header.blockSig = crypto.EC283.Sign(producer.PrivateKey, blkHash[:])
 	return blk, nil
@@ -658,7 +768,7 @@ func (bc *blockchain) CommitBlock(blk *Block) error {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 	// TODO: we should completely remove validation from committing a block
-	if err := bc.validateBlock(blk); err != nil {
+	if err := bc.validateBlock(blk, true); err != nil {
 		return err
 	}
 	return bc.commitBlock(blk)
@@ -679,6 +789,28 @@ func (bc *blockchain) StateByAddr(address string) (*state.State, error) {
 	return nil, errors.New("state factory is nil")
 }
 
+// StateByAddrAtHeight returns the account of an address at a given height
+func (bc *blockchain) StateByAddrAtHeight(address string, height uint64) (*state.State, error) {
+	if bc.sf == nil {
+		return nil, errors.New("state factory is nil")
+	}
+	sf, err := bc.sf.ViewAtHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get states on height %d", height)
+	}
+	return sf.State(address)
+}
+
+// Upgrades returns the heights the protocol upgrades are activated on
+func (bc *blockchain) Upgrades() config.Upgrades {
+	return bc.config.Chain.Upgrades
+}
+
+// Genesis returns the genesis of the chain
+func (bc *blockchain) Genesis() *Genesis {
+	return bc.genesis
+}
+
 // SetValidator sets the current validator object
 func (bc *blockchain) SetValidator(val Validator) {
 	bc.validator = val
@@ -692,17 +824,26 @@ func (bc *blockchain) Validator() Validator {
 // ExecuteContractRead runs a read-only smart contract operation, this is done off the network since it does not
 // cause any state change
 func (bc *blockchain) ExecuteContractRead(ex *action.Execution) ([]byte, error) {
-	// use latest block as carrier to run the offline execution
+	return bc.ExecuteContractReadAtHeight(ex, bc.TipHeight())
+}
+
+// ExecuteContractReadAtHeight runs a read-only smart contract operation on the states at a given height. The
+// execution runs on a read-only view of the state factory, so none of its changes is written into the factory
+func (bc *blockchain) ExecuteContractReadAtHeight(ex *action.Execution, height uint64) ([]byte, error) {
+	// use the block on the given height as carrier to run the offline execution
 	// the block itself is not used
-	h := bc.TipHeight()
-	blk, err := bc.GetBlockByHeight(h)
+	blk, err := bc.GetBlockByHeight(height)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get block in ExecuteContractRead")
 	}
+	sf, err := bc.sf.ViewAtHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get states on height %d", height)
+	}
 	blk.Executions = nil
 	blk.Executions = []*action.Execution{ex}
 	blk.receipts = nil
-	ExecuteContracts(blk, bc)
+	executeContracts(blk, bc, sf)
 	// pull the results from receipt
 	exHash := ex.Hash()
 	receipt, ok := blk.receipts[exHash]
@@ -712,11 +853,123 @@ func (bc *blockchain) ExecuteContractRead(ex *action.Execution) ([]byte, error)
 	return receipt.ReturnValue, nil
 }
 
+// EstimateGasForExecution returns the minimal gas limit with which the execution succeeds on the states of the tip. The
+// gas limit is binary searched between the intrinsic gas and the max gas limit, which is the block gas limit or what
+// the balance of the executor affords. If the execution fails with the max gas limit, it never succeeds, and the reason
+// of the failure is returned instead. The execution is run on scratch copies of the states, so nothing is persisted
+func (bc *blockchain) EstimateGasForExecution(ex *action.Execution) (uint64, string, error) {
+	height := bc.TipHeight()
+	intrinsicGas, err := IntrinsicGas(ex.Data)
+	if err != nil {
+		return 0, "", err
+	}
+	hi := GasLimit
+	if ex.GasPrice != nil && ex.GasPrice.Sign() > 0 {
+		balance, err := bc.sf.Balance(ex.Executor)
+		if err != nil {
+			return 0, "", errors.Wrapf(err, "failed to get balance of executor %s", ex.Executor)
+		}
+		// the EVM only transfers the amount if the balance left after the gas deposit is greater than it
+		spendable := new(big.Int).Sub(balance, big.NewInt(1))
+		if ex.Amount != nil {
+			spendable.Sub(spendable, ex.Amount)
+		}
+		if spendable.Sign() < 0 {
+			spendable.SetUint64(0)
+		}
+		affordable := new(big.Int).Div(spendable, ex.GasPrice)
+		if affordable.IsUint64() && affordable.Uint64() < hi {
+			hi = affordable.Uint64()
+		}
+	}
+	receipt, err := bc.simulateExecution(ex, hi, height)
+	if receipt == nil || (receipt.Status == SuccessStatus && err != nil) {
+		return 0, "", err
+	}
+	if receipt.Status == FailureStatus {
+		return 0, err.Error(), nil
+	}
+	// the execution fails with lo and succeeds with hi
+	lo := intrinsicGas - 1
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		receipt, err := bc.simulateExecution(ex, mid, height)
+		if receipt == nil || (receipt.Status == SuccessStatus && err != nil) {
+			return 0, "", err
+		}
+		if receipt.Status == FailureStatus {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, "", nil
+}
+
+// TraceExecution replays a committed execution on a scratch copy of the states of its parent block, and returns the
+// trace of the EVM running it. The executions before it in the block are replayed without tracing first, since they
+// run before any transfer or vote of the block, and so the execution sees the same states as when it is committed
+func (bc *blockchain) TraceExecution(h hash.Hash32B) (*ExecutionTrace, error) {
+	blkHash, err := bc.GetBlockHashByExecutionHash(h)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := bc.GetBlockByHash(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	if blk.Height() == 0 {
+		return nil, errors.Errorf("execution %x in genesis block cannot be replayed", h)
+	}
+	sf, err := bc.sf.Fork(blk.Height() - 1)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fork states on height %d", blk.Height()-1)
+	}
+	gasLimit := GasLimit
+	for idx, execution := range blk.Executions {
+		if execution.Hash() != h {
+			executeContract(blk, idx, execution, bc, sf, &gasLimit, nil)
+			continue
+		}
+		tracer := newExecutionTracer()
+		receipt, err := executeContract(blk, idx, execution, bc, sf, &gasLimit, tracer)
+		if receipt == nil {
+			return nil, errors.Wrapf(err, "failed to replay execution %x", h)
+		}
+		trace := tracer.trace
+		trace.Receipt = receipt
+		if err != nil {
+			trace.Error = err.Error()
+		}
+		return trace, nil
+	}
+	return nil, errors.Errorf("execution %x is not found in block %x", h, blkHash)
+}
+
 //======================================
 // private functions
 //=====================================
 
-func (bc *blockchain) validateBlock(blk *Block) error {
+// simulateExecution runs a copy of the execution with the gas limit on a scratch copy of the states at the given height,
+// and returns its receipt along with the error failing it. The receipt is nil if the execution cannot be run at all
+func (bc *blockchain) simulateExecution(ex *action.Execution, gasLimit uint64, height uint64) (*Receipt, error) {
+	// run the execution in a carrier block on top of the given height, as it runs once minted into the next block
+	prevHash, err := bc.GetHashByHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get block hash on height %d", height)
+	}
+	blk := NewBlock(bc.chainID, height+1, prevHash, bc.clk, nil, nil, nil)
+	sf, err := bc.sf.Fork(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fork states on height %d", height)
+	}
+	execution := *ex
+	execution.GasLimit = gasLimit
+	blockGasLimit := GasLimit
+	return executeContract(blk, 0, &execution, bc, sf, &blockGasLimit, nil)
+}
+
+func (bc *blockchain) validateBlock(blk *Block, containCommitCert bool) error {
 	if bc.validator == nil {
 		logger.Panic().Msg("no block validator")
 	}
@@ -734,9 +987,15 @@ func (bc *blockchain) validateBlock(blk *Block) error {
 		if err != nil {
 			return errors.Wrapf(err, "Failed to get the last block when replacing the dummy block")
 		}
+		if !containCommitCert {
+			return bc.validator.ValidateProposal(blk, lastBlock.Height(), lastBlock.HashBlock())
+		}
 		return bc.validator.Validate(blk, lastBlock.Height(), lastBlock.HashBlock())
 	}
 
+	if !containCommitCert {
+		return bc.validator.ValidateProposal(blk, bc.tipHeight, bc.tipHash)
+	}
 	return bc.validator.Validate(blk, bc.tipHeight, bc.tipHash)
 }
 
@@ -754,6 +1013,65 @@ func (bc *blockchain) commitBlock(blk *Block) error {
 		logger.Panic().Err(err).Msgf("Failed to update state on height %d", blk.Height())
 	}
 	logger.Info().Uint64("height", blk.Header.height).Msg("commit a block")
+	bc.emitToSubscribers(blk)
+	return nil
+}
+
+// SubscribeBlockCreation sends every block committed to the chain to the channel
+func (bc *blockchain) SubscribeBlockCreation(ch chan *Block) error {
+	if ch == nil {
+		return errors.New("subscriber channel cannot be nil")
+	}
+	bc.listenerMu.Lock()
+	defer bc.listenerMu.Unlock()
+	for _, listener := range bc.blockListeners {
+		if listener == ch {
+			return errors.New("channel has already subscribed block creation")
+		}
+	}
+	bc.blockListeners = append(bc.blockListeners, ch)
+	return nil
+}
+
+// UnsubscribeBlockCreation stops sending the committed blocks to the channel
+func (bc *blockchain) UnsubscribeBlockCreation(ch chan *Block) error {
+	bc.listenerMu.Lock()
+	defer bc.listenerMu.Unlock()
+	for i, listener := range bc.blockListeners {
+		if listener == ch {
+			bc.blockListeners = append(bc.blockListeners[:i], bc.blockListeners[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("channel has not subscribed block creation")
+}
+
+// emitToSubscribers sends the block to the subscribers without blocking, and drops the block for the subscribers not
+// keeping up with the chain
+func (bc *blockchain) emitToSubscribers(blk *Block) {
+	bc.listenerMu.RLock()
+	defer bc.listenerMu.RUnlock()
+	for _, listener := range bc.blockListeners {
+		select {
+		case listener <- blk:
+		default:
+			logger.Warn().Uint64("height", blk.Height()).Msg("Drop the block for a slow subscriber")
+		}
+	}
+}
+
+// fillRoots executes the block on a scratch copy of the states on the tip height, and puts the resulting root hash of
+// the accountTrie and the receipt root into the block header
+func (bc *blockchain) fillRoots(blk *Block) error {
+	if bc.sf == nil {
+		return nil
+	}
+	stateRoot, receiptRoot, err := executeOnFork(blk, bc, bc.sf, bc.tipHeight)
+	if err != nil {
+		return errors.Wrapf(err, "failed to execute block on height %d", blk.Height())
+	}
+	blk.Header.stateRoot = stateRoot
+	blk.Header.receiptRoot = receiptRoot
 	return nil
 }
 
@@ -761,7 +1079,13 @@ func (bc *blockchain) updateState(blk *Block) error {
 	if bc.sf != nil {
 		// update state factory
 		ExecuteContracts(blk, bc)
-		if err := bc.sf.CommitStateChanges(blk.Height(), blk.Transfers, blk.Votes, blk.Executions); err != nil {
+		if err := bc.sf.CommitStateChanges(
+			blk.Height(),
+			blk.Transfers,
+			blk.Votes,
+			blk.Executions,
+			blk.Evidences,
+		); err != nil {
 			return errors.Wrapf(err, "failed to commit state changes on height %d", blk.Height())
 		}
 	}
@@ -771,3 +1095,23 @@ func (bc *blockchain) updateState(blk *Block) error {
 	}
 	return nil
 }
+
+// executeOnFork executes the block on a scratch copy of the states on the given height, which is discarded afterwards,
+// and returns the root hash of the accountTrie after committing the state changes and the receipt root of the block
+func executeOnFork(blk *Block, bc Blockchain, sf state.Factory, height uint64) (hash.Hash32B, hash.Hash32B, error) {
+	fork, err := sf.Fork(height)
+	if err != nil {
+		return hash.ZeroHash32B, hash.ZeroHash32B, errors.Wrapf(err, "failed to fork states on height %d", height)
+	}
+	executeContracts(blk, bc, fork)
+	if err := fork.CommitStateChanges(
+		blk.Height(),
+		blk.Transfers,
+		blk.Votes,
+		blk.Executions,
+		blk.Evidences,
+	); err != nil {
+		return hash.ZeroHash32B, hash.ZeroHash32B, errors.Wrapf(err, "failed to commit state changes on height %d", blk.Height())
+	}
+	return fork.RootHash(), blk.ReceiptRoot(), nil
+}
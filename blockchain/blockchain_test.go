@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -52,7 +53,7 @@ func addTestingTsfBlocks(bc Blockchain) error {
 	tsf6, _ := action.NewTransfer(6, big.NewInt(50<<20), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["foxtrot"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	tsf6, _ = tsf6.Sign(ta.Addrinfo["producer"])
 
-	blk, err := bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4, tsf5, tsf6}, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err := bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4, tsf5, tsf6}, nil, nil, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -72,7 +73,7 @@ func addTestingTsfBlocks(bc Blockchain) error {
 	tsf4, _ = tsf4.Sign(ta.Addrinfo["charlie"])
 	tsf5, _ = action.NewTransfer(5, big.NewInt(1), ta.Addrinfo["charlie"].RawAddress, ta.Addrinfo["producer"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	tsf5, _ = tsf5.Sign(ta.Addrinfo["charlie"])
-	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4, tsf5}, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4, tsf5}, nil, nil, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -90,7 +91,7 @@ func addTestingTsfBlocks(bc Blockchain) error {
 	tsf3, _ = tsf3.Sign(ta.Addrinfo["delta"])
 	tsf4, _ = action.NewTransfer(4, big.NewInt(1), ta.Addrinfo["delta"].RawAddress, ta.Addrinfo["producer"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	tsf4, _ = tsf4.Sign(ta.Addrinfo["delta"])
-	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4}, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4}, nil, nil, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -124,7 +125,7 @@ func addTestingTsfBlocks(bc Blockchain) error {
 		return err
 	}
 
-	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4, tsf5, tsf6}, []*action.Vote{vote1, vote2}, nil, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4, tsf5, tsf6}, []*action.Vote{vote1, vote2}, nil, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -569,7 +570,7 @@ func TestBlockchain_MintNewDummyBlock(t *testing.T) {
 	sf, err := state.NewFactory(cfg, state.DefaultTrieOption())
 	require.NoError(err)
 	require.NoError(sf.Start(context.Background()))
-	val := validator{sf}
+	val := validator{sf: sf, genesis: Gen, upgrades: cfg.Chain.Upgrades}
 
 	ctx := context.Background()
 	bc := NewBlockchain(cfg, InMemDaoOption(), InMemStateFactoryOption())
@@ -593,6 +594,45 @@ func TestBlockchain_MintNewDummyBlock(t *testing.T) {
 	)
 }
 
+func TestBlockchain_SubscribeBlockCreation(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	ctx := context.Background()
+	bc := NewBlockchain(&cfg, InMemDaoOption(), InMemStateFactoryOption())
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+
+	ch := make(chan *Block, 1)
+	require.NoError(bc.SubscribeBlockCreation(ch))
+	require.Error(bc.SubscribeBlockCreation(ch))
+	require.Error(bc.SubscribeBlockCreation(nil))
+
+	blk1, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc.CommitBlock(blk1))
+	// the channel is full, so the block is dropped for it without blocking the commit
+	blk2, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc.CommitBlock(blk2))
+	blk := <-ch
+	require.Equal(blk1.HashBlock(), blk.HashBlock())
+	select {
+	case <-ch:
+		require.Fail("block should have been dropped")
+	default:
+	}
+
+	require.NoError(bc.UnsubscribeBlockCreation(ch))
+	require.Error(bc.UnsubscribeBlockCreation(ch))
+	blk3, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc.CommitBlock(blk3))
+	require.Equal(0, len(ch))
+}
+
 func TestBlockchainInitialCandidate(t *testing.T) {
 	require := require.New(t)
 
@@ -651,7 +691,7 @@ func TestCoinbaseTransfer(t *testing.T) {
 	require.Equal(0, int(height))
 
 	transfers := []*action.Transfer{}
-	blk, err := bc.MintNewBlock(transfers, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err := bc.MintNewBlock(transfers, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.Nil(err)
 	s, err := bc.StateByAddr(ta.Addrinfo["producer"].RawAddress)
 	require.Nil(err)
@@ -668,6 +708,97 @@ func TestCoinbaseTransfer(t *testing.T) {
 	require.True(b.String() == strconv.Itoa(int(Gen.TotalSupply)+int(Gen.BlockReward)))
 }
 
+func TestZeroGasBeforeGasFee(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	cfg.Chain.Upgrades.GasFeeHeight = 2
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	defer func() {
+		require.NoError(bc.Stop(context.Background()))
+	}()
+	_, err := bc.CreateState(ta.Addrinfo["producer"].RawAddress, uint64(100))
+	require.NoError(err)
+
+	// the transfer paying no gas is committed before the gas fee upgrade, as the existing blocks are
+	tsf, err := action.NewTransfer(1, big.NewInt(10), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(0), big.NewInt(0))
+	require.NoError(err)
+	tsf, err = tsf.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	blk, err := bc.MintNewBlock([]*action.Transfer{tsf}, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc.CommitBlock(blk))
+	require.Equal(uint64(1), bc.TipHeight())
+	// and its receipt consumes no gas, as it is not charged any fee
+	receipt, err := bc.GetReceiptByActionHash(tsf.Hash())
+	require.NoError(err)
+	require.Equal(SuccessStatus, receipt.Status)
+	require.Equal(uint64(0), receipt.GasConsumed)
+
+	// but is rejected from the upgrade
+	tsf, err = action.NewTransfer(2, big.NewInt(10), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(0), big.NewInt(10))
+	require.NoError(err)
+	tsf, err = tsf.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	coinbaseTsf := action.NewCoinBaseTransfer(big.NewInt(int64(Gen.BlockReward)), ta.Addrinfo["producer"].RawAddress)
+	blk = NewBlock(Gen.ChainID, 2, bc.TipHash(), clock.New(), []*action.Transfer{tsf, coinbaseTsf}, nil, nil)
+	require.NoError(blk.SignBlock(ta.Addrinfo["producer"]))
+	require.Equal(ErrInsufficientGas, errors.Cause(bc.ValidateBlock(blk)))
+}
+
+func TestBlockRoots(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	cfg.Chain.Upgrades.StateRootHeight = 1
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.Nil(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	bc := NewBlockchain(&cfg, PrecreatedStateFactoryOption(sf), InMemDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	defer func() {
+		require.NoError(bc.Stop(context.Background()))
+	}()
+
+	tsf, err := action.NewTransfer(1, big.NewInt(10), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf, err = tsf.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	blk, err := bc.MintNewBlock([]*action.Transfer{tsf}, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	// minting the block does not change the states
+	root := sf.RootHash()
+	require.NotEqual(root, blk.Header.stateRoot)
+	require.NotEqual(hash.ZeroHash32B, blk.Header.receiptRoot)
+	require.Equal(blk.ReceiptRoot(), blk.Header.receiptRoot)
+
+	// the block is rejected if any of its roots differs from re-execution
+	header := *blk.Header
+	header.stateRoot = root
+	wrongBlk := &Block{Header: &header, Transfers: blk.Transfers}
+	require.NoError(wrongBlk.SignBlock(ta.Addrinfo["producer"]))
+	err = bc.ValidateBlock(wrongBlk)
+	require.Equal(ErrInvalidBlock, errors.Cause(err))
+	require.True(strings.Contains(err.Error(), "wrong state root"))
+	header = *blk.Header
+	header.receiptRoot = root
+	wrongBlk = &Block{Header: &header, Transfers: blk.Transfers}
+	require.NoError(wrongBlk.SignBlock(ta.Addrinfo["producer"]))
+	err = bc.ValidateBlock(wrongBlk)
+	require.Equal(ErrInvalidBlock, errors.Cause(err))
+	require.True(strings.Contains(err.Error(), "wrong receipt root"))
+	// the roots are not verified before the upgrade
+	val := validator{sf: sf, bc: bc.(*blockchain), genesis: Gen, upgrades: config.Default.Chain.Upgrades}
+	require.NoError(val.Validate(wrongBlk, 0, bc.TipHash()))
+
+	require.NoError(bc.ValidateBlock(blk))
+	require.NoError(bc.CommitBlock(blk))
+	require.Equal(blk.Header.stateRoot, sf.RootHash())
+}
+
 func TestBlockchain_StateByAddr(t *testing.T) {
 	require := require.New(t)
 
@@ -687,6 +818,60 @@ func TestBlockchain_StateByAddr(t *testing.T) {
 	require.Equal(map[string]*big.Int(map[string]*big.Int(nil)), s.Voters)
 }
 
+func TestBlockchain_StateByAddrAtHeight(t *testing.T) {
+	require := require.New(t)
+
+	testutil.CleanupPath(t, testTriePath)
+	defer testutil.CleanupPath(t, testTriePath)
+	testutil.CleanupPath(t, testDBPath)
+	defer testutil.CleanupPath(t, testDBPath)
+
+	cfg := config.Default
+	cfg.Chain.TrieDBPath = testTriePath
+	cfg.Chain.ChainDBPath = testDBPath
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+
+	sf, err := state.NewFactory(&cfg, state.DefaultTrieOption())
+	require.Nil(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	bc := NewBlockchain(&cfg, PrecreatedStateFactoryOption(sf), BoltDBDaoOption())
+	require.NoError(bc.Start(context.Background()))
+	require.NotNil(bc)
+	defer func() {
+		require.NoError(bc.Stop(context.Background()))
+	}()
+	require.NoError(addTestingTsfBlocks(bc))
+	require.Equal(uint64(4), bc.TipHeight())
+
+	// alfa does not exist before block 1
+	_, err = bc.StateByAddrAtHeight(ta.Addrinfo["alfa"].RawAddress, 0)
+	require.Equal(state.ErrAccountNotExist, errors.Cause(err))
+	s, err := bc.StateByAddrAtHeight(ta.Addrinfo["alfa"].RawAddress, 1)
+	require.NoError(err)
+	require.Equal(big.NewInt(20), s.Balance)
+	s, err = bc.StateByAddrAtHeight(ta.Addrinfo["producer"].RawAddress, 0)
+	require.NoError(err)
+	require.Equal(uint64(0), s.Nonce)
+	s, err = bc.StateByAddrAtHeight(ta.Addrinfo["producer"].RawAddress, 1)
+	require.NoError(err)
+	require.Equal(uint64(6), s.Nonce)
+
+	// the state on tip height is the same as the latest state
+	for _, name := range []string{"producer", "alfa", "bravo", "charlie"} {
+		s, err := bc.StateByAddr(ta.Addrinfo[name].RawAddress)
+		require.NoError(err)
+		sAtTip, err := bc.StateByAddrAtHeight(ta.Addrinfo[name].RawAddress, bc.TipHeight())
+		require.NoError(err)
+		require.Equal(s, sAtTip)
+	}
+
+	_, err = bc.StateByAddrAtHeight(ta.Addrinfo["alfa"].RawAddress, bc.TipHeight()+1)
+	require.Error(err)
+}
+
 func TestBlocks(t *testing.T) {
 	// This test is used for committing block verify benchmark purpose
 	t.Skip()
@@ -721,7 +906,7 @@ func TestBlocks(t *testing.T) {
 			tsf, _ = tsf.Sign(a)
 			tsfs = append(tsfs, tsf)
 		}
-		blk, _ := bc.MintNewBlock(tsfs, nil, nil, ta.Addrinfo["producer"], "")
+		blk, _ := bc.MintNewBlock(tsfs, nil, nil, nil, ta.Addrinfo["producer"], "")
 		err := bc.CommitBlock(blk)
 		require.Nil(err)
 	}
@@ -753,7 +938,7 @@ func TestActions(t *testing.T) {
 	sf.LoadOrCreateState(a.RawAddress, uint64(100000))
 	sf.LoadOrCreateState(c.RawAddress, uint64(100000))
 
-	val := validator{sf}
+	val := validator{sf: sf, genesis: Gen, upgrades: cfg.Chain.Upgrades}
 	tsfs := []*action.Transfer{}
 	votes := []*action.Vote{}
 	for i := 0; i < 5000; i++ {
@@ -767,7 +952,7 @@ func TestActions(t *testing.T) {
 		vote, _ = vote.Sign(a)
 		votes = append(votes, vote)
 	}
-	blk, _ := bc.MintNewBlock(tsfs, votes, nil, ta.Addrinfo["producer"], "")
+	blk, _ := bc.MintNewBlock(tsfs, votes, nil, nil, ta.Addrinfo["producer"], "")
 	require.Nil(val.Validate(blk, 0, blk.PrevHash()))
 }
 
@@ -791,7 +976,7 @@ func TestDummyReplacement(t *testing.T) {
 	bc := NewBlockchain(&cfg, PrecreatedStateFactoryOption(sf), BoltDBDaoOption())
 	require.NoError(bc.Start(context.Background()))
 	dummy := bc.MintNewDummyBlock()
-	realBlock, err := bc.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	realBlock, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(realBlock)
 	require.NoError(err)
 	err = bc.CommitBlock(dummy)
@@ -806,16 +991,16 @@ func TestDummyReplacement(t *testing.T) {
 	require.NoError(err)
 	require.Equal(realBlock.HashBlock(), actualRealBlock.HashBlock())
 
-	block2, err := bc.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	block2, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	err = bc.CommitBlock(block2)
 	require.NoError(err)
-	block3, err := bc.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	block3, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	dummyBlock3 := bc.MintNewDummyBlock()
 	require.NoError(err)
 	err = bc.CommitBlock(dummyBlock3)
 	require.NoError(err)
-	block4, err := bc.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	block4, err := bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	err = bc.CommitBlock(block4)
 	require.NoError(err)
@@ -8,7 +8,9 @@ package blockchain
 
 import (
 	"context"
+	"sort"
 
+	"github.com/boltdb/bolt"
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/config"
@@ -25,13 +27,16 @@ const (
 	blockTransferBlockMappingNS         = "transfer<->block"
 	blockVoteBlockMappingNS             = "vote<->block"
 	blockExecutionBlockMappingNS        = "execution<->block"
-	blockExecutionReceiptMappingNS      = "ex<->receipt"
+	blockActionReceiptMappingNS         = "ex<->receipt"
 	blockAddressTransferMappingNS       = "address<->transfer"
 	blockAddressTransferCountMappingNS  = "address<->transfercount"
 	blockAddressVoteMappingNS           = "address<->vote"
 	blockAddressVoteCountMappingNS      = "address<->votecount"
 	blockAddressExecutionMappingNS      = "address<->execution"
 	blockAddressExecutionCountMappingNS = "address<->executioncount"
+	blockLogBloomNS                     = "height<->logbloom"
+	blockLogIndexNS                     = "address/topic<->log"
+	blockLogIndexCountNS                = "address/topic<->logcount"
 )
 
 var (
@@ -52,6 +57,8 @@ var (
 	voteToPrefix        = []byte("vote-to.")
 	executionFromPrefix = []byte("execution-from")
 	executionToPrefix   = []byte("execution-to")
+	logAddressPrefix    = []byte("log-address.")
+	logTopicPrefix      = []byte("log-topic.")
 )
 
 var _ lifecycle.StartStopper = (*blockDAO)(nil)
@@ -482,11 +489,11 @@ func (dao *blockDAO) getTotalExecutions() (uint64, error) {
 	return enc.MachineEndian.Uint64(value), nil
 }
 
-// getReceiptByExecutionHash returns the receipt by execution hash
-func (dao *blockDAO) getReceiptByExecutionHash(h hash.Hash32B) (*Receipt, error) {
-	value, err := dao.kvstore.Get(blockExecutionReceiptMappingNS, h[:])
+// getReceiptByActionHash returns the receipt by action hash
+func (dao *blockDAO) getReceiptByActionHash(h hash.Hash32B) (*Receipt, error) {
+	value, err := dao.kvstore.Get(blockActionReceiptMappingNS, h[:])
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get receipt for execution %x", h[:])
+		return nil, errors.Wrapf(err, "failed to get receipt for action %x", h[:])
 	}
 	r := Receipt{}
 	if err := r.Deserialize(value); err != nil {
@@ -495,6 +502,161 @@ func (dao *blockDAO) getReceiptByExecutionHash(h hash.Hash32B) (*Receipt, error)
 	return &r, nil
 }
 
+// getLogs returns the logs matching the filter in the order of heights, actions in the block and logs in the receipt,
+// skipping the first offset logs on the from height and returning at most limit logs. The from height and offset are
+// the cursor to resume from, so a page never rescans the heights before it
+func (dao *blockDAO) getLogs(filter *LogFilter, offset uint64, limit uint64) ([]*Log, error) {
+	heights, err := dao.getLogHeights(filter)
+	if err != nil {
+		return nil, err
+	}
+	var logs []*Log
+	for _, height := range heights {
+		if uint64(len(logs)) >= limit {
+			break
+		}
+		value, err := dao.kvstore.Get(blockLogBloomNS, logIndexKey(heightPrefix, byteutil.Uint64ToBytes(height)))
+		switch errors.Cause(err) {
+		case nil:
+		case db.ErrNotExist, bolt.ErrBucketNotFound:
+			// the block has no log
+			continue
+		default:
+			return nil, errors.Wrapf(err, "failed to get log bloom on height %d", height)
+		}
+		if len(value) != LogBloomSize {
+			return nil, errors.Errorf("log bloom on height %d is broken", height)
+		}
+		var bloom LogBloom
+		copy(bloom[:], value)
+		if !filter.MatchBloom(&bloom) {
+			continue
+		}
+		blkHash, err := dao.getBlockHash(height)
+		if err != nil {
+			return nil, err
+		}
+		blk, err := dao.getBlock(blkHash)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range blk.actionHashes() {
+			receipt, err := dao.getReceiptByActionHash(h)
+			if err != nil {
+				return nil, err
+			}
+			for _, log := range receipt.Logs {
+				if !filter.Match(log) {
+					continue
+				}
+				if height == filter.FromHeight && offset > 0 {
+					offset--
+					continue
+				}
+				if uint64(len(logs)) < limit {
+					logs = append(logs, log)
+				}
+			}
+		}
+	}
+	return logs, nil
+}
+
+// getLogHeights returns the heights in the range of the filter, whose blocks may have the logs matching the filter. The
+// heights are looked up in the index of the contract addresses if the filter has any, otherwise in the index of the
+// topics on the first position having any. If neither is set, all the heights in the range are returned
+func (dao *blockDAO) getLogHeights(filter *LogFilter) ([]uint64, error) {
+	var keys [][]byte
+	for _, addr := range filter.Addresses {
+		keys = append(keys, logIndexKey(logAddressPrefix, []byte(addr)))
+	}
+	if len(keys) == 0 {
+		for _, topics := range filter.Topics {
+			for _, topic := range topics {
+				keys = append(keys, logIndexKey(logTopicPrefix, topic[:]))
+			}
+			if len(keys) > 0 {
+				break
+			}
+		}
+	}
+	var heights []uint64
+	if len(keys) == 0 {
+		tipHeight, err := dao.getBlockchainHeight()
+		if err != nil {
+			return nil, err
+		}
+		for height := filter.FromHeight; height <= filter.ToHeight && height <= tipHeight; height++ {
+			heights = append(heights, height)
+		}
+		return heights, nil
+	}
+
+	added := make(map[uint64]bool)
+	for _, key := range keys {
+		count, err := dao.getLogIndexCount(key)
+		if err != nil {
+			return nil, err
+		}
+		// the heights in the index are in ascending order, so search for the first one in the range
+		var searchErr error
+		start := sort.Search(int(count), func(i int) bool {
+			height, err := dao.getLogIndexHeight(key, uint64(i))
+			if err != nil {
+				searchErr = err
+				return true
+			}
+			return height >= filter.FromHeight
+		})
+		if searchErr != nil {
+			return nil, searchErr
+		}
+		for i := uint64(start); i < count; i++ {
+			height, err := dao.getLogIndexHeight(key, i)
+			if err != nil {
+				return nil, err
+			}
+			if height > filter.ToHeight {
+				break
+			}
+			if !added[height] {
+				added[height] = true
+				heights = append(heights, height)
+			}
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// getLogIndexCount returns the number of blocks in the index of a contract address or topic
+func (dao *blockDAO) getLogIndexCount(key []byte) (uint64, error) {
+	value, err := dao.kvstore.Get(blockLogIndexCountNS, key)
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, bolt.ErrBucketNotFound:
+		return 0, nil
+	default:
+		return 0, errors.Wrapf(err, "failed to get count of logs for %x", key)
+	}
+	if len(value) == 0 {
+		return 0, errors.Errorf("count of logs for %x is broken", key)
+	}
+	return enc.MachineEndian.Uint64(value), nil
+}
+
+// getLogIndexHeight returns the i-th height in the index of a contract address or topic
+func (dao *blockDAO) getLogIndexHeight(key []byte, i uint64) (uint64, error) {
+	value, err := dao.kvstore.Get(blockLogIndexNS, logIndexKey(key, byteutil.Uint64ToBytes(i)))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get height for index %d of logs for %x", i, key)
+	}
+	if len(value) == 0 {
+		return 0, errors.Wrapf(db.ErrNotExist, "height for index %d of logs for %x missing", i, key)
+	}
+	return enc.MachineEndian.Uint64(value), nil
+}
+
 // putBlock puts a block
 func (dao *blockDAO) putBlock(blk *Block) error {
 	batch := dao.kvstore.Batch()
@@ -782,11 +944,55 @@ func (dao *blockDAO) putReceipts(blk *Block) error {
 		if err != nil {
 			return errors.Wrapf(err, "failed to serialize receipt %x", r.Hash[:])
 		}
-		batch.Put(blockExecutionReceiptMappingNS, r.Hash[:], v[:], "failed to put receipt for execution %x", r.Hash[:])
+		batch.Put(blockActionReceiptMappingNS, r.Hash[:], v[:], "failed to put receipt for action %x", r.Hash[:])
+	}
+	// only build log index if enable explorer
+	if dao.config.Explorer.Enabled {
+		if err := putLogIndex(dao, blk, batch); err != nil {
+			return err
+		}
 	}
 	return batch.Commit()
 }
 
+// putLogIndex stores the log bloom of the block, and appends the height of the block to the index of every contract
+// address and topic of the logs in the block
+func putLogIndex(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
+	var receipts []*Receipt
+	for _, h := range blk.actionHashes() {
+		if receipt, ok := blk.receipts[h]; ok {
+			receipts = append(receipts, receipt)
+		}
+	}
+	keys := logIndexKeys(receipts)
+	if len(keys) == 0 {
+		return nil
+	}
+	height := byteutil.Uint64ToBytes(blk.Height())
+	heightKey := logIndexKey(heightPrefix, height)
+	// the receipts are put again when the states are rebuilt from the blocks, which must not index the block twice
+	switch _, err := dao.kvstore.Get(blockLogBloomNS, heightKey); errors.Cause(err) {
+	case nil:
+		return nil
+	case db.ErrNotExist, bolt.ErrBucketNotFound:
+	default:
+		return errors.Wrapf(err, "failed to get log bloom on height %d", blk.Height())
+	}
+	bloom := newLogBloom(receipts)
+	batch.Put(blockLogBloomNS, heightKey, bloom[:], "failed to put log bloom on height %d", blk.Height())
+	for _, key := range keys {
+		count, err := dao.getLogIndexCount(key)
+		if err != nil {
+			return err
+		}
+		batch.Put(blockLogIndexNS, logIndexKey(key, byteutil.Uint64ToBytes(count)), height,
+			"failed to put height %d into index of logs for %x", blk.Height(), key)
+		batch.Put(blockLogIndexCountNS, key, byteutil.Uint64ToBytes(count+1),
+			"failed to bump count of logs for %x", key)
+	}
+	return nil
+}
+
 // deleteBlock deletes the tip block
 func (dao *blockDAO) deleteTipBlock() error {
 	batch := dao.kvstore.Batch()
@@ -893,6 +1099,10 @@ func (dao *blockDAO) deleteTipBlock() error {
 		return err
 	}
 
+	if err = deleteLogIndex(dao, blk, batch); err != nil {
+		return err
+	}
+
 	if err = deleteReceipts(blk, batch); err != nil {
 		return err
 	}
@@ -1111,8 +1321,73 @@ func deleteExecutions(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
 
 // deleteReceipts deletes receipt information from db
 func deleteReceipts(blk *Block, batch db.KVStoreBatch) error {
-	for _, r := range blk.receipts {
-		batch.Delete(blockExecutionReceiptMappingNS, r.Hash[:], "failed to delete receipt for execution %x", r.Hash[:])
+	// the receipts are not loaded with the block, so they are deleted by the hashes of the actions
+	hashes := blk.actionHashes()
+	for i := range hashes {
+		batch.Delete(blockActionReceiptMappingNS, hashes[i][:], "failed to delete receipt for action %x", hashes[i])
 	}
 	return nil
 }
+
+// deleteLogIndex deletes the log bloom of the block, and removes the height of the block from the index of every
+// contract address and topic of the logs in the block
+func deleteLogIndex(dao *blockDAO, blk *Block, batch db.KVStoreBatch) error {
+	heightKey := logIndexKey(heightPrefix, byteutil.Uint64ToBytes(blk.Height()))
+	if _, err := dao.kvstore.Get(blockLogBloomNS, heightKey); err != nil {
+		// the block has no log
+		return nil
+	}
+	// the receipts are not loaded with the block, so they are read from db before being deleted
+	var receipts []*Receipt
+	for _, h := range blk.actionHashes() {
+		receipt, err := dao.getReceiptByActionHash(h)
+		if err != nil {
+			return err
+		}
+		receipts = append(receipts, receipt)
+	}
+	batch.Delete(blockLogBloomNS, heightKey, "failed to delete log bloom on height %d", blk.Height())
+	// the tip block is the last one in the index of each of its addresses and topics
+	for _, key := range logIndexKeys(receipts) {
+		count, err := dao.getLogIndexCount(key)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.Wrapf(db.ErrNotExist, "index of logs for %x missing", key)
+		}
+		batch.Delete(blockLogIndexNS, logIndexKey(key, byteutil.Uint64ToBytes(count-1)),
+			"failed to delete height %d from index of logs for %x", blk.Height(), key)
+		batch.Put(blockLogIndexCountNS, key, byteutil.Uint64ToBytes(count-1),
+			"failed to update count of logs for %x", key)
+	}
+	return nil
+}
+
+// logIndexKeys returns the distinct index keys of the contract addresses and topics of the logs in the receipts
+func logIndexKeys(receipts []*Receipt) [][]byte {
+	var keys [][]byte
+	added := make(map[string]bool)
+	add := func(key []byte) {
+		if !added[string(key)] {
+			added[string(key)] = true
+			keys = append(keys, key)
+		}
+	}
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			add(logIndexKey(logAddressPrefix, []byte(log.Address)))
+			for _, topic := range log.Topics {
+				add(logIndexKey(logTopicPrefix, topic[:]))
+			}
+		}
+	}
+	return keys
+}
+
+// logIndexKey returns a new key of the prefix followed by the suffix
+func logIndexKey(prefix []byte, suffix []byte) []byte {
+	key := make([]byte, 0, len(prefix)+len(suffix))
+	key = append(key, prefix...)
+	return append(key, suffix...)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/test/testaddress"
 	"github.com/iotexproject/iotex-core/testutil"
 )
@@ -505,6 +506,97 @@ func TestBlockDAO(t *testing.T) {
 		require.Equal(uint64(2), execToDeltaCount)
 	}
 
+	testLogIndexDao := func(kvstore db.KVStore, t *testing.T) {
+		require := require.New(t)
+
+		ctx := context.Background()
+		cfg := config.Default
+		cfg.Explorer.Enabled = true
+		dao := newBlockDAO(&cfg, kvstore)
+		require.NoError(dao.Start(ctx))
+		defer func() {
+			require.NoError(dao.Stop(ctx))
+		}()
+
+		contract := testaddress.Addrinfo["delta"].RawAddress
+		topic1 := byteutil.BytesTo32B(hash.Hash256b([]byte("topic1")))
+		topic2 := byteutil.BytesTo32B(hash.Hash256b([]byte("topic2")))
+		topics := [][]hash.Hash32B{{topic1, topic2}, {topic1}, {topic2}}
+		for i, blk := range blks {
+			blk.receipts = make(map[hash.Hash32B]*Receipt)
+			for _, h := range blk.actionHashes() {
+				blk.receipts[h] = &Receipt{Hash: h}
+			}
+			executionHash := blk.Executions[0].Hash()
+			blk.receipts[executionHash].Logs = []*Log{{
+				Address:     contract,
+				Topics:      topics[i],
+				BlockNumber: blk.Height(),
+				TxnHash:     executionHash,
+			}}
+			require.NoError(dao.putBlock(blk))
+			require.NoError(dao.putReceipts(blk))
+		}
+		// putting the receipts again does not index the block twice
+		require.NoError(dao.putReceipts(blks[2]))
+		count, err := dao.getLogIndexCount(logIndexKey(logTopicPrefix, topic2[:]))
+		require.NoError(err)
+		require.Equal(uint64(2), count)
+
+		logs, err := dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3, Addresses: []string{contract}}, 0, 10)
+		require.NoError(err)
+		require.Equal(3, len(logs))
+		for i, log := range logs {
+			require.Equal(blks[i].Height(), log.BlockNumber)
+			require.Equal(blks[i].Executions[0].Hash(), log.TxnHash)
+		}
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 2, ToHeight: 3, Addresses: []string{contract}}, 0, 10)
+		require.NoError(err)
+		require.Equal(2, len(logs))
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3, Topics: [][]hash.Hash32B{{topic1}}}, 0, 10)
+		require.NoError(err)
+		require.Equal(2, len(logs))
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3, Topics: [][]hash.Hash32B{{topic2}}}, 0, 10)
+		require.NoError(err)
+		require.Equal(1, len(logs))
+		require.Equal(uint64(3), logs[0].BlockNumber)
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3, Topics: [][]hash.Hash32B{nil, {topic2}}}, 0, 10)
+		require.NoError(err)
+		require.Equal(1, len(logs))
+		require.Equal(uint64(1), logs[0].BlockNumber)
+		// the offset only skips the logs on the from height
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 10}, 1, 1)
+		require.NoError(err)
+		require.Equal(1, len(logs))
+		require.Equal(uint64(1), logs[0].BlockNumber)
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 1, ToHeight: 10}, 1, 1)
+		require.NoError(err)
+		require.Equal(1, len(logs))
+		require.Equal(uint64(2), logs[0].BlockNumber)
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3, Addresses: []string{testaddress.Addrinfo["alfa"].RawAddress}}, 0, 10)
+		require.NoError(err)
+		require.Equal(0, len(logs))
+
+		// Delete tip block
+		require.NoError(dao.deleteTipBlock())
+		count, err = dao.getLogIndexCount(logIndexKey(logTopicPrefix, topic2[:]))
+		require.NoError(err)
+		require.Equal(uint64(1), count)
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3, Topics: [][]hash.Hash32B{{topic2}}}, 0, 10)
+		require.NoError(err)
+		require.Equal(0, len(logs))
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3}, 0, 10)
+		require.NoError(err)
+		require.Equal(2, len(logs))
+
+		// the tip block is indexed again after being put back
+		require.NoError(dao.putBlock(blks[2]))
+		require.NoError(dao.putReceipts(blks[2]))
+		logs, err = dao.getLogs(&LogFilter{FromHeight: 0, ToHeight: 3, Addresses: []string{contract}}, 0, 10)
+		require.NoError(err)
+		require.Equal(3, len(logs))
+	}
+
 	t.Run("In-memory KV Store for blocks", func(t *testing.T) {
 		testBlockDao(db.NewMemKVStore(), t)
 	})
@@ -536,4 +628,14 @@ func TestBlockDAO(t *testing.T) {
 		defer testutil.CleanupPath(t, path)
 		testDeleteDao(db.NewBoltDB(path, cfg), t)
 	})
+
+	t.Run("In-memory KV Store log index", func(t *testing.T) {
+		testLogIndexDao(db.NewMemKVStore(), t)
+	})
+
+	t.Run("Bolt DB log index", func(t *testing.T) {
+		testutil.CleanupPath(t, path)
+		defer testutil.CleanupPath(t, path)
+		testLogIndexDao(db.NewBoltDB(path, cfg), t)
+	})
 }
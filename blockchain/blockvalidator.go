@@ -8,6 +8,7 @@ package blockchain
 
 import (
 	"bytes"
+	"math/big"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -15,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/pkg/hash"
@@ -25,10 +27,22 @@ import (
 type Validator interface {
 	// Validate validates the given block's content
 	Validate(block *Block, tipHeight uint64, tipHash hash.Hash32B) error
+	// ValidateProposal validates the given proposed block's content, except the commit certificate which is only
+	// attached to the block once it is committed
+	ValidateProposal(block *Block, tipHeight uint64, tipHash hash.Hash32B) error
 }
 
 type validator struct {
 	sf state.Factory
+	// bc is used to re-execute the block when verifying its state root and receipt root, which is skipped if nil
+	bc Blockchain
+	// genesis is the genesis of the chain the block is validated for
+	genesis *Genesis
+	// upgrades are the heights the protocol upgrades are activated on
+	upgrades config.Upgrades
+	// rollDPoS is the delegate parameters of the chain, with which the commit certificates of the blocks are verified
+	// against the delegates of their epochs. The commit certificates are not verified if nil
+	rollDPoS *config.RollDPoS
 }
 
 var (
@@ -44,10 +58,44 @@ var (
 	ErrInsufficientGas = errors.New("insufficient intrinsic gas value")
 	// ErrBalance indicates the error of balance
 	ErrBalance = errors.New("invalid balance")
+	// ErrChainID indicates the error of an action signed for another chain
+	ErrChainID = errors.New("invalid chain ID")
+	// ErrGasPrice indicates the error of an action paying a gas price lower than the minimum
+	ErrGasPrice = errors.New("gas price is too low")
 )
 
+// VerifyChainID verifies an action signed with the chain ID is valid on the given height of the chain of the genesis.
+// Before the chain ID upgrade, the action may also be signed without the chain ID
+func VerifyChainID(chainID uint32, height uint64, genesis *Genesis, upgrades config.Upgrades) error {
+	if chainID == genesis.ChainID || (chainID == 0 && height < upgrades.ChainIDHeight) {
+		return nil
+	}
+	return errors.Wrapf(ErrChainID, "action with chain ID %d is invalid on height %d", chainID, height)
+}
+
+// VerifyGasPrice verifies the gas price of an action is not lower than the minimum gas price of the genesis on the
+// given height of the chain. Any gas price is valid before the gas fee upgrade
+func VerifyGasPrice(gasPrice *big.Int, height uint64, genesis *Genesis, upgrades config.Upgrades) error {
+	if height < upgrades.GasFeeHeight {
+		return nil
+	}
+	if gasPrice == nil || gasPrice.Cmp(new(big.Int).SetUint64(genesis.MinGasPrice)) < 0 {
+		return errors.Wrapf(ErrGasPrice, "gas price is lower than %d on height %d", genesis.MinGasPrice, height)
+	}
+	return nil
+}
+
 // Validate validates the given block's content
 func (v *validator) Validate(blk *Block, tipHeight uint64, tipHash hash.Hash32B) error {
+	return v.validate(blk, tipHeight, tipHash, true)
+}
+
+// ValidateProposal validates the given proposed block's content, except the commit certificate
+func (v *validator) ValidateProposal(blk *Block, tipHeight uint64, tipHash hash.Hash32B) error {
+	return v.validate(blk, tipHeight, tipHash, false)
+}
+
+func (v *validator) validate(blk *Block, tipHeight uint64, tipHash hash.Hash32B, containCommitCert bool) error {
 	if blk == nil {
 		return ErrInvalidBlock
 	}
@@ -94,10 +142,82 @@ func (v *validator) Validate(blk *Block, tipHeight uint64, tipHash hash.Hash32B)
 			hashActual)
 	}
 
-	if v.sf != nil {
-		return v.verifyActions(blk)
+	if v.sf == nil {
+		return nil
+	}
+	if containCommitCert && v.rollDPoS != nil && blk.Header.height > 0 {
+		if err := v.verifyCommitCert(blk); err != nil {
+			return err
+		}
 	}
+	if err := v.verifyActions(blk); err != nil {
+		return err
+	}
+	if v.bc != nil && blk.Header.height > 0 && blk.Header.height >= v.upgrades.StateRootHeight {
+		return v.verifyRoots(blk, tipHeight)
+	}
+
+	return nil
+}
+
+// verifyCommitCert verifies the block carries the commit certificate of the delegates of its epoch. The commit
+// certificates of the blocks before the commit certificate upgrade are not verified
+func (v *validator) verifyCommitCert(blk *Block) error {
+	height := blk.Header.height
+	if height < v.upgrades.CommitCertHeight {
+		return nil
+	}
+	if blk.CommitCert == nil {
+		return errors.Wrapf(ErrInvalidCommitCert, "block %d has no commit certificate", height)
+	}
+	if blk.CommitCert.Height != height {
+		return errors.Wrapf(
+			ErrInvalidCommitCert,
+			"wrong commit certificate height %d, expecting %d",
+			blk.CommitCert.Height,
+			height)
+	}
+	numDlgs := v.rollDPoS.NumDelegates
+	if numDlgs == 0 {
+		return errors.Wrap(ErrInvalidCommitCert, "no delegate to verify the commit certificate against")
+	}
+	numSubEpochs := v.rollDPoS.NumSubEpochs
+	if numSubEpochs == 0 {
+		numSubEpochs = 1
+	}
+	epochNum := (height-1)/(uint64(numDlgs)*uint64(numSubEpochs)) + 1
+	// Delegates of an epoch are chosen from the candidates at the end of the previous epoch
+	candidates, err := v.sf.CandidatesByHeight(uint64(numDlgs) * uint64(numSubEpochs) * (epochNum - 1))
+	if err != nil {
+		return errors.Wrapf(err, "error when getting the candidates of epoch %d", epochNum)
+	}
+	if uint(len(candidates)) < numDlgs {
+		return errors.Wrapf(ErrInvalidCommitCert, "only %d candidates for %d delegates", len(candidates), numDlgs)
+	}
+	return blk.CommitCert.Verify(blk.HashBlock(), SelectDelegates(candidates, epochNum, numDlgs))
+}
 
+// verifyRoots re-executes the block on the states of the tip height, and verifies the resulting roots match the ones
+// in the block header. The roots of the blocks before the state root upgrade are not verified
+func (v *validator) verifyRoots(blk *Block, tipHeight uint64) error {
+	stateRoot, receiptRoot, err := executeOnFork(blk, v.bc, v.sf, tipHeight)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidBlock, "failed to execute the block: %v", err)
+	}
+	if blk.Header.stateRoot != stateRoot {
+		return errors.Wrapf(
+			ErrInvalidBlock,
+			"wrong state root %x, expecting %x",
+			blk.Header.stateRoot,
+			stateRoot)
+	}
+	if blk.Header.receiptRoot != receiptRoot {
+		return errors.Wrapf(
+			ErrInvalidBlock,
+			"wrong receipt root %x, expecting %x",
+			blk.Header.receiptRoot,
+			receiptRoot)
+	}
 	return nil
 }
 
@@ -109,11 +229,16 @@ func (v *validator) verifyActions(blk *Block) error {
 	wg.Add(len(blk.Transfers) + len(blk.Votes) + len(blk.Executions))
 	var correctAction uint64
 	var coinbaseCount uint64
+	// total gas limit of the actions in the block
+	var blockGas uint64
+	// the intrinsic gas of transfers and votes and the block gas limit are only enforced from the gas fee upgrade on
+	gasFee := blk.Header.height >= v.upgrades.GasFeeHeight
 	for _, tsf := range blk.Transfers {
 		// Verify Address
 		// Verify Nonce
 		// Verify Signature
 		// Verify Coinbase transfer
+		// Verify Gas
 
 		if !tsf.IsCoinbase {
 			if _, err := iotxaddress.GetPubkeyHash(tsf.Sender); err != nil {
@@ -125,6 +250,12 @@ func (v *validator) verifyActions(blk *Block) error {
 		}
 
 		if blk.Header.height > 0 && !tsf.IsCoinbase {
+			if err := VerifyChainID(tsf.ChainID, blk.Header.height, v.genesis, v.upgrades); err != nil {
+				return errors.Wrapf(err, "failed to validate transfer %x", tsf.Hash())
+			}
+			if err := VerifyGasPrice(tsf.GasPrice, blk.Header.height, v.genesis, v.upgrades); err != nil {
+				return errors.Wrapf(err, "failed to validate transfer %x", tsf.Hash())
+			}
 			// Store the nonce of the sender and verify later
 			if _, ok := confirmedNonceMap[tsf.Sender]; !ok {
 				accountNonce, err := v.sf.Nonce(tsf.Sender)
@@ -135,6 +266,16 @@ func (v *validator) verifyActions(blk *Block) error {
 				accountNonceMap[tsf.Sender] = make([]uint64, 0)
 			}
 			accountNonceMap[tsf.Sender] = append(accountNonceMap[tsf.Sender], tsf.Nonce)
+
+			if gasFee {
+				intrinsicGas, err := tsf.IntrinsicGas()
+				if intrinsicGas > tsf.GasLimit || err != nil {
+					return errors.Wrapf(ErrInsufficientGas, "insufficient gas for transfer")
+				}
+				if err := addBlockGas(&blockGas, tsf.GasLimit); err != nil {
+					return err
+				}
+			}
 		}
 
 		go func(tsf *action.Transfer, correctTsf *uint64, correctCoinbase *uint64) {
@@ -175,6 +316,7 @@ func (v *validator) verifyActions(blk *Block) error {
 		// Verify Address
 		// Verify Nonce
 		// Verify Signature
+		// Verify Gas
 
 		if _, err := iotxaddress.GetPubkeyHash(vote.GetVote().VoterAddress); err != nil {
 			return errors.Wrapf(err, "failed to validate voter's address %s", vote.GetVote().VoterAddress)
@@ -186,6 +328,13 @@ func (v *validator) verifyActions(blk *Block) error {
 		}
 
 		if blk.Header.height > 0 {
+			if err := VerifyChainID(vote.ChainID, blk.Header.height, v.genesis, v.upgrades); err != nil {
+				return errors.Wrapf(err, "failed to validate vote %x", vote.Hash())
+			}
+			gasPrice := new(big.Int).SetBytes(vote.GasPrice)
+			if err := VerifyGasPrice(gasPrice, blk.Header.height, v.genesis, v.upgrades); err != nil {
+				return errors.Wrapf(err, "failed to validate vote %x", vote.Hash())
+			}
 			// Store the nonce of the voter and verify later
 			voterAddress := vote.GetVote().VoterAddress
 			if _, ok := confirmedNonceMap[voterAddress]; !ok {
@@ -197,6 +346,15 @@ func (v *validator) verifyActions(blk *Block) error {
 				accountNonceMap[voterAddress] = make([]uint64, 0)
 			}
 			accountNonceMap[voterAddress] = append(accountNonceMap[voterAddress], vote.Nonce)
+
+			if gasFee {
+				if vote.IntrinsicGas() > vote.GasLimit {
+					return errors.Wrapf(ErrInsufficientGas, "insufficient gas for vote")
+				}
+				if err := addBlockGas(&blockGas, vote.GasLimit); err != nil {
+					return err
+				}
+			}
 		}
 
 		// Verify signature
@@ -233,6 +391,12 @@ func (v *validator) verifyActions(blk *Block) error {
 		}
 
 		if blk.Header.height > 0 {
+			if err := VerifyChainID(execution.ChainID, blk.Header.height, v.genesis, v.upgrades); err != nil {
+				return errors.Wrapf(err, "failed to validate execution %x", execution.Hash())
+			}
+			if err := VerifyGasPrice(execution.GasPrice, blk.Header.height, v.genesis, v.upgrades); err != nil {
+				return errors.Wrapf(err, "failed to validate execution %x", execution.Hash())
+			}
 			// Store the nonce of the executor and verify later
 			executor := execution.Executor
 			if _, ok := confirmedNonceMap[executor]; !ok {
@@ -268,12 +432,31 @@ func (v *validator) verifyActions(blk *Block) error {
 		if intrinsicGas > execution.GasLimit || err != nil {
 			return errors.Wrapf(ErrInsufficientGas, "insufficient gas for execution")
 		}
+		if gasFee {
+			if err := addBlockGas(&blockGas, execution.GasLimit); err != nil {
+				return err
+			}
+		}
 
 		// Reject execution of negative amount
 		if execution.Amount.Sign() < 0 {
 			return errors.Wrapf(ErrBalance, "negative value")
 		}
 	}
+	// Verify evidences (signatures are checked against the offender's key in CommitStateChanges)
+	offenders := make(map[string]bool)
+	for _, evidence := range blk.Evidences {
+		if blk.Header.height < v.upgrades.SlashingHeight {
+			return errors.Wrapf(ErrInvalidBlock, "evidence before the slashing upgrade at height %d", v.upgrades.SlashingHeight)
+		}
+		if evidence.Height() == 0 || evidence.Height() > blk.Header.height {
+			return errors.Wrapf(ErrInvalidBlock, "evidence at invalid height %d", evidence.Height())
+		}
+		if offenders[evidence.Offender()] {
+			return errors.Wrapf(ErrInvalidBlock, "more than one evidence against %s", evidence.Offender())
+		}
+		offenders[evidence.Offender()] = true
+	}
 	wg.Wait()
 	// Verify coinbase transfer count
 	if (blk.Header.height != 0 && coinbaseCount != 1) || (blk.Header.height == 0 && coinbaseCount != 0) {
@@ -302,3 +485,12 @@ func (v *validator) verifyActions(blk *Block) error {
 	}
 	return nil
 }
+
+// addBlockGas adds the gas of an action to the total gas of the block, which should not exceed the block gas limit
+func addBlockGas(blockGas *uint64, gas uint64) error {
+	if gas > GasLimit-*blockGas {
+		return errors.Wrapf(ErrGasHigherThanLimit, "block gas is higher than gas limit")
+	}
+	*blockGas += gas
+	return nil
+}
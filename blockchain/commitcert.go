@@ -0,0 +1,108 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// ErrInvalidCommitCert indicates the error of an invalid commit certificate
+var ErrInvalidCommitCert = errors.New("invalid commit certificate")
+
+// Endorsement is the endorsement of a block by a delegate, which is the signature of the delegate's vote for the block
+type Endorsement struct {
+	Endorser  string
+	Signature []byte
+}
+
+// CommitCert is the commit certificate of a block, which consists of the endorsements of more than 2/3 of the
+// delegates, collected in the consensus round in which the block is committed
+type CommitCert struct {
+	Height       uint64
+	Round        uint32
+	Endorsements []*Endorsement
+}
+
+// SelectDelegates returns the delegates of the epoch, which are the first numDelegates candidates in the pseudo random
+// order of the epoch. All the candidates are returned if there are not more than numDelegates of them
+func SelectDelegates(candidates []*state.Candidate, epochNum uint64, numDelegates uint) []*state.Candidate {
+	addrs := make([]string, 0, len(candidates))
+	candidatesMap := make(map[string]*state.Candidate, len(candidates))
+	for _, candidate := range candidates {
+		addrs = append(addrs, candidate.Address)
+		candidatesMap[candidate.Address] = candidate
+	}
+	crypto.SortCandidates(addrs, epochNum)
+	if uint(len(addrs)) > numDelegates {
+		addrs = addrs[:numDelegates]
+	}
+	delegates := make([]*state.Candidate, 0, len(addrs))
+	for _, addr := range addrs {
+		delegates = append(delegates, candidatesMap[addr])
+	}
+	return delegates
+}
+
+// ConvertToCommitCertPb converts CommitCert to CommitCertPb
+func (c *CommitCert) ConvertToCommitCertPb() *iproto.CommitCertPb {
+	endorsements := make([]*iproto.EndorsementPb, 0, len(c.Endorsements))
+	for _, e := range c.Endorsements {
+		endorsements = append(endorsements, &iproto.EndorsementPb{Endorser: e.Endorser, Signature: e.Signature})
+	}
+	return &iproto.CommitCertPb{Height: c.Height, Round: c.Round, Endorsements: endorsements}
+}
+
+// ConvertFromCommitCertPb converts CommitCertPb to CommitCert
+func (c *CommitCert) ConvertFromCommitCertPb(pbCert *iproto.CommitCertPb) {
+	c.Height = pbCert.GetHeight()
+	c.Round = pbCert.GetRound()
+	c.Endorsements = make([]*Endorsement, 0, len(pbCert.GetEndorsements()))
+	for _, e := range pbCert.GetEndorsements() {
+		c.Endorsements = append(c.Endorsements, &Endorsement{Endorser: e.GetEndorser(), Signature: e.GetSignature()})
+	}
+}
+
+// Verify verifies the certificate carries the valid endorsements of the block of the hash from more than 2/3 of the
+// delegates. The invalid endorsements, i.e., the ones not signed by a delegate for the block at the height and round
+// of the certificate, and the duplicate ones are ignored
+func (c *CommitCert) Verify(blkHash hash.Hash32B, delegates []*state.Candidate) error {
+	pubKeys := make(map[string][]byte, len(delegates))
+	for _, delegate := range delegates {
+		pubKeys[delegate.Address] = delegate.PubKey
+	}
+	h := action.ViewChangeHash(c.Height, c.Round, iproto.ViewChangeMsg_VOTE, blkHash, true)
+	endorsed := make(map[string]bool, len(c.Endorsements))
+	for _, e := range c.Endorsements {
+		pkBytes, ok := pubKeys[e.Endorser]
+		if !ok || endorsed[e.Endorser] {
+			continue
+		}
+		pubKey, err := keypair.BytesToPublicKey(pkBytes)
+		if err != nil {
+			continue
+		}
+		if crypto.EC283.Verify(pubKey, h[:], e.Signature) {
+			endorsed[e.Endorser] = true
+		}
+	}
+	if len(endorsed) < len(delegates)*2/3+1 {
+		return errors.Wrapf(
+			ErrInvalidCommitCert,
+			"%d valid endorsements are not more than 2/3 of %d delegates",
+			len(endorsed),
+			len(delegates),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facebookgo/clock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestCommitCert(t *testing.T) {
+	require := require.New(t)
+
+	names := []string{"alfa", "bravo", "charlie", "delta"}
+	delegates := make([]*state.Candidate, 0, len(names))
+	for _, name := range names {
+		addr := testaddress.Addrinfo[name]
+		delegates = append(delegates, &state.Candidate{Address: addr.RawAddress, PubKey: addr.PublicKey[:]})
+	}
+	blk := NewBlock(0, 1, hash.ZeroHash32B, clock.New(), nil, nil, nil)
+	blkHash := blk.HashBlock()
+	endorse := func(addr *iotxaddress.Address, blkHash hash.Hash32B) *Endorsement {
+		h := action.ViewChangeHash(1, 0, iproto.ViewChangeMsg_VOTE, blkHash, true)
+		return &Endorsement{Endorser: addr.RawAddress, Signature: crypto.EC283.Sign(addr.PrivateKey, h[:])}
+	}
+
+	cert := &CommitCert{Height: 1}
+	for _, name := range names[:3] {
+		cert.Endorsements = append(cert.Endorsements, endorse(testaddress.Addrinfo[name], blkHash))
+	}
+	require.NoError(cert.Verify(blkHash, delegates))
+
+	// Endorsements of another block
+	require.Equal(ErrInvalidCommitCert, errors.Cause(cert.Verify(hash.ZeroHash32B, delegates)))
+
+	// Not more than 2/3 of the delegates
+	notEnough := &CommitCert{Height: 1, Endorsements: cert.Endorsements[:2]}
+	require.Equal(ErrInvalidCommitCert, errors.Cause(notEnough.Verify(blkHash, delegates)))
+
+	// Duplicate endorsements
+	duplicate := &CommitCert{
+		Height:       1,
+		Endorsements: []*Endorsement{cert.Endorsements[0], cert.Endorsements[1], cert.Endorsements[0]},
+	}
+	require.Equal(ErrInvalidCommitCert, errors.Cause(duplicate.Verify(blkHash, delegates)))
+
+	// Endorsement from a non-delegate
+	nonDelegate := &CommitCert{
+		Height: 1,
+		Endorsements: []*Endorsement{
+			cert.Endorsements[0],
+			cert.Endorsements[1],
+			endorse(testaddress.Addrinfo["echo"], blkHash),
+		},
+	}
+	require.Equal(ErrInvalidCommitCert, errors.Cause(nonDelegate.Verify(blkHash, delegates)))
+
+	// The invalid endorsements are ignored if there are enough valid ones
+	withInvalid := &CommitCert{
+		Height: 1,
+		Endorsements: append(
+			[]*Endorsement{endorse(testaddress.Addrinfo["delta"], hash.ZeroHash32B)},
+			cert.Endorsements...,
+		),
+	}
+	require.NoError(withInvalid.Verify(blkHash, delegates))
+	withInvalid.Endorsements = withInvalid.Endorsements[:3]
+	require.Equal(ErrInvalidCommitCert, errors.Cause(withInvalid.Verify(blkHash, delegates)))
+
+	// The certificate is persisted together with the block, and doesn't change the block hash
+	blk.CommitCert = cert
+	require.Equal(blkHash, blk.HashBlock())
+	cfg := config.Default
+	dao := newBlockDAO(&cfg, db.NewMemKVStore())
+	require.NoError(dao.Start(context.Background()))
+	defer func() {
+		require.NoError(dao.Stop(context.Background()))
+	}()
+	require.NoError(dao.putBlock(blk))
+	stored, err := dao.getBlock(blkHash)
+	require.NoError(err)
+	require.Equal(cert, stored.CommitCert)
+	require.NoError(stored.CommitCert.Verify(stored.HashBlock(), delegates))
+}
@@ -7,6 +7,8 @@
 package blockchain
 
 import (
+	"bytes"
+	"encoding/hex"
 	"math"
 	"math/big"
 
@@ -16,10 +18,12 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/state"
 )
 
 var (
@@ -31,8 +35,13 @@ var (
 	ErrInconsistentNonce = errors.New("Nonce is not identical to executor nonce")
 	// ErrOutOfGas is the error when running out of gas
 	ErrOutOfGas = errors.New("Out of gas")
+	// ErrExecutionReverted is the error that the contract reverts the execution with a reason
+	ErrExecutionReverted = errors.New("Execution reverted")
 )
 
+// revertSelector is the selector of Error(string), which solidity encodes the reason of a revert with
+var revertSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+
 // CanTransfer checks whether the from account has enough balance
 func CanTransfer(db vm.StateDB, fromHash common.Address, balance *big.Int) bool {
 	return db.GetBalance(fromHash).Cmp(balance) > 0
@@ -153,36 +162,79 @@ func securityDeposit(ps *EVMParams, stateDB vm.StateDB, gasLimit *uint64) error
 	return nil
 }
 
-// ExecuteContracts process the contracts in a block
+// ExecuteContracts process the contracts in a block, and generates the receipts of all the actions in the block
 func ExecuteContracts(blk *Block, bc Blockchain) {
+	executeContracts(blk, bc, bc.GetFactory())
+}
+
+// executeContracts process the contracts in a block on the given state factory, and generates the receipts of all the
+// actions in the block
+func executeContracts(blk *Block, bc Blockchain, sf state.Factory) {
 	gasLimit := GasLimit
 	blk.receipts = make(map[hash.Hash32B]*Receipt)
+	// a transfer or vote in a block always succeeds, otherwise committing the block fails. It consumes no gas before
+	// the gas fee upgrade, as it is not charged the fee of its intrinsic gas
+	gasFee := blk.Height() >= bc.Upgrades().GasFeeHeight
+	for _, tsf := range blk.Transfers {
+		receipt := &Receipt{Status: SuccessStatus, Hash: tsf.Hash()}
+		if gasFee && !tsf.IsCoinbase && !tsf.IsContract() {
+			receipt.GasConsumed, _ = tsf.IntrinsicGas()
+		}
+		blk.receipts[tsf.Hash()] = receipt
+	}
+	for _, vote := range blk.Votes {
+		receipt := &Receipt{Status: SuccessStatus, Hash: vote.Hash()}
+		if gasFee {
+			receipt.GasConsumed = vote.IntrinsicGas()
+		}
+		blk.receipts[vote.Hash()] = receipt
+	}
 	for idx, execution := range blk.Executions {
 		// TODO (zhi) log receipt to stateDB
-		if receipt, _ := executeContract(blk, idx, execution, bc, &gasLimit); receipt != nil {
+		if receipt, _ := executeContract(blk, idx, execution, bc, sf, &gasLimit, nil); receipt != nil {
 			blk.receipts[execution.Hash()] = receipt
 		}
 	}
+	blkHash := blk.HashBlock()
+	for i, h := range blk.actionHashes() {
+		if receipt, ok := blk.receipts[h]; ok {
+			receipt.BlockHeight = blk.Height()
+			receipt.BlockHash = blkHash
+			receipt.Index = uint(i)
+		}
+	}
 }
 
-// executeContract processes a transfer which contains a contract
-func executeContract(blk *Block, idx int, execution *action.Execution, bc Blockchain, gasLimit *uint64) (*Receipt, error) {
-	stateDB := NewEVMStateDBAdapter(bc, blk.Height(), blk.HashBlock(), uint(idx), execution.Hash())
+// executeContract processes a transfer which contains a contract, the EVM running it is traced if the tracer is not nil
+func executeContract(
+	blk *Block,
+	idx int,
+	execution *action.Execution,
+	bc Blockchain,
+	sf state.Factory,
+	gasLimit *uint64,
+	tracer vm.Tracer,
+) (*Receipt, error) {
+	stateDB := newEVMStateDBAdapter(bc, sf, blk.Height(), blk.HashBlock(), uint(idx), execution.Hash())
 	ps, err := NewEVMParams(blk, execution, stateDB)
 	if err != nil {
 		return nil, err
 	}
-	retval, depositGas, remainingGas, contractAddress, err := executeInEVM(ps, stateDB, gasLimit)
+	retval, depositGas, remainingGas, contractAddress, err := executeInEVM(ps, stateDB, gasLimit, tracer)
 	receipt := &Receipt{
-		ReturnValue:     retval,
 		GasConsumed:     ps.gas - remainingGas,
 		Hash:            execution.Hash(),
 		ContractAddress: contractAddress,
 	}
 	if err != nil {
+		// the receipt of a failed execution has no return value, and the output of a revert is kept in the error
 		receipt.Status = FailureStatus
+		if len(retval) > 0 {
+			err = errors.Wrap(ErrExecutionReverted, revertReason(retval))
+		}
 	} else {
 		receipt.Status = SuccessStatus
+		receipt.ReturnValue = retval
 	}
 	if remainingGas > 0 {
 		*gasLimit += remainingGas
@@ -194,10 +246,28 @@ func executeContract(blk *Block, idx int, execution *action.Execution, bc Blockc
 		stateDB.AddBalance(ps.context.Coinbase, gasValue)
 	}
 	receipt.Logs = stateDB.Logs()
+	// write the changes surviving the execution into state factory
+	if commitErr := stateDB.CommitContracts(); commitErr != nil {
+		return receipt, errors.Wrap(commitErr, "failed to commit contract changes")
+	}
 	logger.Debug().Msgf("Receipt: %+v, %v", receipt, err)
 	return receipt, err
 }
 
+// revertReason decodes the reason of a revert from the output of the EVM, which is the string of Error(string) if the
+// contract reverts with a message, or the hex of the output otherwise
+func revertReason(output []byte) string {
+	// 4 bytes of the selector, 32 bytes of the string offset, 32 bytes of the string length and the string
+	if len(output) < 68 || !bytes.Equal(output[:4], revertSelector) {
+		return hex.EncodeToString(output)
+	}
+	length := new(big.Int).SetBytes(output[36:68])
+	if !length.IsUint64() || length.Uint64() > uint64(len(output)-68) {
+		return hex.EncodeToString(output)
+	}
+	return string(output[68 : 68+length.Uint64()])
+}
+
 // IntrinsicGas returns the intrinsic gas of an execution
 func IntrinsicGas(data []byte) (uint64, error) {
 	dataSize := uint64(len(data))
@@ -208,21 +278,35 @@ func IntrinsicGas(data []byte) (uint64, error) {
 	return dataSize*ExecutionDataGas + BaseIntrinsicGas, nil
 }
 
-func getChainConfig() *params.ChainConfig {
+// getChainConfig returns the EVM chain config, where the forks of Ethereum are switched on by the upgrades
+func getChainConfig(upgrades config.Upgrades) *params.ChainConfig {
 	var chainConfig params.ChainConfig
 	// chainConfig.ChainID
 	chainConfig.ConstantinopleBlock = new(big.Int).SetUint64(0) // Constantinople switch block (nil = no fork, 0 = already activated)
+	// the forks up to Byzantium are switched on together, the EVM picks the rules by the height of the block
+	byzantium := new(big.Int).SetUint64(upgrades.ByzantiumHeight)
+	chainConfig.HomesteadBlock = byzantium
+	chainConfig.EIP150Block = byzantium
+	chainConfig.EIP155Block = byzantium
+	chainConfig.EIP158Block = byzantium
+	chainConfig.ByzantiumBlock = byzantium
 
 	return &chainConfig
 }
 
-func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *uint64) ([]byte, uint64, uint64, string, error) {
+func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *uint64, tracer vm.Tracer) ([]byte, uint64, uint64, string, error) {
 	remainingGas := evmParams.gas
 	if err := securityDeposit(evmParams, stateDB, gasLimit); err != nil {
 		return nil, 0, 0, action.EmptyAddress, err
 	}
+	// the changes of the execution are reverted if the state fails, while the deposit is kept
+	snapshot := stateDB.Snapshot()
 	var config vm.Config
-	chainConfig := getChainConfig()
+	if tracer != nil {
+		config.Debug = true
+		config.Tracer = tracer
+	}
+	chainConfig := getChainConfig(stateDB.bc.Upgrades())
 	evm := vm.NewEVM(evmParams.context, stateDB, chainConfig, config)
 	intriGas, err := IntrinsicGas(evmParams.data)
 	if err != nil {
@@ -241,7 +325,7 @@ func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *ui
 		ret, evmContractAddress, remainingGas, err = evm.Create(executor, evmParams.data, remainingGas, evmParams.amount)
 		logger.Warn().Hex("contract addrHash", evmContractAddress[:]).Msg("evm.Create")
 		if err != nil {
-			return nil, evmParams.gas, remainingGas, action.EmptyAddress, err
+			return ret, evmParams.gas, remainingGas, action.EmptyAddress, err
 		}
 		contractAddress, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, evmContractAddress.Bytes())
 		if err != nil {
@@ -252,14 +336,19 @@ func executeInEVM(evmParams *EVMParams, stateDB *EVMStateDBAdapter, gasLimit *ui
 		// process contract
 		ret, remainingGas, err = evm.Call(executor, *evmParams.contract, evmParams.data, remainingGas, evmParams.amount)
 	}
-	if err == nil {
-		err = stateDB.Error()
-	}
 	if err == vm.ErrInsufficientBalance {
 		return nil, evmParams.gas, remainingGas, action.EmptyAddress, err
 	}
 	if err != nil {
-		// TODO (zhi) should we refund if any error
+		// the EVM only outputs along with an error if the contract reverts, and the output is the reason of the revert.
+		// The EVM consumes all the gas on any other error, so only the gas left by a revert is refunded
+		if stateDB.blockHeight < stateDB.bc.Upgrades().RevertRefundHeight {
+			remainingGas = 0
+		}
+		return ret, evmParams.gas, remainingGas, contractRawAddress, err
+	}
+	if err := stateDB.Error(); err != nil {
+		stateDB.RevertToSnapshot(snapshot)
 		return nil, evmParams.gas, 0, contractRawAddress, err
 	}
 	// TODO (zhi) figure out what the following function does
@@ -11,8 +11,12 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/core/vm"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
@@ -46,7 +50,7 @@ func TestEVM(t *testing.T) {
 		require.NoError(err)
 	}()
 	_, err := bc.CreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
-	bc.GetFactory().CommitStateChanges(0, nil, nil, nil)
+	bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil)
 	require.NoError(err)
 	// data, _ := hex.DecodeString("6080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b5060766004803603810190808035906020019092919050505060a0565b005b348015608357600080fd5b50608a60aa565b6040518082815260200191505060405180910390f35b8060008190555050565b600080549050905600a165627a7a723058202b8e3ee299d6212c404a3f109eb874d5af929b6d2d701819421e3686c4c82fbd0029")
 	data, _ := hex.DecodeString("608060405234801561001057600080fd5b5060df8061001f6000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b5060766004803603810190808035906020019092919050505060a0565b005b348015608357600080fd5b50608a60aa565b6040518082815260200191505060405180910390f35b8060008190555050565b600080549050905600a165627a7a7230582002faabbefbbda99b20217cf33cb8ab8100caf1542bf1f48117d72e2c59139aea0029")
@@ -56,10 +60,14 @@ func TestEVM(t *testing.T) {
 	require.NoError(err)
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	require.NoError(err)
-	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
-	require.Equal(1, len(blk.receipts))
+	// the coinbase transfer has a receipt as well
+	require.Equal(2, len(blk.receipts))
+	require.NotEqual(hash.ZeroHash32B, blk.Header.receiptRoot)
+	require.Equal(blk.ReceiptRoot(), blk.Header.receiptRoot)
+	require.Equal(blk.Header.stateRoot, bc.GetFactory().RootHash())
 
 	eHash := execution.Hash()
 	r, _ := bc.GetReceiptByExecutionHash(eHash)
@@ -92,10 +100,10 @@ func TestEVM(t *testing.T) {
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	logger.Info().Msgf("execution %+v", execution)
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
-	require.Equal(1, len(blk.receipts))
+	require.Equal(2, len(blk.receipts))
 
 	v, err := bc.GetFactory().GetContractState(contractAddrHash, hash.ZeroHash32B)
 	require.Nil(err)
@@ -104,6 +112,7 @@ func TestEVM(t *testing.T) {
 	eHash = execution.Hash()
 	r, _ = bc.GetReceiptByExecutionHash(eHash)
 	require.Equal(eHash, r.Hash)
+	storeHash, storeReceipt := eHash, r
 
 	// read from key 0
 	contractAddr = "io1qyqsyqcy3kcd2pyfwus69nzgvkwhg8mk8h336dt86pg6cj"
@@ -114,14 +123,157 @@ func TestEVM(t *testing.T) {
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	logger.Info().Msgf("execution %+v", execution)
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
-	require.Equal(1, len(blk.receipts))
+	require.Equal(2, len(blk.receipts))
 
 	eHash = execution.Hash()
 	r, _ = bc.GetReceiptByExecutionHash(eHash)
 	require.Equal(eHash, r.Hash)
+
+	// estimate the gas of storing to key 0, which succeeds with the estimate but not with any less gas
+	data, _ = hex.DecodeString("60fe47b1000000000000000000000000000000000000000000000000000000000000001f")
+	execution, err = action.NewExecution(
+		ta.Addrinfo["producer"].RawAddress, contractAddr, 4, big.NewInt(0), uint64(0), big.NewInt(10), data)
+	require.NoError(err)
+	gas, reason, err := bc.EstimateGasForExecution(execution)
+	require.NoError(err)
+	require.Equal("", reason)
+	receipt, err := bc.(*blockchain).simulateExecution(execution, gas, bc.TipHeight())
+	require.NoError(err)
+	require.Equal(SuccessStatus, receipt.Status)
+	receipt, err = bc.(*blockchain).simulateExecution(execution, gas-1, bc.TipHeight())
+	require.Error(err)
+	require.Equal(FailureStatus, receipt.Status)
+	// the estimation is not persisted
+	v, err = bc.GetFactory().GetContractState(contractAddrHash, hash.ZeroHash32B)
+	require.NoError(err)
+	require.Equal(byte(15), v[31])
+	nonce, err := bc.Nonce(ta.Addrinfo["producer"].RawAddress)
+	require.NoError(err)
+	require.Equal(uint64(3), nonce)
+
+	// the contract reverts a function it does not have, whatever the gas limit is
+	data, _ = hex.DecodeString("deadbeef")
+	execution, err = action.NewExecution(
+		ta.Addrinfo["producer"].RawAddress, contractAddr, 4, big.NewInt(0), uint64(0), big.NewInt(10), data)
+	require.NoError(err)
+	gas, reason, err = bc.EstimateGasForExecution(execution)
+	require.NoError(err)
+	require.Equal(uint64(0), gas)
+	require.NotEqual("", reason)
+
+	// trace storing to key 0, which is replayed on the states before it
+	trace, err := bc.TraceExecution(storeHash)
+	require.NoError(err)
+	require.Equal(SuccessStatus, trace.Receipt.Status)
+	require.Equal(storeReceipt.GasConsumed, trace.Receipt.GasConsumed)
+	require.Equal("", trace.Error)
+	require.False(trace.Truncated)
+	require.Equal("CALL", trace.Call.Type)
+	require.Equal(ta.Addrinfo["producer"].RawAddress, trace.Call.From)
+	require.Equal(contractAddr, trace.Call.To)
+	require.Equal(0, len(trace.Call.Calls))
+	// the 3rd step stores the free memory pointer 0x80 to 0x40
+	require.Equal("MSTORE", trace.Steps[2].Op)
+	require.Equal(uint64(4), trace.Steps[2].PC)
+	require.Equal(&MemoryDiff{Offset: 0x5f, Data: []byte{0x80}}, trace.Steps[2].MemoryDiff)
+	var writes []*StorageWrite
+	for _, step := range trace.Steps {
+		require.Equal(1, step.Depth)
+		if step.StorageWrite != nil {
+			require.Equal("SSTORE", step.Op)
+			writes = append(writes, step.StorageWrite)
+		}
+	}
+	require.Equal(1, len(writes))
+	require.Equal(hash.ZeroHash32B, writes[0].Key)
+	require.Equal(byte(15), writes[0].Value[31])
+
+	// trace a committed execution reverted by the contract
+	execution, err = action.NewExecution(
+		ta.Addrinfo["producer"].RawAddress, contractAddr, 4, big.NewInt(0), uint64(120000), big.NewInt(10), data)
+	require.NoError(err)
+	execution, err = execution.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.Nil(bc.CommitBlock(blk))
+	trace, err = bc.TraceExecution(execution.Hash())
+	require.NoError(err)
+	require.Equal(FailureStatus, trace.Receipt.Status)
+	// the gas left by the revert is not refunded before the upgrade
+	require.Equal(uint64(120000), trace.Receipt.GasConsumed)
+	require.NotEqual("", trace.Error)
+	require.NotEqual("", trace.Call.Error)
+	require.Equal("REVERT", trace.Steps[len(trace.Steps)-1].Op)
+
+	_, err = bc.TraceExecution(hash.ZeroHash32B)
+	require.Error(err)
+
+	// deploy a contract returning the number of the block, which is simulated as the block next to the tip
+	data, _ = hex.DecodeString("684360005260206000f360005260096017f3")
+	execution, err = action.NewExecution(
+		ta.Addrinfo["producer"].RawAddress, action.EmptyAddress, 5, big.NewInt(0), uint64(100000), big.NewInt(10), data)
+	require.NoError(err)
+	execution, err = execution.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.Nil(bc.CommitBlock(blk))
+	r, err = bc.GetReceiptByExecutionHash(execution.Hash())
+	require.NoError(err)
+	require.Equal(SuccessStatus, r.Status)
+	execution, err = action.NewExecution(
+		ta.Addrinfo["producer"].RawAddress, r.ContractAddress, 6, big.NewInt(0), uint64(0), big.NewInt(10), nil)
+	require.NoError(err)
+	receipt, err = bc.(*blockchain).simulateExecution(execution, uint64(100000), bc.TipHeight())
+	require.NoError(err)
+	require.Equal(SuccessStatus, receipt.Status)
+	require.Equal(bc.TipHeight()+1, new(big.Int).SetBytes(receipt.ReturnValue).Uint64())
+}
+
+func TestRevertReason(t *testing.T) {
+	require := require.New(t)
+
+	reason := hex.EncodeToString([]byte("insufficient"))
+	output, err := hex.DecodeString("08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"000000000000000000000000000000000000000000000000000000000000000c" +
+		reason + strings.Repeat("0", 64-len(reason)))
+	require.NoError(err)
+	require.Equal("insufficient", revertReason(output))
+	// the output is not a complete Error(string)
+	require.Equal(hex.EncodeToString(output[:70]), revertReason(output[:70]))
+	require.Equal("0102", revertReason([]byte{1, 2}))
+}
+
+func TestTraceTruncated(t *testing.T) {
+	require := require.New(t)
+
+	tracer := newExecutionTracer()
+	require.NoError(tracer.CaptureStart(common.Address{}, common.Address{1}, true, nil, 100000, big.NewInt(0)))
+	contract := vm.NewContract(vm.AccountRef(common.Address{}), vm.AccountRef(common.Address{1}), big.NewInt(0), 100000)
+	memory := vm.NewMemory()
+	for pc := uint64(0); pc <= MaxTraceSteps; pc++ {
+		require.NoError(tracer.CaptureState(nil, pc, vm.JUMPDEST, 100000, 1, memory, &vm.Stack{}, contract, 1, nil))
+	}
+	require.Equal(MaxTraceSteps, len(tracer.trace.Steps))
+	require.True(tracer.trace.Truncated)
+	// the steps beyond are not traced
+	require.Equal(uint64(MaxTraceSteps-1), tracer.trace.Steps[MaxTraceSteps-1].PC)
+}
+
+func TestChainConfigUpgrades(t *testing.T) {
+	require := require.New(t)
+
+	chainConfig := getChainConfig(config.Upgrades{ByzantiumHeight: 5})
+	require.False(chainConfig.IsByzantium(big.NewInt(4)))
+	require.False(chainConfig.IsEIP158(big.NewInt(4)))
+	require.True(chainConfig.IsByzantium(big.NewInt(5)))
+	require.True(chainConfig.IsEIP158(big.NewInt(5)))
+	require.True(chainConfig.IsHomestead(big.NewInt(6)))
 }
 
 func TestLogReceipt(t *testing.T) {
@@ -145,8 +297,9 @@ func TestLogReceipt(t *testing.T) {
 	require.Equal(log.BlockHash, actuallog.BlockHash)
 	require.Equal(log.Index, actuallog.Index)
 
-	receipt := Receipt{ReturnValue: []byte("12345"), Status: 5, GasConsumed: 6, ContractAddress: "aaaaa", Logs: []*Log{&log}}
+	receipt := Receipt{ReturnValue: []byte("12345"), Status: 5, GasConsumed: 6, ContractAddress: "aaaaa", Logs: []*Log{&log}, BlockHeight: 7, Index: 8}
 	copy(receipt.Hash[:], hash.Hash256b([]byte("33333")))
+	copy(receipt.BlockHash[:], hash.Hash256b([]byte("44444")))
 	s, err = receipt.Serialize()
 	require.NoError(err)
 	actualReceipt := Receipt{}
@@ -158,6 +311,9 @@ func TestLogReceipt(t *testing.T) {
 	require.Equal(receipt.Logs[0], actualReceipt.Logs[0])
 	require.Equal(len(receipt.Logs), len(actualReceipt.Logs))
 	require.Equal(receipt.Hash, actualReceipt.Hash)
+	require.Equal(receipt.BlockHeight, actualReceipt.BlockHeight)
+	require.Equal(receipt.BlockHash, actualReceipt.BlockHash)
+	require.Equal(receipt.Index, actualReceipt.Index)
 }
 
 func TestRollDice(t *testing.T) {
@@ -186,14 +342,14 @@ func TestRollDice(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(ta.Addrinfo["bravo"].RawAddress, 12000000)
 	require.NoError(err)
-	bc.GetFactory().CommitStateChanges(0, nil, nil, nil)
+	bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil)
 	data, _ := hex.DecodeString("608060405234801561001057600080fd5b506102f5806100206000396000f3006080604052600436106100615763ffffffff7c01000000000000000000000000000000000000000000000000000000006000350416632885ad2c8114610066578063797d9fbd14610070578063cd5e3c5d14610091578063d0e30db0146100b8575b600080fd5b61006e6100c0565b005b61006e73ffffffffffffffffffffffffffffffffffffffff600435166100cb565b34801561009d57600080fd5b506100a6610159565b60408051918252519081900360200190f35b61006e610229565b6100c9336100cb565b565b60006100d5610159565b6040805182815290519192507fbae72e55df73720e0f671f4d20a331df0c0dc31092fda6c573f35ff7f37f283e919081900360200190a160405173ffffffffffffffffffffffffffffffffffffffff8316906305f5e100830280156108fc02916000818181858888f19350505050158015610154573d6000803e3d6000fd5b505050565b604080514460208083019190915260001943014082840152825180830384018152606090920192839052815160009360059361021a9360029391929182918401908083835b602083106101bd5780518252601f19909201916020918201910161019e565b51815160209384036101000a600019018019909216911617905260405191909301945091925050808303816000865af11580156101fe573d6000803e3d6000fd5b5050506040513d602081101561021357600080fd5b5051610261565b81151561022357fe5b06905090565b60408051348152905133917fe1fffcc4923d04b559f4d29a8bfc6cda04eb5b0d3c460751c2402c5c5cc9109c919081900360200190a2565b600080805b60208110156102c25780600101602060ff160360080260020a848260208110151561028d57fe5b7f010000000000000000000000000000000000000000000000000000000000000091901a810204029190910190600101610266565b50929150505600a165627a7a72305820a426929891673b0a04d7163b60113d28e7d0f48ea667680ba48126c182b872c10029")
 	execution, err := action.NewExecution(
 		ta.Addrinfo["producer"].RawAddress, action.EmptyAddress, 1, big.NewInt(0), uint64(1000000), big.NewInt(10), data)
 	require.NoError(err)
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	require.NoError(err)
-	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 
@@ -209,7 +365,7 @@ func TestRollDice(t *testing.T) {
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	logger.Info().Msgf("execution %+v", execution)
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 
@@ -225,7 +381,7 @@ func TestRollDice(t *testing.T) {
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	logger.Info().Msgf("execution %+v\n", execution)
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 
@@ -248,7 +404,7 @@ func TestRollDice(t *testing.T) {
 	execution, err = execution.Sign(ta.Addrinfo["bravo"])
 	logger.Info().Msgf("execution %+v\n", execution)
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 	balance, err = bc.Balance(ta.Addrinfo["bravo"].RawAddress)
@@ -282,7 +438,7 @@ func TestERC20(t *testing.T) {
 	require.NoError(err)
 	_, err = bc.CreateState(ta.Addrinfo["bravo"].RawAddress, 0)
 	require.NoError(err)
-	bc.GetFactory().CommitStateChanges(0, nil, nil, nil)
+	bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil)
 	//data, _ := hex.DecodeString("608060405234801561001057600080fd5b5060df8061001f6000396000f3006080604052600436106049576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806360fe47b114604e5780636d4ce63c146078575b600080fd5b348015605957600080fd5b5060766004803603810190808035906020019092919050505060a0565b005b348015608357600080fd5b50608a60aa565b6040518082815260200191505060405180910390f35b8060008190555050565b600080549050905600a165627a7a7230582002faabbefbbda99b20217cf33cb8ab8100caf1542bf1f48117d72e2c59139aea0029")
 	data, _ := hex.DecodeString("60806040526000600360146101000a81548160ff02191690831515021790555034801561002b57600080fd5b506040516020806119938339810180604052810190808051906020019092919050505033600360006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff16021790555080600181905550806000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055503373ffffffffffffffffffffffffffffffffffffffff16600073ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef836040518082815260200191505060405180910390a3506118448061014f6000396000f3006080604052600436106100e6576000357c0100000000000000000000000000000000000000000000000000000000900463ffffffff16806306fdde03146100eb578063095ea7b31461017b57806318160ddd146101e057806323b872dd1461020b578063313ce567146102905780633f4ba83a146102c15780635c975abb146102d8578063661884631461030757806370a082311461036c5780638456cb59146103c35780638da5cb5b146103da57806395d89b4114610431578063a9059cbb146104c1578063d73dd62314610526578063dd62ed3e1461058b578063f2fde38b14610602575b600080fd5b3480156100f757600080fd5b50610100610645565b6040518080602001828103825283818151815260200191508051906020019080838360005b83811015610140578082015181840152602081019050610125565b50505050905090810190601f16801561016d5780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b34801561018757600080fd5b506101c6600480360381019080803573ffffffffffffffffffffffffffffffffffffffff1690602001909291908035906020019092919050505061067e565b604051808215151515815260200191505060405180910390f35b3480156101ec57600080fd5b506101f56106ae565b6040518082815260200191505060405180910390f35b34801561021757600080fd5b50610276600480360381019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803590602001909291905050506106b8565b604051808215151515815260200191505060405180910390f35b34801561029c57600080fd5b506102a5610763565b604051808260ff1660ff16815260200191505060405180910390f35b3480156102cd57600080fd5b506102d6610768565b005b3480156102e457600080fd5b506102ed610828565b604051808215151515815260200191505060405180910390f35b34801561031357600080fd5b50610352600480360381019080803573ffffffffffffffffffffffffffffffffffffffff1690602001909291908035906020019092919050505061083b565b604051808215151515815260200191505060405180910390f35b34801561037857600080fd5b506103ad600480360381019080803573ffffffffffffffffffffffffffffffffffffffff16906020019092919050505061086b565b6040518082815260200191505060405180910390f35b3480156103cf57600080fd5b506103d86108b3565b005b3480156103e657600080fd5b506103ef610974565b604051808273ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200191505060405180910390f35b34801561043d57600080fd5b5061044661099a565b6040518080602001828103825283818151815260200191508051906020019080838360005b8381101561048657808201518184015260208101905061046b565b50505050905090810190601f1680156104b35780820380516001836020036101000a031916815260200191505b509250505060405180910390f35b3480156104cd57600080fd5b5061050c600480360381019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803590602001909291905050506109d3565b604051808215151515815260200191505060405180910390f35b34801561053257600080fd5b50610571600480360381019080803573ffffffffffffffffffffffffffffffffffffffff16906020019092919080359060200190929190505050610a7c565b604051808215151515815260200191505060405180910390f35b34801561059757600080fd5b506105ec600480360381019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190803573ffffffffffffffffffffffffffffffffffffffff169060200190929190505050610aac565b6040518082815260200191505060405180910390f35b34801561060e57600080fd5b50610643600480360381019080803573ffffffffffffffffffffffffffffffffffffffff169060200190929190505050610b33565b005b6040805190810160405280600d81526020017f496f546558204e6574776f726b0000000000000000000000000000000000000081525081565b6000600360149054906101000a900460ff1615151561069c57600080fd5b6106a68383610c8b565b905092915050565b6000600154905090565b6000600360149054906101000a900460ff161515156106d657600080fd5b82600073ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff161415151561071357600080fd5b3073ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff161415151561074e57600080fd5b610759858585610d7d565b9150509392505050565b601281565b600360009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff161415156107c457600080fd5b600360149054906101000a900460ff1615156107df57600080fd5b6000600360146101000a81548160ff0219169083151502179055507f7805862f689e2f13df9f062ff482ad3ad112aca9e0847911ed832e158c525b3360405160405180910390a1565b600360149054906101000a900460ff1681565b6000600360149054906101000a900460ff1615151561085957600080fd5b6108638383611137565b905092915050565b60008060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020549050919050565b600360009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff1614151561090f57600080fd5b600360149054906101000a900460ff1615151561092b57600080fd5b6001600360146101000a81548160ff0219169083151502179055507f6985a02210a168e66602d3235cb6db0e70f92b3ba4d376a33c0f3d9434bff62560405160405180910390a1565b600360009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b6040805190810160405280600481526020017f494f54580000000000000000000000000000000000000000000000000000000081525081565b6000600360149054906101000a900460ff161515156109f157600080fd5b82600073ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff1614151515610a2e57600080fd5b3073ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff1614151515610a6957600080fd5b610a7384846113c8565b91505092915050565b6000600360149054906101000a900460ff16151515610a9a57600080fd5b610aa483836115e7565b905092915050565b6000600260008473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054905092915050565b600360009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff16141515610b8f57600080fd5b600073ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff1614151515610bcb57600080fd5b8073ffffffffffffffffffffffffffffffffffffffff16600360009054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff167f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e060405160405180910390a380600360006101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff16021790555050565b600081600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508273ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925846040518082815260200191505060405180910390a36001905092915050565b60008073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff1614151515610dba57600080fd5b6000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020548211151515610e0757600080fd5b600260008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020548211151515610e9257600080fd5b610ee3826000808773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546117e390919063ffffffff16565b6000808673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002081905550610f76826000808673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546117fc90919063ffffffff16565b6000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020016000208190555061104782600260008773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546117e390919063ffffffff16565b600260008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508273ffffffffffffffffffffffffffffffffffffffff168473ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef846040518082815260200191505060405180910390a3600190509392505050565b600080600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054905080831115611248576000600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055506112dc565b61125b83826117e390919063ffffffff16565b600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055505b8373ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008873ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546040518082815260200191505060405180910390a3600191505092915050565b60008073ffffffffffffffffffffffffffffffffffffffff168373ffffffffffffffffffffffffffffffffffffffff161415151561140557600080fd5b6000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002054821115151561145257600080fd5b6114a3826000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546117e390919063ffffffff16565b6000803373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002081905550611536826000808673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546117fc90919063ffffffff16565b6000808573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508273ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef846040518082815260200191505060405180910390a36001905092915050565b600061167882600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008673ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546117fc90919063ffffffff16565b600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008573ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020819055508273ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff167f8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925600260003373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260200190815260200160002060008773ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff168152602001908152602001600020546040518082815260200191505060405180910390a36001905092915050565b60008282111515156117f157fe5b818303905092915050565b6000818301905082811015151561180f57fe5b809050929150505600a165627a7a72305820ffa710f4c82e1f12645713d71da89f0c795cce49fbe12e060ea17f520d6413f800290000000000000000000000000000000000000000204fce5e3e25026110000000")
 	execution, err := action.NewExecution(
@@ -290,10 +446,10 @@ func TestERC20(t *testing.T) {
 	require.NoError(err)
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	require.NoError(err)
-	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
-	require.Equal(1, len(blk.receipts))
+	require.Equal(2, len(blk.receipts))
 
 	eHash := execution.Hash()
 	r, _ := bc.GetReceiptByExecutionHash(eHash)
@@ -338,7 +494,7 @@ func TestERC20(t *testing.T) {
 	require.NoError(err)
 	ex2, err = ex2.Sign(ta.Addrinfo["producer"])
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution, ex2}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution, ex2}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 
@@ -356,7 +512,7 @@ func TestERC20(t *testing.T) {
 	require.NoError(err)
 	ex3, err = ex3.Sign(ta.Addrinfo["alfa"])
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{ex3}, ta.Addrinfo["alfa"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{ex3}, nil, ta.Addrinfo["alfa"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 
@@ -369,7 +525,7 @@ func TestERC20(t *testing.T) {
 	require.NoError(err)
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	require.NoError(err)
-	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 
@@ -380,4 +536,44 @@ func TestERC20(t *testing.T) {
 	h = r.ReturnValue[len(r.ReturnValue)-8:]
 	amount := binary.BigEndian.Uint64(h)
 	require.Equal(uint64(10000), amount)
+
+	// get the Transfer events of the token
+	topic, _ := hex.DecodeString("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	transferTopic := byteutil.BytesTo32B(topic)
+	logs, err := bc.GetLogs(&LogFilter{FromHeight: 1, ToHeight: 4, Addresses: []string{contract}}, 0, 10)
+	require.NoError(err)
+	// alfa has no balance to pay the gas, so its transfer to bravo fails without emitting a log
+	require.Equal(3, len(logs))
+	for _, log := range logs {
+		require.Equal(contract, log.Address)
+		require.Equal(transferTopic, log.Topics[0])
+	}
+	require.Equal(uint64(1), logs[0].BlockNumber)
+	require.Equal(uint64(2), logs[2].BlockNumber)
+	logs, err = bc.GetLogs(&LogFilter{FromHeight: 1, ToHeight: 4, Addresses: []string{contract}}, 1, 2)
+	require.NoError(err)
+	require.Equal(2, len(logs))
+	require.Equal(uint64(2), logs[0].BlockNumber)
+	require.Equal(uint64(2), logs[1].BlockNumber)
+	// the transfers to bravo
+	logs, err = bc.GetLogs(&LogFilter{FromHeight: 0, ToHeight: 10, Topics: [][]hash.Hash32B{{transferTopic}, nil, {bravo}}}, 0, 10)
+	require.NoError(err)
+	require.Equal(1, len(logs))
+	require.Equal(ex2.Hash(), logs[0].TxnHash)
+	// the transfers from alfa
+	logs, err = bc.GetLogs(&LogFilter{FromHeight: 0, ToHeight: 10, Topics: [][]hash.Hash32B{nil, {alfa}}}, 0, 10)
+	require.NoError(err)
+	require.Equal(0, len(logs))
+	logs, err = bc.GetLogs(&LogFilter{FromHeight: 0, ToHeight: 2, Topics: [][]hash.Hash32B{nil, {alfa}}}, 0, 10)
+	require.NoError(err)
+	require.Equal(0, len(logs))
+	// all the logs in the height range
+	logs, err = bc.GetLogs(&LogFilter{FromHeight: 2, ToHeight: 2}, 0, 10)
+	require.NoError(err)
+	require.Equal(2, len(logs))
+	logs, err = bc.GetLogs(&LogFilter{FromHeight: 0, ToHeight: 10, Addresses: []string{ta.Addrinfo["alfa"].RawAddress}}, 0, 10)
+	require.NoError(err)
+	require.Equal(0, len(logs))
+	_, err = bc.GetLogs(&LogFilter{FromHeight: 2, ToHeight: 1}, 0, 10)
+	require.Equal(ErrInvalidLogFilter, errors.Cause(err))
 }
@@ -11,6 +11,8 @@ import (
 
 	"github.com/CoderZhi/go-ethereum/common"
 	"github.com/CoderZhi/go-ethereum/core/types"
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
@@ -19,6 +21,8 @@ import (
 )
 
 // EVMStateDBAdapter represents the state db adapter for evm to access iotx blockchain
+// All the changes made by the evm are kept in a journaled cache, so they can be reverted to any snapshot taken during
+// the execution. The cached changes are written into the state factory only when the execution finishes.
 type EVMStateDBAdapter struct {
 	bc             Blockchain
 	sf             state.Factory
@@ -28,19 +32,38 @@ type EVMStateDBAdapter struct {
 	blockHash      hash.Hash32B
 	executionIndex uint
 	executionHash  hash.Hash32B
+	// journaled state cache
+	createdAccount map[hash.AddrHash]bool
+	cachedBalance  map[hash.AddrHash]*big.Int
+	cachedCode     map[hash.AddrHash][]byte
+	cachedStorage  map[hash.AddrHash]map[hash.Hash32B]hash.Hash32B
+	suicided       map[hash.AddrHash]bool
+	refund         uint64
+	journal        []journalEntry
 }
 
 // NewEVMStateDBAdapter creates a new state db with iotx blockchain
 func NewEVMStateDBAdapter(bc Blockchain, blockHeight uint64, blockHash hash.Hash32B, executionIndex uint, executionHash hash.Hash32B) *EVMStateDBAdapter {
+	return newEVMStateDBAdapter(bc, bc.GetFactory(), blockHeight, blockHash, executionIndex, executionHash)
+}
+
+// newEVMStateDBAdapter creates a new state db which reads and writes the states in the given state factory
+func newEVMStateDBAdapter(bc Blockchain, sf state.Factory, blockHeight uint64, blockHash hash.Hash32B, executionIndex uint, executionHash hash.Hash32B) *EVMStateDBAdapter {
 	return &EVMStateDBAdapter{
-		bc,
-		bc.GetFactory(),
-		[]*Log{},
-		nil,
-		blockHeight,
-		blockHash,
-		executionIndex,
-		executionHash,
+		bc:             bc,
+		sf:             sf,
+		logs:           []*Log{},
+		err:            nil,
+		blockHeight:    blockHeight,
+		blockHash:      blockHash,
+		executionIndex: executionIndex,
+		executionHash:  executionHash,
+		createdAccount: make(map[hash.AddrHash]bool),
+		cachedBalance:  make(map[hash.AddrHash]*big.Int),
+		cachedCode:     make(map[hash.AddrHash][]byte),
+		cachedStorage:  make(map[hash.AddrHash]map[hash.Hash32B]hash.Hash32B),
+		suicided:       make(map[hash.AddrHash]bool),
+		journal:        []journalEntry{},
 	}
 }
 
@@ -57,18 +80,13 @@ func (stateDB *EVMStateDBAdapter) Error() error {
 
 // CreateAccount creates an account in iotx blockchain
 func (stateDB *EVMStateDBAdapter) CreateAccount(evmAddr common.Address) {
-	addr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, evmAddr.Bytes())
-	if err != nil {
-		logger.Error().Err(err).Msgf("Failed to generate address for %s", evmAddr.Hex())
-		stateDB.logError(err)
-		return
-	}
-	_, err = stateDB.sf.LoadOrCreateState(addr.RawAddress, 0)
-	if err != nil {
-		logger.Error().Err(err).Msg("CreateAccount")
-		// stateDB.logError(err)
+	if stateDB.Exist(evmAddr) {
+		logger.Debug().Hex("addrHash", evmAddr[:]).Msg("CreateAccount on existing account")
 		return
 	}
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	stateDB.journal = append(stateDB.journal, createAccountChange{addrHash})
+	stateDB.createdAccount[addrHash] = true
 	logger.Debug().Hex("addrHash", evmAddr[:]).Msg("CreateAccount")
 }
 
@@ -77,22 +95,14 @@ func (stateDB *EVMStateDBAdapter) SubBalance(evmAddr common.Address, amount *big
 	if amount.Cmp(big.NewInt(int64(0))) == 0 {
 		return
 	}
-	// stateDB.GetBalance(evmAddr)
 	logger.Debug().Msgf("SubBalance %v from %s", amount, evmAddr.Hex())
-	addr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, evmAddr.Bytes())
-	if err != nil {
-		logger.Error().Err(err).Msgf("Failed to generate address for %s", evmAddr.Hex())
-		stateDB.logError(err)
+	balance := stateDB.GetBalance(evmAddr)
+	if balance.Cmp(amount) < 0 {
+		logger.Error().Hex("addrHash", evmAddr[:]).Msg("SubBalance")
+		stateDB.logError(state.ErrNotEnoughBalance)
 		return
 	}
-	state, err := stateDB.sf.CachedState(addr.RawAddress)
-	if err != nil {
-		logger.Error().Err(err).Msg("SubBalance")
-		stateDB.logError(err)
-		return
-	}
-	state.SubBalance(amount)
-	// stateDB.GetBalance(evmAddr)
+	stateDB.setBalance(byteutil.BytesTo20B(evmAddr[:]), balance.Sub(balance, amount))
 }
 
 // AddBalance adds balance to account
@@ -100,41 +110,34 @@ func (stateDB *EVMStateDBAdapter) AddBalance(evmAddr common.Address, amount *big
 	if amount.Cmp(big.NewInt(int64(0))) == 0 {
 		return
 	}
-	// stateDB.GetBalance(evmAddr)
 	logger.Debug().Msgf("AddBalance %v to %s", amount, evmAddr.Hex())
-
-	addr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, evmAddr.Bytes())
-	if err != nil {
-		logger.Error().Err(err).Msgf("Failed to generate address for %s", evmAddr.Hex())
-		stateDB.logError(err)
-		return
-	}
-	state, err := stateDB.sf.CachedState(addr.RawAddress)
-	if err != nil {
-		logger.Error().Err(err).Hex("addrHash", evmAddr[:]).Msg("AddBalance")
-		stateDB.logError(err)
-		return
-	}
-	state.AddBalance(amount)
-	// stateDB.GetBalance(evmAddr)
+	balance := stateDB.GetBalance(evmAddr)
+	stateDB.setBalance(byteutil.BytesTo20B(evmAddr[:]), balance.Add(balance, amount))
 }
 
 // GetBalance gets the balance of account
 func (stateDB *EVMStateDBAdapter) GetBalance(evmAddr common.Address) *big.Int {
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	if balance, ok := stateDB.cachedBalance[addrHash]; ok {
+		return new(big.Int).Set(balance)
+	}
+	if stateDB.createdAccount[addrHash] {
+		return big.NewInt(0)
+	}
 	addr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, evmAddr.Bytes())
 	if err != nil {
 		logger.Error().Err(err).Msgf("Failed to generate address for %s", evmAddr.Hex())
 		stateDB.logError(err)
-		return nil
+		return big.NewInt(0)
 	}
 	state, err := stateDB.sf.CachedState(addr.RawAddress)
 	if err != nil {
-		logger.Error().Err(err).Msg("GetBalance")
-		return nil
+		logger.Debug().Err(err).Msg("GetBalance")
+		return big.NewInt(0)
 	}
 	logger.Debug().Msgf("Balance of %s is %v", evmAddr.Hex(), state.Balance)
 
-	return state.Balance
+	return new(big.Int).Set(state.Balance)
 }
 
 // GetNonce gets the nonce of account
@@ -145,7 +148,7 @@ func (stateDB *EVMStateDBAdapter) GetNonce(evmAddr common.Address) uint64 {
 		stateDB.logError(err)
 		return 0
 	}
-	nonce, err := stateDB.bc.Nonce(addr.RawAddress)
+	nonce, err := stateDB.sf.Nonce(addr.RawAddress)
 	if err != nil {
 		logger.Error().Err(err).Msg("GetNonce")
 		// stateDB.logError(err)
@@ -163,7 +166,15 @@ func (stateDB *EVMStateDBAdapter) SetNonce(common.Address, uint64) {
 // GetCodeHash gets the code hash of account
 func (stateDB *EVMStateDBAdapter) GetCodeHash(evmAddr common.Address) common.Hash {
 	codeHash := common.Hash{}
-	hash, err := stateDB.sf.GetCodeHash(byteutil.BytesTo20B(evmAddr[:]))
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	if code, ok := stateDB.cachedCode[addrHash]; ok {
+		copy(codeHash[:], hash.Hash256b(code))
+		return codeHash
+	}
+	if stateDB.createdAccount[addrHash] {
+		return codeHash
+	}
+	hash, err := stateDB.sf.GetCodeHash(addrHash)
 	if err != nil {
 		logger.Error().Err(err).Msgf("GetCodeHash")
 		// TODO (zhi) not all err should be logged
@@ -177,7 +188,14 @@ func (stateDB *EVMStateDBAdapter) GetCodeHash(evmAddr common.Address) common.Has
 
 // GetCode gets the code saved in hash
 func (stateDB *EVMStateDBAdapter) GetCode(evmAddr common.Address) []byte {
-	code, err := stateDB.sf.GetCode(byteutil.BytesTo20B(evmAddr[:]))
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	if code, ok := stateDB.cachedCode[addrHash]; ok {
+		return code
+	}
+	if stateDB.createdAccount[addrHash] {
+		return nil
+	}
+	code, err := stateDB.sf.GetCode(addrHash)
 	if err != nil {
 		// TODO: we need to change the log level to error later
 		logger.Debug().Err(err).Msg("GetCode")
@@ -189,10 +207,11 @@ func (stateDB *EVMStateDBAdapter) GetCode(evmAddr common.Address) []byte {
 
 // SetCode sets the code saved in hash
 func (stateDB *EVMStateDBAdapter) SetCode(evmAddr common.Address, code []byte) {
-	if err := stateDB.sf.SetCode(byteutil.BytesTo20B(evmAddr[:]), code); err != nil {
-		logger.Error().Err(err).Msg("SetCode")
-	}
-	logger.Debug().Hex("code", code).Hex("hash", hash.Hash256b(code)[:]).Msg("SetCode")
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	prev, cached := stateDB.cachedCode[addrHash]
+	stateDB.journal = append(stateDB.journal, codeChange{addrHash, prev, cached})
+	stateDB.cachedCode[addrHash] = code
+	logger.Debug().Hex("code", code).Hex("hash", hash.Hash256b(code)).Msg("SetCode")
 }
 
 // GetCodeSize gets the code size saved in hash
@@ -205,21 +224,30 @@ func (stateDB *EVMStateDBAdapter) GetCodeSize(evmAddr common.Address) int {
 	return len(code)
 }
 
-// AddRefund adds refund
-func (stateDB *EVMStateDBAdapter) AddRefund(uint64) {
-	logger.Error().Msg("AddRefund is not implemented")
+// AddRefund adds gas to the refund counter
+func (stateDB *EVMStateDBAdapter) AddRefund(gas uint64) {
+	stateDB.journal = append(stateDB.journal, refundChange{stateDB.refund})
+	stateDB.refund += gas
 }
 
-// GetRefund gets refund
+// GetRefund returns the current value of the refund counter
 func (stateDB *EVMStateDBAdapter) GetRefund() uint64 {
-	logger.Error().Msg("GetRefund is not implemented")
-	return 0
+	return stateDB.refund
 }
 
 // GetState gets state
 func (stateDB *EVMStateDBAdapter) GetState(evmAddr common.Address, k common.Hash) common.Hash {
 	storage := common.Hash{}
-	v, err := stateDB.sf.GetContractState(byteutil.BytesTo20B(evmAddr[:]), byteutil.BytesTo32B(k[:]))
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	key := byteutil.BytesTo32B(k[:])
+	if v, ok := stateDB.cachedStorage[addrHash][key]; ok {
+		copy(storage[:], v[:])
+		return storage
+	}
+	if stateDB.createdAccount[addrHash] {
+		return storage
+	}
+	v, err := stateDB.sf.GetContractState(addrHash, key)
 	if err != nil {
 		logger.Error().Err(err).Msg("GetState")
 		return storage
@@ -231,27 +259,43 @@ func (stateDB *EVMStateDBAdapter) GetState(evmAddr common.Address, k common.Hash
 
 // SetState sets state
 func (stateDB *EVMStateDBAdapter) SetState(evmAddr common.Address, k, v common.Hash) {
-	if err := stateDB.sf.SetContractState(byteutil.BytesTo20B(evmAddr[:]), byteutil.BytesTo32B(k[:]), byteutil.BytesTo32B(v[:])); err != nil {
-		logger.Error().Err(err).Msg("SetState")
-		return
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	key := byteutil.BytesTo32B(k[:])
+	if _, ok := stateDB.cachedStorage[addrHash]; !ok {
+		stateDB.cachedStorage[addrHash] = make(map[hash.Hash32B]hash.Hash32B)
 	}
+	prev, cached := stateDB.cachedStorage[addrHash][key]
+	stateDB.journal = append(stateDB.journal, storageChange{addrHash, key, prev, cached})
+	stateDB.cachedStorage[addrHash][key] = byteutil.BytesTo32B(v[:])
 	logger.Debug().Hex("addrHash", evmAddr[:]).Hex("k", k[:]).Hex("v", v[:]).Msg("SetState")
 }
 
 // Suicide kills the contract
-func (stateDB *EVMStateDBAdapter) Suicide(common.Address) bool {
-	logger.Error().Msg("Suicide is not implemented")
-	return false
+// The remaining balance has already been transferred to the beneficiary by the evm, the contract's code and storage
+// are deleted from the state factory on commit
+func (stateDB *EVMStateDBAdapter) Suicide(evmAddr common.Address) bool {
+	if !stateDB.Exist(evmAddr) {
+		logger.Debug().Hex("addrHash", evmAddr[:]).Msg("Suicide on non-existing account")
+		return false
+	}
+	addrHash := byteutil.BytesTo20B(evmAddr[:])
+	stateDB.journal = append(stateDB.journal, suicideChange{addrHash, stateDB.suicided[addrHash]})
+	stateDB.suicided[addrHash] = true
+	stateDB.setBalance(addrHash, big.NewInt(0))
+	logger.Debug().Hex("addrHash", evmAddr[:]).Msg("Suicide")
+	return true
 }
 
 // HasSuicided returns whether the contract has been killed
-func (stateDB *EVMStateDBAdapter) HasSuicided(common.Address) bool {
-	logger.Error().Msg("HasSuicide is not implemented")
-	return false
+func (stateDB *EVMStateDBAdapter) HasSuicided(evmAddr common.Address) bool {
+	return stateDB.suicided[byteutil.BytesTo20B(evmAddr[:])]
 }
 
 // Exist checks the existence of an address
 func (stateDB *EVMStateDBAdapter) Exist(evmAddr common.Address) bool {
+	if stateDB.createdAccount[byteutil.BytesTo20B(evmAddr[:])] {
+		return true
+	}
 	addr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, evmAddr.Bytes())
 	if err != nil {
 		logger.Error().Err(err).Msgf("Failed to generate address for %s", evmAddr.Hex())
@@ -273,14 +317,22 @@ func (stateDB *EVMStateDBAdapter) Empty(common.Address) bool {
 }
 
 // RevertToSnapshot reverts the state factory to snapshot
-func (stateDB *EVMStateDBAdapter) RevertToSnapshot(int) {
-	logger.Debug().Msg("RevertToSnapshot is not implemented")
+func (stateDB *EVMStateDBAdapter) RevertToSnapshot(snapshot int) {
+	if snapshot < 0 || snapshot > len(stateDB.journal) {
+		logger.Error().Int("snapshot", snapshot).Msg("Invalid snapshot id")
+		return
+	}
+	// undo the changes in reverse order
+	for i := len(stateDB.journal) - 1; i >= snapshot; i-- {
+		stateDB.journal[i].revert(stateDB)
+	}
+	stateDB.journal = stateDB.journal[:snapshot]
+	logger.Debug().Int("snapshot", snapshot).Msg("RevertToSnapshot")
 }
 
 // Snapshot returns the snapshot id
 func (stateDB *EVMStateDBAdapter) Snapshot() int {
-	logger.Debug().Msg("Snapshot is not implemented")
-	return 0
+	return len(stateDB.journal)
 }
 
 // AddLog adds log
@@ -305,6 +357,7 @@ func (stateDB *EVMStateDBAdapter) AddLog(evmLog *types.Log) {
 		stateDB.blockHash,
 		stateDB.executionIndex,
 	}
+	stateDB.journal = append(stateDB.journal, addLogChange{})
 	stateDB.logs = append(stateDB.logs, log)
 }
 
@@ -322,3 +375,163 @@ func (stateDB *EVMStateDBAdapter) AddPreimage(common.Hash, []byte) {
 func (stateDB *EVMStateDBAdapter) ForEachStorage(common.Address, func(common.Hash, common.Hash) bool) {
 	logger.Error().Msg("ForEachStorage is not implemented")
 }
+
+// CommitContracts writes the cached changes into the state factory
+func (stateDB *EVMStateDBAdapter) CommitContracts() error {
+	for addrHash := range stateDB.createdAccount {
+		if _, err := stateDB.loadOrCreateState(addrHash); err != nil {
+			return errors.Wrapf(err, "failed to create account %x", addrHash)
+		}
+	}
+	for addrHash, balance := range stateDB.cachedBalance {
+		state, err := stateDB.loadOrCreateState(addrHash)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load the state of %x", addrHash)
+		}
+		state.Balance = new(big.Int).Set(balance)
+	}
+	for addrHash, code := range stateDB.cachedCode {
+		if stateDB.suicided[addrHash] {
+			continue
+		}
+		if err := stateDB.sf.SetCode(addrHash, code); err != nil {
+			return errors.Wrapf(err, "failed to set code of contract %x", addrHash)
+		}
+	}
+	for addrHash, storage := range stateDB.cachedStorage {
+		if stateDB.suicided[addrHash] {
+			continue
+		}
+		for k, v := range storage {
+			if err := stateDB.sf.SetContractState(addrHash, k, v); err != nil {
+				return errors.Wrapf(err, "failed to set state of contract %x", addrHash)
+			}
+		}
+	}
+	for addrHash := range stateDB.suicided {
+		if err := stateDB.sf.DeleteContract(addrHash); err != nil {
+			return errors.Wrapf(err, "failed to delete contract %x", addrHash)
+		}
+	}
+	stateDB.clearCache()
+	return nil
+}
+
+//======================================
+// private functions
+//======================================
+func (stateDB *EVMStateDBAdapter) setBalance(addrHash hash.AddrHash, balance *big.Int) {
+	prev, cached := stateDB.cachedBalance[addrHash]
+	stateDB.journal = append(stateDB.journal, balanceChange{addrHash, prev, cached})
+	stateDB.cachedBalance[addrHash] = balance
+}
+
+// loadOrCreateState returns the state of the address in state factory, and creates it if not exist yet
+func (stateDB *EVMStateDBAdapter) loadOrCreateState(addrHash hash.AddrHash) (*state.State, error) {
+	addr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, addrHash[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to generate address for %x", addrHash)
+	}
+	// CachedState also looks up the contract cache, which LoadOrCreateState does not
+	s, err := stateDB.sf.CachedState(addr.RawAddress)
+	if errors.Cause(err) == state.ErrAccountNotExist {
+		return stateDB.sf.LoadOrCreateState(addr.RawAddress, 0)
+	}
+	return s, err
+}
+
+func (stateDB *EVMStateDBAdapter) clearCache() {
+	stateDB.createdAccount = make(map[hash.AddrHash]bool)
+	stateDB.cachedBalance = make(map[hash.AddrHash]*big.Int)
+	stateDB.cachedCode = make(map[hash.AddrHash][]byte)
+	stateDB.cachedStorage = make(map[hash.AddrHash]map[hash.Hash32B]hash.Hash32B)
+	stateDB.suicided = make(map[hash.AddrHash]bool)
+	stateDB.refund = 0
+	stateDB.journal = []journalEntry{}
+}
+
+//======================================
+// journal of state changes
+//======================================
+type (
+	// journalEntry is a change to the state cache which can be reverted
+	journalEntry interface {
+		revert(*EVMStateDBAdapter)
+	}
+
+	createAccountChange struct {
+		addrHash hash.AddrHash
+	}
+
+	balanceChange struct {
+		addrHash hash.AddrHash
+		prev     *big.Int
+		cached   bool
+	}
+
+	codeChange struct {
+		addrHash hash.AddrHash
+		prev     []byte
+		cached   bool
+	}
+
+	storageChange struct {
+		addrHash hash.AddrHash
+		key      hash.Hash32B
+		prev     hash.Hash32B
+		cached   bool
+	}
+
+	suicideChange struct {
+		addrHash hash.AddrHash
+		prev     bool
+	}
+
+	addLogChange struct{}
+
+	refundChange struct {
+		prev uint64
+	}
+)
+
+func (c createAccountChange) revert(stateDB *EVMStateDBAdapter) {
+	delete(stateDB.createdAccount, c.addrHash)
+}
+
+func (c balanceChange) revert(stateDB *EVMStateDBAdapter) {
+	if !c.cached {
+		delete(stateDB.cachedBalance, c.addrHash)
+		return
+	}
+	stateDB.cachedBalance[c.addrHash] = c.prev
+}
+
+func (c codeChange) revert(stateDB *EVMStateDBAdapter) {
+	if !c.cached {
+		delete(stateDB.cachedCode, c.addrHash)
+		return
+	}
+	stateDB.cachedCode[c.addrHash] = c.prev
+}
+
+func (c storageChange) revert(stateDB *EVMStateDBAdapter) {
+	if !c.cached {
+		delete(stateDB.cachedStorage[c.addrHash], c.key)
+		return
+	}
+	stateDB.cachedStorage[c.addrHash][c.key] = c.prev
+}
+
+func (c suicideChange) revert(stateDB *EVMStateDBAdapter) {
+	if !c.prev {
+		delete(stateDB.suicided, c.addrHash)
+	}
+}
+
+func (c addLogChange) revert(stateDB *EVMStateDBAdapter) {
+	stateDB.logs = stateDB.logs[:len(stateDB.logs)-1]
+}
+
+func (c refundChange) revert(stateDB *EVMStateDBAdapter) {
+	stateDB.refund = c.prev
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestEVMStateDBAdapter_RevertToSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	cfg := config.Default
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc)
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	_, err := bc.CreateState(ta.Addrinfo["alfa"].RawAddress, 100)
+	require.NoError(err)
+	require.NoError(bc.GetFactory().CommitStateChanges(0, nil, nil, nil, nil))
+
+	alfaHash, err := iotxaddress.GetPubkeyHash(ta.Addrinfo["alfa"].RawAddress)
+	require.NoError(err)
+	alfa := common.BytesToAddress(alfaHash)
+	bravoHash, err := iotxaddress.GetPubkeyHash(ta.Addrinfo["bravo"].RawAddress)
+	require.NoError(err)
+	bravo := common.BytesToAddress(bravoHash)
+
+	stateDB := NewEVMStateDBAdapter(bc, 1, hash.ZeroHash32B, 0, hash.ZeroHash32B)
+	k := common.BytesToHash([]byte("key"))
+	v1 := common.BytesToHash([]byte("value1"))
+	v2 := common.BytesToHash([]byte("value2"))
+
+	// changes before the snapshot
+	stateDB.SubBalance(alfa, big.NewInt(10))
+	stateDB.CreateAccount(bravo)
+	stateDB.SetState(bravo, k, v1)
+	stateDB.AddRefund(5)
+	snapshot := stateDB.Snapshot()
+
+	// changes after the snapshot
+	stateDB.SubBalance(alfa, big.NewInt(20))
+	stateDB.AddBalance(bravo, big.NewInt(20))
+	stateDB.SetState(bravo, k, v2)
+	stateDB.SetCode(bravo, []byte("code"))
+	stateDB.AddLog(&types.Log{Address: bravo})
+	stateDB.AddRefund(10)
+	require.Equal(0, stateDB.GetBalance(alfa).Cmp(big.NewInt(70)))
+	require.Equal(0, stateDB.GetBalance(bravo).Cmp(big.NewInt(20)))
+	require.Equal(v2, stateDB.GetState(bravo, k))
+	require.Equal([]byte("code"), stateDB.GetCode(bravo))
+	require.Equal(1, len(stateDB.Logs()))
+	require.Equal(uint64(15), stateDB.GetRefund())
+
+	stateDB.RevertToSnapshot(snapshot)
+	require.Equal(0, stateDB.GetBalance(alfa).Cmp(big.NewInt(90)))
+	require.Equal(0, stateDB.GetBalance(bravo).Cmp(big.NewInt(0)))
+	require.True(stateDB.Exist(bravo))
+	require.Equal(v1, stateDB.GetState(bravo, k))
+	require.Nil(stateDB.GetCode(bravo))
+	require.Equal(0, len(stateDB.Logs()))
+	require.Equal(uint64(5), stateDB.GetRefund())
+
+	// revert to the very beginning
+	stateDB.RevertToSnapshot(0)
+	require.False(stateDB.Exist(bravo))
+	require.Equal(uint64(0), stateDB.GetRefund())
+	require.Equal(0, stateDB.GetBalance(alfa).Cmp(big.NewInt(100)))
+
+	// nothing is written into state factory before commit
+	stateDB.SubBalance(alfa, big.NewInt(30))
+	balance, err := bc.GetFactory().Balance(ta.Addrinfo["alfa"].RawAddress)
+	require.NoError(err)
+	require.Equal(0, balance.Cmp(big.NewInt(100)))
+	require.NoError(stateDB.CommitContracts())
+	state, err := bc.GetFactory().CachedState(ta.Addrinfo["alfa"].RawAddress)
+	require.NoError(err)
+	require.Equal(0, state.Balance.Cmp(big.NewInt(70)))
+}
+
+func TestEVMStateDBAdapter_Suicide(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+	cfg := config.Default
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc)
+	require.NoError(bc.Start(ctx))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+	sf := bc.GetFactory()
+	_, err := bc.CreateState(ta.Addrinfo["alfa"].RawAddress, 100)
+	require.NoError(err)
+	_, err = bc.CreateState(ta.Addrinfo["bravo"].RawAddress, 50)
+	require.NoError(err)
+
+	alfaHash, err := iotxaddress.GetPubkeyHash(ta.Addrinfo["alfa"].RawAddress)
+	require.NoError(err)
+	alfa := common.BytesToAddress(alfaHash)
+	bravoHash, err := iotxaddress.GetPubkeyHash(ta.Addrinfo["bravo"].RawAddress)
+	require.NoError(err)
+	bravo := common.BytesToAddress(bravoHash)
+	contract := byteutil.BytesTo20B(bravoHash)
+	k := byteutil.BytesTo32B(hash.Hash256b([]byte("key")))
+	v := byteutil.BytesTo32B(hash.Hash256b([]byte("value")))
+	require.NoError(sf.SetCode(contract, []byte("code")))
+	require.NoError(sf.SetContractState(contract, k, v))
+	require.NoError(sf.CommitStateChanges(0, nil, nil, nil, nil))
+	codeHash, err := sf.GetCodeHash(contract)
+	require.NoError(err)
+	require.NotEqual(hash.ZeroHash32B, codeHash)
+
+	stateDB := NewEVMStateDBAdapter(bc, 1, hash.ZeroHash32B, 0, hash.ZeroHash32B)
+	charlie := common.BytesToAddress(hash.Hash160b([]byte("charlie")))
+	require.False(stateDB.Suicide(charlie))
+	require.False(stateDB.HasSuicided(charlie))
+
+	// revert suicide
+	snapshot := stateDB.Snapshot()
+	require.True(stateDB.Suicide(bravo))
+	require.True(stateDB.HasSuicided(bravo))
+	stateDB.RevertToSnapshot(snapshot)
+	require.False(stateDB.HasSuicided(bravo))
+	require.Equal(0, stateDB.GetBalance(bravo).Cmp(big.NewInt(50)))
+
+	// the evm transfers the remaining balance to the beneficiary before calling Suicide
+	stateDB.AddBalance(alfa, stateDB.GetBalance(bravo))
+	require.True(stateDB.Suicide(bravo))
+	require.True(stateDB.HasSuicided(bravo))
+	require.Equal(0, stateDB.GetBalance(bravo).Cmp(big.NewInt(0)))
+	require.Equal(0, stateDB.GetBalance(alfa).Cmp(big.NewInt(150)))
+	// the account is still alive for the rest of the execution
+	require.True(stateDB.Exist(bravo))
+	require.NoError(stateDB.CommitContracts())
+	require.NoError(sf.CommitStateChanges(1, nil, nil, nil, nil))
+
+	state, err := sf.State(ta.Addrinfo["bravo"].RawAddress)
+	require.NoError(err)
+	require.Nil(state.CodeHash)
+	require.Equal(hash.ZeroHash32B, state.Root)
+	require.Equal(0, state.Balance.Cmp(big.NewInt(0)))
+	state, err = sf.State(ta.Addrinfo["alfa"].RawAddress)
+	require.NoError(err)
+	require.Equal(0, state.Balance.Cmp(big.NewInt(150)))
+}
@@ -9,18 +9,22 @@ package blockchain
 import (
 	"github.com/golang/protobuf/proto"
 
+	"github.com/iotexproject/iotex-core/pkg/enc"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/proto"
 )
 
-// Receipt represents the result of a contract
+// Receipt represents the result of an action, which is a transfer, vote or contract execution
 type Receipt struct {
 	ReturnValue     []byte
 	Status          uint64
-	Hash            hash.Hash32B
+	Hash            hash.Hash32B // hash of the action
 	GasConsumed     uint64
 	ContractAddress string
 	Logs            []*Log
+	BlockHeight     uint64
+	BlockHash       hash.Hash32B
+	Index           uint // index of the action in the block, in the order of transfers, votes and executions
 }
 
 // Log stores an evm contract event
@@ -34,6 +38,31 @@ type Log struct {
 	Index       uint
 }
 
+// ByteStream returns a byte stream of the receipt, which is hashed into the receipt root of the block. The block
+// height, block hash and index are not included, since the receipt root is part of the block hash and its leaves are
+// ordered by the index already
+func (receipt *Receipt) ByteStream() []byte {
+	stream := make([]byte, 8)
+	enc.MachineEndian.PutUint64(stream, receipt.Status)
+	stream = append(stream, receipt.Hash[:]...)
+	temp := make([]byte, 8)
+	enc.MachineEndian.PutUint64(temp, receipt.GasConsumed)
+	stream = append(stream, temp...)
+	stream = append(stream, receipt.ContractAddress...)
+	stream = append(stream, receipt.ReturnValue...)
+	for _, log := range receipt.Logs {
+		stream = append(stream, log.Address...)
+		for _, topic := range log.Topics {
+			stream = append(stream, topic[:]...)
+		}
+		stream = append(stream, log.Data...)
+		temp = make([]byte, 8)
+		enc.MachineEndian.PutUint64(temp, uint64(log.Index))
+		stream = append(stream, temp...)
+	}
+	return stream
+}
+
 // ConvertToReceiptPb converts a Receipt to protobuf's ReceiptPb
 func (receipt *Receipt) ConvertToReceiptPb() *iproto.ReceiptPb {
 	r := &iproto.ReceiptPb{}
@@ -46,6 +75,9 @@ func (receipt *Receipt) ConvertToReceiptPb() *iproto.ReceiptPb {
 	for _, log := range receipt.Logs {
 		r.Logs = append(r.Logs, log.ConvertToLogPb())
 	}
+	r.BlockHeight = receipt.BlockHeight
+	r.BlockHash = receipt.BlockHash[:]
+	r.Index = uint32(receipt.Index)
 	return r
 }
 
@@ -62,6 +94,9 @@ func (receipt *Receipt) ConvertFromReceiptPb(pbReceipt *iproto.ReceiptPb) {
 		receipt.Logs[i] = &Log{}
 		receipt.Logs[i].ConvertFromLogPb(log)
 	}
+	receipt.BlockHeight = pbReceipt.GetBlockHeight()
+	copy(receipt.BlockHash[:], pbReceipt.GetBlockHash())
+	receipt.Index = uint(pbReceipt.GetIndex())
 }
 
 // Serialize returns a serialized byte stream for the Receipt
@@ -84,8 +119,8 @@ func (log *Log) ConvertToLogPb() *iproto.LogPb {
 	l := &iproto.LogPb{}
 	l.Address = log.Address
 	l.Topics = [][]byte{}
-	for _, topic := range log.Topics {
-		l.Topics = append(l.Topics, topic[:])
+	for i := range log.Topics {
+		l.Topics = append(l.Topics, log.Topics[i][:])
 	}
 	l.Data = log.Data
 	l.BlockNumber = log.BlockNumber
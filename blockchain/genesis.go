@@ -7,10 +7,13 @@
 package blockchain
 
 import (
+	"context"
 	"encoding/hex"
 	"io/ioutil"
 	"math/big"
+	"time"
 
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
@@ -19,22 +22,41 @@ import (
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/pkg/util/fileutil"
 	"github.com/iotexproject/iotex-core/pkg/version"
+	"github.com/iotexproject/iotex-core/state"
 )
 
 const testnetActionPath = "testnet_actions.yaml"
 
+// ErrInvalidGenesis indicates the genesis is invalid
+var ErrInvalidGenesis = errors.New("invalid genesis")
+
 // Genesis defines the Genesis default settings
 type Genesis struct {
-	ChainID             uint32
-	TotalSupply         uint64
-	BlockReward         uint64
-	Timestamp           uint64
-	ParentHash          hash.Hash32B
-	GenesisCoinbaseData string
-	CreatorAddr         string
-	CreatorPubKey       string
+	ChainID     uint32 `yaml:"chainID"`
+	TotalSupply uint64 `yaml:"totalSupply"`
+	BlockReward uint64 `yaml:"blockReward"`
+	// RewardSchedule changes the block reward from the heights set in it, in ascending order of height
+	RewardSchedule []Reward `yaml:"rewardSchedule"`
+	// MinGasPrice is the lowest gas price an action may pay from the gas fee upgrade on
+	MinGasPrice         uint64       `yaml:"minGasPrice"`
+	Timestamp           uint64       `yaml:"timestamp"`
+	ParentHash          hash.Hash32B `yaml:"-"`
+	GenesisCoinbaseData string       `yaml:"genesisCoinbaseData"`
+	CreatorAddr         string       `yaml:"creatorAddr"`
+	CreatorPubKey       string       `yaml:"creatorPubKey"`
+	// Balances are the initial balances, which are deducted from the total supply held by the creator
+	Balances []Balance `yaml:"balances"`
+	// Delegates are the initial delegates, who nominate themselves in the genesis block
+	Delegates []Nominator `yaml:"delegates"`
+	// Transfers are the transfers signed by the creator in the genesis block
+	Transfers []Transfer `yaml:"transfers"`
+	// Contracts are the contracts deployed in the genesis state
+	Contracts []Contract `yaml:"contracts"`
+	// Consensus is the consensus parameters of the chain
+	Consensus GenesisConsensus `yaml:"consensus"`
 }
 
 // GenesisAction is the root action struct, each package's action should be put as its sub struct
@@ -57,11 +79,42 @@ type Transfer struct {
 	Signature string `yaml:"signature"`
 }
 
+// Reward is the block reward from a height on
+type Reward struct {
+	Height uint64 `yaml:"height"`
+	Amount uint64 `yaml:"amount"`
+}
+
+// Balance is the initial balance of an account
+type Balance struct {
+	Address string `yaml:"address"`
+	Amount  uint64 `yaml:"amount"`
+}
+
+// Contract is a contract deployed in the genesis state
+type Contract struct {
+	Address string `yaml:"address"`
+	Balance uint64 `yaml:"balance"`
+	// Code is the hex encoded runtime code of the contract
+	Code string `yaml:"code"`
+	// Storage maps the hex encoded storage keys of the contract to their hex encoded values
+	Storage map[string]string `yaml:"storage"`
+}
+
+// GenesisConsensus is the consensus parameters of the chain, each of which overrides the one in the config if set
+type GenesisConsensus struct {
+	NumDelegates     uint          `yaml:"numDelegates"`
+	NumSubEpochs     uint          `yaml:"numSubEpochs"`
+	ProposerInterval time.Duration `yaml:"proposerInterval"`
+	DelegateInterval time.Duration `yaml:"delegateInterval"`
+}
+
 // Gen hardcodes genesis default settings
 var Gen = &Genesis{
 	ChainID:             uint32(1),
 	TotalSupply:         uint64(10000000000),
 	BlockReward:         uint64(5),
+	MinGasPrice:         uint64(1),
 	Timestamp:           uint64(1524676419),
 	ParentHash:          hash.Hash32B{},
 	GenesisCoinbaseData: "Connecting the physical world, block by block",
@@ -69,40 +122,241 @@ var Gen = &Genesis{
 	CreatorPubKey:       "d01164c3afe47406728d3e17861a3251dcff39e62bdc2b93ccb69a02785a175e195b5605517fd647eb7dd095b3d862dffb087f35eacf10c6859d04a100dbfb7358eeca9d5c37c904",
 }
 
-// NewGenesisBlock creates a new genesis block
-func NewGenesisBlock(cfg *config.Config) *Block {
+// NewGenesis returns the genesis set in the config. It is loaded from the genesis file if the config sets one,
+// otherwise it is the default genesis Gen with the genesis actions of the config or the testnet ones
+func NewGenesis(cfg *config.Config) (*Genesis, error) {
+	if cfg != nil && cfg.Chain.GenesisPath != "" {
+		return LoadGenesis(cfg.Chain.GenesisPath)
+	}
 	var filePath string
 	if cfg != nil && cfg.Chain.GenesisActionsPath != "" {
 		filePath = cfg.Chain.GenesisActionsPath
 	} else {
 		filePath = fileutil.GetFileAbsPath(testnetActionPath)
 	}
-
 	actionsBytes, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		logger.Fatal().Err(err).Msg("Fail to create genesis block")
+		return nil, errors.Wrapf(err, "failed to read genesis actions from %s", filePath)
 	}
 	actions := GenesisAction{}
 	if err := yaml.Unmarshal(actionsBytes, &actions); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal genesis actions from %s", filePath)
+	}
+	genesis := *Gen
+	genesis.Delegates = actions.SelfNominators
+	genesis.Transfers = actions.Transfers
+	return &genesis, nil
+}
+
+// LoadGenesis loads the genesis from a genesis file, in which the fields not set keep the values of Gen
+func LoadGenesis(path string) (*Genesis, error) {
+	genesisBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read genesis from %s", path)
+	}
+	genesis := *Gen
+	if err := yaml.UnmarshalStrict(genesisBytes, &genesis); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal genesis from %s", path)
+	}
+	if err := genesis.validate(); err != nil {
+		return nil, errors.Wrapf(err, "failed to validate genesis from %s", path)
+	}
+	return &genesis, nil
+}
+
+// Hash returns the hash of the genesis, which the nodes compare to refuse to peer with the nodes of another network. It
+// commits to the hash of the genesis block, the root of the genesis state and the protocol parameters of the genesis in
+// a fixed binary encoding
+func (g *Genesis) Hash() (hash.Hash32B, error) {
+	blk, err := g.Block()
+	if err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to create genesis block")
+	}
+	stateRoot, err := g.stateRoot(blk)
+	if err != nil {
+		return hash.ZeroHash32B, err
+	}
+	blkHash := blk.HashBlock()
+	stream := append(blkHash[:], stateRoot[:]...)
+	stream = append(stream, byteutil.Uint64ToBytes(g.BlockReward)...)
+	stream = append(stream, byteutil.Uint64ToBytes(uint64(len(g.RewardSchedule)))...)
+	for _, reward := range g.RewardSchedule {
+		stream = append(stream, byteutil.Uint64ToBytes(reward.Height)...)
+		stream = append(stream, byteutil.Uint64ToBytes(reward.Amount)...)
+	}
+	stream = append(stream, byteutil.Uint64ToBytes(g.MinGasPrice)...)
+	stream = append(stream, byteutil.Uint64ToBytes(uint64(g.Consensus.NumDelegates))...)
+	stream = append(stream, byteutil.Uint64ToBytes(uint64(g.Consensus.NumSubEpochs))...)
+	stream = append(stream, byteutil.Uint64ToBytes(uint64(g.Consensus.ProposerInterval))...)
+	stream = append(stream, byteutil.Uint64ToBytes(uint64(g.Consensus.DelegateInterval))...)
+	return byteutil.BytesTo32B(hash.Hash256b(stream)), nil
+}
+
+// stateRoot returns the root of the state after the genesis block, which is created in memory with the default config
+// so that it does not depend on the config of the node
+func (g *Genesis) stateRoot(blk *Block) (hash.Hash32B, error) {
+	cfg := config.Default
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	if err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to create state factory")
+	}
+	ctx := context.Background()
+	if err := sf.Start(ctx); err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to start state factory")
+	}
+	defer func() {
+		if err := sf.Stop(ctx); err != nil {
+			logger.Error().Err(err).Msg("Failed to stop state factory")
+		}
+	}()
+	if err := g.createState(sf); err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to create genesis state")
+	}
+	if err := sf.CommitStateChanges(0, blk.Transfers, blk.Votes, nil, nil); err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to commit genesis state")
+	}
+	return sf.RootHash(), nil
+}
+
+// BlockRewardAt returns the block reward of the block at a height
+func (g *Genesis) BlockRewardAt(height uint64) uint64 {
+	reward := g.BlockReward
+	for _, r := range g.RewardSchedule {
+		if r.Height > height {
+			break
+		}
+		reward = r.Amount
+	}
+	return reward
+}
+
+// OverrideConsensus overrides the consensus config with the consensus parameters set in the genesis
+func (g *Genesis) OverrideConsensus(cfg *config.Consensus) {
+	if g.Consensus.NumDelegates != 0 {
+		cfg.RollDPoS.NumDelegates = g.Consensus.NumDelegates
+	}
+	if g.Consensus.NumSubEpochs != 0 {
+		cfg.RollDPoS.NumSubEpochs = g.Consensus.NumSubEpochs
+	}
+	if g.Consensus.ProposerInterval != 0 {
+		cfg.RollDPoS.ProposerInterval = g.Consensus.ProposerInterval
+	}
+	if g.Consensus.DelegateInterval != 0 {
+		cfg.RollDPoS.DelegateInterval = g.Consensus.DelegateInterval
+	}
+}
+
+func (g *Genesis) validate() error {
+	for i := 1; i < len(g.RewardSchedule); i++ {
+		if g.RewardSchedule[i].Height <= g.RewardSchedule[i-1].Height {
+			return errors.Wrap(ErrInvalidGenesis, "reward schedule is not in ascending order of height")
+		}
+	}
+	if g.allocated().Cmp(new(big.Int).SetUint64(g.TotalSupply)) > 0 {
+		return errors.Wrap(ErrInvalidGenesis, "initial balances exceed total supply")
+	}
+	return nil
+}
+
+// allocated returns the sum of the initial balances of the accounts and contracts
+func (g *Genesis) allocated() *big.Int {
+	allocated := big.NewInt(0)
+	for _, balance := range g.Balances {
+		allocated.Add(allocated, new(big.Int).SetUint64(balance.Amount))
+	}
+	for _, contract := range g.Contracts {
+		allocated.Add(allocated, new(big.Int).SetUint64(contract.Balance))
+	}
+	return allocated
+}
+
+// createState creates the genesis state, in which the creator holds the total supply less the initial balances and
+// the contracts are deployed with their code and storage
+func (g *Genesis) createState(sf state.Factory) error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+	if _, err := sf.LoadOrCreateState(g.CreatorAddr, g.TotalSupply-g.allocated().Uint64()); err != nil {
+		return errors.Wrap(err, "failed to add creator into state factory")
+	}
+	for _, balance := range g.Balances {
+		if _, err := sf.LoadOrCreateState(balance.Address, balance.Amount); err != nil {
+			return errors.Wrapf(err, "failed to add initial balance of %s into state factory", balance.Address)
+		}
+	}
+	for _, contract := range g.Contracts {
+		if err := createContract(sf, contract); err != nil {
+			return errors.Wrapf(err, "failed to deploy contract %s", contract.Address)
+		}
+	}
+	return nil
+}
+
+func createContract(sf state.Factory, contract Contract) error {
+	if _, err := sf.LoadOrCreateState(contract.Address, contract.Balance); err != nil {
+		return err
+	}
+	pkHash, err := iotxaddress.GetPubkeyHash(contract.Address)
+	if err != nil {
+		return errors.Wrap(err, "error when getting the pubkey hash")
+	}
+	addr := byteutil.BytesTo20B(pkHash)
+	code, err := hex.DecodeString(contract.Code)
+	if err != nil {
+		return errors.Wrap(err, "failed to decode code")
+	}
+	if err := sf.SetCode(addr, code); err != nil {
+		return err
+	}
+	for k, v := range contract.Storage {
+		key, err := hex.DecodeString(k)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode storage key %s", k)
+		}
+		value, err := hex.DecodeString(v)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode storage value %s", v)
+		}
+		if err := sf.SetContractState(addr, byteutil.BytesTo32B(key), byteutil.BytesTo32B(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewGenesisBlock creates a new genesis block
+func NewGenesisBlock(cfg *config.Config) *Block {
+	genesis, err := NewGenesis(cfg)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Fail to create genesis block")
+	}
+	block, err := genesis.Block()
+	if err != nil {
 		logger.Fatal().Err(err).Msg("Fail to create genesis block")
 	}
+	return block
+}
+
+// Block returns the genesis block, which carries the self-nominations of the initial delegates and the transfers
+// signed by the creator
+func (g *Genesis) Block() (*Block, error) {
 	votes := []*action.Vote{}
-	for _, nominator := range actions.SelfNominators {
+	for _, nominator := range g.Delegates {
 		pubk, err := keypair.DecodePublicKey(nominator.PubKey)
 		if err != nil {
-			logger.Fatal().Err(err).Msg("Fail to create genesis block")
+			return nil, errors.Wrapf(err, "failed to decode public key of delegate %s", nominator.Address)
 		}
 		address, err := iotxaddress.GetAddressByPubkey(iotxaddress.IsTestnet, iotxaddress.ChainID, pubk)
 		if err != nil {
-			logger.Fatal().Err(err).Msg("Fail to create genesis block")
+			return nil, errors.Wrapf(err, "failed to get address of delegate %s", nominator.Address)
 		}
 		sign, err := hex.DecodeString(nominator.Signature)
 		if err != nil {
-			logger.Fatal().Err(err).Msg("Fail to create genesis block")
+			return nil, errors.Wrapf(err, "failed to decode signature of delegate %s", nominator.Address)
 		}
 		vote, err := action.NewVote(0, address.RawAddress, address.RawAddress, 0, big.NewInt(0))
 		if err != nil {
-			logger.Fatal().Err(err).Msg("Fail to create genesis block")
+			return nil, errors.Wrapf(err, "failed to create self-nomination of delegate %s", nominator.Address)
 		}
 		vote.GetVote().SelfPubkey = address.PublicKey[:]
 		vote.Signature = sign
@@ -110,18 +364,18 @@ func NewGenesisBlock(cfg *config.Config) *Block {
 	}
 
 	transfers := []*action.Transfer{}
-	creatorPK, err := keypair.DecodePublicKey(Gen.CreatorPubKey)
+	creatorPK, err := keypair.DecodePublicKey(g.CreatorPubKey)
 	if err != nil {
-		logger.Fatal().Err(err).Msg("Fail to create genesis block")
+		return nil, errors.Wrap(err, "failed to decode public key of creator")
 	}
-	for _, transfer := range actions.Transfers {
+	for _, transfer := range g.Transfers {
 		signature, err := hex.DecodeString(transfer.Signature)
 		if err != nil {
-			logger.Fatal().Err(err).Msg("Fail to create genesis block")
+			return nil, errors.Wrapf(err, "failed to decode signature of transfer to %s", transfer.Recipient)
 		}
-		tsf, err := action.NewTransfer(0, big.NewInt(transfer.Amount), Gen.CreatorAddr, transfer.Recipient, []byte{}, 0, big.NewInt(0))
+		tsf, err := action.NewTransfer(0, big.NewInt(transfer.Amount), g.CreatorAddr, transfer.Recipient, []byte{}, 0, big.NewInt(0))
 		if err != nil {
-			logger.Fatal().Err(err).Msg("Fail to create genesis block")
+			return nil, errors.Wrapf(err, "failed to create transfer to %s", transfer.Recipient)
 		}
 		tsf.SenderPublicKey = creatorPK
 		tsf.Signature = signature
@@ -131,10 +385,10 @@ func NewGenesisBlock(cfg *config.Config) *Block {
 	block := &Block{
 		Header: &BlockHeader{
 			version:       version.ProtocolVersion,
-			chainID:       Gen.ChainID,
+			chainID:       g.ChainID,
 			height:        uint64(0),
-			timestamp:     Gen.Timestamp,
-			prevBlockHash: Gen.ParentHash,
+			timestamp:     g.Timestamp,
+			prevBlockHash: g.ParentHash,
 			txRoot:        hash.ZeroHash32B,
 			stateRoot:     hash.ZeroHash32B,
 			blockSig:      []byte{},
@@ -144,5 +398,5 @@ func NewGenesisBlock(cfg *config.Config) *Block {
 	}
 
 	block.Header.txRoot = block.TxRoot()
-	return block
+	return block, nil
 }
@@ -7,11 +7,24 @@
 package blockchain
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
 )
 
 func TestGenesis(t *testing.T) {
@@ -36,3 +49,111 @@ func TestGenesis(t *testing.T) {
 	assert.Equal(uint64(1524676419), genesisBlk.Header.timestamp)
 	assert.Equal(expectedParentHash, genesisBlk.Header.prevBlockHash)
 }
+
+func TestLoadGenesis(t *testing.T) {
+	require := require.New(t)
+
+	genesisStr := fmt.Sprintf(`
+chainID: 2
+blockReward: 10
+rewardSchedule:
+- height: 10
+  amount: 8
+- height: 20
+  amount: 6
+balances:
+- address: %s
+  amount: 1000
+contracts:
+- address: %s
+  balance: 10
+  code: "6080"
+  storage:
+    "01": "02"
+consensus:
+  numDelegates: 4
+  proposerInterval: 2s
+`,
+		ta.Addrinfo["alfa"].RawAddress,
+		ta.Addrinfo["bravo"].RawAddress,
+	)
+	genesisPath := filepath.Join(os.TempDir(), "genesis.yaml")
+	require.NoError(ioutil.WriteFile(genesisPath, []byte(genesisStr), 0666))
+	defer func() {
+		require.NoError(os.Remove(genesisPath))
+	}()
+
+	genesis, err := LoadGenesis(genesisPath)
+	require.NoError(err)
+	require.Equal(uint32(2), genesis.ChainID)
+	// the fields not set keep the values of the default genesis
+	require.Equal(Gen.TotalSupply, genesis.TotalSupply)
+	require.Equal(Gen.CreatorAddr, genesis.CreatorAddr)
+	require.Equal(uint64(10), genesis.BlockRewardAt(9))
+	require.Equal(uint64(8), genesis.BlockRewardAt(10))
+	require.Equal(uint64(6), genesis.BlockRewardAt(25))
+
+	consensusCfg := config.Default.Consensus
+	genesis.OverrideConsensus(&consensusCfg)
+	require.Equal(uint(4), consensusCfg.RollDPoS.NumDelegates)
+	require.Equal(2*time.Second, consensusCfg.RollDPoS.ProposerInterval)
+	require.Equal(config.Default.Consensus.RollDPoS.NumSubEpochs, consensusCfg.RollDPoS.NumSubEpochs)
+
+	defaultGenesis, err := NewGenesis(nil)
+	require.NoError(err)
+	genesisHash, err := genesis.Hash()
+	require.NoError(err)
+	defaultGenesisHash, err := defaultGenesis.Hash()
+	require.NoError(err)
+	require.NotEqual(defaultGenesisHash, genesisHash)
+	// the hash commits to the genesis state
+	withoutContracts := *genesis
+	withoutContracts.Contracts = nil
+	withoutContractsHash, err := withoutContracts.Hash()
+	require.NoError(err)
+	require.NotEqual(genesisHash, withoutContractsHash)
+	sameHash, err := genesis.Hash()
+	require.NoError(err)
+	require.Equal(genesisHash, sameHash)
+
+	// start a chain from the genesis
+	cfg := config.Default
+	cfg.Chain.GenesisPath = genesisPath
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc)
+	require.NoError(bc.Start(context.Background()))
+	defer func() {
+		require.NoError(bc.Stop(context.Background()))
+	}()
+	blk, err := bc.GetBlockByHeight(0)
+	require.NoError(err)
+	require.Equal(uint32(2), blk.Header.chainID)
+	// the blocks minted carry the chain ID of the genesis
+	blk, err = bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.Equal(uint32(2), blk.Header.chainID)
+	balance, err := bc.Balance(ta.Addrinfo["alfa"].RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(1000), balance)
+	balance, err = bc.Balance(Gen.CreatorAddr)
+	require.NoError(err)
+	require.Equal(new(big.Int).SetUint64(Gen.TotalSupply-1010), balance)
+	pkHash, err := iotxaddress.GetPubkeyHash(ta.Addrinfo["bravo"].RawAddress)
+	require.NoError(err)
+	contract := byteutil.BytesTo20B(pkHash)
+	code, err := bc.GetFactory().GetCode(contract)
+	require.NoError(err)
+	require.Equal([]byte{0x60, 0x80}, code)
+	value, err := bc.GetFactory().GetContractState(contract, byteutil.BytesTo32B([]byte{1}))
+	require.NoError(err)
+	require.Equal(byteutil.BytesTo32B([]byte{2}), value)
+
+	// reject the genesis in which the initial balances exceed the total supply
+	require.NoError(ioutil.WriteFile(genesisPath, []byte("totalSupply: 1000\n"+genesisStr), 0666))
+	_, err = LoadGenesis(genesisPath)
+	require.Equal(ErrInvalidGenesis, errors.Cause(err))
+	// and the one with an unknown field
+	require.NoError(ioutil.WriteFile(genesisPath, []byte("blockRewards: 1\n"), 0666))
+	_, err = LoadGenesis(genesisPath)
+	require.Error(err)
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+const (
+	// MaxLogTopics is the maximum number of topics of a log, which is also the maximum number of topic positions in
+	// a log filter
+	MaxLogTopics = 4
+	// LogBloomSize is the size of the log bloom in bytes
+	LogBloomSize = 256
+	// MaxLogFilterRange is the maximum number of heights in the range of a log filter
+	MaxLogFilterRange = 10000
+)
+
+// ErrInvalidLogFilter indicates the log filter is invalid
+var ErrInvalidLogFilter = errors.New("invalid log filter")
+
+// LogBloom is a 2048-bit bloom filter of the contract addresses and topics of the logs in a block
+type LogBloom [LogBloomSize]byte
+
+// Add adds an address or a topic into the bloom
+func (b *LogBloom) Add(data []byte) {
+	for _, bit := range bloomBits(data) {
+		b[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Test returns false if the address or topic is definitely not in the bloom
+func (b *LogBloom) Test(data []byte) bool {
+	for _, bit := range bloomBits(data) {
+		if b[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// bloomBits returns the 3 bits set in the bloom for the data, which are taken from the first 6 bytes of its hash
+func bloomBits(data []byte) []uint {
+	h := hash.Hash256b(data)
+	bits := make([]uint, 3)
+	for i := range bits {
+		bits[i] = (uint(h[2*i])<<8 | uint(h[2*i+1])) % (LogBloomSize * 8)
+	}
+	return bits
+}
+
+// newLogBloom returns the bloom of the logs in the receipts
+func newLogBloom(receipts []*Receipt) LogBloom {
+	var bloom LogBloom
+	for _, receipt := range receipts {
+		for _, log := range receipt.Logs {
+			bloom.Add([]byte(log.Address))
+			for _, topic := range log.Topics {
+				bloom.Add(topic[:])
+			}
+		}
+	}
+	return bloom
+}
+
+// LogFilter selects the logs on the heights in [FromHeight, ToHeight], which are emitted by any of the Addresses and
+// match the Topics position by position. Empty Addresses match any contract, and an empty set of topics matches any
+// topic on the position, so that Topics [[A], [], [B, C]] matches the logs with A as the first topic, and B or C as
+// the third topic
+type LogFilter struct {
+	FromHeight uint64
+	ToHeight   uint64
+	Addresses  []string
+	Topics     [][]hash.Hash32B
+}
+
+// Validate checks the height range and the number of topic positions of the filter
+func (f *LogFilter) Validate() error {
+	if f.FromHeight > f.ToHeight {
+		return errors.Wrapf(ErrInvalidLogFilter, "from height %d is higher than to height %d", f.FromHeight, f.ToHeight)
+	}
+	if f.ToHeight-f.FromHeight >= MaxLogFilterRange {
+		return errors.Wrapf(
+			ErrInvalidLogFilter,
+			"height range [%d, %d] exceeds limit %d",
+			f.FromHeight,
+			f.ToHeight,
+			MaxLogFilterRange,
+		)
+	}
+	if len(f.Topics) > MaxLogTopics {
+		return errors.Wrapf(ErrInvalidLogFilter, "%d topics exceed limit %d", len(f.Topics), MaxLogTopics)
+	}
+	return nil
+}
+
+// MatchBloom returns false if the block of the bloom definitely has no log matching the filter
+func (f *LogFilter) MatchBloom(bloom *LogBloom) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if bloom.Test([]byte(addr)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range topics {
+			if bloom.Test(topic[:]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns true if the log matches the addresses and topics of the filter, regardless of its height
+func (f *LogFilter) Match(log *Log) bool {
+	if len(f.Addresses) > 0 {
+		found := false
+		for _, addr := range f.Addresses {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	// as in ethereum, the log must have a topic on every position of the filter, even if any topic matches
+	if len(f.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, topics := range f.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		found := false
+		for _, topic := range topics {
+			if topic == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+func TestLogFilter(t *testing.T) {
+	require := require.New(t)
+
+	topic1 := byteutil.BytesTo32B(hash.Hash256b([]byte("topic1")))
+	topic2 := byteutil.BytesTo32B(hash.Hash256b([]byte("topic2")))
+	topic3 := byteutil.BytesTo32B(hash.Hash256b([]byte("topic3")))
+	log1 := &Log{Address: "contract1", Topics: []hash.Hash32B{topic1, topic2}}
+	log2 := &Log{Address: "contract2", Topics: []hash.Hash32B{topic2}}
+
+	bloom := newLogBloom([]*Receipt{{Logs: []*Log{log1}}, {}})
+	require.True(bloom.Test([]byte("contract1")))
+	require.True(bloom.Test(topic1[:]))
+	require.True(bloom.Test(topic2[:]))
+	require.False(bloom.Test([]byte("contract2")))
+	require.False(bloom.Test(topic3[:]))
+	var empty LogBloom
+	require.False(empty.Test(topic1[:]))
+
+	filter := &LogFilter{FromHeight: 2, ToHeight: 1}
+	require.Equal(ErrInvalidLogFilter, errors.Cause(filter.Validate()))
+	filter = &LogFilter{Topics: make([][]hash.Hash32B, MaxLogTopics+1)}
+	require.Equal(ErrInvalidLogFilter, errors.Cause(filter.Validate()))
+	filter = &LogFilter{FromHeight: 1, ToHeight: MaxLogFilterRange}
+	require.NoError(filter.Validate())
+	filter = &LogFilter{FromHeight: 1, ToHeight: MaxLogFilterRange + 1}
+	require.Equal(ErrInvalidLogFilter, errors.Cause(filter.Validate()))
+
+	// any log matches an empty filter
+	filter = &LogFilter{}
+	require.NoError(filter.Validate())
+	require.True(filter.Match(log1))
+	require.True(filter.Match(log2))
+	require.True(filter.MatchBloom(&empty))
+
+	filter = &LogFilter{Addresses: []string{"contract2", "contract3"}}
+	require.False(filter.Match(log1))
+	require.True(filter.Match(log2))
+	require.False(filter.MatchBloom(&bloom))
+
+	// topics are matched by position
+	filter = &LogFilter{Topics: [][]hash.Hash32B{{topic2}}}
+	require.False(filter.Match(log1))
+	require.True(filter.Match(log2))
+	require.True(filter.MatchBloom(&bloom))
+	filter = &LogFilter{Topics: [][]hash.Hash32B{{topic1, topic2}}}
+	require.True(filter.Match(log1))
+	require.True(filter.Match(log2))
+	filter = &LogFilter{Topics: [][]hash.Hash32B{nil, {topic2}}}
+	require.True(filter.Match(log1))
+	require.False(filter.Match(log2))
+	filter = &LogFilter{Topics: [][]hash.Hash32B{nil, {topic3}}}
+	require.False(filter.Match(log1))
+	require.False(filter.MatchBloom(&bloom))
+	// the log must have a topic on every position of the filter
+	filter = &LogFilter{Topics: [][]hash.Hash32B{nil, nil}}
+	require.True(filter.Match(log1))
+	require.False(filter.Match(log2))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/state"
+)
+
+// ExportSnapshot writes the block on the height into w, followed by the state snapshot on the height. The block is
+// serialized as iproto.BlockPb and prefixed by its length in 4-byte big endian
+func ExportSnapshot(bc Blockchain, w io.Writer, height uint64) error {
+	if bc.GetFactory() == nil {
+		return errors.New("statefactory cannot be nil")
+	}
+	blk, err := bc.GetBlockByHeight(height)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get block on height %d", height)
+	}
+	data, err := blk.Serialize()
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize block on height %d", height)
+	}
+	bw := bufio.NewWriter(w)
+	lenBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lenBuf, uint32(len(data)))
+	if _, err := bw.Write(lenBuf); err != nil {
+		return errors.Wrap(err, "failed to write block")
+	}
+	if _, err := bw.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write block")
+	}
+	if err := bc.GetFactory().Snapshot(height, bw); err != nil {
+		return errors.Wrapf(err, "failed to write state snapshot on height %d", height)
+	}
+	return bw.Flush()
+}
+
+// startFromSnapshot bootstraps an empty blockchain from the snapshot file. The block in the snapshot must have the
+// trusted height and hash in config, and the states are loaded only if their root matches the state root in its
+// header. Then the block becomes the tip, so only the subsequent blocks need to be synced
+func (bc *blockchain) startFromSnapshot(path string) error {
+	if bc.sf == nil {
+		return errors.New("statefactory cannot be nil")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open snapshot %s", path)
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	lenBuf := make([]byte, 4)
+	if _, err := io.ReadFull(br, lenBuf); err != nil {
+		return errors.Wrap(state.ErrInvalidSnapshot, "failed to read block length")
+	}
+	size := binary.BigEndian.Uint32(lenBuf)
+	if size > maxArchivedBlockSize {
+		return errors.Wrapf(state.ErrInvalidSnapshot, "block size %d exceeds limit", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return errors.Wrap(state.ErrInvalidSnapshot, "failed to read block")
+	}
+	blk := &Block{}
+	if err := blk.Deserialize(data); err != nil {
+		return errors.Wrapf(state.ErrInvalidSnapshot, "failed to deserialize block: %v", err)
+	}
+	if blk.Height() == 0 {
+		return errors.Wrap(state.ErrInvalidSnapshot, "snapshot on genesis block")
+	}
+	if blk.Height() < bc.config.Chain.Upgrades.StateRootHeight {
+		return errors.Wrapf(state.ErrInvalidSnapshot, "state root of block %d is not verified", blk.Height())
+	}
+	blkHash := blk.HashBlock()
+	if blk.Height() != bc.config.Chain.SnapshotHeight || hex.EncodeToString(blkHash[:]) != bc.config.Chain.SnapshotBlockHash {
+		return errors.Wrapf(
+			state.ErrInvalidSnapshot,
+			"block %x on height %d is not the trusted block %s on height %d",
+			blkHash,
+			blk.Height(),
+			bc.config.Chain.SnapshotBlockHash,
+			bc.config.Chain.SnapshotHeight,
+		)
+	}
+	if err := bc.sf.LoadSnapshot(br, blk.Height(), blk.Header.stateRoot); err != nil {
+		return errors.Wrapf(err, "failed to load state snapshot on height %d", blk.Height())
+	}
+	// the blocks before the snapshot are not in DB except the genesis block
+	genesis, err := bc.genesis.Block()
+	if err != nil {
+		return errors.Wrap(err, "cannot create genesis block")
+	}
+	if err := bc.dao.putBlock(genesis); err != nil {
+		return errors.Wrap(err, "failed to put genesis block")
+	}
+	if err := bc.dao.putBlock(blk); err != nil {
+		return errors.Wrapf(err, "failed to put block on height %d", blk.Height())
+	}
+	bc.tipHeight = blk.Height()
+	bc.tipHash = blkHash
+	logger.Info().Uint64("height", bc.tipHeight).Str("snapshot", path).Msg("Bootstrapped blockchain from snapshot")
+	return nil
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/state"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+const testSnapshotPath = "snapshot.test"
+
+func TestStartFromSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	testutil.CleanupPath(t, testSnapshotPath)
+	defer testutil.CleanupPath(t, testSnapshotPath)
+
+	cfg := config.Default
+	cfg.Chain.Upgrades.StateRootHeight = 1
+	// Disable block reward to make bookkeeping easier
+	Gen.BlockReward = uint64(0)
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.Nil(err)
+	require.NoError(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, Gen.TotalSupply)
+	require.NoError(err)
+	bc := NewBlockchain(&cfg, PrecreatedStateFactoryOption(sf), InMemDaoOption())
+	require.NotNil(bc)
+	require.NoError(bc.Start(context.Background()))
+	defer func() {
+		require.NoError(bc.Stop(context.Background()))
+	}()
+	require.NoError(addTestingTsfBlocks(bc))
+
+	blk, err := bc.GetBlockByHeight(4)
+	require.NoError(err)
+	blkHash := blk.HashBlock()
+	snapshotCfg := cfg
+	snapshotCfg.Chain.SnapshotPath = testSnapshotPath
+	snapshotCfg.Chain.SnapshotHeight = 4
+	snapshotCfg.Chain.SnapshotBlockHash = hex.EncodeToString(blkHash[:])
+	// the state snapshot does not match the state root in the block header
+	data, err := blk.Serialize()
+	require.NoError(err)
+	f, err := os.Create(testSnapshotPath)
+	require.NoError(err)
+	require.NoError(binary.Write(f, binary.BigEndian, uint32(len(data))))
+	_, err = f.Write(data)
+	require.NoError(err)
+	require.NoError(sf.Snapshot(3, f))
+	require.NoError(f.Close())
+	bc2 := NewBlockchain(&snapshotCfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc2)
+	require.Equal(state.ErrInvalidSnapshot, errors.Cause(bc2.Start(context.Background())))
+
+	// the block in the snapshot is not the trusted one
+	f, err = os.Create(testSnapshotPath)
+	require.NoError(err)
+	require.NoError(ExportSnapshot(bc, f, 3))
+	require.NoError(f.Close())
+	bc2 = NewBlockchain(&snapshotCfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc2)
+	require.Equal(state.ErrInvalidSnapshot, errors.Cause(bc2.Start(context.Background())))
+
+	f, err = os.Create(testSnapshotPath)
+	require.NoError(err)
+	require.NoError(ExportSnapshot(bc, f, 4))
+	require.NoError(f.Close())
+
+	bc3 := NewBlockchain(&snapshotCfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc3)
+	require.NoError(bc3.Start(context.Background()))
+	defer func() {
+		require.NoError(bc3.Stop(context.Background()))
+	}()
+	require.Equal(uint64(4), bc3.TipHeight())
+	require.Equal(blk.HashBlock(), bc3.TipHash())
+	_, err = bc3.GetBlockByHeight(0)
+	require.NoError(err)
+	for _, name := range []string{"producer", "alfa", "bravo", "charlie", "delta", "echo", "foxtrot"} {
+		b1, err := bc.Balance(ta.Addrinfo[name].RawAddress)
+		require.NoError(err)
+		b2, err := bc3.Balance(ta.Addrinfo[name].RawAddress)
+		require.NoError(err)
+		require.Equal(b1, b2)
+	}
+
+	// the subsequent blocks are committed on top of the snapshot
+	nonce, err := bc3.Nonce(ta.Addrinfo["alfa"].RawAddress)
+	require.NoError(err)
+	tsf, err := action.NewTransfer(nonce+1, big.NewInt(1), ta.Addrinfo["alfa"].RawAddress, ta.Addrinfo["bravo"].RawAddress, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf, err = tsf.Sign(ta.Addrinfo["alfa"])
+	require.NoError(err)
+	blk, err = bc3.MintNewBlock([]*action.Transfer{tsf}, nil, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.NoError(bc3.ValidateBlock(blk))
+	require.NoError(bc3.CommitBlock(blk))
+	require.Equal(uint64(5), bc3.TipHeight())
+}
+
+func TestStartFromSnapshotRollDPoS(t *testing.T) {
+	require := require.New(t)
+
+	testutil.CleanupPath(t, testSnapshotPath)
+	defer testutil.CleanupPath(t, testSnapshotPath)
+
+	// a Roll-DPoS chain of the 4 genesis delegates in epochs of 4 blocks, which requires the commit certificates from
+	// the height of the snapshot
+	delegates := []*iotxaddress.Address{
+		ta.Addrinfo["alfa"],
+		ta.Addrinfo["bravo"],
+		ta.Addrinfo["charlie"],
+		ta.Addrinfo["delta"],
+	}
+	cfg := config.Default
+	cfg.Chain.Upgrades.StateRootHeight = 1
+	cfg.Chain.Upgrades.CommitCertHeight = 4
+	cfg.Consensus.Scheme = config.RollDPoSScheme
+	cfg.Consensus.RollDPoS.NumDelegates = uint(len(delegates))
+	cfg.Consensus.RollDPoS.NumSubEpochs = 1
+	cfg.Chain.GenesisPath = writeDelegatesGenesis(t, delegates)
+	defer testutil.CleanupPath(t, cfg.Chain.GenesisPath)
+	bc := NewBlockchain(&cfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc)
+	require.NoError(bc.Start(context.Background()))
+	defer func() {
+		require.NoError(bc.Stop(context.Background()))
+	}()
+	mintCertifiedBlock := func(chain Blockchain) *Block {
+		blk, err := chain.MintNewBlock(nil, nil, nil, nil, delegates[0], "")
+		require.NoError(err)
+		blkHash := blk.HashBlock()
+		h := action.ViewChangeHash(blk.Height(), 0, iproto.ViewChangeMsg_VOTE, blkHash, true)
+		blk.CommitCert = &CommitCert{Height: blk.Height()}
+		for _, addr := range delegates[:3] {
+			blk.CommitCert.Endorsements = append(blk.CommitCert.Endorsements, &Endorsement{
+				Endorser:  addr.RawAddress,
+				Signature: crypto.EC283.Sign(addr.PrivateKey, h[:]),
+			})
+		}
+		return blk
+	}
+	for i := 0; i < 4; i++ {
+		require.NoError(bc.CommitBlock(mintCertifiedBlock(bc)))
+	}
+
+	f, err := os.Create(testSnapshotPath)
+	require.NoError(err)
+	require.NoError(ExportSnapshot(bc, f, 4))
+	require.NoError(f.Close())
+	blkHash, err := bc.GetHashByHeight(4)
+	require.NoError(err)
+	snapshotCfg := cfg
+	snapshotCfg.Chain.SnapshotPath = testSnapshotPath
+	snapshotCfg.Chain.SnapshotHeight = 3
+	snapshotCfg.Chain.SnapshotBlockHash = hex.EncodeToString(blkHash[:])
+	// the snapshot in the middle of an epoch misses the candidates the delegates of the next blocks are chosen from
+	require.Equal(config.ErrInvalidCfg, errors.Cause(config.ValidateChain(&snapshotCfg)))
+	snapshotCfg.Chain.SnapshotHeight = 4
+	require.NoError(config.ValidateChain(&snapshotCfg))
+
+	bc2 := NewBlockchain(&snapshotCfg, InMemStateFactoryOption(), InMemDaoOption())
+	require.NotNil(bc2)
+	require.NoError(bc2.Start(context.Background()))
+	defer func() {
+		require.NoError(bc2.Stop(context.Background()))
+	}()
+	require.Equal(uint64(4), bc2.TipHeight())
+
+	// the commit certificates of the blocks after the snapshot are verified against the delegates of the next epoch
+	blk := mintCertifiedBlock(bc2)
+	cert := blk.CommitCert
+	blk.CommitCert = nil
+	require.Equal(ErrInvalidCommitCert, errors.Cause(bc2.ValidateBlock(blk)))
+	blk.CommitCert = cert
+	require.NoError(bc2.ValidateBlock(blk))
+	require.NoError(bc2.CommitBlock(blk))
+	require.Equal(uint64(5), bc2.TipHeight())
+}
+
+// writeDelegatesGenesis writes a genesis file in which the delegates nominate themselves, and returns its path
+func writeDelegatesGenesis(t *testing.T, delegates []*iotxaddress.Address) string {
+	genesisStr := "delegates:\n"
+	for _, addr := range delegates {
+		vote, err := action.NewVote(0, addr.RawAddress, addr.RawAddress, 0, big.NewInt(0))
+		require.NoError(t, err)
+		vote, err = vote.Sign(addr)
+		require.NoError(t, err)
+		genesisStr += fmt.Sprintf(
+			"- pubKey: %s\n  address: %s\n  signature: %s\n",
+			keypair.EncodePublicKey(addr.PublicKey),
+			addr.RawAddress,
+			hex.EncodeToString(vote.Signature),
+		)
+	}
+	genesisFile, err := ioutil.TempFile("", "genesis")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, genesisFile.Close())
+	}()
+	_, err = genesisFile.WriteString(genesisStr)
+	require.NoError(t, err)
+	return genesisFile.Name()
+}
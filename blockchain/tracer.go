@@ -0,0 +1,322 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/core/vm"
+
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+)
+
+// MaxTraceSteps is the maximum number of steps traced in an execution, the steps and calls beyond are not traced
+const MaxTraceSteps = 65536
+
+type (
+	// ExecutionTrace is the trace of the EVM running an execution
+	ExecutionTrace struct {
+		// Receipt is the receipt of replaying the execution
+		Receipt *Receipt
+		// Error is why the execution fails, which is empty if it succeeds
+		Error string
+		// Steps are the opcodes run by the EVM in order, in all the calls
+		Steps []*TraceStep
+		// Truncated is true if the steps beyond MaxTraceSteps are not traced, in which case the calls made by them are
+		// missing, and the gas used by the calls running is counted up to the last step traced
+		Truncated bool
+		// Call is the top level call of the call tree
+		Call *CallFrame
+	}
+
+	// TraceStep is an opcode run by the EVM, along with the stack before running it, and the memory and storage it
+	// changes
+	TraceStep struct {
+		PC      uint64
+		Op      string
+		Gas     uint64
+		GasCost uint64
+		Depth   int
+		Stack   []*big.Int
+		// MemoryDiff is the memory range changed by the opcode, nil if the memory is not changed
+		MemoryDiff *MemoryDiff
+		// StorageWrite is the storage slot written by the opcode, nil if the opcode is not SSTORE
+		StorageWrite *StorageWrite
+		Error        string
+	}
+
+	// MemoryDiff is a range of memory written with data
+	MemoryDiff struct {
+		Offset uint64
+		Data   []byte
+	}
+
+	// StorageWrite is a storage slot written with a value
+	StorageWrite struct {
+		Key   hash.Hash32B
+		Value hash.Hash32B
+	}
+
+	// CallFrame is a call or contract creation in the call tree, where Calls are the ones it makes. GasUsed of a nested
+	// call is the gas it spends on its own opcodes and the calls it makes
+	CallFrame struct {
+		Type    string
+		From    string
+		To      string
+		Value   *big.Int
+		Gas     uint64
+		GasUsed uint64
+		Input   []byte
+		Output  []byte
+		Error   string
+		Calls   []*CallFrame
+	}
+)
+
+// executionTracer implements vm.Tracer, which records the steps and builds the call tree of an execution
+type executionTracer struct {
+	trace *ExecutionTrace
+	// frames are the calls being run, where frames[i] is at depth i+1
+	frames []*CallFrame
+	// lastSteps are the last steps of the calls being run, and memories are their memory as of the last steps
+	lastSteps []*TraceStep
+	memories  [][]byte
+	// pending is the call made by the last step, which is entered if the next step is one level deeper
+	pending *CallFrame
+}
+
+func newExecutionTracer() *executionTracer {
+	return &executionTracer{trace: &ExecutionTrace{}}
+}
+
+// CaptureStart starts the top level call, which creates a contract if create is true
+func (t *executionTracer) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	frame := &CallFrame{
+		Type:  "CALL",
+		From:  rawAddress(from),
+		To:    rawAddress(to),
+		Value: new(big.Int).Set(value),
+		Gas:   gas,
+		Input: common.CopyBytes(input),
+	}
+	if create {
+		frame.Type = "CREATE"
+	}
+	t.trace.Call = frame
+	t.frames = []*CallFrame{frame}
+	t.lastSteps = []*TraceStep{nil}
+	t.memories = [][]byte{nil}
+	return nil
+}
+
+// CaptureState records the step before the EVM runs an opcode
+func (t *executionTracer) CaptureState(
+	env *vm.EVM,
+	pc uint64,
+	op vm.OpCode,
+	gas, cost uint64,
+	memory *vm.Memory,
+	stack *vm.Stack,
+	contract *vm.Contract,
+	depth int,
+	err error,
+) error {
+	if len(t.trace.Steps) >= MaxTraceSteps {
+		t.trace.Truncated = true
+		return nil
+	}
+	// the calls deeper than the step have returned
+	for len(t.frames) > depth {
+		t.exitFrame()
+	}
+	if t.pending != nil {
+		if depth == len(t.frames)+1 {
+			// the step is the first one of the call made by the last step
+			t.pending.To = rawAddress(contract.Address())
+			t.pending.Gas = gas
+			t.frames = append(t.frames, t.pending)
+			t.lastSteps = append(t.lastSteps, nil)
+			t.memories = append(t.memories, nil)
+		}
+		// otherwise the call runs no code, such as a transfer to an account or a call to a precompiled contract
+		t.pending = nil
+	}
+	if len(t.frames) != depth {
+		logger.Warn().Int("depth", depth).Int("frames", len(t.frames)).Msg("Unexpected depth of EVM step")
+		return nil
+	}
+
+	data := memory.Data()
+	i := depth - 1
+	if last := t.lastSteps[i]; last != nil {
+		last.MemoryDiff = diffMemory(t.memories[i], data)
+	}
+	t.memories[i] = common.CopyBytes(data)
+
+	values := stack.Data()
+	step := &TraceStep{
+		PC:      pc,
+		Op:      op.String(),
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   depth,
+		Stack:   make([]*big.Int, 0, len(values)),
+	}
+	for _, value := range values {
+		step.Stack = append(step.Stack, new(big.Int).Set(value))
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	t.lastSteps[i] = step
+	t.trace.Steps = append(t.trace.Steps, step)
+
+	caller := rawAddress(contract.Address())
+	switch step.Op {
+	case "SSTORE":
+		if len(values) >= 2 {
+			step.StorageWrite = &StorageWrite{Key: bigToHash(stack.Back(0)), Value: bigToHash(stack.Back(1))}
+		}
+	case "REVERT":
+		t.frames[i].Error = "execution reverted"
+	case "CALL", "CALLCODE":
+		if len(values) >= 5 {
+			t.call(step.Op, caller, stack.Back(1), stack.Back(2), data, stack.Back(3), stack.Back(4))
+		}
+	case "DELEGATECALL", "STATICCALL":
+		if len(values) >= 4 {
+			t.call(step.Op, caller, stack.Back(1), nil, data, stack.Back(2), stack.Back(3))
+		}
+	case "CREATE", "CREATE2":
+		if len(values) >= 3 {
+			t.call(step.Op, caller, nil, stack.Back(0), data, stack.Back(1), stack.Back(2))
+		}
+	}
+	return nil
+}
+
+// CaptureFault records the error failing the step and its call
+func (t *executionTracer) CaptureFault(
+	env *vm.EVM,
+	pc uint64,
+	op vm.OpCode,
+	gas, cost uint64,
+	memory *vm.Memory,
+	stack *vm.Stack,
+	contract *vm.Contract,
+	depth int,
+	err error,
+) error {
+	if err == nil || depth < 1 || depth > len(t.frames) {
+		return nil
+	}
+	if last := t.lastSteps[depth-1]; last != nil && last.PC == pc {
+		last.Error = err.Error()
+	}
+	t.frames[depth-1].Error = err.Error()
+	return nil
+}
+
+// CaptureEnd ends the top level call
+func (t *executionTracer) CaptureEnd(output []byte, gasUsed uint64, _ time.Duration, err error) error {
+	for len(t.frames) > 1 {
+		t.exitFrame()
+	}
+	if t.trace.Call == nil {
+		return nil
+	}
+	t.trace.Call.Output = common.CopyBytes(output)
+	t.trace.Call.GasUsed = gasUsed
+	if err != nil {
+		t.trace.Call.Error = err.Error()
+	}
+	return nil
+}
+
+// call adds a pending call made by the current call, the value is nil for the calls not transferring value, and the
+// callee is nil for the contract creations, whose address is known once entered
+func (t *executionTracer) call(op string, caller string, callee *big.Int, value *big.Int, memory []byte, inOffset *big.Int, inSize *big.Int) {
+	frame := &CallFrame{
+		Type:  op,
+		From:  caller,
+		Value: big.NewInt(0),
+		Input: memorySlice(memory, inOffset, inSize),
+	}
+	if callee != nil {
+		frame.To = rawAddress(common.BigToAddress(callee))
+	}
+	if value != nil {
+		frame.Value.Set(value)
+	}
+	parent := t.frames[len(t.frames)-1]
+	parent.Calls = append(parent.Calls, frame)
+	t.pending = frame
+}
+
+// exitFrame closes the deepest call, whose gas used is the gas left when it starts minus that after its last step
+func (t *executionTracer) exitFrame() {
+	i := len(t.frames) - 1
+	if last := t.lastSteps[i]; last != nil && t.frames[i].Gas >= last.Gas {
+		t.frames[i].GasUsed = t.frames[i].Gas - last.Gas + last.GasCost
+	}
+	t.frames = t.frames[:i]
+	t.lastSteps = t.lastSteps[:i]
+	t.memories = t.memories[:i]
+}
+
+// diffMemory returns the smallest range of the memory changed, where the expanded memory is compared with zeros. The
+// memory of a call never shrinks, so the current memory is no shorter than the previous one
+func diffMemory(prev []byte, cur []byte) *MemoryDiff {
+	first, last := -1, -1
+	for i := range cur {
+		var old byte
+		if i < len(prev) {
+			old = prev[i]
+		}
+		if old != cur[i] {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	return &MemoryDiff{Offset: uint64(first), Data: common.CopyBytes(cur[first : last+1])}
+}
+
+// memorySlice returns a copy of the memory in the range, which is empty if the range exceeds the memory
+func memorySlice(memory []byte, offset *big.Int, size *big.Int) []byte {
+	if !offset.IsUint64() || !size.IsUint64() {
+		return nil
+	}
+	start, end := offset.Uint64(), offset.Uint64()+size.Uint64()
+	if end < start || end > uint64(len(memory)) {
+		return nil
+	}
+	return common.CopyBytes(memory[start:end])
+}
+
+func bigToHash(value *big.Int) hash.Hash32B {
+	h := common.BigToHash(value)
+	return byteutil.BytesTo32B(h[:])
+}
+
+func rawAddress(addr common.Address) string {
+	iotxAddr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, addr.Bytes())
+	if err != nil {
+		logger.Error().Err(err).Hex("address", addr.Bytes()).Msg("Invalid address in trace")
+		return ""
+	}
+	return iotxAddr.RawAddress
+}
@@ -8,6 +8,10 @@ package blocksync
 
 import (
 	"context"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"math/big"
 	"testing"
 	"time"
 
@@ -18,9 +22,13 @@ import (
 
 	"github.com/iotexproject/iotex-core/actpool"
 	bc "github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/network"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
 	pb "github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blocksync"
@@ -244,7 +252,7 @@ func TestBlockSyncerProcessBlockTipHeight(t *testing.T) {
 	}()
 
 	h := chain.TipHeight()
-	blk, err := chain.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err := chain.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk)
 	require.NoError(err)
 	bs.(*blockSyncer).ackBlockCommit = false
@@ -297,15 +305,15 @@ func TestBlockSyncerProcessBlockOutOfOrder(t *testing.T) {
 	}()
 
 	// commit top
-	blk1, err := chain1.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk1, err := chain1.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk1)
 	require.Nil(err)
 	require.Nil(bs1.ProcessBlock(blk1))
-	blk2, err := chain1.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk2, err := chain1.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk2)
 	require.Nil(err)
 	require.Nil(bs1.ProcessBlock(blk2))
-	blk3, err := chain1.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk3, err := chain1.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk3)
 	require.Nil(err)
 	require.Nil(bs1.ProcessBlock(blk3))
@@ -353,15 +361,15 @@ func TestBlockSyncerProcessBlockSync(t *testing.T) {
 	}()
 
 	// commit top
-	blk1, err := chain1.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk1, err := chain1.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk1)
 	require.NoError(err)
 	require.Nil(bs1.ProcessBlock(blk1))
-	blk2, err := chain1.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk2, err := chain1.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk2)
 	require.NoError(err)
 	require.Nil(bs1.ProcessBlock(blk2))
-	blk3, err := chain1.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk3, err := chain1.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk3)
 	require.NoError(err)
 	require.Nil(bs1.ProcessBlock(blk3))
@@ -403,18 +411,103 @@ func TestBlockSyncerSync(t *testing.T) {
 		testutil.CleanupPath(t, cfg.Chain.TrieDBPath)
 	}()
 
-	blk, err := chain.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err := chain.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk)
 	require.NoError(err)
 	require.Nil(bs.ProcessBlock(blk))
 
-	blk, err = chain.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err = chain.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.NotNil(blk)
 	require.NoError(err)
 	require.Nil(bs.ProcessBlock(blk))
 	time.Sleep(time.Millisecond << 7)
 }
 
+func TestBlockSyncerProcessBlockCommitCert(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	cfg, err := newTestConfig()
+	require.NoError(err)
+	// a full node runs the NOOP scheme, and still verifies the commit certificates of the delegates in the genesis
+	delegates := []*iotxaddress.Address{
+		ta.Addrinfo["alfa"],
+		ta.Addrinfo["bravo"],
+		ta.Addrinfo["charlie"],
+		ta.Addrinfo["delta"],
+	}
+	cfg.NodeType = config.FullNodeType
+	cfg.Chain.Upgrades.CommitCertHeight = 1
+	cfg.Consensus.RollDPoS.NumDelegates = uint(len(delegates))
+	cfg.Chain.GenesisPath = writeDelegatesGenesis(t, delegates)
+	defer testutil.CleanupPath(t, cfg.Chain.GenesisPath)
+	require.NoError(config.ValidateConsensusScheme(cfg))
+
+	chain := bc.NewBlockchain(cfg, bc.InMemStateFactoryOption(), bc.InMemDaoOption())
+	require.NotNil(chain)
+	require.NoError(chain.Start(ctx))
+	ap, err := actpool.NewActPool(chain, cfg.ActPool)
+	require.NoError(err)
+	bs, err := NewBlockSyncer(cfg, chain, ap, network.NewOverlay(&cfg.Network))
+	require.NoError(err)
+	defer func() {
+		require.NoError(chain.Stop(ctx))
+	}()
+
+	blk, err := chain.MintNewBlock(nil, nil, nil, nil, delegates[0], "")
+	require.NoError(err)
+	endorse := func(endorsers []*iotxaddress.Address) *bc.CommitCert {
+		blkHash := blk.HashBlock()
+		h := action.ViewChangeHash(blk.Height(), 0, pb.ViewChangeMsg_VOTE, blkHash, true)
+		cert := &bc.CommitCert{Height: blk.Height()}
+		for _, addr := range endorsers {
+			cert.Endorsements = append(cert.Endorsements, &bc.Endorsement{
+				Endorser:  addr.RawAddress,
+				Signature: crypto.EC283.Sign(addr.PrivateKey, h[:]),
+			})
+		}
+		return cert
+	}
+
+	// the block without the commit certificate is rejected
+	require.NoError(bs.ProcessBlock(blk))
+	require.Equal(uint64(0), chain.TipHeight())
+
+	// the block with a commit certificate forged by the ones who are not delegates is rejected
+	blk.CommitCert = endorse([]*iotxaddress.Address{ta.Addrinfo["producer"], ta.Addrinfo["echo"], ta.Addrinfo["foxtrot"]})
+	require.NoError(bs.ProcessBlock(blk))
+	require.Equal(uint64(0), chain.TipHeight())
+
+	// the block with the commit certificate of more than 2/3 of the delegates is committed
+	blk.CommitCert = endorse(delegates[:3])
+	require.NoError(bs.ProcessBlock(blk))
+	require.Equal(uint64(1), chain.TipHeight())
+}
+
+// writeDelegatesGenesis writes a genesis file in which the delegates nominate themselves, and returns its path
+func writeDelegatesGenesis(t *testing.T, delegates []*iotxaddress.Address) string {
+	genesisStr := "delegates:\n"
+	for _, addr := range delegates {
+		vote, err := action.NewVote(0, addr.RawAddress, addr.RawAddress, 0, big.NewInt(0))
+		require.NoError(t, err)
+		vote, err = vote.Sign(addr)
+		require.NoError(t, err)
+		genesisStr += fmt.Sprintf(
+			"- pubKey: %s\n  address: %s\n  signature: %s\n",
+			keypair.EncodePublicKey(addr.PublicKey),
+			addr.RawAddress,
+			hex.EncodeToString(vote.Signature),
+		)
+	}
+	genesisFile, err := ioutil.TempFile("", "genesis")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, genesisFile.Close())
+	}()
+	_, err = genesisFile.WriteString(genesisStr)
+	require.NoError(t, err)
+	return genesisFile.Name()
+}
+
 func newTestConfig() (*config.Config, error) {
 	cfg := config.Default
 	cfg.Chain.TrieDBPath = "trie.test"
@@ -51,7 +51,7 @@ func TestBlockBufferFlush(t *testing.T) {
 		confirmedHeight: 0,
 	}
 
-	blk, err := chain.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err := chain.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.Nil(err)
 	moved, re := b.Flush(blk)
 	assert.Equal(true, moved)
@@ -137,7 +137,7 @@ func TestBlockBufferGetBlocksIntervalsToSync(t *testing.T) {
 	assert.Len(b.GetBlocksIntervalsToSync(5), 2)
 	assert.Len(b.GetBlocksIntervalsToSync(1), 1)
 
-	blk, err = chain.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err = chain.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.Nil(err)
 	b.Flush(blk)
 	assert.Len(b.GetBlocksIntervalsToSync(0), 0)
@@ -31,7 +31,7 @@ func balance(args []string) string {
 		logger.Error().Err(err).Msg("cannot getexplorer client")
 		return ""
 	}
-	balance, err := client.GetAddressBalance(args[0])
+	balance, err := client.GetAddressBalance(args[0], nil)
 	if err != nil {
 		logger.Error().Err(err).Msgf("cannot get balance for address %s", args[0])
 		return ""
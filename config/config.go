@@ -7,6 +7,7 @@
 package config
 
 import (
+	"encoding/hex"
 	"flag"
 	"os"
 	"time"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
 )
 
@@ -49,6 +51,11 @@ const (
 	StandaloneScheme = "STANDALONE"
 	// NOOPScheme means that the node does not create only block
 	NOOPScheme = "NOOP"
+
+	// ArchiveMode means that the node keeps the state of all heights
+	ArchiveMode = "ARCHIVE"
+	// PruneMode means that the node only keeps the state of the latest heights and prunes the rest in background
+	PruneMode = "PRUNE"
 )
 
 var (
@@ -71,6 +78,7 @@ var (
 			RateLimitEnabled:                    false,
 			RateLimitPerSec:                     10000,
 			RateLimitWindowSize:                 60 * time.Second,
+			InvalidMsgPenalty:                   time.Minute,
 			BootstrapNodes:                      make([]string, 0),
 			TLSEnabled:                          false,
 			CACrtPath:                           "",
@@ -90,14 +98,36 @@ var (
 			ProducerPubKey:          keypair.EncodePublicKey(keypair.ZeroPublicKey),
 			ProducerPrivKey:         keypair.EncodePrivateKey(keypair.ZeroPrivateKey),
 			InMemTest:               false,
+			GenesisPath:             "",
 			GenesisActionsPath:      "",
 			NumCandidates:           101,
 			EnableFallBackToFreshDB: false,
+			PruningMode:             ArchiveMode,
+			NumRetainedHeights:      128,
+			PruningInterval:         time.Minute,
+			SnapshotPath:            "",
+			SnapshotHeight:          0,
+			SnapshotBlockHash:       "",
+			Upgrades: Upgrades{
+				GasFeeHeight:       100000,
+				ChainIDHeight:      100000,
+				ByzantiumHeight:    100000,
+				RevertRefundHeight: 100000,
+				CommitCertHeight:   100000,
+				SlashingHeight:     100000,
+				IndexedTrieHeight:  100000,
+				StateRootHeight:    100000,
+			},
+			Slashing: Slashing{
+				RatePercent: 10,
+				JailEpochs:  2,
+			},
 		},
 		ActPool: ActPool{
-			MaxNumActsPerPool: 32000,
-			MaxNumActsPerAcct: 2000,
-			MaxNumActsToPick:  0,
+			MaxNumActsPerPool:    32000,
+			MaxNumActsPerAcct:    2000,
+			MaxNumActsToPick:     0,
+			ReplacementPriceBump: 10,
 		},
 		Consensus: Consensus{
 			Scheme: NOOPScheme,
@@ -114,8 +144,9 @@ var (
 				NumSubEpochs:           1,
 				EventChanSize:          10000,
 				NumDelegates:           21,
-				EnableDummyBlock:       true,
 				TimeBasedRotation:      false,
+				MaxTTLBackoff:          4,
+				WALPath:                "/tmp/wal.db",
 			},
 			BlockCreationInterval: 10 * time.Second,
 		},
@@ -133,6 +164,11 @@ var (
 			TpsWindow:               10,
 			MaxTransferPayloadBytes: 1024,
 		},
+		EthRPC: EthRPC{
+			Enabled: false,
+			Port:    14014,
+			MaxLogs: 10000,
+		},
 		System: System{
 			HeartbeatInterval: 10 * time.Second,
 			HTTPProfilingPort: 0,
@@ -153,6 +189,7 @@ var (
 		ValidateRollDPoS,
 		ValidateDispatcher,
 		ValidateExplorer,
+		ValidateEthRPC,
 		ValidateNetwork,
 		ValidateActPool,
 		ValidateChain,
@@ -178,6 +215,7 @@ type (
 		RateLimitEnabled                    bool                        `yaml:"rateLimitEnabled"`
 		RateLimitPerSec                     uint64                      `yaml:"rateLimitPerSec"`
 		RateLimitWindowSize                 time.Duration               `yaml:"rateLimitWindowSize"`
+		InvalidMsgPenalty                   time.Duration               `yaml:"invalidMsgPenalty"`
 		BootstrapNodes                      []string                    `yaml:"bootstrapNodes"`
 		TLSEnabled                          bool                        `yaml:"tlsEnabled"`
 		CACrtPath                           string                      `yaml:"caCrtPath"`
@@ -201,10 +239,66 @@ type (
 		ProducerPrivKey string `yaml:"producerPrivKey"`
 
 		// InMemTest creates in-memory DB file for local testing
-		InMemTest               bool   `yaml:"inMemTest"`
+		InMemTest bool `yaml:"inMemTest"`
+		// GenesisPath is the path of the genesis file of the chain, which overrides the default genesis and the
+		// genesis actions of GenesisActionsPath
+		GenesisPath             string `yaml:"genesisPath"`
 		GenesisActionsPath      string `yaml:"genesisActionsPath"`
 		NumCandidates           uint   `yaml:"numCandidates"`
 		EnableFallBackToFreshDB bool   `yaml:"enablefallbacktofreshdb"`
+
+		// PruningMode is either ARCHIVE or PRUNE
+		PruningMode string `yaml:"pruningMode"`
+		// NumRetainedHeights is the number of latest heights whose state is kept in PRUNE mode
+		NumRetainedHeights uint64        `yaml:"numRetainedHeights"`
+		PruningInterval    time.Duration `yaml:"pruningInterval"`
+		// SnapshotPath is the path of a state snapshot to bootstrap the node with an empty chain DB
+		SnapshotPath string `yaml:"snapshotPath"`
+		// SnapshotHeight and SnapshotBlockHash are the trusted height and hex encoded hash of the block in the
+		// snapshot, which must match them to be loaded
+		SnapshotHeight    uint64 `yaml:"snapshotHeight"`
+		SnapshotBlockHash string `yaml:"snapshotBlockHash"`
+		// Upgrades are the heights the protocol upgrades are activated on
+		Upgrades Upgrades `yaml:"upgrades"`
+		// Slashing is the penalty of the delegates whose equivocations are evidenced in the blocks
+		Slashing Slashing `yaml:"slashing"`
+	}
+
+	// Upgrades is the registry of the protocol upgrades, each of which is activated from the height set for it. The
+	// blocks before the height of an upgrade are still processed under the rules before it, so that a node replays the
+	// existing history of a chain deterministically
+	Upgrades struct {
+		// GasFeeHeight is the height from which transfers and votes are charged the fee of their intrinsic gas
+		GasFeeHeight uint64 `yaml:"gasFeeHeight"`
+		// ChainIDHeight is the height from which every action has to be signed with the chain ID
+		ChainIDHeight uint64 `yaml:"chainIDHeight"`
+		// ByzantiumHeight is the height from which the EVM runs the gas table and rules of the Byzantium fork of
+		// Ethereum, instead of the ones of the Frontier fork
+		ByzantiumHeight uint64 `yaml:"byzantiumHeight"`
+		// RevertRefundHeight is the height from which the gas left by an execution the contract reverts is refunded to
+		// the executor, instead of being consumed along with the rest of the gas limit
+		RevertRefundHeight uint64 `yaml:"revertRefundHeight"`
+		// CommitCertHeight is the height from which every block of a Roll-DPoS chain, except the dummy ones, has to carry
+		// the commit certificate of the delegates' votes for it
+		CommitCertHeight uint64 `yaml:"commitCertHeight"`
+		// SlashingHeight is the height from which the evidences of the delegates' equivocations are included in the
+		// blocks, and the offenders are slashed
+		SlashingHeight uint64 `yaml:"slashingHeight"`
+		// IndexedTrieHeight is the height from which the hash of a trie node commits to the index of each child of a
+		// branch, and the lengths of the path and value of a leaf. The states are rehashed on this height
+		IndexedTrieHeight uint64 `yaml:"indexedTrieHeight"`
+		// StateRootHeight is the height from which the state root and receipt root in the header of a block have to
+		// match the ones of re-executing the block
+		StateRootHeight uint64 `yaml:"stateRootHeight"`
+	}
+
+	// Slashing is the config of the penalty of a delegate who signs conflicting consensus messages at the same height
+	Slashing struct {
+		// RatePercent is the percentage of the offender's balance slashed, which lowers the votes of the offender as a
+		// candidate accordingly
+		RatePercent uint64 `yaml:"ratePercent"`
+		// JailEpochs is the number of epochs the offender is removed from the candidates for
+		JailEpochs uint64 `yaml:"jailEpochs"`
 	}
 
 	// Consensus is the config struct for consensus package
@@ -235,8 +329,15 @@ type (
 		NumSubEpochs           uint          `yaml:"numSubEpochs"`
 		EventChanSize          uint          `yaml:"eventChanSize"`
 		NumDelegates           uint          `yaml:"numDelegates"`
-		EnableDummyBlock       bool          `yaml:"enableDummyBlock"`
-		TimeBasedRotation      bool          `yaml:"timeBasedRotation"`
+		// EnableDummyBlock is deprecated and ignored. A height at which the delegates fail to reach consensus is retried
+		// in the next round with the next proposer, instead of being filled with a dummy block
+		EnableDummyBlock  bool `yaml:"enableDummyBlock"`
+		TimeBasedRotation bool `yaml:"timeBasedRotation"`
+		// MaxTTLBackoff is the max number of times the TTLs of accepting the proposal, prevotes and votes are doubled,
+		// once in each round at the same height
+		MaxTTLBackoff uint `yaml:"maxTTLBackoff"`
+		// WALPath is the path of the DB of the consensus write-ahead log, which is required by roll-DPoS
+		WALPath string `yaml:"walPath"`
 	}
 
 	// Dispatcher is the dispatcher config
@@ -254,6 +355,14 @@ type (
 		MaxTransferPayloadBytes uint64 `yaml:"maxTransferPayloadBytes"`
 	}
 
+	// EthRPC is the config of the Ethereum-compatible JSON-RPC gateway
+	EthRPC struct {
+		Enabled bool `yaml:"enabled"`
+		Port    int  `yaml:"port"`
+		// MaxLogs limits how many logs eth_getLogs returns at most
+		MaxLogs uint64 `yaml:"maxLogs"`
+	}
+
 	// System is the system config
 	System struct {
 		HeartbeatInterval time.Duration `yaml:"heartbeatInterval"`
@@ -272,6 +381,9 @@ type (
 		// MaxNumActsToPick indicates maximum number of actions to pick to mint a block. Default is 0, which means no
 		// limit on the number of actions to pick.
 		MaxNumActsToPick uint64 `yaml:"maxNumActsToPick"`
+		// ReplacementPriceBump indicates the minimum percentage of gas price increase for a pending action to be
+		// replaced by a new action with the same nonce
+		ReplacementPriceBump uint64 `yaml:"replacementPriceBump"`
 	}
 
 	// DB is the blotDB config
@@ -290,6 +402,7 @@ type (
 		BlockSync  BlockSync  `yaml:"blockSync"`
 		Dispatcher Dispatcher `yaml:"dispatcher"`
 		Explorer   Explorer   `yaml:"explorer"`
+		EthRPC     EthRPC     `yaml:"ethRPC"`
 		System     System     `yaml:"system"`
 		DB         DB         `yaml:"db"`
 	}
@@ -393,6 +506,43 @@ func ValidateChain(cfg *Config) error {
 	if cfg.Consensus.Scheme == RollDPoSScheme && cfg.Chain.NumCandidates < cfg.Consensus.RollDPoS.NumDelegates {
 		return errors.Wrapf(ErrInvalidCfg, "candidate number should be greater than or equal to delegate number")
 	}
+	switch cfg.Chain.PruningMode {
+	case ArchiveMode:
+	case PruneMode:
+		if cfg.Chain.NumRetainedHeights == 0 {
+			return errors.Wrap(ErrInvalidCfg, "number of retained heights should be greater than 0")
+		}
+		if cfg.Chain.PruningInterval <= 0 {
+			return errors.Wrap(ErrInvalidCfg, "pruning interval should be greater than 0")
+		}
+	default:
+		return errors.Wrapf(ErrInvalidCfg, "unknown pruning mode %s", cfg.Chain.PruningMode)
+	}
+	if cfg.Chain.Slashing.RatePercent > 100 {
+		return errors.Wrap(ErrInvalidCfg, "slashing rate should not be greater than 100 percent")
+	}
+	if cfg.Chain.SnapshotPath != "" {
+		if cfg.Chain.SnapshotHeight == 0 {
+			return errors.Wrap(ErrInvalidCfg, "trusted snapshot height should be greater than 0")
+		}
+		if h, err := hex.DecodeString(cfg.Chain.SnapshotBlockHash); err != nil || len(h) != hash.HashSize {
+			return errors.Wrap(ErrInvalidCfg, "trusted snapshot block hash should be a hex encoded 32-byte hash")
+		}
+		// the snapshot only carries the candidates on its height, from which the delegates of the blocks after it are
+		// chosen only if the height is the end of an epoch
+		numSubEpochs := cfg.Consensus.RollDPoS.NumSubEpochs
+		if numSubEpochs == 0 {
+			numSubEpochs = 1
+		}
+		epochLen := uint64(cfg.Consensus.RollDPoS.NumDelegates) * uint64(numSubEpochs)
+		if epochLen > 0 && cfg.Chain.SnapshotHeight%epochLen != 0 {
+			return errors.Wrapf(
+				ErrInvalidCfg,
+				"trusted snapshot height should be the end of an epoch of %d blocks",
+				epochLen,
+			)
+		}
+	}
 	return nil
 }
 
@@ -430,10 +580,8 @@ func ValidateRollDPoS(cfg *Config) error {
 	if cfg.Consensus.Scheme == RollDPoSScheme && cfg.Consensus.RollDPoS.NumDelegates <= 0 {
 		return errors.Wrap(ErrInvalidCfg, "roll-DPoS event delegate number should be greater than 0")
 	}
-	if cfg.Consensus.Scheme == RollDPoSScheme &&
-		cfg.Consensus.RollDPoS.EnableDummyBlock &&
-		cfg.Consensus.RollDPoS.TimeBasedRotation {
-		return errors.Wrap(ErrInvalidCfg, "roll-DPoS should enable dummy block when doing time based rotation")
+	if cfg.Consensus.Scheme == RollDPoSScheme && cfg.Consensus.RollDPoS.WALPath == "" {
+		return errors.Wrap(ErrInvalidCfg, "roll-DPoS WAL path should not be empty")
 	}
 	return nil
 }
@@ -446,6 +594,18 @@ func ValidateExplorer(cfg *Config) error {
 	return nil
 }
 
+// ValidateEthRPC validates the Ethereum-compatible JSON-RPC gateway configs
+func ValidateEthRPC(cfg *Config) error {
+	// the receipts and logs are only indexed when the explorer is enabled
+	if cfg.EthRPC.Enabled && !cfg.Explorer.Enabled {
+		return errors.Wrap(ErrInvalidCfg, "explorer should be enabled when the eth JSON-RPC gateway is enabled")
+	}
+	if cfg.EthRPC.Enabled && cfg.EthRPC.MaxLogs == 0 {
+		return errors.Wrap(ErrInvalidCfg, "max logs is not a positive integer when the eth JSON-RPC gateway is enabled")
+	}
+	return nil
+}
+
 // ValidateNetwork validates the network configs
 func ValidateNetwork(cfg *Config) error {
 	if !cfg.Network.PeerDiscovery && cfg.Network.TopologyPath == "" {
@@ -197,6 +197,25 @@ func TestValidateExplorer(t *testing.T) {
 	)
 }
 
+func TestValidateEthRPC(t *testing.T) {
+	cfg := Default
+	cfg.EthRPC.Enabled = true
+	err := ValidateEthRPC(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(
+		t,
+		strings.Contains(err.Error(), "explorer should be enabled when the eth JSON-RPC gateway is enabled"),
+	)
+
+	cfg.Explorer.Enabled = true
+	require.NoError(t, ValidateEthRPC(&cfg))
+	cfg.EthRPC.MaxLogs = 0
+	err = ValidateEthRPC(&cfg)
+	require.NotNil(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+}
+
 func TestValidateChain(t *testing.T) {
 	cfg := Default
 	cfg.Chain.NumCandidates = 0
@@ -220,6 +239,52 @@ func TestValidateChain(t *testing.T) {
 		t,
 		strings.Contains(err.Error(), "candidate number should be greater than or equal to delegate number"),
 	)
+
+	cfg = Default
+	cfg.Chain.PruningMode = "UNKNOWN"
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "unknown pruning mode"))
+
+	cfg.Chain.PruningMode = PruneMode
+	cfg.Chain.NumRetainedHeights = 0
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "number of retained heights should be greater than 0"))
+
+	cfg.Chain.NumRetainedHeights = 16
+	require.NoError(t, ValidateChain(&cfg))
+
+	cfg.Chain.Slashing.RatePercent = 101
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "slashing rate should not be greater than 100 percent"))
+
+	cfg = Default
+	cfg.Chain.SnapshotPath = "snapshot"
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "trusted snapshot height should be greater than 0"))
+
+	cfg.Chain.SnapshotHeight = 4
+	cfg.Chain.SnapshotBlockHash = "abcd"
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "trusted snapshot block hash should be a hex encoded 32-byte hash"))
+
+	cfg.Chain.SnapshotBlockHash = strings.Repeat("ab", 32)
+	err = ValidateChain(&cfg)
+	require.Error(t, err)
+	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
+	require.True(t, strings.Contains(err.Error(), "trusted snapshot height should be the end of an epoch of 21 blocks"))
+
+	cfg.Chain.SnapshotHeight = 42
+	require.NoError(t, ValidateChain(&cfg))
 }
 
 func TestValidateConsensusScheme(t *testing.T) {
@@ -289,14 +354,13 @@ func TestValidateRollDPoS(t *testing.T) {
 	)
 
 	cfg.Consensus.RollDPoS.NumDelegates = 1
-	cfg.Consensus.RollDPoS.EnableDummyBlock = true
-	cfg.Consensus.RollDPoS.TimeBasedRotation = true
+	cfg.Consensus.RollDPoS.WALPath = ""
 	err = ValidateRollDPoS(&cfg)
 	require.NotNil(t, err)
 	require.Equal(t, ErrInvalidCfg, errors.Cause(err))
 	require.True(
 		t,
-		strings.Contains(err.Error(), "roll-DPoS should enable dummy block when doing time based rotation"),
+		strings.Contains(err.Error(), "roll-DPoS WAL path should not be empty"),
 	)
 }
 
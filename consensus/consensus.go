@@ -17,6 +17,7 @@ import (
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
 	"github.com/iotexproject/iotex-core/consensus/scheme/rolldpos"
+	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
 	"github.com/iotexproject/iotex-core/pkg/errcode"
@@ -61,7 +62,7 @@ func NewConsensus(
 		if err != nil {
 			return nil, err
 		}
-		blk, err := bc.MintNewBlock(transfers, votes, executions, addr, "")
+		blk, err := bc.MintNewBlock(transfers, votes, executions, nil, addr, "")
 		if err != nil {
 			logger.Error().Msg("Failed to mint a block")
 			return nil, err
@@ -97,12 +98,17 @@ func NewConsensus(
 
 	switch cfg.Consensus.Scheme {
 	case config.RollDPoSScheme:
+		var walStore db.KVStore
+		if cfg.Consensus.RollDPoS.WALPath != "" {
+			walStore = db.NewBoltDB(cfg.Consensus.RollDPoS.WALPath, &cfg.DB)
+		}
 		cs.scheme, err = rolldpos.NewRollDPoSBuilder().
 			SetAddr(addr).
 			SetConfig(cfg.Consensus.RollDPoS).
 			SetBlockchain(bc).
 			SetActPool(ap).
 			SetP2P(p2p).
+			SetWALStore(walStore).
 			Build()
 		if err != nil {
 			logger.Panic().Err(err).Msg("error when constructing RollDPoS")
@@ -0,0 +1,89 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+// ErrEquivocation indicates the message conflicts with another message signed by the same delegate
+var ErrEquivocation = errors.New("the delegate equivocates")
+
+// equivocationDetector keeps the verified view change messages received at the current height and the next one, to
+// detect a delegate signing conflicting messages at the same height and round
+type equivocationDetector struct {
+	mutex sync.Mutex
+	msgs  map[uint64]map[string]*iproto.ViewChangeMsg
+}
+
+func newEquivocationDetector() *equivocationDetector {
+	return &equivocationDetector{msgs: make(map[uint64]map[string]*iproto.ViewChangeMsg)}
+}
+
+// observe records the message, and returns the evidence if the sender has signed a conflicting message before. The
+// message must carry the hash of the block it is about, and its signature must have been verified. Only the messages
+// at the current height and the next one are observed, so that a message at a far height can't evict the others
+func (d *equivocationDetector) observe(msg *iproto.ViewChangeMsg, height uint64) (*action.Evidence, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if msg.Height != height && msg.Height != height+1 {
+		return nil, nil
+	}
+	// The messages of the heights passed are not kept any more
+	for h := range d.msgs {
+		if h < height {
+			delete(d.msgs, h)
+		}
+	}
+	msgs, ok := d.msgs[msg.Height]
+	if !ok {
+		msgs = make(map[string]*iproto.ViewChangeMsg)
+		d.msgs[msg.Height] = msgs
+	}
+	key := fmt.Sprintf("%s-%d-%s", msg.SenderAddr, msg.Round, msg.Vctype)
+	seen, ok := msgs[key]
+	if !ok {
+		msgs[key] = msg
+		return nil, nil
+	}
+	if bytes.Equal(seen.BlockHash, msg.BlockHash) && seen.Decision == msg.Decision {
+		return nil, nil
+	}
+	return action.NewEvidence(seen, msg)
+}
+
+// detectEquivocation checks the verified message against the messages received before at the current height and the
+// next one. If the sender equivocates, the evidence is added into the action pool and broadcast, and ErrEquivocation is
+// returned
+func (ctx *rollDPoSCtx) detectEquivocation(msg *iproto.ViewChangeMsg, height uint64) error {
+	evidence, err := ctx.detector.observe(msg, height)
+	if err != nil {
+		return errors.Wrap(err, "error when creating the evidence")
+	}
+	if evidence == nil {
+		return nil
+	}
+	logger.Warn().
+		Str("offender", evidence.Offender()).
+		Uint64("height", evidence.Height()).
+		Msg("Detected a delegate signing conflicting messages")
+	if err := ctx.actPool.AddEvidence(evidence); err != nil {
+		logger.Error().Err(err).Msg("error when adding the evidence into the action pool")
+	} else if err := ctx.p2p.Broadcast(evidence.ConvertToActionPb()); err != nil {
+		logger.Error().Err(err).Msg("error when broadcasting the evidence")
+	}
+	return errors.Wrapf(ErrEquivocation, "%s signs conflicting %s at height %d", msg.SenderAddr, msg.Vctype, msg.Height)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"testing"
+
+	"github.com/facebookgo/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+func TestEquivocationDetector(t *testing.T) {
+	require := require.New(t)
+
+	d := newEquivocationDetector()
+	prevote := func(height uint64, blkHash hash.Hash32B) *iproto.ViewChangeMsg {
+		evt := newVoteEvt(ePrevote, blkHash, true, testAddrs[0].RawAddress, clock.New())
+		evt.height = height
+		require.NoError(evt.sign(testAddrs[0]))
+		msg, err := evt.toProtoMsg()
+		require.NoError(err)
+		return msg
+	}
+
+	evidence, err := d.observe(prevote(5, hash.Hash32B{1}), 5)
+	require.NoError(err)
+	require.Nil(evidence)
+	// The same message again
+	evidence, err = d.observe(prevote(5, hash.Hash32B{1}), 5)
+	require.NoError(err)
+	require.Nil(evidence)
+
+	// The messages at a far height are ignored, and don't evict the ones at the current height
+	evidence, err = d.observe(prevote(100, hash.Hash32B{1}), 5)
+	require.NoError(err)
+	require.Nil(evidence)
+	evidence, err = d.observe(prevote(100, hash.Hash32B{2}), 5)
+	require.NoError(err)
+	require.Nil(evidence)
+
+	// The messages at the next height are kept apart from the ones at the current height
+	evidence, err = d.observe(prevote(6, hash.Hash32B{2}), 5)
+	require.NoError(err)
+	require.Nil(evidence)
+
+	evidence, err = d.observe(prevote(5, hash.Hash32B{2}), 5)
+	require.NoError(err)
+	require.NotNil(evidence)
+	require.Equal(testAddrs[0].RawAddress, evidence.Offender())
+	require.Equal(uint64(5), evidence.Height())
+
+	// The messages of the next height are still kept once it becomes the current height
+	evidence, err = d.observe(prevote(6, hash.Hash32B{3}), 6)
+	require.NoError(err)
+	require.NotNil(evidence)
+	require.Equal(uint64(6), evidence.Height())
+	require.Equal(2, len(d.msgs))
+	_, ok := d.msgs[5]
+	require.False(ok)
+}
@@ -19,9 +19,12 @@ import (
 	"github.com/zjshen14/go-fsm"
 
 	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/proto"
 )
 
@@ -75,6 +78,8 @@ var (
 	ErrEvtCast = errors.New("error when casting the event")
 	// ErrEvtConvert indicates the error of converting the event from/to the proto message
 	ErrEvtConvert = errors.New("error when converting the event from/to the proto message")
+	// ErrEvtSign indicates the error of signing the event
+	ErrEvtSign = errors.New("error when signing the event")
 
 	// consensusStates is a slice consisting of all consensusEvt states
 	consensusStates = []fsm.State{
@@ -125,10 +130,21 @@ func (e *consensusEvt) fromProtoMsg(pMsg *iproto.ViewChangeMsg) error {
 	return errors.Wrap(ErrEvtConvert, "converting from the proto message is not implemented")
 }
 
+func signViewChange(h hash.Hash32B, signer *iotxaddress.Address) ([]byte, error) {
+	sig := crypto.EC283.Sign(signer.PrivateKey, h[:])
+	if sig == nil {
+		return nil, errors.Wrapf(ErrEvtSign, "failed to sign the view change hash %x", h)
+	}
+	return sig, nil
+}
+
 type proposeBlkEvt struct {
 	consensusEvt
-	block    *blockchain.Block
-	proposer string
+	block     *blockchain.Block
+	proposer  string
+	height    uint64
+	round     uint32
+	signature []byte
 }
 
 func newProposeBlkEvt(block *blockchain.Block, proposer string, c clock.Clock) *proposeBlkEvt {
@@ -139,11 +155,36 @@ func newProposeBlkEvt(block *blockchain.Block, proposer string, c clock.Clock) *
 	}
 }
 
+func (e *proposeBlkEvt) hash() hash.Hash32B {
+	var blkHash hash.Hash32B
+	if e.block != nil {
+		blkHash = e.block.HashBlock()
+	}
+	return action.ViewChangeHash(e.height, e.round, iproto.ViewChangeMsg_PROPOSE, blkHash, false)
+}
+
+func (e *proposeBlkEvt) sign(signer *iotxaddress.Address) error {
+	sig, err := signViewChange(e.hash(), signer)
+	if err != nil {
+		return err
+	}
+	e.signature = sig
+	return nil
+}
+
+func (e *proposeBlkEvt) verify(pubKey keypair.PublicKey) bool {
+	h := e.hash()
+	return crypto.EC283.Verify(pubKey, h[:], e.signature)
+}
+
 func (e *proposeBlkEvt) toProtoMsg() (*iproto.ViewChangeMsg, error) {
 	return &iproto.ViewChangeMsg{
 		Vctype:     iproto.ViewChangeMsg_PROPOSE,
 		Block:      e.block.ConvertToBlockPb(),
 		SenderAddr: e.proposer,
+		Height:     e.height,
+		Round:      e.round,
+		Signature:  e.signature,
 	}, nil
 }
 
@@ -156,14 +197,20 @@ func (e *proposeBlkEvt) fromProtoMsg(pMsg *iproto.ViewChangeMsg) error {
 		e.block.ConvertFromBlockPb(pMsg.Block)
 	}
 	e.proposer = pMsg.SenderAddr
+	e.height = pMsg.Height
+	e.round = pMsg.Round
+	e.signature = pMsg.Signature
 	return nil
 }
 
 type voteEvt struct {
 	consensusEvt
-	blkHash  hash.Hash32B
-	decision bool
-	voter    string
+	blkHash   hash.Hash32B
+	decision  bool
+	voter     string
+	height    uint64
+	round     uint32
+	signature []byte
 }
 
 func newVoteEvt(t fsm.EventType, blkHash hash.Hash32B, decision bool, voter string, c clock.Clock) *voteEvt {
@@ -178,7 +225,7 @@ func newVoteEvt(t fsm.EventType, blkHash hash.Hash32B, decision bool, voter stri
 	}
 }
 
-func (e *voteEvt) toProtoMsg() (*iproto.ViewChangeMsg, error) {
+func (e *voteEvt) vctype() iproto.ViewChangeMsg_ViewChangeType {
 	var vctype iproto.ViewChangeMsg_ViewChangeType
 	switch e.t {
 	case ePrevote:
@@ -186,11 +233,36 @@ func (e *voteEvt) toProtoMsg() (*iproto.ViewChangeMsg, error) {
 	case eVote:
 		vctype = iproto.ViewChangeMsg_VOTE
 	}
+	return vctype
+}
+
+func (e *voteEvt) hash() hash.Hash32B {
+	return action.ViewChangeHash(e.height, e.round, e.vctype(), e.blkHash, e.decision)
+}
+
+func (e *voteEvt) sign(signer *iotxaddress.Address) error {
+	sig, err := signViewChange(e.hash(), signer)
+	if err != nil {
+		return err
+	}
+	e.signature = sig
+	return nil
+}
+
+func (e *voteEvt) verify(pubKey keypair.PublicKey) bool {
+	h := e.hash()
+	return crypto.EC283.Verify(pubKey, h[:], e.signature)
+}
+
+func (e *voteEvt) toProtoMsg() (*iproto.ViewChangeMsg, error) {
 	return &iproto.ViewChangeMsg{
-		Vctype:     vctype,
+		Vctype:     e.vctype(),
 		BlockHash:  e.blkHash[:],
 		SenderAddr: e.voter,
 		Decision:   e.decision,
+		Height:     e.height,
+		Round:      e.round,
+		Signature:  e.signature,
 	}, nil
 }
 
@@ -204,6 +276,9 @@ func (e *voteEvt) fromProtoMsg(pMsg *iproto.ViewChangeMsg) error {
 	copy(e.blkHash[:], pMsg.BlockHash)
 	e.voter = pMsg.SenderAddr
 	e.decision = pMsg.Decision
+	e.height = pMsg.Height
+	e.round = pMsg.Round
+	e.signature = pMsg.Signature
 	return nil
 }
 
@@ -252,7 +327,12 @@ func newConsensusFSM(ctx *rollDPoSCtx) (*cFSM, error) {
 		AddTransition(sDKGGeneration, eGenerateDKG, cm.handleGenerateDKGEvt, []fsm.State{sRoundStart}).
 		AddTransition(sRoundStart, eStartRound, cm.handleStartRoundEvt, []fsm.State{sInitPropose, sAcceptPropose}).
 		AddTransition(sInitPropose, eInitBlock, cm.handleInitBlockEvt, []fsm.State{sAcceptPropose}).
-		AddTransition(sAcceptPropose, eProposeBlock, cm.handleProposeBlockEvt, []fsm.State{sAcceptPrevote}).
+		AddTransition(
+			sAcceptPropose,
+			eProposeBlock,
+			cm.handleProposeBlockEvt,
+			[]fsm.State{sAcceptPropose, sAcceptPrevote},
+		).
 		AddTransition(sAcceptPropose, eProposeBlockTimeout, cm.handleProposeBlockEvt, []fsm.State{sAcceptPrevote}).
 		AddTransition(sAcceptPrevote, ePrevote, cm.handlePrevoteEvt, []fsm.State{sAcceptPrevote, sAcceptVote}).
 		AddTransition(sAcceptPrevote, ePrevoteTimeout, cm.handlePrevoteEvt, []fsm.State{sAcceptVote}).
@@ -285,6 +365,9 @@ func (m *cFSM) Start(c context.Context) error {
 					logger.Debug().Msg("timeoutEvt is stale")
 					continue
 				}
+				if prevoteEvt, ok := evt.(*voteEvt); ok && prevoteEvt.Type() == ePrevote {
+					m.observePrevote(prevoteEvt)
+				}
 				src := m.fsm.CurrentState()
 				if err := m.fsm.Handle(evt); err != nil {
 					if errors.Cause(err) == fsm.ErrTransitionNotFound {
@@ -409,24 +492,46 @@ func (m *cFSM) handleGenerateDKGEvt(_ fsm.Event) (fsm.State, error) {
 }
 
 func (m *cFSM) handleStartRoundEvt(_ fsm.Event) (fsm.State, error) {
-	proposer, height, err := m.ctx.rotatedProposer()
+	proposer, height, round, err := m.ctx.rotatedProposer()
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Msg("error when getting the proposer")
 		return sInvalid, err
 	}
-	m.ctx.round = roundCtx{
-		height:    height,
-		timestamp: m.ctx.clock.Now(),
-		prevotes:  make(map[string]bool),
-		votes:     make(map[string]bool),
-		proposer:  proposer,
+	// The block locked in the current round and the POLs are carried over to the next round at the same height
+	var lockedBlock *blockchain.Block
+	var lockedRound uint32
+	pols := make(proofsOfLock)
+	if round > 0 {
+		if m.ctx.round.height == height && m.ctx.round.pols != nil {
+			pols = m.ctx.round.pols
+		}
+		if lockedBlock, lockedRound, err = m.restoreLock(height); err != nil {
+			logger.Error().
+				Err(err).
+				Uint64("height", height).
+				Msg("error when restoring the locked block")
+			return sInvalid, err
+		}
 	}
+	m.ctx.setRound(roundCtx{
+		height:       height,
+		num:          round,
+		timestamp:    m.ctx.clock.Now(),
+		lockedBlock:  lockedBlock,
+		lockedRound:  lockedRound,
+		pols:         pols,
+		prevotes:     make(map[string]bool),
+		votes:        make(map[string]bool),
+		endorsements: make(map[string][]byte),
+		proposer:     proposer,
+	})
 	if proposer == m.ctx.addr.RawAddress {
 		logger.Info().
 			Str("proposer", proposer).
 			Uint64("height", height).
+			Uint32("round", round).
 			Msg("current node is the proposer")
 		m.produce(m.newCEvt(eInitBlock), 0)
 		// TODO: we may need timeout event for block producer too
@@ -435,18 +540,49 @@ func (m *cFSM) handleStartRoundEvt(_ fsm.Event) (fsm.State, error) {
 	logger.Info().
 		Str("proposer", proposer).
 		Uint64("height", height).
+		Uint32("round", round).
 		Msg("current node is not the proposer")
+	// Replay the proposal received before the node restarted
+	if proposeBlkEvt := m.recordedProposeBlkEvt(); proposeBlkEvt != nil {
+		m.produce(proposeBlkEvt, 0)
+	}
 	// Setup timeout for waiting for proposed block
-	m.produce(m.newTimeoutEvt(eProposeBlockTimeout, m.ctx.round.height), m.ctx.cfg.AcceptProposeTTL)
+	m.produce(m.newTimeoutEvt(eProposeBlockTimeout, m.ctx.round.height), m.ctx.ttl(m.ctx.cfg.AcceptProposeTTL))
 	return sAcceptPropose, nil
 }
 
 func (m *cFSM) handleInitBlockEvt(evt fsm.Event) (fsm.State, error) {
-	blk, err := m.ctx.mintBlock()
-	if err != nil {
-		return sInvalid, errors.Wrap(err, "error when minting a block")
+	// Propose the block proposed before the node restarted again, instead of a conflicting one
+	proposeBlkEvt := m.recordedProposeBlkEvt()
+	if proposeBlkEvt == nil {
+		var blk *blockchain.Block
+		var err error
+		if m.ctx.round.lockedBlock != nil {
+			// Re-propose the block locked in an earlier round at the height
+			logger.Info().
+				Uint64("height", m.ctx.round.height).
+				Uint32("round", m.ctx.round.num).
+				Msg("re-propose the locked block")
+			blk = m.ctx.round.lockedBlock
+		} else {
+			if blk, err = m.ctx.mintBlock(); err != nil {
+				return sInvalid, errors.Wrap(err, "error when minting a block")
+			}
+		}
+		if proposeBlkEvt, err = m.newProposeBlkEvt(blk); err != nil {
+			logger.Error().
+				Err(err).
+				Uint64("height", m.ctx.round.height).
+				Msg("error when proposing the block, skip proposing")
+			// Wait for the proposal to time out as the other delegates do
+			m.produce(m.newTimeoutEvt(eProposeBlockTimeout, m.ctx.round.height), m.ctx.ttl(m.ctx.cfg.AcceptProposeTTL))
+			return sAcceptPropose, nil
+		}
+		if m.ctx.round.lockedBlock != nil {
+			// Broadcast the POL of the block ahead of it, for the delegates locked on another block
+			m.broadcastPOL()
+		}
 	}
-	proposeBlkEvt := m.newProposeBlkEvt(blk)
 	proposeBlkEvtProto, err := proposeBlkEvt.toProtoMsg()
 	if err != nil {
 		return sInvalid, errors.Wrap(err, "error when converting a proposeBlkEvt into a proto msg")
@@ -472,7 +608,11 @@ func (m *cFSM) handleProposeBlockEvt(evt fsm.Event) (fsm.State, error) {
 		if !ok {
 			return sInvalid, errors.Wrap(ErrEvtCast, "the event is not a proposeBlkEvt")
 		}
-		proposer, err := m.ctx.calcProposer(proposeBlkEvt.block.Height(), m.ctx.epoch.delegates)
+		if !m.matchRound(proposeBlkEvt, proposeBlkEvt.height, proposeBlkEvt.round) {
+			// Keep waiting for the proposal of the current round
+			return sAcceptPropose, nil
+		}
+		proposer, err := m.ctx.calcProposer(proposeBlkEvt.block.Height(), m.ctx.round.num, m.ctx.epoch.delegates)
 		if err != nil {
 			return sInvalid, errors.Wrap(err, "error when calculating the proposer")
 		}
@@ -488,7 +628,7 @@ func (m *cFSM) handleProposeBlockEvt(evt fsm.Event) (fsm.State, error) {
 			validated = false
 		} else if proposeBlkEvt.proposer != m.ctx.addr.RawAddress {
 			// If the block is self proposed, skip validation
-			if err := m.ctx.chain.ValidateBlock(proposeBlkEvt.block); err != nil {
+			if err := m.ctx.chain.ValidateProposal(proposeBlkEvt.block); err != nil {
 				logger.Error().
 					Str("proposer", proposeBlkEvt.proposer).
 					Uint64("block", proposeBlkEvt.block.Height()).
@@ -510,7 +650,45 @@ func (m *cFSM) handleProposeBlockEvt(evt fsm.Event) (fsm.State, error) {
 				}
 			}
 		}
+		// Prevote no for any block other than the one locked in an earlier round at the height, unless the block has a
+		// POL in a round after the one the node is locked in, in which case the node is locked on the block instead
+		lockedBlock := m.ctx.round.lockedBlock
+		if validated && lockedBlock != nil && lockedBlock.HashBlock() != blkHash {
+			if polRound, ok := m.polRound(blkHash); ok {
+				if err := m.ctx.wal.lock(proposeBlkEvt.block, polRound); err != nil {
+					logger.Error().
+						Err(err).
+						Uint64("height", m.ctx.round.height).
+						Msg("error when writing the locked block into the WAL")
+					validated = false
+				} else {
+					logger.Info().
+						Uint64("block", proposeBlkEvt.block.Height()).
+						Str("hash", hex.EncodeToString(blkHash[:])).
+						Uint32("polRound", polRound).
+						Uint32("lockedRound", m.ctx.round.lockedRound).
+						Msg("unlock from the locked block by the POL of the proposed block")
+					m.ctx.round.lockedBlock = proposeBlkEvt.block
+					m.ctx.round.lockedRound = polRound
+				}
+			} else {
+				logger.Warn().
+					Str("proposer", proposeBlkEvt.proposer).
+					Uint64("block", proposeBlkEvt.block.Height()).
+					Str("hash", hex.EncodeToString(blkHash[:])).
+					Msg("the proposed block is not the locked one")
+				validated = false
+			}
+		}
 		m.ctx.round.block = proposeBlkEvt.block
+		if proposeBlkEvt.proposer == proposer {
+			if err := m.appendWAL(proposeBlkEvt); err != nil {
+				logger.Error().
+					Err(err).
+					Uint64("height", m.ctx.round.height).
+					Msg("error when writing the proposal into the WAL")
+			}
+		}
 	case eProposeBlockTimeout:
 		received = false
 		validated = false
@@ -521,33 +699,44 @@ func (m *cFSM) handleProposeBlockEvt(evt fsm.Event) (fsm.State, error) {
 	}
 
 	if received {
-		prevoteEvt := m.newPrevoteEvt(m.ctx.round.block.HashBlock(), validated)
-		prevoteEvtProto, err := prevoteEvt.toProtoMsg()
+		prevoteEvt, err := m.newPrevoteEvt(m.ctx.round.block.HashBlock(), validated)
 		if err != nil {
-			return sInvalid, errors.Wrap(err, "error when converting a prevoteEvt into a proto msg")
-		}
-		// Notify itself
-		m.produce(prevoteEvt, 0)
-		// Notify other delegates
-		if err := m.ctx.p2p.Broadcast(prevoteEvtProto); err != nil {
 			logger.Error().
 				Err(err).
-				Msg("error when broadcasting prevoteEvtProto")
+				Uint64("height", m.ctx.round.height).
+				Msg("error when signing the prevote, skip prevoting")
+		} else {
+			prevoteEvtProto, err := prevoteEvt.toProtoMsg()
+			if err != nil {
+				return sInvalid, errors.Wrap(err, "error when converting a prevoteEvt into a proto msg")
+			}
+			// Notify itself
+			m.produce(prevoteEvt, 0)
+			// Notify other delegates
+			if err := m.ctx.p2p.Broadcast(prevoteEvtProto); err != nil {
+				logger.Error().
+					Err(err).
+					Msg("error when broadcasting prevoteEvtProto")
+			}
 		}
 	}
 	// Setup timeout for waiting for prevote
-	m.produce(m.newTimeoutEvt(ePrevoteTimeout, m.ctx.round.height), m.ctx.cfg.AcceptPrevoteTTL)
+	m.produce(m.newTimeoutEvt(ePrevoteTimeout, m.ctx.round.height), m.ctx.ttl(m.ctx.cfg.AcceptPrevoteTTL))
 	return sAcceptPrevote, nil
 }
 
 func (m *cFSM) handlePrevoteEvt(evt fsm.Event) (fsm.State, error) {
 	var vEvt *voteEvt
+	var err error
 	switch evt.Type() {
 	case ePrevote:
 		prevoteEvt, ok := evt.(*voteEvt)
 		if !ok {
 			return sInvalid, errors.Wrap(ErrEvtCast, "the event is not a voteEvt")
 		}
+		if !m.matchRound(prevoteEvt, prevoteEvt.height, prevoteEvt.round) {
+			return sAcceptPrevote, nil
+		}
 		var blkHash hash.Hash32B
 		if m.ctx.round.block != nil {
 			blkHash = m.ctx.round.block.HashBlock()
@@ -558,9 +747,21 @@ func (m *cFSM) handlePrevoteEvt(evt fsm.Event) (fsm.State, error) {
 		// if ether yes or no is true, block must exists and blkHash must be a valid one
 		yes, no := m.ctx.calcQuorum(m.ctx.round.prevotes)
 		if yes {
-			vEvt = m.newVoteEvt(blkHash, true)
+			// Lock on the block, which is re-proposed in the later rounds at the height if no block is committed. The
+			// lock is persisted before voting, so that it survives a restart
+			m.ctx.round.lockedBlock = m.ctx.round.block
+			m.ctx.round.lockedRound = m.ctx.round.num
+			if err = m.ctx.wal.lock(m.ctx.round.block, m.ctx.round.num); err == nil {
+				vEvt, err = m.newVoteEvt(blkHash, true)
+			}
 		} else if no {
-			vEvt = m.newVoteEvt(blkHash, false)
+			vEvt, err = m.newVoteEvt(blkHash, false)
+		}
+		if err != nil {
+			logger.Error().
+				Err(err).
+				Uint64("height", m.ctx.round.height).
+				Msg("error when signing the vote, skip voting")
 		}
 		if vEvt == nil {
 			// Wait for more prevotes to come
@@ -569,7 +770,12 @@ func (m *cFSM) handlePrevoteEvt(evt fsm.Event) (fsm.State, error) {
 		// Reached the agreement
 	case ePrevoteTimeout:
 		if m.ctx.round.block != nil {
-			vEvt = m.newVoteEvt(m.ctx.round.block.HashBlock(), false)
+			if vEvt, err = m.newVoteEvt(m.ctx.round.block.HashBlock(), false); err != nil {
+				logger.Error().
+					Err(err).
+					Uint64("height", m.ctx.round.height).
+					Msg("error when signing the vote, skip voting")
+			}
 		}
 		logger.Warn().
 			Uint64("height", m.ctx.round.height).
@@ -591,7 +797,7 @@ func (m *cFSM) handlePrevoteEvt(evt fsm.Event) (fsm.State, error) {
 		}
 	}
 	// Setup timeout for waiting for vote
-	m.produce(m.newTimeoutEvt(eVoteTimeout, m.ctx.round.height), m.ctx.cfg.AcceptVoteTTL)
+	m.produce(m.newTimeoutEvt(eVoteTimeout, m.ctx.round.height), m.ctx.ttl(m.ctx.cfg.AcceptVoteTTL))
 	return sAcceptVote, nil
 }
 
@@ -605,12 +811,30 @@ func (m *cFSM) handleVoteEvt(evt fsm.Event) (fsm.State, error) {
 		if !ok {
 			return sInvalid, errors.Wrap(ErrEvtCast, "the event is not a voteEvt")
 		}
+		if !m.matchRound(voteEvt, voteEvt.height, voteEvt.round) {
+			return sAcceptVote, nil
+		}
 		var blkHash hash.Hash32B
 		if m.ctx.round.block != nil {
 			blkHash = m.ctx.round.block.HashBlock()
 		}
 		if bytes.Equal(blkHash[:], voteEvt.blkHash[:]) {
+			// A yes vote is counted only if it's a valid endorsement, so that the commit certificate made up of the
+			// endorsements is valid once the consensus is reached
+			if voteEvt.decision && !m.ctx.isEndorsement(voteEvt) {
+				logger.Warn().
+					Str("voter", voteEvt.voter).
+					Uint64("height", voteEvt.height).
+					Uint32("round", voteEvt.round).
+					Msg("the yes vote is not a valid endorsement of the block")
+				return sAcceptVote, nil
+			}
 			m.ctx.round.votes[voteEvt.voter] = voteEvt.decision
+			if voteEvt.decision {
+				m.ctx.round.endorsements[voteEvt.voter] = voteEvt.signature
+			} else {
+				delete(m.ctx.round.endorsements, voteEvt.voter)
+			}
 		}
 		// if ether yes or no is true, block must exists and blkHash must be a valid one
 		yes, no := m.ctx.calcQuorum(m.ctx.round.votes)
@@ -630,57 +854,53 @@ func (m *cFSM) handleVoteEvt(evt fsm.Event) (fsm.State, error) {
 			Int("votes", len(m.ctx.round.votes)).
 			Msg("didn't collect enough votes before timeout")
 	}
-	var pendingBlock *blockchain.Block
-	if consensus {
-		pendingBlock = m.ctx.round.block
-		logger.Info().
-			Uint64("block", pendingBlock.Height()).
-			Msg("consensus reached")
-		consensusMtc.WithLabelValues("true").Inc()
-	} else {
-		var height uint64
-		if m.ctx.round.block != nil {
-			height = m.ctx.round.block.Height()
-		}
+	if !consensus {
 		logger.Warn().
-			Uint64("block", height).
+			Uint64("height", m.ctx.round.height).
+			Uint32("round", m.ctx.round.num).
 			Bool("timeout", timeout).
 			Bool("disagreement", disagreement).
-			Msg("consensus did not reach")
+			Msg("consensus did not reach, start the next round at the height")
 		consensusMtc.WithLabelValues("false").Inc()
-		if m.ctx.cfg.EnableDummyBlock {
-			pendingBlock = m.ctx.chain.MintNewDummyBlock()
-			logger.Warn().
-				Uint64("block", pendingBlock.Height()).
-				Msg("dummy block is generated")
-		}
+		// Start the next round at the same height with the next proposer right away
+		m.produce(m.newCEvt(eStartRound), 0)
+		return sRoundStart, nil
 	}
-	if pendingBlock != nil {
-		// Commit and broadcast the pending block
-		if err := m.ctx.chain.CommitBlock(pendingBlock); err != nil {
+	pendingBlock := m.ctx.round.block
+	pendingBlock.CommitCert = m.ctx.round.commitCert()
+	logger.Info().
+		Uint64("block", pendingBlock.Height()).
+		Uint32("round", m.ctx.round.num).
+		Msg("consensus reached")
+	consensusMtc.WithLabelValues("true").Inc()
+	// Commit and broadcast the pending block
+	if err := m.ctx.chain.CommitBlock(pendingBlock); err != nil {
+		logger.Error().
+			Err(err).
+			Uint64("block", pendingBlock.Height()).
+			Msg("error when committing a block")
+	}
+	// The messages recorded at the committed height are not needed any more
+	if err := m.ctx.wal.prune(pendingBlock.Height() + 1); err != nil {
+		logger.Error().
+			Err(err).
+			Uint64("block", pendingBlock.Height()).
+			Msg("error when pruning the WAL")
+	}
+	// Remove transfers in this block from ActPool and reset ActPool state
+	m.ctx.actPool.Reset()
+	// Broadcast the committed block to the network
+	if blkProto := pendingBlock.ConvertToBlockPb(); blkProto != nil {
+		if err := m.ctx.p2p.Broadcast(blkProto); err != nil {
 			logger.Error().
 				Err(err).
 				Uint64("block", pendingBlock.Height()).
-				Bool("dummy", pendingBlock.IsDummyBlock()).
-				Msg("error when committing a block")
-		}
-		// Remove transfers in this block from ActPool and reset ActPool state
-		m.ctx.actPool.Reset()
-		// Broadcast the committed block to the network
-		if blkProto := pendingBlock.ConvertToBlockPb(); blkProto != nil {
-			if err := m.ctx.p2p.Broadcast(blkProto); err != nil {
-				logger.Error().
-					Err(err).
-					Uint64("block", pendingBlock.Height()).
-					Bool("dummy", pendingBlock.IsDummyBlock()).
-					Msg("error when broadcasting blkProto")
-			}
-		} else {
-			logger.Error().
-				Uint64("block", pendingBlock.Height()).
-				Bool("dummy", pendingBlock.IsDummyBlock()).
-				Msg("error when converting a block into a proto msg")
+				Msg("error when broadcasting blkProto")
 		}
+	} else {
+		logger.Error().
+			Uint64("block", pendingBlock.Height()).
+			Msg("error when converting a block into a proto msg")
 	}
 	m.produce(m.newCEvt(eFinishEpoch), 0)
 	return sRoundStart, nil
@@ -702,6 +922,20 @@ func (m *cFSM) handleFinishEpochEvt(evt fsm.Event) (fsm.State, error) {
 
 }
 
+// matchRound checks whether the event is of the current round, i.e., of the current round number at the current
+// height. An event of a later round at the current height or of the next height is put back into the queue, since the
+// node may enter the round later
+func (m *cFSM) matchRound(evt iConsensusEvt, height uint64, round uint32) bool {
+	if height == m.ctx.round.height && round == m.ctx.round.num {
+		return true
+	}
+	later := height == m.ctx.round.height && round > m.ctx.round.num || height == m.ctx.round.height+1
+	if later && m.ctx.clock.Now().Sub(evt.timestamp()) <= m.ctx.cfg.UnmatchedEventTTL {
+		m.produce(evt, m.ctx.cfg.UnmatchedEventInterval)
+	}
+	return false
+}
+
 func (m *cFSM) isDelegate(delegates []string) bool {
 	for _, d := range delegates {
 		if m.ctx.addr.RawAddress == d {
@@ -747,16 +981,100 @@ func (m *cFSM) newCEvt(t fsm.EventType) *consensusEvt {
 	return newCEvt(t, m.ctx.clock)
 }
 
-func (m *cFSM) newProposeBlkEvt(blk *blockchain.Block) *proposeBlkEvt {
-	return newProposeBlkEvt(blk, m.ctx.addr.RawAddress, m.ctx.clock)
+// newProposeBlkEvt returns the proposal of the block signed by the node in the current round. The proposal is recorded
+// in the WAL before it is returned, and an error is returned if it can't be signed or recorded, in which case the node
+// must not propose, because it would propose a conflicting block in the round once restarted
+func (m *cFSM) newProposeBlkEvt(blk *blockchain.Block) (*proposeBlkEvt, error) {
+	evt := newProposeBlkEvt(blk, m.ctx.addr.RawAddress, m.ctx.clock)
+	evt.height = blk.Height()
+	evt.round = m.ctx.round.num
+	if err := evt.sign(m.ctx.addr); err != nil {
+		return nil, errors.Wrap(err, "error when signing proposeBlkEvt")
+	}
+	if err := m.appendWAL(evt); err != nil {
+		return nil, errors.Wrap(err, "error when writing the proposal into the WAL")
+	}
+	return evt, nil
+}
+
+func (m *cFSM) newPrevoteEvt(blkHash hash.Hash32B, decision bool) (*voteEvt, error) {
+	return m.newSignedVoteEvt(ePrevote, blkHash, decision)
+}
+
+func (m *cFSM) newVoteEvt(blkHash hash.Hash32B, decision bool) (*voteEvt, error) {
+	return m.newSignedVoteEvt(eVote, blkHash, decision)
+}
+
+// newSignedVoteEvt returns the vote signed by the node in the current round. The vote is recorded in the WAL before it
+// is returned, and an error is returned if the WAL can't be read or written, in which case the node must not vote
+func (m *cFSM) newSignedVoteEvt(t fsm.EventType, blkHash hash.Hash32B, decision bool) (*voteEvt, error) {
+	evt := newVoteEvt(t, blkHash, decision, m.ctx.addr.RawAddress, m.ctx.clock)
+	evt.height = m.ctx.round.height
+	evt.round = m.ctx.round.num
+	// Never sign a vote conflicting with the one signed in the round before the node restarted
+	msg, err := m.ctx.wal.get(evt.height, evt.round, evt.vctype())
+	if err != nil {
+		return nil, errors.Wrapf(err, "error when reading the signed %s from the WAL", t)
+	}
+	if msg != nil {
+		if err := evt.fromProtoMsg(msg); err != nil {
+			return nil, errors.Wrapf(err, "error when converting the signed %s from the WAL", t)
+		}
+		return evt, nil
+	}
+	if err := evt.sign(m.ctx.addr); err != nil {
+		return nil, errors.Wrapf(err, "error when signing %s", t)
+	}
+	if err := m.appendWAL(evt); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// appendWAL writes the event into the WAL before the node acts on it
+func (m *cFSM) appendWAL(evt protoMsg) error {
+	msg, err := evt.toProtoMsg()
+	if err != nil {
+		return errors.Wrap(err, "error when converting the event into a proto msg")
+	}
+	return m.ctx.wal.append(msg)
 }
 
-func (m *cFSM) newPrevoteEvt(blkHash hash.Hash32B, decision bool) *voteEvt {
-	return newVoteEvt(ePrevote, blkHash, decision, m.ctx.addr.RawAddress, m.ctx.clock)
+// restoreLock returns the block locked in an earlier round at the height and the round it's locked in, which are read
+// from the WAL if the node has restarted since
+func (m *cFSM) restoreLock(height uint64) (*blockchain.Block, uint32, error) {
+	if m.ctx.round.lockedBlock != nil && m.ctx.round.height == height {
+		return m.ctx.round.lockedBlock, m.ctx.round.lockedRound, nil
+	}
+	return m.ctx.wal.lockedBlock(height)
 }
 
-func (m *cFSM) newVoteEvt(blkHash hash.Hash32B, decision bool) *voteEvt {
-	return newVoteEvt(eVote, blkHash, decision, m.ctx.addr.RawAddress, m.ctx.clock)
+// recordedProposeBlkEvt returns the proposal of the current round recorded in the WAL, or nil if there is none
+func (m *cFSM) recordedProposeBlkEvt() *proposeBlkEvt {
+	msg, err := m.ctx.wal.get(m.ctx.round.height, m.ctx.round.num, iproto.ViewChangeMsg_PROPOSE)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Uint64("height", m.ctx.round.height).
+			Msg("error when reading the proposal from the WAL")
+		return nil
+	}
+	if msg == nil || msg.SenderAddr != m.ctx.round.proposer {
+		return nil
+	}
+	evt := newProposeBlkEvt(nil, "", m.ctx.clock)
+	if err := evt.fromProtoMsg(msg); err != nil || evt.block == nil {
+		logger.Error().
+			Err(err).
+			Uint64("height", m.ctx.round.height).
+			Msg("error when converting the proposal from the WAL")
+		return nil
+	}
+	logger.Info().
+		Str("proposer", evt.proposer).
+		Uint64("height", m.ctx.round.height).
+		Msg("replay the proposal from the WAL")
+	return evt
 }
 
 func (m *cFSM) newTimeoutEvt(t fsm.EventType, height uint64) *timeoutEvt {
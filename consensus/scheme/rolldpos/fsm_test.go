@@ -24,9 +24,11 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
@@ -246,6 +248,33 @@ func TestStartRoundEvt(t *testing.T) {
 		assert.NotNil(t, cfsm.ctx.round.votes, s)
 		assert.Equal(t, eProposeBlockTimeout, (<-cfsm.evtq).Type())
 	})
+	t.Run("next-round", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		delegates := make([]string, 4)
+		for i := 0; i < 4; i++ {
+			delegates[i] = testAddrs[i].RawAddress
+		}
+		cfsm := newTestCFSM(t, testAddrs[0], ctrl, delegates, nil, nil, clock.New())
+		cfsm.ctx.epoch = epochCtx{
+			delegates:    delegates,
+			num:          uint64(1),
+			height:       uint64(1),
+			numSubEpochs: uint(1),
+		}
+		lockedBlock := blockchain.NewBlock(1, 2, hash.ZeroHash32B, clock.New(), nil, nil, nil)
+		cfsm.ctx.round = roundCtx{height: 2, lockedBlock: lockedBlock, proposer: delegates[2]}
+		// No block is committed at height 2 in round 0, so that the next proposer takes over at the height
+		s, err := cfsm.handleStartRoundEvt(cfsm.newCEvt(eStartRound))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, s)
+		assert.Equal(t, uint64(2), cfsm.ctx.round.height)
+		assert.Equal(t, uint32(1), cfsm.ctx.round.num)
+		assert.Equal(t, delegates[3], cfsm.ctx.round.proposer)
+		assert.Equal(t, lockedBlock, cfsm.ctx.round.lockedBlock)
+		assert.Equal(t, eProposeBlockTimeout, (<-cfsm.evtq).Type())
+	})
 }
 
 func TestHandleInitBlockEvt(t *testing.T) {
@@ -266,7 +295,7 @@ func TestHandleInitBlockEvt(t *testing.T) {
 		delegates,
 		nil,
 		func(p2p *mock_network.MockOverlay) {
-			p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(1)
+			p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(2)
 		},
 		clock.New(),
 	)
@@ -277,9 +306,10 @@ func TestHandleInitBlockEvt(t *testing.T) {
 		numSubEpochs: uint(1),
 	}
 	cfsm.ctx.round = roundCtx{
-		prevotes: make(map[string]bool),
-		votes:    make(map[string]bool),
-		proposer: delegates[2],
+		prevotes:     make(map[string]bool),
+		votes:        make(map[string]bool),
+		endorsements: make(map[string][]byte),
+		proposer:     delegates[2],
 	}
 
 	s, err := cfsm.handleInitBlockEvt(cfsm.newCEvt(eInitBlock))
@@ -292,7 +322,37 @@ func TestHandleInitBlockEvt(t *testing.T) {
 	require.NotNil(t, pbe.block)
 	require.Equal(t, 1, len(pbe.block.Transfers))
 	require.Equal(t, 1, len(pbe.block.Votes))
-
+	// The proposal is recorded in the WAL before it is broadcast
+	msg, err := cfsm.ctx.wal.get(pbe.height, 0, iproto.ViewChangeMsg_PROPOSE)
+	require.NoError(t, err)
+	require.NotNil(t, msg)
+	require.Equal(t, pbe.signature, msg.Signature)
+
+	// The block locked in an earlier round is re-proposed instead of a new one
+	lockedBlock := blockchain.NewBlock(1, 2, hash.ZeroHash32B, clock.New(), nil, nil, nil)
+	cfsm.ctx.round.num = 1
+	cfsm.ctx.round.lockedBlock = lockedBlock
+	s, err = cfsm.handleInitBlockEvt(cfsm.newCEvt(eInitBlock))
+	require.NoError(t, err)
+	require.Equal(t, sAcceptPropose, s)
+	pbe, ok = (<-cfsm.evtq).(*proposeBlkEvt)
+	require.True(t, ok)
+	require.Equal(t, lockedBlock.HashBlock(), pbe.block.HashBlock())
+	require.Equal(t, uint32(1), pbe.round)
+
+	// The node doesn't propose if the WAL fails
+	path := "/tmp/test-consensus-propose-wal.db"
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+	failed := newWAL(db.NewBoltDB(path, &config.Default.DB))
+	require.NoError(t, failed.Start(context.Background()))
+	require.NoError(t, failed.Stop(context.Background()))
+	cfsm.ctx.wal = failed
+	cfsm.ctx.round.num = 2
+	s, err = cfsm.handleInitBlockEvt(cfsm.newCEvt(eInitBlock))
+	require.NoError(t, err)
+	require.Equal(t, sAcceptPropose, s)
+	require.Equal(t, eProposeBlockTimeout, (<-cfsm.evtq).Type())
 }
 
 func TestHandleProposeBlockEvt(t *testing.T) {
@@ -313,9 +373,10 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		numSubEpochs: uint(1),
 	}
 	round := roundCtx{
-		prevotes: make(map[string]bool),
-		votes:    make(map[string]bool),
-		proposer: delegates[2],
+		prevotes:     make(map[string]bool),
+		votes:        make(map[string]bool),
+		endorsements: make(map[string][]byte),
+		proposer:     delegates[2],
 	}
 
 	t.Run("pass-validation", func(t *testing.T) {
@@ -396,7 +457,7 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 			ctrl,
 			delegates,
 			func(chain *mock_blockchain.MockBlockchain) {
-				chain.EXPECT().ValidateBlock(gomock.Any()).Return(errors.New("mock error")).Times(1)
+				chain.EXPECT().ValidateProposal(gomock.Any()).Return(errors.New("mock error")).Times(1)
 			},
 			func(p2p *mock_network.MockOverlay) {
 				p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(1)
@@ -426,7 +487,7 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 			ctrl,
 			delegates,
 			func(chain *mock_blockchain.MockBlockchain) {
-				chain.EXPECT().ValidateBlock(gomock.Any()).Times(0)
+				chain.EXPECT().ValidateProposal(gomock.Any()).Times(0)
 			},
 			func(p2p *mock_network.MockOverlay) {
 				p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(1)
@@ -449,6 +510,85 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 		assert.Equal(t, ePrevoteTimeout, (<-cfsm.evtq).Type())
 	})
 
+	t.Run("locked-on-another-block", func(t *testing.T) {
+		cfsm := newTestCFSM(
+			t,
+			testAddrs[0],
+			ctrl,
+			delegates,
+			nil,
+			func(p2p *mock_network.MockOverlay) {
+				p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(1)
+			},
+			clock.New(),
+		)
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = round
+		cfsm.ctx.round.lockedBlock = blockchain.NewBlock(1, 2, hash.ZeroHash32B, clock.New(), nil, nil, nil)
+
+		blk, err := cfsm.ctx.mintBlock()
+		assert.NoError(t, err)
+		state, err := cfsm.handleProposeBlockEvt(newProposeBlkEvt(blk, delegates[2], cfsm.ctx.clock))
+		assert.NoError(t, err)
+		assert.Equal(t, sAcceptPrevote, state)
+		e := <-cfsm.evtq
+		evt, ok := e.(*voteEvt)
+		require.True(t, ok)
+		assert.Equal(t, ePrevote, evt.Type())
+		assert.False(t, evt.decision)
+		assert.Equal(t, ePrevoteTimeout, (<-cfsm.evtq).Type())
+	})
+
+	t.Run("later-round", func(t *testing.T) {
+		cfsm := newTestCFSM(
+			t,
+			testAddrs[0],
+			ctrl,
+			delegates,
+			nil,
+			func(p2p *mock_network.MockOverlay) {
+				p2p.EXPECT().Broadcast(gomock.Any()).Times(0)
+			},
+			clock.New(),
+		)
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = round
+
+		blk, err := cfsm.ctx.mintBlock()
+		assert.NoError(t, err)
+		pbEvt := newProposeBlkEvt(blk, delegates[3], cfsm.ctx.clock)
+		pbEvt.round = 1
+		state, err := cfsm.handleProposeBlockEvt(pbEvt)
+		assert.NoError(t, err)
+		assert.Equal(t, sAcceptPropose, state)
+		assert.Equal(t, 0, len(cfsm.evtq))
+	})
+
+	t.Run("next-height", func(t *testing.T) {
+		cfsm := newTestCFSM(
+			t,
+			testAddrs[0],
+			ctrl,
+			delegates,
+			nil,
+			func(p2p *mock_network.MockOverlay) {
+				p2p.EXPECT().Broadcast(gomock.Any()).Times(0)
+			},
+			clock.New(),
+		)
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = round
+
+		blk, err := cfsm.ctx.mintBlock()
+		assert.NoError(t, err)
+		pbEvt := newProposeBlkEvt(blk, delegates[3], cfsm.ctx.clock)
+		pbEvt.height = round.height + 1
+		state, err := cfsm.handleProposeBlockEvt(pbEvt)
+		assert.NoError(t, err)
+		assert.Equal(t, sAcceptPropose, state)
+		assert.Equal(t, 0, len(cfsm.evtq))
+	})
+
 	t.Run("invalid-proposer", func(t *testing.T) {
 		cfsm := newTestCFSM(
 			t,
@@ -516,7 +656,7 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 			ctrl,
 			delegates,
 			func(chain *mock_blockchain.MockBlockchain) {
-				chain.EXPECT().ValidateBlock(gomock.Any()).Times(0)
+				chain.EXPECT().ValidateProposal(gomock.Any()).Times(0)
 			},
 			func(p2p *mock_network.MockOverlay) {
 				p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(0)
@@ -533,6 +673,131 @@ func TestHandleProposeBlockEvt(t *testing.T) {
 	})
 }
 
+func TestProofOfLock(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	delegates := make([]string, 4)
+	for i := 0; i < 4; i++ {
+		delegates[i] = testAddrs[i].RawAddress
+	}
+	epoch := epochCtx{
+		delegates:    delegates,
+		num:          uint64(1),
+		height:       uint64(1),
+		numSubEpochs: uint(1),
+	}
+	prevote := func(i int, round uint32, blkHash hash.Hash32B) *voteEvt {
+		evt := newVoteEvt(ePrevote, blkHash, true, delegates[i], clock.New())
+		evt.height = 2
+		evt.round = round
+		require.NoError(t, evt.sign(testAddrs[i]))
+		return evt
+	}
+	// The delegates are split into two sets locked on different blocks at height 2, as the prevotes are only partly
+	// delivered. Delegates 0 and 1 are locked on X in round 1, and delegates 2 and 3 on Y in round 2
+	newLockedCFSM := func(
+		addr *iotxaddress.Address,
+		lockedBlock *blockchain.Block,
+		lockedRound uint32,
+		num uint32,
+		mockP2P func(*mock_network.MockOverlay),
+	) *cFSM {
+		cfsm := newTestCFSM(t, addr, ctrl, delegates, nil, mockP2P, clock.New())
+		cfsm.ctx.epoch = epoch
+		cfsm.ctx.round = roundCtx{
+			height:       2,
+			num:          num,
+			lockedBlock:  lockedBlock,
+			lockedRound:  lockedRound,
+			prevotes:     make(map[string]bool),
+			votes:        make(map[string]bool),
+			endorsements: make(map[string][]byte),
+			proposer:     delegates[(2+num)%4],
+		}
+		require.NoError(t, cfsm.ctx.wal.lock(lockedBlock, lockedRound))
+		return cfsm
+	}
+	handleProposal := func(cfsm *cFSM, blk *blockchain.Block) *voteEvt {
+		pbEvt := newProposeBlkEvt(blk, cfsm.ctx.round.proposer, cfsm.ctx.clock)
+		pbEvt.height = blk.Height()
+		pbEvt.round = cfsm.ctx.round.num
+		state, err := cfsm.handleProposeBlockEvt(pbEvt)
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPrevote, state)
+		evt, ok := (<-cfsm.evtq).(*voteEvt)
+		require.True(t, ok)
+		require.Equal(t, ePrevote, evt.Type())
+		require.Equal(t, ePrevoteTimeout, (<-cfsm.evtq).Type())
+		return evt
+	}
+
+	blkX := blockchain.NewBlock(1, 2, hash.ZeroHash32B, clock.New(), nil, nil, nil)
+	minter := newTestCFSM(t, testAddrs[0], ctrl, delegates, nil, nil, clock.New())
+	minter.ctx.epoch = epoch
+	blkY, err := minter.ctx.mintBlock()
+	require.NoError(t, err)
+	require.NotEqual(t, blkX.HashBlock(), blkY.HashBlock())
+
+	t.Run("older-pol", func(t *testing.T) {
+		// Delegate 1 re-proposes X in round 3, whose POL is older than the lock on Y
+		cfsm := newLockedCFSM(testAddrs[2], blkY, 2, 3, nil)
+		for i := 0; i < 3; i++ {
+			cfsm.observePrevote(prevote(i, 1, blkX.HashBlock()))
+		}
+		pv := handleProposal(cfsm, blkX)
+		assert.False(t, pv.decision)
+		assert.Equal(t, blkY, cfsm.ctx.round.lockedBlock)
+		assert.Equal(t, uint32(2), cfsm.ctx.round.lockedRound)
+	})
+
+	t.Run("no-pol", func(t *testing.T) {
+		// Delegate 2 re-proposes Y in round 4, but the prevotes for it are too few to unlock from X
+		cfsm := newLockedCFSM(testAddrs[0], blkX, 1, 4, nil)
+		for i := 2; i < 4; i++ {
+			cfsm.observePrevote(prevote(i, 2, blkY.HashBlock()))
+		}
+		pv := handleProposal(cfsm, blkY)
+		assert.False(t, pv.decision)
+		assert.Equal(t, blkX, cfsm.ctx.round.lockedBlock)
+	})
+
+	t.Run("later-pol", func(t *testing.T) {
+		// Delegate 2 re-proposes Y in round 4, whose POL in round 2 unlocks the delegate locked on X in round 1
+		cfsm := newLockedCFSM(testAddrs[0], blkX, 1, 4, nil)
+		for i := 1; i < 4; i++ {
+			cfsm.observePrevote(prevote(i, 2, blkY.HashBlock()))
+		}
+		pv := handleProposal(cfsm, blkY)
+		assert.True(t, pv.decision)
+		assert.Equal(t, blkY.HashBlock(), pv.blkHash)
+		assert.Equal(t, blkY, cfsm.ctx.round.lockedBlock)
+		assert.Equal(t, uint32(2), cfsm.ctx.round.lockedRound)
+		locked, lockedRound, err := cfsm.ctx.wal.lockedBlock(2)
+		require.NoError(t, err)
+		assert.Equal(t, blkY.HashBlock(), locked.HashBlock())
+		assert.Equal(t, uint32(2), lockedRound)
+	})
+
+	t.Run("broadcast-pol", func(t *testing.T) {
+		// Delegate 2 broadcasts the POL of Y ahead of re-proposing it in round 4
+		cfsm := newLockedCFSM(testAddrs[2], blkY, 2, 4, func(p2p *mock_network.MockOverlay) {
+			p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(4)
+		})
+		for i := 1; i < 4; i++ {
+			cfsm.observePrevote(prevote(i, 2, blkY.HashBlock()))
+		}
+		state, err := cfsm.handleInitBlockEvt(cfsm.newCEvt(eInitBlock))
+		require.NoError(t, err)
+		require.Equal(t, sAcceptPropose, state)
+		pbEvt, ok := (<-cfsm.evtq).(*proposeBlkEvt)
+		require.True(t, ok)
+		assert.Equal(t, blkY.HashBlock(), pbEvt.block.HashBlock())
+	})
+}
+
 func TestHandlePrevoteEvt(t *testing.T) {
 	t.Parallel()
 
@@ -551,9 +816,10 @@ func TestHandlePrevoteEvt(t *testing.T) {
 		numSubEpochs: uint(1),
 	}
 	round := roundCtx{
-		prevotes: make(map[string]bool),
-		votes:    make(map[string]bool),
-		proposer: delegates[2],
+		prevotes:     make(map[string]bool),
+		votes:        make(map[string]bool),
+		endorsements: make(map[string][]byte),
+		proposer:     delegates[2],
 	}
 
 	t.Run("gather-prevotes", func(t *testing.T) {
@@ -589,6 +855,15 @@ func TestHandlePrevoteEvt(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptPrevote, state)
 
+		// A validly signed prevote of the current round number at another height is ignored
+		otherHeight := newVoteEvt(ePrevote, blk.HashBlock(), true, delegates[3], cfsm.ctx.clock)
+		otherHeight.height = cfsm.ctx.round.height + 1
+		require.NoError(t, otherHeight.sign(testAddrs[3]))
+		state, err = cfsm.handlePrevoteEvt(otherHeight)
+		assert.NoError(t, err)
+		assert.Equal(t, sAcceptPrevote, state)
+		assert.Equal(t, 2, len(cfsm.ctx.round.prevotes))
+
 		// Third prevote, could move on
 		state, err = cfsm.handlePrevoteEvt(
 			newVoteEvt(ePrevote, blk.HashBlock(), true, delegates[2], cfsm.ctx.clock),
@@ -601,6 +876,8 @@ func TestHandlePrevoteEvt(t *testing.T) {
 		assert.Equal(t, eVote, evt.Type())
 		assert.True(t, evt.decision)
 		assert.Equal(t, eVoteTimeout, (<-cfsm.evtq).Type())
+		// The node locks on the block
+		assert.Equal(t, blk, cfsm.ctx.round.lockedBlock)
 	})
 
 	t.Run("timeout", func(t *testing.T) {
@@ -652,9 +929,10 @@ func TestHandleVoteEvt(t *testing.T) {
 		numSubEpochs: uint(1),
 	}
 	round := roundCtx{
-		prevotes: make(map[string]bool),
-		votes:    make(map[string]bool),
-		proposer: delegates[2],
+		prevotes:     make(map[string]bool),
+		votes:        make(map[string]bool),
+		endorsements: make(map[string][]byte),
+		proposer:     delegates[2],
 	}
 
 	t.Run("gather-votes", func(t *testing.T) {
@@ -664,6 +942,14 @@ func TestHandleVoteEvt(t *testing.T) {
 			ctrl,
 			delegates,
 			func(chain *mock_blockchain.MockBlockchain) {
+				candidates := make([]*state.Candidate, 0, len(delegates))
+				for i := range delegates {
+					candidates = append(candidates, &state.Candidate{
+						Address: testAddrs[i].RawAddress,
+						PubKey:  testAddrs[i].PublicKey[:],
+					})
+				}
+				chain.EXPECT().CandidatesByHeight(gomock.Any()).Return(candidates, nil).AnyTimes()
 				chain.EXPECT().CommitBlock(gomock.Any()).Return(nil).Times(1)
 			},
 			func(p2p *mock_network.MockOverlay) {
@@ -677,73 +963,70 @@ func TestHandleVoteEvt(t *testing.T) {
 		blk, err := cfsm.ctx.mintBlock()
 		assert.NoError(t, err)
 		cfsm.ctx.round.block = blk
+		cfsm.ctx.round.height = blk.Height()
+		signedVote := func(i int, signer *iotxaddress.Address) *voteEvt {
+			vEvt := newVoteEvt(eVote, blk.HashBlock(), true, delegates[i], cfsm.ctx.clock)
+			vEvt.height = blk.Height()
+			require.NoError(t, vEvt.sign(signer))
+			return vEvt
+		}
 
-		// First prevote
-		state, err := cfsm.handleVoteEvt(
-			newVoteEvt(eVote, blk.HashBlock(), true, delegates[0], cfsm.ctx.clock),
-		)
+		// First vote
+		state, err := cfsm.handleVoteEvt(signedVote(0, testAddrs[0]))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptVote, state)
 
-		// Second prevote
-		state, err = cfsm.handleVoteEvt(
-			newVoteEvt(eVote, blk.HashBlock(), true, delegates[1], cfsm.ctx.clock),
-		)
+		// Second vote
+		state, err = cfsm.handleVoteEvt(signedVote(1, testAddrs[1]))
 		assert.NoError(t, err)
 		assert.Equal(t, sAcceptVote, state)
 
-		// Third prevote, could move on
-		state, err = cfsm.handleVoteEvt(
-			newVoteEvt(eVote, blk.HashBlock(), true, delegates[2], cfsm.ctx.clock),
-		)
+		// A yes vote not endorsing the block is not counted
+		state, err = cfsm.handleVoteEvt(signedVote(3, testAddrs[0]))
 		assert.NoError(t, err)
-		assert.Equal(t, sRoundStart, state)
-		assert.Equal(t, eFinishEpoch, (<-cfsm.evtq).Type())
-	})
-	t.Run("timeout-blocking", func(t *testing.T) {
-		cfsm := newTestCFSM(
-			t,
-			testAddrs[0],
-			ctrl,
-			delegates,
-			func(chain *mock_blockchain.MockBlockchain) {
-				chain.EXPECT().CommitBlock(gomock.Any()).Return(nil).Times(0)
-				chain.EXPECT().
-					MintNewDummyBlock().
-					Return(blockchain.NewBlock(0, 0, hash.ZeroHash32B, clock.New(), nil, nil, nil)).Times(0)
-			},
-			func(p2p *mock_network.MockOverlay) {
-				p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(0)
-			},
-			clock.New(),
-		)
-		cfsm.ctx.cfg.EnableDummyBlock = false
-		cfsm.ctx.epoch = epoch
-		cfsm.ctx.round = round
-
-		blk, err := cfsm.ctx.mintBlock()
+		assert.Equal(t, sAcceptVote, state)
+		assert.Equal(t, 2, len(cfsm.ctx.round.votes))
+		assert.Equal(t, 2, len(cfsm.ctx.round.endorsements))
+
+		// A validly signed vote of the current round number at another height is ignored
+		otherHeight := newVoteEvt(eVote, blk.HashBlock(), false, delegates[3], cfsm.ctx.clock)
+		otherHeight.height = blk.Height() + 1
+		require.NoError(t, otherHeight.sign(testAddrs[3]))
+		require.True(t, otherHeight.verify(testAddrs[3].PublicKey))
+		state, err = cfsm.handleVoteEvt(otherHeight)
 		assert.NoError(t, err)
-		cfsm.ctx.round.block = blk
+		assert.Equal(t, sAcceptVote, state)
+		assert.Equal(t, 2, len(cfsm.ctx.round.votes))
 
-		state, err := cfsm.handleVoteEvt(cfsm.newCEvt(eVoteTimeout))
+		// The messages recorded at the height are pruned once the block is committed
+		recorded := &iproto.ViewChangeMsg{Vctype: iproto.ViewChangeMsg_VOTE, Height: blk.Height()}
+		require.NoError(t, cfsm.ctx.wal.append(recorded))
+
+		// Third vote, could move on
+		state, err = cfsm.handleVoteEvt(signedVote(2, testAddrs[2]))
 		assert.NoError(t, err)
 		assert.Equal(t, sRoundStart, state)
 		assert.Equal(t, eFinishEpoch, (<-cfsm.evtq).Type())
+		// The committed block carries the votes as its commit certificate
+		require.NotNil(t, blk.CommitCert)
+		assert.Equal(t, blk.Height(), blk.CommitCert.Height)
+		assert.Equal(t, cfsm.ctx.round.num, blk.CommitCert.Round)
+		assert.Equal(t, 3, len(blk.CommitCert.Endorsements))
+		msg, err := cfsm.ctx.wal.get(blk.Height(), 0, iproto.ViewChangeMsg_VOTE)
+		require.NoError(t, err)
+		assert.Nil(t, msg)
 	})
-	t.Run("timeout-dummy-block", func(t *testing.T) {
+	t.Run("timeout-next-round", func(t *testing.T) {
 		cfsm := newTestCFSM(
 			t,
 			testAddrs[0],
 			ctrl,
 			delegates,
 			func(chain *mock_blockchain.MockBlockchain) {
-				chain.EXPECT().CommitBlock(gomock.Any()).Return(nil).Times(1)
-				chain.EXPECT().
-					MintNewDummyBlock().
-					Return(blockchain.NewBlock(0, 0, hash.ZeroHash32B, clock.New(), nil, nil, nil)).Times(1)
+				chain.EXPECT().CommitBlock(gomock.Any()).Times(0)
 			},
 			func(p2p *mock_network.MockOverlay) {
-				p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(1)
+				p2p.EXPECT().Broadcast(gomock.Any()).Times(0)
 			},
 			clock.New(),
 		)
@@ -754,10 +1037,11 @@ func TestHandleVoteEvt(t *testing.T) {
 		assert.NoError(t, err)
 		cfsm.ctx.round.block = blk
 
+		// No block is committed, and the next round at the height is started right away
 		state, err := cfsm.handleVoteEvt(cfsm.newCEvt(eVoteTimeout))
 		assert.NoError(t, err)
 		assert.Equal(t, sRoundStart, state)
-		assert.Equal(t, eFinishEpoch, (<-cfsm.evtq).Type())
+		assert.Equal(t, eStartRound, (<-cfsm.evtq).Type())
 	})
 }
 
@@ -779,9 +1063,10 @@ func TestHandleFinishEpochEvt(t *testing.T) {
 		numSubEpochs: uint(1),
 	}
 	round := roundCtx{
-		prevotes: make(map[string]bool),
-		votes:    make(map[string]bool),
-		proposer: delegates[2],
+		prevotes:     make(map[string]bool),
+		votes:        make(map[string]bool),
+		endorsements: make(map[string][]byte),
+		proposer:     delegates[2],
 	}
 
 	t.Run("not-finished", func(t *testing.T) {
@@ -864,14 +1149,13 @@ func newTestCFSM(
 		addr,
 		ctrl,
 		config.RollDPoS{
-			EventChanSize:    2,
-			NumDelegates:     uint(len(delegates)),
-			EnableDummyBlock: true,
+			EventChanSize: 2,
+			NumDelegates:  uint(len(delegates)),
 		},
 		func(blockchain *mock_blockchain.MockBlockchain) {
 			blockchain.EXPECT().GetBlockByHeight(uint64(1)).Return(lastBlk, nil).AnyTimes()
 			blockchain.EXPECT().
-				MintNewBlock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				MintNewBlock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				Return(blkToMint, nil).
 				AnyTimes()
 			if mockChain == nil {
@@ -882,7 +1166,7 @@ func newTestCFSM(
 					{Address: delegates[3]},
 				}, nil).AnyTimes()
 				blockchain.EXPECT().TipHeight().Return(uint64(1)).AnyTimes()
-				blockchain.EXPECT().ValidateBlock(gomock.Any()).Return(nil).AnyTimes()
+				blockchain.EXPECT().ValidateProposal(gomock.Any()).Return(nil).AnyTimes()
 			} else {
 				mockChain(blockchain)
 			}
@@ -892,6 +1176,7 @@ func newTestCFSM(
 				PickActs().
 				Return([]*action.Transfer{transfer}, []*action.Vote{vote}, []*action.Execution{}).
 				AnyTimes()
+			actPool.EXPECT().PickEvidences().Return(nil).AnyTimes()
 			actPool.EXPECT().Reset().AnyTimes()
 		},
 		func(p2p *mock_network.MockOverlay) {
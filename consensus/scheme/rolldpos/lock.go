@@ -0,0 +1,89 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// proofsOfLock keeps the yes prevotes received in every round at a height, by the round and the hash of the block they
+// are for. More than 2/3 yes prevotes for a block in a round is a proof of lock (POL) on the block in the round, which
+// unlocks the delegates locked on another block in an earlier round
+type proofsOfLock map[uint32]map[hash.Hash32B]map[string]*voteEvt
+
+// add records the yes prevote
+func (p proofsOfLock) add(prevote *voteEvt) {
+	if !prevote.decision {
+		return
+	}
+	blks, ok := p[prevote.round]
+	if !ok {
+		blks = make(map[hash.Hash32B]map[string]*voteEvt)
+		p[prevote.round] = blks
+	}
+	prevotes, ok := blks[prevote.blkHash]
+	if !ok {
+		prevotes = make(map[string]*voteEvt)
+		blks[prevote.blkHash] = prevotes
+	}
+	prevotes[prevote.voter] = prevote
+}
+
+// prevotes returns the yes prevotes for the block received in the round
+func (p proofsOfLock) prevotes(round uint32, blkHash hash.Hash32B) []*voteEvt {
+	prevotes := make([]*voteEvt, 0, len(p[round][blkHash]))
+	for _, prevote := range p[round][blkHash] {
+		prevotes = append(prevotes, prevote)
+	}
+	return prevotes
+}
+
+// observePrevote records the yes prevote of the current height, whichever round it's of and whichever state the node is
+// in, so that a POL of an earlier round is known when a block locked in it is re-proposed
+func (m *cFSM) observePrevote(prevote *voteEvt) {
+	if prevote.height != m.ctx.round.height {
+		return
+	}
+	if m.ctx.round.pols == nil {
+		m.ctx.round.pols = make(proofsOfLock)
+	}
+	m.ctx.round.pols.add(prevote)
+}
+
+// polRound returns the latest round after the one the node is locked in, up to the current round, in which the block
+// has a POL, and false if there is none
+func (m *cFSM) polRound(blkHash hash.Hash32B) (uint32, bool) {
+	for round := m.ctx.round.num; round > m.ctx.round.lockedRound; round-- {
+		decisions := make(map[string]bool)
+		for voter := range m.ctx.round.pols[round][blkHash] {
+			decisions[voter] = true
+		}
+		if yes, _ := m.ctx.calcQuorum(decisions); yes {
+			return round, true
+		}
+	}
+	return 0, false
+}
+
+// broadcastPOL broadcasts the prevotes making up the POL of the locked block, so that the delegates which have missed
+// them and are locked on another block in an earlier round could unlock
+func (m *cFSM) broadcastPOL() {
+	if m.ctx.round.lockedBlock == nil {
+		return
+	}
+	for _, prevote := range m.ctx.round.pols.prevotes(m.ctx.round.lockedRound, m.ctx.round.lockedBlock.HashBlock()) {
+		msg, err := prevote.toProtoMsg()
+		if err != nil {
+			logger.Error().Err(err).Msg("error when converting a prevoteEvt into a proto msg")
+			continue
+		}
+		if err := m.ctx.p2p.Broadcast(msg); err != nil {
+			logger.Error().Err(err).Msg("error when broadcasting the POL prevote")
+		}
+	}
+}
@@ -8,6 +8,8 @@ package rolldpos
 
 import (
 	"context"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/facebookgo/clock"
@@ -18,14 +20,18 @@ import (
 
 	"github.com/iotexproject/iotex-core/actpool"
 	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/blocksync"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
 	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/dispatch/dispatcher"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/state"
 )
@@ -49,9 +55,16 @@ var (
 	ErrNewRollDPoS = errors.New("error when constructing RollDPoS")
 	// ErrZeroDelegate indicates seeing 0 delegates in the network
 	ErrZeroDelegate = errors.New("zero delegates in the network")
+	// ErrNotDelegate indicates the address is not a delegate at the given height
+	ErrNotDelegate = errors.New("the address is not a delegate")
+	// ErrUnmatchedHeight indicates the message is neither of the current height nor of the next one
+	ErrUnmatchedHeight = errors.New("the message is not of the current height or the next one")
 )
 
 type rollDPoSCtx struct {
+	// height is the height of the current round, which is published for the message handlers running out of the FSM
+	// goroutine. It must be accessed atomically, and is kept first for the 64-bit alignment
+	height  uint64
 	cfg     config.RollDPoS
 	addr    *iotxaddress.Address
 	chain   blockchain.Blockchain
@@ -60,6 +73,9 @@ type rollDPoSCtx struct {
 	epoch   epochCtx
 	round   roundCtx
 	clock   clock.Clock
+	wal     *wal
+	// detector detects the delegates signing conflicting messages
+	detector *equivocationDetector
 	// candidatesByHeightFunc is only used for testing purpose
 	candidatesByHeightFunc func(uint64) ([]*state.Candidate, error)
 	sync                   blocksync.BlockSync
@@ -72,8 +88,21 @@ var (
 
 // rollingDelegates will only allows the delegates chosen for given epoch to enter the epoch
 func (ctx *rollDPoSCtx) rollingDelegates(epochNum uint64) ([]string, error) {
+	delegates, err := ctx.rollingCandidates(epochNum)
+	if err != nil {
+		return []string{}, err
+	}
+	delegatesAddress := make([]string, 0, len(delegates))
+	for _, delegate := range delegates {
+		delegatesAddress = append(delegatesAddress, delegate.Address)
+	}
+	return delegatesAddress, nil
+}
+
+// rollingCandidates returns the candidates chosen as the delegates of the given epoch, in the order of the delegates
+func (ctx *rollDPoSCtx) rollingCandidates(epochNum uint64) ([]*state.Candidate, error) {
 	numDlgs := ctx.cfg.NumDelegates
-	height := uint64(numDlgs) * uint64(ctx.cfg.NumSubEpochs) * (epochNum - 1)
+	height := uint64(numDlgs) * uint64(ctx.getNumSubEpochs()) * (epochNum - 1)
 	var candidates []*state.Candidate
 	var err error
 	if ctx.candidatesByHeightFunc != nil {
@@ -83,19 +112,57 @@ func (ctx *rollDPoSCtx) rollingDelegates(epochNum uint64) ([]string, error) {
 		candidates, err = ctx.chain.CandidatesByHeight(height)
 	}
 	if err != nil {
-		return []string{}, errors.Wrap(err, "error when getting delegates from the candidate pool")
+		return nil, errors.Wrap(err, "error when getting delegates from the candidate pool")
 	}
 	if len(candidates) < int(numDlgs) {
-		return []string{}, errors.Wrapf(ErrNotEnoughCandidates, "only %d delegates from the candidate pool", len(candidates))
+		return nil, errors.Wrapf(ErrNotEnoughCandidates, "only %d delegates from the candidate pool", len(candidates))
 	}
 
-	var candidatesAddress []string
-	for _, candidate := range candidates {
-		candidatesAddress = append(candidatesAddress, candidate.Address)
+	return blockchain.SelectDelegates(candidates, epochNum, numDlgs), nil
+}
+
+// delegatePubKey returns the public key of the given address if it is a delegate of the epoch containing the height
+func (ctx *rollDPoSCtx) delegatePubKey(height uint64, addr string) (keypair.PublicKey, error) {
+	if height == 0 {
+		return keypair.ZeroPublicKey, errors.Wrap(ErrNotDelegate, "no delegate at height 0")
+	}
+	epochNum := (height-1)/(uint64(ctx.cfg.NumDelegates)*uint64(ctx.getNumSubEpochs())) + 1
+	delegates, err := ctx.rollingCandidates(epochNum)
+	if err != nil {
+		return keypair.ZeroPublicKey, errors.Wrapf(err, "error when getting the delegates of epoch %d", epochNum)
+	}
+	for _, delegate := range delegates {
+		if delegate.Address == addr {
+			return keypair.BytesToPublicKey(delegate.PubKey)
+		}
 	}
-	crypto.SortCandidates(candidatesAddress, epochNum)
+	return keypair.ZeroPublicKey, errors.Wrapf(ErrNotDelegate, "%s at height %d", addr, height)
+}
+
+// setRound sets the current round, and publishes its height
+func (ctx *rollDPoSCtx) setRound(round roundCtx) {
+	ctx.round = round
+	atomic.StoreUint64(&ctx.height, round.height)
+}
 
-	return candidatesAddress[:numDlgs], nil
+// currentHeight returns the height of the current round. It is safe to be called out of the FSM goroutine
+func (ctx *rollDPoSCtx) currentHeight() uint64 {
+	return atomic.LoadUint64(&ctx.height)
+}
+
+// isEndorsement checks whether the vote is an endorsement of the block of the current round, which is a yes vote
+// signed by a delegate for the height and the round number of the current round
+func (ctx *rollDPoSCtx) isEndorsement(vote *voteEvt) bool {
+	if ctx.round.block == nil || !vote.decision || vote.height != ctx.round.height || vote.round != ctx.round.num {
+		return false
+	}
+	pubKey, err := ctx.delegatePubKey(ctx.round.height, vote.voter)
+	if err != nil {
+		return false
+	}
+	blkHash := ctx.round.block.HashBlock()
+	h := action.ViewChangeHash(ctx.round.height, ctx.round.num, iproto.ViewChangeMsg_VOTE, blkHash, true)
+	return crypto.EC283.Verify(pubKey, h[:], vote.signature)
 }
 
 // calcEpochNum calculates the epoch ordinal number and the epoch start height offset, which is based on the height of
@@ -126,23 +193,36 @@ func (ctx *rollDPoSCtx) getNumSubEpochs() uint {
 }
 
 // rotatedProposer will rotate among the delegates to choose the proposer. It is pseudo order based on the position
-// in the delegate list and the block height
-func (ctx *rollDPoSCtx) rotatedProposer() (string, uint64, error) {
+// in the delegate list, the block height and the round at the height. If no block is committed in the current round,
+// the proposer of the next round at the same height is returned, otherwise the one of the first round at the next
+// height, or of the last round recorded in the WAL at it if the node has restarted in the middle of the height
+func (ctx *rollDPoSCtx) rotatedProposer() (string, uint64, uint32, error) {
 	height := ctx.chain.TipHeight()
 	// Next block height
 	height++
-	proposer, err := ctx.calcProposer(height, ctx.epoch.delegates)
-	return proposer, height, err
+	var round uint32
+	if height == ctx.round.height {
+		round = ctx.round.num + 1
+	} else {
+		// Resume the last round the node has been in at the height before it restarted
+		var err error
+		if round, err = ctx.wal.lastRound(height); err != nil {
+			return "", 0, 0, errors.Wrap(err, "error when reading the last round from the WAL")
+		}
+	}
+	proposer, err := ctx.calcProposer(height, round, ctx.epoch.delegates)
+	return proposer, height, round, err
 }
 
-// calcProposer calculates the proposer for the block at a given height
-func (ctx *rollDPoSCtx) calcProposer(height uint64, delegates []string) (string, error) {
+// calcProposer calculates the proposer for the block at a given height in a given round. The proposer falls back to the
+// next delegate in each round at the height
+func (ctx *rollDPoSCtx) calcProposer(height uint64, round uint32, delegates []string) (string, error) {
 	numDelegates := len(delegates)
 	if numDelegates == 0 {
 		return "", ErrZeroDelegate
 	}
 	if !ctx.cfg.TimeBasedRotation {
-		return delegates[(height)%uint64(numDelegates)], nil
+		return delegates[(height+uint64(round))%uint64(numDelegates)], nil
 	}
 	duration, err := ctx.calcDurationSinceLastBlock()
 	if err != nil {
@@ -155,7 +235,7 @@ func (ctx *rollDPoSCtx) calcProposer(height uint64, delegates []string) (string,
 	// TODO: should downgrade to debug level in the future
 	logger.Info().Int64("slot", timeSlotIndex).Msg("calculate time slot offset")
 	timeSlotMtc.WithLabelValues().Set(float64(timeSlotIndex))
-	return delegates[(height+uint64(timeSlotIndex))%uint64(numDelegates)], nil
+	return delegates[(height+uint64(timeSlotIndex)+uint64(round))%uint64(numDelegates)], nil
 }
 
 // mintBlock picks the actions and creates an block to propose
@@ -165,7 +245,8 @@ func (ctx *rollDPoSCtx) mintBlock() (*blockchain.Block, error) {
 		Int("transfer", len(transfers)).
 		Int("votes", len(votes)).
 		Msg("pick actions from the action pool")
-	blk, err := ctx.chain.MintNewBlock(transfers, votes, executions, ctx.addr, "")
+	evidences := ctx.actPool.PickEvidences()
+	blk, err := ctx.chain.MintNewBlock(transfers, votes, executions, evidences, ctx.addr, "")
 	if err != nil {
 		logger.Error().Msg("error when minting a block")
 		return nil, err
@@ -189,6 +270,16 @@ func (ctx *rollDPoSCtx) calcDurationSinceLastBlock() (time.Duration, error) {
 	return ctx.clock.Now().Sub(blk.Header.Timestamp()), nil
 }
 
+// ttl returns the TTL of waiting for a step of the current round, which doubles in each round at the height up to the
+// configured number of times, so that the delegates have more time to reach consensus after failing in a round
+func (ctx *rollDPoSCtx) ttl(base time.Duration) time.Duration {
+	backoff := uint(ctx.round.num)
+	if backoff > ctx.cfg.MaxTTLBackoff {
+		backoff = ctx.cfg.MaxTTLBackoff
+	}
+	return base << backoff
+}
+
 // calcQuorum calculates if more than 2/3 vote yes or no
 func (ctx *rollDPoSCtx) calcQuorum(decisions map[string]bool) (bool, bool) {
 	yes := 0
@@ -231,12 +322,38 @@ type epochCtx struct {
 
 // roundCtx keeps the context data for the current round and block.
 type roundCtx struct {
-	height    uint64
+	height uint64
+	// num is the ordinal number of the round at the height, which starts from 0 and increases each time the delegates
+	// fail to reach consensus on a block at the height
+	num       uint32
 	timestamp time.Time
 	block     *blockchain.Block
-	prevotes  map[string]bool
-	votes     map[string]bool
-	proposer  string
+	// lockedBlock is the block which has collected more than 2/3 yes prevotes in an earlier round at the height. It's
+	// carried over to the later rounds at the height, in which it's re-proposed and the other blocks are prevoted no,
+	// unless they have collected more than 2/3 yes prevotes in a later round
+	lockedBlock *blockchain.Block
+	// lockedRound is the round the block is locked in
+	lockedRound uint32
+	// pols are the yes prevotes received in every round at the height, which unlock the node from the locked block
+	pols     proofsOfLock
+	prevotes map[string]bool
+	votes    map[string]bool
+	// endorsements are the signatures of the yes votes for the block, which make up the commit certificate
+	endorsements map[string][]byte
+	proposer     string
+}
+
+// commitCert returns the commit certificate made up of the collected endorsements of the block
+func (r *roundCtx) commitCert() *blockchain.CommitCert {
+	cert := &blockchain.CommitCert{Height: r.height, Round: r.num}
+	for voter, sig := range r.endorsements {
+		cert.Endorsements = append(cert.Endorsements, &blockchain.Endorsement{Endorser: voter, Signature: sig})
+	}
+	// Sort the endorsements to make the certificate independent of the order the votes are received in
+	sort.Slice(cert.Endorsements, func(i, j int) bool {
+		return cert.Endorsements[i].Endorser < cert.Endorsements[j].Endorser
+	})
+	return cert
 }
 
 // RollDPoS is Roll-DPoS consensus main entrance
@@ -247,6 +364,13 @@ type RollDPoS struct {
 
 // Start starts RollDPoS consensus
 func (r *RollDPoS) Start(ctx context.Context) error {
+	if err := r.ctx.wal.Start(ctx); err != nil {
+		return errors.Wrap(err, "error when starting the consensus WAL")
+	}
+	// Only the records at the next height are replayed, and the ones below it are stale
+	if err := r.ctx.wal.prune(r.ctx.chain.TipHeight() + 1); err != nil {
+		return errors.Wrap(err, "error when pruning the consensus WAL")
+	}
 	if err := r.cfsm.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting the consensus FSM")
 	}
@@ -256,7 +380,10 @@ func (r *RollDPoS) Start(ctx context.Context) error {
 
 // Stop stops RollDPoS consensus
 func (r *RollDPoS) Stop(ctx context.Context) error {
-	return errors.Wrap(r.cfsm.Stop(ctx), "error when stopping the consensus FSM")
+	if err := r.cfsm.Stop(ctx); err != nil {
+		return errors.Wrap(err, "error when stopping the consensus FSM")
+	}
+	return errors.Wrap(r.ctx.wal.Stop(ctx), "error when stopping the consensus WAL")
 }
 
 // Handle handles RollDPoS events coming from the network from other delegates
@@ -287,8 +414,8 @@ func (r *RollDPoS) Metrics() (scheme.ConsensusMetrics, error) {
 	}
 	// Compute the height
 	height := r.ctx.chain.TipHeight()
-	// Compute block producer
-	producer, err := r.ctx.calcProposer(height+1, delegates)
+	// Compute block producer of the first round
+	producer, err := r.ctx.calcProposer(height+1, 0, delegates)
 	if err != nil {
 		return metrics, errors.Wrap(err, "error when calculating the block producer")
 	}
@@ -329,30 +456,83 @@ func (r *RollDPoS) convertToConsensusEvt(msg proto.Message) (iConsensusEvt, erro
 		return nil, errors.Wrap(ErrEvtCast, "error when casting a proto msg to a ViewChangeMsg")
 	}
 	var cEvt iConsensusEvt
+	var verified bool
+	// The message checked for equivocation, which carries the hash of the block it is about
+	detected := vcMsg
+	// Only the messages of the current height and the next one are accepted, so that the sender is checked against the
+	// delegates of the epochs being run rather than of an arbitrary height. The messages of the next height are kept
+	// for the delegates lagging behind, and the others are dropped without penalizing the sender, which may be a
+	// delegate just ahead of or behind the node
+	height := r.ctx.currentHeight()
+	if vcMsg.Height != height && vcMsg.Height != height+1 {
+		return nil, errors.Wrapf(
+			ErrUnmatchedHeight,
+			"the message is of height %d while the current height is %d",
+			vcMsg.Height,
+			height,
+		)
+	}
+	pubKey, err := r.ctx.delegatePubKey(vcMsg.Height, vcMsg.SenderAddr)
+	if errors.Cause(err) == ErrNotDelegate {
+		return nil, errors.Wrapf(dispatcher.ErrInvalidMsg, "%v", err)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error when getting the public key of the sender")
+	}
 	switch vcMsg.Vctype {
 	case iproto.ViewChangeMsg_PROPOSE:
-		pbEvt := r.cfsm.newProposeBlkEvt(nil)
+		pbEvt := newProposeBlkEvt(nil, "", r.ctx.clock)
 		if err := pbEvt.fromProtoMsg(vcMsg); err != nil {
 			return nil, errors.Wrap(err, "error when casting a proto msg to proposeBlkEvt")
 		}
+		if pbEvt.block == nil || pbEvt.block.Height() != pbEvt.height {
+			return nil, errors.Wrapf(
+				dispatcher.ErrInvalidMsg,
+				"the proposed block doesn't match the height %d of the message",
+				pbEvt.height,
+			)
+		}
+		verified = pbEvt.verify(pubKey)
 		cEvt = pbEvt
+		blkHash := pbEvt.block.HashBlock()
+		detected = &iproto.ViewChangeMsg{
+			Vctype:     vcMsg.Vctype,
+			BlockHash:  blkHash[:],
+			SenderAddr: vcMsg.SenderAddr,
+			Height:     vcMsg.Height,
+			Round:      vcMsg.Round,
+			Signature:  vcMsg.Signature,
+		}
 	case iproto.ViewChangeMsg_PREVOTE:
 		var blkHash hash.Hash32B
-		pvEvt := r.cfsm.newPrevoteEvt(blkHash, false)
+		pvEvt := newVoteEvt(ePrevote, blkHash, false, "", r.ctx.clock)
 		if err := pvEvt.fromProtoMsg(vcMsg); err != nil {
 			return nil, errors.Wrap(err, "error when casting a proto msg to prevoteEvt")
 		}
+		verified = pvEvt.verify(pubKey)
 		cEvt = pvEvt
 	case iproto.ViewChangeMsg_VOTE:
 		var blkHash hash.Hash32B
-		vEvt := r.cfsm.newVoteEvt(blkHash, false)
+		vEvt := newVoteEvt(eVote, blkHash, false, "", r.ctx.clock)
 		if err := vEvt.fromProtoMsg(vcMsg); err != nil {
 			return nil, errors.Wrap(err, "error when casting a proto msg to voteEvt")
 		}
+		verified = vEvt.verify(pubKey)
 		cEvt = vEvt
 	default:
 		return nil, errors.Wrapf(ErrEvtCast, "unexpected ViewChangeMsg type %d", vcMsg.Vctype)
 	}
+	if !verified {
+		return nil, errors.Wrapf(
+			dispatcher.ErrInvalidMsg,
+			"failed to verify the signature of %s from %s",
+			vcMsg.Vctype,
+			vcMsg.SenderAddr,
+		)
+	}
+	if err := r.ctx.detectEquivocation(detected, height); err != nil {
+		return nil, err
+	}
 	return cEvt, nil
 }
 
@@ -365,6 +545,7 @@ type Builder struct {
 	actPool                actpool.ActPool
 	p2p                    network.Overlay
 	clock                  clock.Clock
+	walStore               db.KVStore
 	candidatesByHeightFunc func(uint64) ([]*state.Candidate, error)
 }
 
@@ -409,6 +590,12 @@ func (b *Builder) SetClock(clock clock.Clock) *Builder {
 	return b
 }
 
+// SetWALStore sets the KV store of the consensus WAL, which is in memory if not set
+func (b *Builder) SetWALStore(walStore db.KVStore) *Builder {
+	b.walStore = walStore
+	return b
+}
+
 // SetCandidatesByHeightFunc sets candidatesByHeightFunc, which is only used by tests
 func (b *Builder) SetCandidatesByHeightFunc(
 	candidatesByHeightFunc func(uint64) ([]*state.Candidate, error),
@@ -431,13 +618,18 @@ func (b *Builder) Build() (*RollDPoS, error) {
 	if b.clock == nil {
 		b.clock = clock.New()
 	}
+	if b.walStore == nil {
+		b.walStore = db.NewMemKVStore()
+	}
 	ctx := rollDPoSCtx{
-		cfg:     b.cfg,
-		addr:    b.addr,
-		chain:   b.chain,
-		actPool: b.actPool,
-		p2p:     b.p2p,
-		clock:   b.clock,
+		cfg:      b.cfg,
+		addr:     b.addr,
+		chain:    b.chain,
+		actPool:  b.actPool,
+		p2p:      b.p2p,
+		clock:    b.clock,
+		wal:      newWAL(b.walStore),
+		detector: newEquivocationDetector(),
 		candidatesByHeightFunc: b.candidatesByHeightFunc,
 	}
 	cfsm, err := newConsensusFSM(&ctx)
@@ -7,7 +7,9 @@
 package rolldpos
 
 import (
+	"encoding/hex"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"net"
 	"testing"
@@ -26,9 +28,12 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/crypto"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/dispatch/dispatcher"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/network/node"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
@@ -68,7 +73,7 @@ func TestRollDPoSCtx(t *testing.T) {
 			NumDelegates: 4,
 		},
 		func(blockchain *mock_blockchain.MockBlockchain) {
-			blockchain.EXPECT().TipHeight().Return(uint64(8)).Times(3)
+			blockchain.EXPECT().TipHeight().Return(uint64(8)).Times(5)
 			blockchain.EXPECT().GetBlockByHeight(uint64(8)).Return(blk, nil).Times(1)
 			blockchain.EXPECT().CandidatesByHeight(gomock.Any()).Return([]*state.Candidate{
 				{Address: candidates[0]},
@@ -98,10 +103,28 @@ func TestRollDPoSCtx(t *testing.T) {
 		numSubEpochs: 1,
 		delegates:    delegates,
 	}
-	proposer, height, err := ctx.rotatedProposer()
+	proposer, height, round, err := ctx.rotatedProposer()
 	require.NoError(t, err)
 	assert.Equal(t, candidates[1], proposer)
 	assert.Equal(t, uint64(9), height)
+	assert.Equal(t, uint32(0), round)
+
+	// The proposer falls back to the next delegate in the next round at the height
+	ctx.round = roundCtx{height: 9}
+	proposer, height, round, err = ctx.rotatedProposer()
+	require.NoError(t, err)
+	assert.Equal(t, candidates[2], proposer)
+	assert.Equal(t, uint64(9), height)
+	assert.Equal(t, uint32(1), round)
+
+	// The restarted node resumes the last round recorded at the height
+	require.NoError(t, ctx.wal.append(&iproto.ViewChangeMsg{Vctype: iproto.ViewChangeMsg_PREVOTE, Height: 9, Round: 2}))
+	ctx.round = roundCtx{}
+	proposer, height, round, err = ctx.rotatedProposer()
+	require.NoError(t, err)
+	assert.Equal(t, candidates[3], proposer)
+	assert.Equal(t, uint64(9), height)
+	assert.Equal(t, uint32(2), round)
 
 	clock.Add(time.Second)
 	duration, err := ctx.calcDurationSinceLastBlock()
@@ -183,6 +206,36 @@ func TestIsEpochFinished(t *testing.T) {
 	})
 }
 
+func TestRollingCandidatesNoSubEpoch(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	candidates := make([]*state.Candidate, 4)
+	for i := 0; i < len(candidates); i++ {
+		candidates[i] = &state.Candidate{Address: testAddrs[i].RawAddress}
+	}
+	// No sub-epoch counts as one sub-epoch, so the delegates of epoch 3 are chosen at the end of epoch 2
+	ctx := makeTestRollDPoSCtx(
+		testAddrs[0],
+		ctrl,
+		config.RollDPoS{
+			NumSubEpochs: 0,
+			NumDelegates: 4,
+		},
+		func(blockchain *mock_blockchain.MockBlockchain) {
+			blockchain.EXPECT().CandidatesByHeight(uint64(8)).Return(candidates, nil).Times(1)
+		},
+		func(_ *mock_actpool.MockActPool) {},
+		func(_ *mock_network.MockOverlay) {},
+		clock.NewMock(),
+	)
+	delegates, err := ctx.rollingCandidates(3)
+	require.NoError(t, err)
+	assert.Equal(t, 4, len(delegates))
+}
+
 func TestNewRollDPoS(t *testing.T) {
 	t.Parallel()
 
@@ -272,18 +325,22 @@ func TestRollDPoS_convertToConsensusEvt(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
+	addr := newTestAddr()
 	r, err := NewRollDPoSBuilder().
-		SetConfig(config.RollDPoS{}).
+		SetConfig(config.RollDPoS{NumDelegates: 1}).
 		SetAddr(newTestAddr()).
 		SetBlockchain(mock_blockchain.NewMockBlockchain(ctrl)).
 		SetActPool(mock_actpool.NewMockActPool(ctrl)).
 		SetP2P(mock_network.NewMockOverlay(ctrl)).
+		SetCandidatesByHeightFunc(func(_ uint64) ([]*state.Candidate, error) {
+			return []*state.Candidate{{Address: addr.RawAddress, PubKey: addr.PublicKey[:]}}, nil
+		}).
 		Build()
 	assert.NoError(t, err)
 	assert.NotNil(t, r)
+	r.ctx.setRound(roundCtx{height: 1})
 
 	// Test propose msg
-	addr := newTestAddr()
 	transfer, err := action.NewTransfer(1, big.NewInt(100), "src", "dst", []byte{}, uint64(100000), big.NewInt(10))
 	require.NoError(t, err)
 	selfPubKey := testaddress.Addrinfo["producer"].PublicKey
@@ -300,52 +357,171 @@ func TestRollDPoS_convertToConsensusEvt(t *testing.T) {
 		[]*action.Transfer{transfer}, []*action.Vote{vote},
 		nil,
 	)
-	msg := iproto.ViewChangeMsg{
-		Vctype:     iproto.ViewChangeMsg_PROPOSE,
-		Block:      blk.ConvertToBlockPb(),
-		SenderAddr: addr.RawAddress,
-	}
-	evt, err := r.convertToConsensusEvt(&msg)
+	pEvt := newProposeBlkEvt(blk, addr.RawAddress, r.ctx.clock)
+	pEvt.height = blk.Height()
+	require.NoError(t, pEvt.sign(addr))
+	msg, err := pEvt.toProtoMsg()
+	require.NoError(t, err)
+	evt, err := r.convertToConsensusEvt(msg)
 	assert.NoError(t, err)
 	assert.NotNil(t, evt)
 	pbEvt, ok := evt.(*proposeBlkEvt)
 	assert.True(t, ok)
 	assert.NotNil(t, pbEvt.block)
 
+	// Test propose msg of the next height mismatching the block
+	msg.Height = 2
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, dispatcher.ErrInvalidMsg, errors.Cause(err))
+
+	// Test propose msg of the current height mismatching the block
+	r.ctx.setRound(roundCtx{height: 2})
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, dispatcher.ErrInvalidMsg, errors.Cause(err))
+	r.ctx.setRound(roundCtx{height: 1})
+
 	// Test prevote msg
 	blkHash := blk.HashBlock()
-	msg = iproto.ViewChangeMsg{
-		Vctype:     iproto.ViewChangeMsg_PREVOTE,
-		BlockHash:  blkHash[:],
-		SenderAddr: addr.RawAddress,
-	}
-	evt, err = r.convertToConsensusEvt(&msg)
+	pvEvt := newVoteEvt(ePrevote, blkHash, true, addr.RawAddress, r.ctx.clock)
+	pvEvt.height = 1
+	require.NoError(t, pvEvt.sign(addr))
+	msg, err = pvEvt.toProtoMsg()
+	require.NoError(t, err)
+	evt, err = r.convertToConsensusEvt(msg)
 	assert.NoError(t, err)
 	assert.NotNil(t, evt)
 	_, ok = evt.(*voteEvt)
 	assert.True(t, ok)
 
-	// Test prevote msg
-	msg = iproto.ViewChangeMsg{
+	// Test prevote msg with a tampered decision
+	msg.Decision = false
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, dispatcher.ErrInvalidMsg, errors.Cause(err))
+
+	// Test vote msg
+	vEvt := newVoteEvt(eVote, blkHash, true, addr.RawAddress, r.ctx.clock)
+	vEvt.height = 1
+	require.NoError(t, vEvt.sign(addr))
+	msg, err = vEvt.toProtoMsg()
+	require.NoError(t, err)
+	evt, err = r.convertToConsensusEvt(msg)
+	assert.NoError(t, err)
+	assert.NotNil(t, evt)
+	_, ok = evt.(*voteEvt)
+	assert.True(t, ok)
+
+	// Test vote msg signed as a prevote
+	msg.Vctype = iproto.ViewChangeMsg_PREVOTE
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, dispatcher.ErrInvalidMsg, errors.Cause(err))
+
+	// Test unsigned vote msg
+	msg = &iproto.ViewChangeMsg{
 		Vctype:     iproto.ViewChangeMsg_VOTE,
 		BlockHash:  blkHash[:],
 		SenderAddr: addr.RawAddress,
+		Height:     1,
 	}
-	evt, err = r.convertToConsensusEvt(&msg)
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, dispatcher.ErrInvalidMsg, errors.Cause(err))
+
+	// Test vote msg of the next height, which is kept for the time the node enters the height
+	vEvt = newVoteEvt(eVote, blkHash, true, addr.RawAddress, r.ctx.clock)
+	vEvt.height = 2
+	require.NoError(t, vEvt.sign(addr))
+	msg, err = vEvt.toProtoMsg()
+	require.NoError(t, err)
+	evt, err = r.convertToConsensusEvt(msg)
 	assert.NoError(t, err)
 	assert.NotNil(t, evt)
-	_, ok = evt.(*voteEvt)
-	assert.True(t, ok)
+
+	// Test vote msg of a far height, which is dropped without penalizing the sender
+	vEvt = newVoteEvt(eVote, blkHash, true, addr.RawAddress, r.ctx.clock)
+	vEvt.height = 3
+	require.NoError(t, vEvt.sign(addr))
+	msg, err = vEvt.toProtoMsg()
+	require.NoError(t, err)
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, ErrUnmatchedHeight, errors.Cause(err))
+
+	// Test vote msg from a non-delegate
+	other := newTestAddr()
+	vEvt = newVoteEvt(eVote, blkHash, true, other.RawAddress, r.ctx.clock)
+	vEvt.height = 1
+	require.NoError(t, vEvt.sign(other))
+	msg, err = vEvt.toProtoMsg()
+	require.NoError(t, err)
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, dispatcher.ErrInvalidMsg, errors.Cause(err))
+
+	// Test vote msg claiming to be from the delegate but signed by another key
+	msg.SenderAddr = addr.RawAddress
+	_, err = r.convertToConsensusEvt(msg)
+	assert.Equal(t, dispatcher.ErrInvalidMsg, errors.Cause(err))
 
 	// Test invalid msg
-	msg = iproto.ViewChangeMsg{
-		Vctype: 100,
+	msg = &iproto.ViewChangeMsg{
+		Vctype:     100,
+		SenderAddr: addr.RawAddress,
+		Height:     1,
 	}
-	evt, err = r.convertToConsensusEvt(&msg)
+	evt, err = r.convertToConsensusEvt(msg)
 	assert.Error(t, err)
 	assert.Nil(t, evt)
 }
 
+func TestRollDPoS_convertToConsensusEvtEquivocation(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	addr := newTestAddr()
+	actPool := mock_actpool.NewMockActPool(ctrl)
+	actPool.EXPECT().AddEvidence(gomock.Any()).Return(nil).Times(2)
+	p2p := mock_network.NewMockOverlay(ctrl)
+	p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(2)
+	r, err := NewRollDPoSBuilder().
+		SetConfig(config.RollDPoS{NumDelegates: 1}).
+		SetAddr(newTestAddr()).
+		SetBlockchain(mock_blockchain.NewMockBlockchain(ctrl)).
+		SetActPool(actPool).
+		SetP2P(p2p).
+		SetCandidatesByHeightFunc(func(_ uint64) ([]*state.Candidate, error) {
+			return []*state.Candidate{{Address: addr.RawAddress, PubKey: addr.PublicKey[:]}}, nil
+		}).
+		Build()
+	require.NoError(t, err)
+	r.ctx.setRound(roundCtx{height: 1})
+
+	prevote := func(height uint64, blkHash hash.Hash32B) *iproto.ViewChangeMsg {
+		evt := newVoteEvt(ePrevote, blkHash, true, addr.RawAddress, r.ctx.clock)
+		evt.height = height
+		require.NoError(t, evt.sign(addr))
+		msg, err := evt.toProtoMsg()
+		require.NoError(t, err)
+		return msg
+	}
+
+	// Conflicting prevotes at the current height
+	_, err = r.convertToConsensusEvt(prevote(1, hash.Hash32B{1}))
+	require.NoError(t, err)
+	_, err = r.convertToConsensusEvt(prevote(1, hash.Hash32B{2}))
+	assert.Equal(t, ErrEquivocation, errors.Cause(err))
+
+	// Conflicting prevotes at the next height are detected before the node enters the height
+	_, err = r.convertToConsensusEvt(prevote(2, hash.Hash32B{1}))
+	require.NoError(t, err)
+	_, err = r.convertToConsensusEvt(prevote(2, hash.Hash32B{2}))
+	assert.Equal(t, ErrEquivocation, errors.Cause(err))
+
+	// Prevotes at a far height are dropped before being checked
+	_, err = r.convertToConsensusEvt(prevote(3, hash.Hash32B{1}))
+	assert.Equal(t, ErrUnmatchedHeight, errors.Cause(err))
+	_, err = r.convertToConsensusEvt(prevote(3, hash.Hash32B{2}))
+	assert.Equal(t, ErrUnmatchedHeight, errors.Cause(err))
+}
+
 func makeTestRollDPoSCtx(
 	addr *iotxaddress.Address,
 	ctrl *gomock.Controller,
@@ -362,12 +538,14 @@ func makeTestRollDPoSCtx(
 	p2p := mock_network.NewMockOverlay(ctrl)
 	mockP2P(p2p)
 	return &rollDPoSCtx{
-		cfg:     cfg,
-		addr:    addr,
-		chain:   chain,
-		actPool: actPool,
-		p2p:     p2p,
-		clock:   clock,
+		cfg:      cfg,
+		addr:     addr,
+		chain:    chain,
+		actPool:  actPool,
+		p2p:      p2p,
+		clock:    clock,
+		wal:      newWAL(db.NewMemKVStore()),
+		detector: newEquivocationDetector(),
 	}
 }
 
@@ -410,7 +588,12 @@ func (o *directOverlay) GetPeers() []net.Addr {
 func TestRollDPoSConsensus(t *testing.T) {
 	t.Parallel()
 
-	newConsensusComponents := func(numNodes int) ([]*RollDPoS, []*directOverlay, []blockchain.Blockchain) {
+	// newConsensusComponents creates the nodes of a chain. If commitCertHeight is set, the chains require the commit
+	// certificates from the height, with the nodes as the genesis delegates
+	newConsensusComponents := func(
+		numNodes int,
+		commitCertHeight uint64,
+	) ([]*RollDPoS, []*directOverlay, []blockchain.Blockchain) {
 		cfg := config.Default
 		cfg.Consensus.RollDPoS.Delay = 300 * time.Millisecond
 		cfg.Consensus.RollDPoS.ProposerInterval = time.Second
@@ -440,11 +623,16 @@ func TestRollDPoSConsensus(t *testing.T) {
 		candidatesByHeightFunc := func(_ uint64) ([]*state.Candidate, error) {
 			candidates := make([]*state.Candidate, 0, numNodes)
 			for _, addr := range chainAddrs {
-				candidates = append(candidates, &state.Candidate{Address: addr.RawAddress})
+				candidates = append(candidates, &state.Candidate{Address: addr.RawAddress, PubKey: addr.PublicKey[:]})
 			}
 			return candidates, nil
 		}
 
+		if commitCertHeight > 0 {
+			cfg.Chain.Upgrades.CommitCertHeight = commitCertHeight
+			cfg.Chain.GenesisPath = writeDelegatesGenesis(t, chainAddrs)
+		}
+
 		chains := make([]blockchain.Blockchain, 0, numNodes)
 		p2ps := make([]*directOverlay, 0, numNodes)
 		cs := make([]*RollDPoS, 0, numNodes)
@@ -480,12 +668,16 @@ func TestRollDPoSConsensus(t *testing.T) {
 				}
 			}
 		}
+		// The genesis is loaded once the chains are created
+		if cfg.Chain.GenesisPath != "" {
+			testutil.CleanupPath(t, cfg.Chain.GenesisPath)
+		}
 		return cs, p2ps, chains
 	}
 
 	t.Run("1-block", func(t *testing.T) {
 		ctx := context.Background()
-		cs, p2ps, chains := newConsensusComponents(4)
+		cs, p2ps, chains := newConsensusComponents(4, 0)
 
 		for i := 0; i < 4; i++ {
 			require.NoError(t, chains[i].Start(ctx))
@@ -515,7 +707,7 @@ func TestRollDPoSConsensus(t *testing.T) {
 			t.Skip("Skip the 10-epoch test in short mode.")
 		}
 		ctx := context.Background()
-		cs, p2ps, chains := newConsensusComponents(4)
+		cs, p2ps, chains := newConsensusComponents(4, 0)
 
 		for i := 0; i < 4; i++ {
 			require.NoError(t, chains[i].Start(ctx))
@@ -540,32 +732,9 @@ func TestRollDPoSConsensus(t *testing.T) {
 		}))
 	})
 
-	checkChains := func(chains []blockchain.Blockchain, height uint64) {
-		assert.NoError(t, testutil.WaitUntil(100*time.Millisecond, 10*time.Second, func() (bool, error) {
-			for _, chain := range chains {
-				blk, err := chain.GetBlockByHeight(height)
-				if blk == nil || err != nil {
-					return false, nil
-				}
-				if !blk.IsDummyBlock() {
-					return true, errors.New("not a dummy block")
-				}
-			}
-			return true, nil
-		}))
-	}
-
-	t.Run("proposer-network-partition-dummy-block", func(t *testing.T) {
+	t.Run("commit-cert-upgrade", func(t *testing.T) {
 		ctx := context.Background()
-		cs, p2ps, chains := newConsensusComponents(4)
-		// 1 should be the block 1's proposer
-		for i, p2p := range p2ps {
-			if i == 1 {
-				p2p.peers = make(map[net.Addr]*RollDPoS)
-			} else {
-				delete(p2p.peers, p2ps[1].addr)
-			}
-		}
+		cs, p2ps, chains := newConsensusComponents(4, 1)
 
 		for i := 0; i < 4; i++ {
 			require.NoError(t, chains[i].Start(ctx))
@@ -580,42 +749,40 @@ func TestRollDPoSConsensus(t *testing.T) {
 				require.NoError(t, chains[i].Stop(ctx))
 			}
 		}()
-
-		checkChains(chains, 1)
-	})
-
-	t.Run("non-proposer-network-partition-dummy-block", func(t *testing.T) {
-		ctx := context.Background()
-		cs, p2ps, chains := newConsensusComponents(4)
-		// 1 should be the block 1's proposer
-		for i, p2p := range p2ps {
-			if i == 0 {
-				p2p.peers = make(map[net.Addr]*RollDPoS)
-			} else {
-				delete(p2p.peers, p2ps[0].addr)
+		// The proposals without commit certificates are accepted, and the blocks are committed with the certificates
+		assert.NoError(t, testutil.WaitUntil(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+			for _, chain := range chains {
+				blk, err := chain.GetBlockByHeight(2)
+				if blk == nil || err != nil {
+					return false, nil
+				}
+				if blk.CommitCert == nil {
+					return false, errors.New("a block is committed without the commit certificate")
+				}
 			}
-		}
-
-		for i := 0; i < 4; i++ {
-			require.NoError(t, chains[i].Start(ctx))
-			require.NoError(t, p2ps[i].Start(ctx))
-			require.NoError(t, cs[i].Start(ctx))
-		}
+			return true, nil
+		}))
+	})
 
-		defer func() {
-			for i := 0; i < 4; i++ {
-				require.NoError(t, cs[i].Stop(ctx))
-				require.NoError(t, p2ps[i].Stop(ctx))
-				require.NoError(t, chains[i].Stop(ctx))
+	// checkChains checks the chains of the nodes of the given indexes commit a non-dummy block at the height
+	checkChains := func(chains []blockchain.Blockchain, indexes []int, height uint64) {
+		assert.NoError(t, testutil.WaitUntil(100*time.Millisecond, 10*time.Second, func() (bool, error) {
+			for _, i := range indexes {
+				blk, err := chains[i].GetBlockByHeight(height)
+				if blk == nil || err != nil {
+					return false, nil
+				}
+				if blk.IsDummyBlock() {
+					return false, errors.New("a dummy block is committed")
+				}
 			}
-		}()
-
-		checkChains(chains, 1)
-	})
+			return true, nil
+		}))
+	}
 
-	t.Run("network-partition-time-rotation", func(t *testing.T) {
+	t.Run("proposer-network-partition-next-round", func(t *testing.T) {
 		ctx := context.Background()
-		cs, p2ps, chains := newConsensusComponents(4)
+		cs, p2ps, chains := newConsensusComponents(4, 0)
 		// 1 should be the block 1's proposer
 		for i, p2p := range p2ps {
 			if i == 1 {
@@ -626,8 +793,6 @@ func TestRollDPoSConsensus(t *testing.T) {
 		}
 
 		for i := 0; i < 4; i++ {
-			cs[i].ctx.cfg.TimeBasedRotation = true
-			cs[i].ctx.cfg.EnableDummyBlock = false
 			require.NoError(t, chains[i].Start(ctx))
 			require.NoError(t, p2ps[i].Start(ctx))
 			require.NoError(t, cs[i].Start(ctx))
@@ -641,12 +806,16 @@ func TestRollDPoSConsensus(t *testing.T) {
 			}
 		}()
 
-		checkChains(chains, 4)
+		// The block 1 is proposed by the next delegate in the next round, instead of being a dummy block
+		checkChains(chains, []int{0, 2, 3}, 1)
+		blk, err := chains[1].GetBlockByHeight(1)
+		assert.Nil(t, blk)
+		assert.Error(t, err)
 	})
 
-	t.Run("proposer-network-partition-blocking", func(t *testing.T) {
+	t.Run("network-partition-time-rotation", func(t *testing.T) {
 		ctx := context.Background()
-		cs, p2ps, chains := newConsensusComponents(4)
+		cs, p2ps, chains := newConsensusComponents(4, 0)
 		// 1 should be the block 1's proposer
 		for i, p2p := range p2ps {
 			if i == 1 {
@@ -657,7 +826,7 @@ func TestRollDPoSConsensus(t *testing.T) {
 		}
 
 		for i := 0; i < 4; i++ {
-			cs[i].ctx.cfg.EnableDummyBlock = false
+			cs[i].ctx.cfg.TimeBasedRotation = true
 			require.NoError(t, chains[i].Start(ctx))
 			require.NoError(t, p2ps[i].Start(ctx))
 			require.NoError(t, cs[i].Start(ctx))
@@ -670,17 +839,13 @@ func TestRollDPoSConsensus(t *testing.T) {
 				require.NoError(t, chains[i].Stop(ctx))
 			}
 		}()
-		time.Sleep(2 * time.Second)
-		for _, chain := range chains {
-			blk, err := chain.GetBlockByHeight(1)
-			assert.Nil(t, blk)
-			assert.Error(t, err)
-		}
+
+		checkChains(chains, []int{0, 2, 3}, 4)
 	})
 
 	t.Run("non-proposer-network-partition-blocking", func(t *testing.T) {
 		ctx := context.Background()
-		cs, p2ps, chains := newConsensusComponents(4)
+		cs, p2ps, chains := newConsensusComponents(4, 0)
 		// 1 should be the block 1's proposer
 		for i, p2p := range p2ps {
 			if i == 0 {
@@ -691,7 +856,6 @@ func TestRollDPoSConsensus(t *testing.T) {
 		}
 
 		for i := 0; i < 4; i++ {
-			cs[i].ctx.cfg.EnableDummyBlock = false
 			require.NoError(t, chains[i].Start(ctx))
 			require.NoError(t, p2ps[i].Start(ctx))
 			require.NoError(t, cs[i].Start(ctx))
@@ -717,3 +881,29 @@ func TestRollDPoSConsensus(t *testing.T) {
 		}
 	})
 }
+
+// writeDelegatesGenesis writes a genesis file in which the given addresses nominate themselves as the delegates, and
+// returns the path of the file
+func writeDelegatesGenesis(t *testing.T, delegates []*iotxaddress.Address) string {
+	genesisStr := "delegates:\n"
+	for _, addr := range delegates {
+		vote, err := action.NewVote(0, addr.RawAddress, addr.RawAddress, 0, big.NewInt(0))
+		require.NoError(t, err)
+		vote, err = vote.Sign(addr)
+		require.NoError(t, err)
+		genesisStr += fmt.Sprintf(
+			"- pubKey: %s\n  address: %s\n  signature: %s\n",
+			keypair.EncodePublicKey(addr.PublicKey),
+			addr.RawAddress,
+			hex.EncodeToString(vote.Signature),
+		)
+	}
+	genesisFile, err := ioutil.TempFile("", "genesis")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, genesisFile.Close())
+	}()
+	_, err = genesisFile.WriteString(genesisStr)
+	require.NoError(t, err)
+	return genesisFile.Name()
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+const (
+	// walNamespace is the KV namespace of the consensus write-ahead log
+	walNamespace = "consensusWAL"
+)
+
+var _ lifecycle.StartStopper = (*wal)(nil)
+
+// wal is the write-ahead log of the consensus. It persists the proposal received and the votes signed by the node in
+// each round at each height, and the block locked at each height, before they are acted on, so that a restarted node
+// resumes the round at the height and never signs a conflicting vote in it
+type wal struct {
+	kvStore db.KVStore
+}
+
+func newWAL(kvStore db.KVStore) *wal {
+	return &wal{kvStore: kvStore}
+}
+
+// Start starts the KV store of the log
+func (w *wal) Start(ctx context.Context) error { return w.kvStore.Start(ctx) }
+
+// Stop stops the KV store of the log
+func (w *wal) Stop(ctx context.Context) error { return w.kvStore.Stop(ctx) }
+
+// append records the message in its round at its height, which replaces the message of the same type recorded before
+func (w *wal) append(msg *iproto.ViewChangeMsg) error {
+	value, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "error when marshaling the view change message")
+	}
+	if err := w.kvStore.Put(walNamespace, walKey(msg.Height, msg.Round, msg.Vctype), value); err != nil {
+		return errors.Wrapf(
+			err,
+			"error when writing %s in round %d at height %d into the WAL",
+			msg.Vctype,
+			msg.Round,
+			msg.Height,
+		)
+	}
+	return nil
+}
+
+// get returns the message of the type recorded in the round at the height, or nil if there is none
+func (w *wal) get(
+	height uint64,
+	round uint32,
+	vctype iproto.ViewChangeMsg_ViewChangeType,
+) (*iproto.ViewChangeMsg, error) {
+	value, err := w.kvStore.Get(walNamespace, walKey(height, round, vctype))
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, bolt.ErrBucketNotFound:
+		return nil, nil
+	default:
+		return nil, errors.Wrapf(
+			err,
+			"error when reading %s in round %d at height %d from the WAL",
+			vctype,
+			round,
+			height,
+		)
+	}
+	msg := &iproto.ViewChangeMsg{}
+	if err := proto.Unmarshal(value, msg); err != nil {
+		return nil, errors.Wrap(err, "error when unmarshaling the view change message")
+	}
+	return msg, nil
+}
+
+// lastRound returns the highest round in which a message is recorded at the height, which is 0 if there is none
+func (w *wal) lastRound(height uint64) (uint32, error) {
+	var round uint32
+	if err := w.kvStore.ForEach(walNamespace, func(k, _ []byte) error {
+		// The key of the locked block doesn't carry a round
+		if byteutil.BytesToUint64(k[:8]) != height || bytes.Equal(k, walLockKey(height)) {
+			return nil
+		}
+		if r := byteutil.BytesToUint32(k[8:12]); r > round {
+			round = r
+		}
+		return nil
+	}); err != nil {
+		return 0, errors.Wrapf(err, "error when iterating the WAL at height %d", height)
+	}
+	return round, nil
+}
+
+// lock records the block locked at its height and the round it's locked in
+func (w *wal) lock(blk *blockchain.Block, round uint32) error {
+	value, err := blk.Serialize()
+	if err != nil {
+		return errors.Wrap(err, "error when serializing the locked block")
+	}
+	value = append(byteutil.Uint32ToBytes(round), value...)
+	if err := w.kvStore.Put(walNamespace, walLockKey(blk.Height()), value); err != nil {
+		return errors.Wrapf(err, "error when writing the block locked at height %d into the WAL", blk.Height())
+	}
+	return nil
+}
+
+// lockedBlock returns the block locked at the height and the round it's locked in, or nil if there is none
+func (w *wal) lockedBlock(height uint64) (*blockchain.Block, uint32, error) {
+	value, err := w.kvStore.Get(walNamespace, walLockKey(height))
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, bolt.ErrBucketNotFound:
+		return nil, 0, nil
+	default:
+		return nil, 0, errors.Wrapf(err, "error when reading the block locked at height %d from the WAL", height)
+	}
+	if len(value) < 4 {
+		return nil, 0, errors.Errorf("the block locked at height %d in the WAL is malformed", height)
+	}
+	blk := &blockchain.Block{}
+	if err := blk.Deserialize(value[4:]); err != nil {
+		return nil, 0, errors.Wrap(err, "error when deserializing the locked block")
+	}
+	return blk, byteutil.BytesToUint32(value[:4]), nil
+}
+
+// prune deletes the messages and the locked blocks recorded below the height, which are not needed once the blocks at
+// them are committed
+func (w *wal) prune(height uint64) error {
+	var keys [][]byte
+	if err := w.kvStore.ForEach(walNamespace, func(k, _ []byte) error {
+		if byteutil.BytesToUint64(k[:8]) < height {
+			keys = append(keys, k)
+		}
+		return nil
+	}); err != nil {
+		return errors.Wrap(err, "error when iterating the WAL")
+	}
+	for _, k := range keys {
+		if err := w.kvStore.Delete(walNamespace, k); err != nil {
+			return errors.Wrap(err, "error when deleting from the WAL")
+		}
+	}
+	return nil
+}
+
+func walKey(height uint64, round uint32, vctype iproto.ViewChangeMsg_ViewChangeType) []byte {
+	key := append(byteutil.Uint64ToBytes(height), byteutil.Uint32ToBytes(round)...)
+	return append(key, byteutil.Uint32ToBytes(uint32(vctype))...)
+}
+
+func walLockKey(height uint64) []byte {
+	return append(byteutil.Uint64ToBytes(height), []byte("lockedBlock")...)
+}
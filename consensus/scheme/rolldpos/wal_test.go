@@ -0,0 +1,202 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package rolldpos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+	"github.com/iotexproject/iotex-core/testutil"
+)
+
+func TestWAL(t *testing.T) {
+	require := require.New(t)
+
+	w := newWAL(db.NewMemKVStore())
+	require.NoError(w.Start(context.Background()))
+	defer func() {
+		require.NoError(w.Stop(context.Background()))
+	}()
+
+	msg, err := w.get(1, 0, iproto.ViewChangeMsg_PREVOTE)
+	require.NoError(err)
+	require.Nil(msg)
+
+	prevote1 := &iproto.ViewChangeMsg{Vctype: iproto.ViewChangeMsg_PREVOTE, Height: 1, Decision: true}
+	vote1 := &iproto.ViewChangeMsg{Vctype: iproto.ViewChangeMsg_VOTE, Height: 1, Decision: true}
+	prevote2 := &iproto.ViewChangeMsg{Vctype: iproto.ViewChangeMsg_PREVOTE, Height: 2}
+	require.NoError(w.append(prevote1))
+	require.NoError(w.append(vote1))
+	require.NoError(w.append(prevote2))
+
+	msg, err = w.get(1, 0, iproto.ViewChangeMsg_PREVOTE)
+	require.NoError(err)
+	require.True(msg.Decision)
+	msg, err = w.get(1, 0, iproto.ViewChangeMsg_VOTE)
+	require.NoError(err)
+	require.Equal(iproto.ViewChangeMsg_VOTE, msg.Vctype)
+
+	// The messages of different rounds at the height are recorded apart
+	require.NoError(w.append(&iproto.ViewChangeMsg{Vctype: iproto.ViewChangeMsg_PREVOTE, Height: 1, Round: 1}))
+	msg, err = w.get(1, 1, iproto.ViewChangeMsg_PREVOTE)
+	require.NoError(err)
+	require.False(msg.Decision)
+	msg, err = w.get(1, 0, iproto.ViewChangeMsg_PREVOTE)
+	require.NoError(err)
+	require.True(msg.Decision)
+	round, err := w.lastRound(1)
+	require.NoError(err)
+	require.Equal(uint32(1), round)
+	round, err = w.lastRound(3)
+	require.NoError(err)
+	require.Equal(uint32(0), round)
+
+	// The locked blocks are recorded by height
+	blk, _, err := w.lockedBlock(1)
+	require.NoError(err)
+	require.Nil(blk)
+	blk1 := blockchain.NewBlock(0, 1, hash.ZeroHash32B, clock.New(), nil, nil, nil)
+	blk2 := blockchain.NewBlock(0, 2, blk1.HashBlock(), clock.New(), nil, nil, nil)
+	require.NoError(w.lock(blk1, 0))
+	require.NoError(w.lock(blk2, 3))
+	blk, round, err = w.lockedBlock(1)
+	require.NoError(err)
+	require.Equal(blk1.HashBlock(), blk.HashBlock())
+	require.Equal(uint32(0), round)
+	// The round of the locked block doesn't count as a recorded round
+	round, err = w.lastRound(2)
+	require.NoError(err)
+	require.Equal(uint32(0), round)
+
+	require.NoError(w.prune(2))
+	blk, _, err = w.lockedBlock(1)
+	require.NoError(err)
+	require.Nil(blk)
+	blk, round, err = w.lockedBlock(2)
+	require.NoError(err)
+	require.Equal(blk2.HashBlock(), blk.HashBlock())
+	require.Equal(uint32(3), round)
+	msg, err = w.get(1, 0, iproto.ViewChangeMsg_PREVOTE)
+	require.NoError(err)
+	require.Nil(msg)
+	msg, err = w.get(1, 1, iproto.ViewChangeMsg_PREVOTE)
+	require.NoError(err)
+	require.Nil(msg)
+	msg, err = w.get(1, 0, iproto.ViewChangeMsg_VOTE)
+	require.NoError(err)
+	require.Nil(msg)
+	msg, err = w.get(2, 0, iproto.ViewChangeMsg_PREVOTE)
+	require.NoError(err)
+	require.Equal(uint64(2), msg.Height)
+}
+
+func TestWALReplay(t *testing.T) {
+	require := require.New(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	delegates := make([]string, 4)
+	for i := 0; i < 4; i++ {
+		delegates[i] = testAddrs[i].RawAddress
+	}
+	epoch := epochCtx{
+		delegates:    delegates,
+		num:          uint64(1),
+		height:       uint64(1),
+		numSubEpochs: uint(1),
+	}
+	newRound := func() roundCtx {
+		return roundCtx{
+			height:       2,
+			prevotes:     make(map[string]bool),
+			votes:        make(map[string]bool),
+			endorsements: make(map[string][]byte),
+			proposer:     delegates[2],
+		}
+	}
+
+	cfsm := newTestCFSM(
+		t,
+		testAddrs[0],
+		ctrl,
+		delegates,
+		nil,
+		func(p2p *mock_network.MockOverlay) {
+			p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(1)
+		},
+		clock.New(),
+	)
+	cfsm.ctx.epoch = epoch
+	cfsm.ctx.round = newRound()
+	blk, err := cfsm.ctx.mintBlock()
+	require.NoError(err)
+	pbEvt := newProposeBlkEvt(blk, delegates[2], cfsm.ctx.clock)
+	pbEvt.height = blk.Height()
+	_, err = cfsm.handleProposeBlockEvt(pbEvt)
+	require.NoError(err)
+	prevote, ok := (<-cfsm.evtq).(*voteEvt)
+	require.True(ok)
+	require.True(prevote.decision)
+
+	// The restarted node replays the proposal and doesn't sign a conflicting prevote at the height
+	restarted := newTestCFSM(t, testAddrs[0], ctrl, delegates, nil, func(_ *mock_network.MockOverlay) {}, clock.New())
+	restarted.ctx.wal = cfsm.ctx.wal
+	restarted.ctx.epoch = epoch
+	restarted.ctx.round = newRound()
+	replayed := restarted.recordedProposeBlkEvt()
+	require.NotNil(replayed)
+	require.Equal(blk.HashBlock(), replayed.block.HashBlock())
+	require.Equal(delegates[2], replayed.proposer)
+
+	pv, err := restarted.newPrevoteEvt(blk.HashBlock(), false)
+	require.NoError(err)
+	require.True(pv.decision)
+	require.Equal(prevote.signature, pv.signature)
+
+	// A vote not signed before is signed and recorded
+	v, err := restarted.newVoteEvt(blk.HashBlock(), false)
+	require.NoError(err)
+	require.False(v.decision)
+	require.True(v.verify(testAddrs[0].PublicKey))
+	msg, err := restarted.ctx.wal.get(2, 0, iproto.ViewChangeMsg_VOTE)
+	require.NoError(err)
+	require.Equal(v.signature, msg.Signature)
+
+	// The block locked before the node restarted is restored
+	locked, _, err := restarted.restoreLock(2)
+	require.NoError(err)
+	require.Nil(locked)
+	require.NoError(cfsm.ctx.wal.lock(blk, 1))
+	locked, lockedRound, err := restarted.restoreLock(2)
+	require.NoError(err)
+	require.Equal(blk.HashBlock(), locked.HashBlock())
+	require.Equal(uint32(1), lockedRound)
+
+	// The node doesn't vote if the WAL fails
+	path := "/tmp/test-consensus-wal.db"
+	testutil.CleanupPath(t, path)
+	defer testutil.CleanupPath(t, path)
+	failed := newWAL(db.NewBoltDB(path, &config.Default.DB))
+	require.NoError(failed.Start(context.Background()))
+	require.NoError(failed.Stop(context.Background()))
+	restarted.ctx.wal = failed
+	restarted.ctx.round.num = 1
+	pv, err = restarted.newPrevoteEvt(blk.HashBlock(), true)
+	require.Error(err)
+	require.Nil(pv)
+}
@@ -58,6 +58,11 @@ func (v *byzVal) Validate(blk *blockchain.Block, tipHeight uint64, tipHash hash.
 	return nil
 }
 
+// ValidateProposal for the byzantine node accepts any proposed block as Validate does
+func (v *byzVal) ValidateProposal(blk *blockchain.Block, tipHeight uint64, tipHash hash.Hash32B) error {
+	return nil
+}
+
 // Ping implements simulator.SimulatorServer
 func (s *server) Init(in *pb.InitRequest, stream pb.Simulator_InitServer) error {
 	nPlayers := in.NBF + in.NFS + in.NHonest
@@ -7,17 +7,22 @@
 package crypto
 
 import (
+	"github.com/pkg/errors"
 	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 )
 
+// ErrInvalidLeafIndex indicates the leaf index is out of the range of merkle tree
+var ErrInvalidLeafIndex = errors.New("invalid merkle leaf index")
+
 // Merkle tree struct
 type Merkle struct {
-	root hash.Hash32B
-	leaf []hash.Hash32B
-	size int
+	root  hash.Hash32B
+	leaf  []hash.Hash32B
+	size  int
+	count int // number of leaves before the last one is copied
 }
 
 // NewMerkleTree creates a merkle tree given hashed leaves
@@ -29,8 +34,9 @@ func NewMerkleTree(leaves []hash.Hash32B) *Merkle {
 	}
 
 	mk := &Merkle{
-		leaf: make([]hash.Hash32B, (size+1)>>1<<1),
-		size: size,
+		leaf:  make([]hash.Hash32B, (size+1)>>1<<1),
+		size:  size,
+		count: size,
 	}
 
 	copy(mk.leaf, leaves)
@@ -83,3 +89,65 @@ func (mk *Merkle) HashTree() hash.Hash32B {
 	mk.root = merkle[0]
 	return mk.root
 }
+
+// AuditPath returns the hashes of the siblings on the path from the leaf at index to the root, ordered from the
+// bottom level to the top level. The path can be checked by VerifyAuditPath. The copy of the last leaf padding a
+// tree of odd number of leaves is not a leaf
+func (mk *Merkle) AuditPath(index int) ([]hash.Hash32B, error) {
+	if index < 0 || index >= mk.count {
+		return nil, errors.Wrapf(ErrInvalidLeafIndex, "index = %d, count = %d", index, mk.count)
+	}
+	path := []hash.Hash32B{}
+	if mk.size == 1 {
+		// the root is the only leaf
+		return path, nil
+	}
+	level := make([]hash.Hash32B, mk.size)
+	copy(level, mk.leaf[:mk.size])
+	for len(level) > 1 {
+		// copy the last hash if the level has odd number of nodes, same as HashTree()
+		if len(level)&1 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+		path = append(path, level[index^1])
+		next := make([]hash.Hash32B, len(level)>>1)
+		for i := range next {
+			next[i] = hashPair(level[i<<1], level[i<<1+1])
+		}
+		level = next
+		index >>= 1
+	}
+	return path, nil
+}
+
+// VerifyAuditPath checks whether the audit path proves the leaf at index is in the merkle tree with the root, which
+// is built on count leaves. The index of the copy padding the last leaf is rejected, as well as a path not as long
+// as the height of the tree
+func VerifyAuditPath(root hash.Hash32B, leaf hash.Hash32B, index int, count int, path []hash.Hash32B) bool {
+	if index < 0 || index >= count {
+		return false
+	}
+	height := 0
+	for n := count; n > 1; n = (n + 1) >> 1 {
+		height++
+	}
+	if len(path) != height {
+		return false
+	}
+	h := leaf
+	for _, sibling := range path {
+		if index&1 == 0 {
+			h = hashPair(h, sibling)
+		} else {
+			h = hashPair(sibling, h)
+		}
+		index >>= 1
+	}
+	// index must be consumed by the path, otherwise the leaf is not at the given position
+	return index == 0 && h == root
+}
+
+func hashPair(left, right hash.Hash32B) hash.Hash32B {
+	h := append(left[:], right[:]...)
+	return blake2b.Sum256(h)
+}
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
 
 	"github.com/iotexproject/iotex-core/pkg/hash"
 )
@@ -69,3 +70,37 @@ func TestMerkleTree(t *testing.T) {
 	assert.Equal(t, 0, bytes.Compare(expected[:], actual5[:]))
 	assert.Equal(t, -1, bytes.Compare(actual5[:], actual4[:]))
 }
+
+func TestMerkleAuditPath(t *testing.T) {
+	var inputs []hash.Hash32B
+	for i := 0; i < 9; i++ {
+		inputs = append(inputs, blake2b.Sum256([]byte{byte(i)}))
+		m := NewMerkleTree(inputs)
+		root := m.HashTree()
+		for j, leaf := range inputs {
+			path, err := m.AuditPath(j)
+			assert.Nil(t, err)
+			assert.True(t, VerifyAuditPath(root, leaf, j, len(inputs), path))
+			// wrong position, leaf or count
+			assert.False(t, VerifyAuditPath(root, leaf, j+1<<uint(len(path)), len(inputs), path))
+			assert.False(t, VerifyAuditPath(root, hash.ZeroHash32B, j, len(inputs), path))
+			assert.False(t, VerifyAuditPath(root, leaf, j, j, path))
+			if len(path) > 0 {
+				assert.False(t, VerifyAuditPath(root, leaf, j, len(inputs), path[1:]))
+			}
+		}
+		_, err := m.AuditPath(-1)
+		assert.Error(t, err)
+		_, err = m.AuditPath(len(inputs))
+		assert.Error(t, err)
+		_, err = m.AuditPath(len(inputs) + 1)
+		assert.Error(t, err)
+		if n := len(inputs); n > 1 && n&1 != 0 {
+			// the copy padding the last leaf hashes to the root with the path of the last leaf, but it is not a leaf
+			last := inputs[n-1]
+			path, err := m.AuditPath(n - 1)
+			assert.Nil(t, err)
+			assert.False(t, VerifyAuditPath(root, last, n, n, path))
+		}
+	}
+}
@@ -8,6 +8,7 @@ package db
 
 import (
 	"context"
+	"strings"
 	"sync"
 
 	"github.com/boltdb/bolt"
@@ -38,6 +39,8 @@ type KVStore interface {
 	Get(string, []byte) ([]byte, error)
 	// Delete deletes a record by (namespace, key)
 	Delete(string, []byte) error
+	// ForEach iterates over all records in a namespace
+	ForEach(string, func([]byte, []byte) error) error
 	// Batch return a kv store batch api object
 	Batch() KVStoreBatch
 }
@@ -99,6 +102,21 @@ func (m *memKVStore) Delete(namespace string, key []byte) error {
 	return nil
 }
 
+// ForEach iterates over all records in a namespace
+func (m *memKVStore) ForEach(namespace string, fn func([]byte, []byte) error) error {
+	prefix := namespace + keyDelimiter
+	var err error
+	m.data.Range(func(k, v interface{}) bool {
+		key := k.(string)
+		if !strings.HasPrefix(key, prefix) {
+			return true
+		}
+		err = fn([]byte(strings.TrimPrefix(key, prefix)), v.([]byte))
+		return err == nil
+	})
+	return err
+}
+
 // Batch return a kv store batch api object
 func (m *memKVStore) Batch() KVStoreBatch {
 	return NewMemKVStoreBatch(m)
@@ -235,6 +253,20 @@ func (b *boltDB) Delete(namespace string, key []byte) error {
 	return err
 }
 
+// ForEach iterates over all records in a namespace
+func (b *boltDB) ForEach(namespace string, fn func([]byte, []byte) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(namespace))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			// key and value are only valid during the transaction, pass a copy to the callback
+			return fn(append([]byte{}, k...), append([]byte{}, v...))
+		})
+	})
+}
+
 // Batch return a kv store batch api object
 func (b *boltDB) Batch() KVStoreBatch {
 	return NewBoltDBBatch(b)
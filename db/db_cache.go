@@ -87,6 +87,12 @@ func (c *cachedKVStore) Delete(namespace string, key []byte) error {
 	return c.KVStoreBatch.Delete(namespace, key, "failed to delete key = %x", key)
 }
 
+// ForEach iterates over all records in a namespace of the underlying KVStore, the pending writes in the local cache
+// are not included
+func (c *cachedKVStore) ForEach(namespace string, fn func([]byte, []byte) error) error {
+	return c.kv.ForEach(namespace, fn)
+}
+
 // Clear clear write queue
 func (c *cachedKVStore) Clear() error {
 	c.mutex.Lock()
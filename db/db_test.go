@@ -80,6 +80,55 @@ func TestKVStorePutGet(t *testing.T) {
 	})
 }
 
+func TestKVStoreForEach(t *testing.T) {
+	testKVStoreForEach := func(kvStore KVStore, t *testing.T) {
+		require := require.New(t)
+		ctx := context.Background()
+
+		require.Nil(kvStore.Start(ctx))
+		defer func() {
+			require.Nil(kvStore.Stop(ctx))
+		}()
+
+		// iterating a non-existing namespace is a no-op
+		require.Nil(kvStore.ForEach(bucket1, func(k, v []byte) error {
+			return errors.New("should not be called")
+		}))
+		for i := 0; i < len(testK1); i++ {
+			require.Nil(kvStore.Put(bucket1, testK1[i], testV1[i]))
+			require.Nil(kvStore.Put(bucket2, testK2[i], testV2[i]))
+		}
+		records := make(map[string]string)
+		require.Nil(kvStore.ForEach(bucket1, func(k, v []byte) error {
+			records[string(k)] = string(v)
+			return nil
+		}))
+		require.Equal(len(testK1), len(records))
+		for i := 0; i < len(testK1); i++ {
+			require.Equal(string(testV1[i]), records[string(testK1[i])])
+		}
+		// stop iterating on error
+		count := 0
+		err := kvStore.ForEach(bucket2, func(k, v []byte) error {
+			count++
+			return ErrInvalidDB
+		})
+		require.Equal(ErrInvalidDB, errors.Cause(err))
+		require.Equal(1, count)
+	}
+
+	t.Run("In-memory KV Store", func(t *testing.T) {
+		testKVStoreForEach(NewMemKVStore(), t)
+	})
+
+	path := "/tmp/test-kv-store-" + strconv.Itoa(rand.Int())
+	t.Run("Bolt DB", func(t *testing.T) {
+		testutil.CleanupPath(t, path)
+		defer testutil.CleanupPath(t, path)
+		testKVStoreForEach(NewBoltDB(path, cfg), t)
+	})
+}
+
 func TestBatchRollback(t *testing.T) {
 	testBatchRollback := func(kvStore KVStore, t *testing.T) {
 		assert := assert.New(t)
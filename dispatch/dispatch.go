@@ -188,6 +188,13 @@ func (d *IotxDispatcher) handleActionMsg(m *actionMsg) {
 			requestMtc.WithLabelValues("addExecution", "false").Inc()
 			logger.Debug().Err(err).Msg("Failed to add execution")
 		}
+	} else if pbEvidence := m.action.GetEvidence(); pbEvidence != nil {
+		evidence := &action.Evidence{}
+		evidence.ConvertFromActionPb(m.action)
+		if err := d.ap.AddEvidence(evidence); err != nil {
+			requestMtc.WithLabelValues("addEvidence", "false").Inc()
+			logger.Debug().Err(err).Msg("Failed to add evidence")
+		}
 	}
 	// signal to let caller know we are done
 	if m.done != nil {
@@ -285,7 +292,7 @@ func (d *IotxDispatcher) dispatchBlockSyncData(msg proto.Message, done chan bool
 }
 
 // HandleBroadcast handles incoming broadcast message
-func (d *IotxDispatcher) HandleBroadcast(message proto.Message, done chan bool) {
+func (d *IotxDispatcher) HandleBroadcast(message proto.Message, done chan bool) error {
 	msgType, err := pb.GetTypeFromProtoMsg(message)
 	if err != nil {
 		logger.Warn().
@@ -300,6 +307,7 @@ func (d *IotxDispatcher) HandleBroadcast(message proto.Message, done chan bool)
 			logger.Error().
 				Err(err).
 				Msgf("failed to handle view change")
+			return err
 		}
 	case pb.MsgActionType:
 		d.dispatchAction(message, done)
@@ -310,6 +318,7 @@ func (d *IotxDispatcher) HandleBroadcast(message proto.Message, done chan bool)
 			Uint32("msgType", msgType).
 			Msg("unexpected msgType handled by HandleBroadcast")
 	}
+	return nil
 }
 
 // HandleTell handles incoming unicast message
@@ -11,17 +11,22 @@ import (
 	"net"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
 )
 
+// ErrInvalidMsg indicates the error of an invalid message, whose sender should be penalized
+var ErrInvalidMsg = errors.New("invalid message")
+
 // Dispatcher is used by peers, handles incoming block and header notifications and relays announcements of new blocks.
 type Dispatcher interface {
 	lifecycle.StartStopper
 
 	// HandleBroadcast handles the incoming broadcast message. The transportation layer semantics is at least once.
-	// That said, the handler is likely to receive duplicate messages.
-	HandleBroadcast(proto.Message, chan bool)
+	// That said, the handler is likely to receive duplicate messages. An error caused by ErrInvalidMsg is returned if
+	// the message is forged or malformed, so that the message is not relayed further.
+	HandleBroadcast(proto.Message, chan bool) error
 	// HandleTell handles the incoming tell message. The transportation layer semantics is exact once. The sender is
 	// given for the sake of replying the message
 	HandleTell(net.Addr, proto.Message, chan bool)
@@ -142,7 +142,7 @@ func TestLocalCommit(t *testing.T) {
 	require.Nil(err)
 
 	tsf, _, _ := svr.ActionPool().PickActs()
-	blk1, err := svr.Blockchain().MintNewBlock(tsf, nil, nil, ta.Addrinfo["producer"], "")
+	blk1, err := svr.Blockchain().MintNewBlock(tsf, nil, nil, nil, ta.Addrinfo["producer"], "")
 	hash1 := blk1.HashBlock()
 	require.Nil(err)
 
@@ -509,7 +509,7 @@ func TestVoteLocalCommit(t *testing.T) {
 	require.Nil(err)
 
 	transfers, votes, executions := svr.ActionPool().PickActs()
-	blk1, err := svr.Blockchain().MintNewBlock(transfers, votes, executions, ta.Addrinfo["producer"], "")
+	blk1, err := svr.Blockchain().MintNewBlock(transfers, votes, executions, nil, ta.Addrinfo["producer"], "")
 	hash1 := blk1.HashBlock()
 	require.Nil(err)
 
@@ -751,7 +751,7 @@ func TestDummyBlockReplacement(t *testing.T) {
 	require.Nil(err)
 
 	tsf, _, _ := svr.ActionPool().PickActs()
-	blk1, err := originChain.MintNewBlock(tsf, nil, nil, ta.Addrinfo["producer"], "")
+	blk1, err := originChain.MintNewBlock(tsf, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.Nil(err)
 
 	err = p.Broadcast(blk1.ConvertToBlockPb())
@@ -791,7 +791,7 @@ func TestDummyBlockReplacement(t *testing.T) {
 	require.Nil(err)
 
 	tsf, _, _ = svr.ActionPool().PickActs()
-	blk2, err := originChain.MintNewBlock(tsf, nil, nil, ta.Addrinfo["producer"], "")
+	blk2, err := originChain.MintNewBlock(tsf, nil, nil, nil, ta.Addrinfo["producer"], "")
 	require.Nil(err)
 	err = p.Broadcast(blk2.ConvertToBlockPb())
 	require.NoError(err)
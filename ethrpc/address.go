@@ -0,0 +1,40 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package ethrpc
+
+import (
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/iotxaddress"
+)
+
+// An io1 address is the bech32 encoding of the address version, the chain ID and the 20-byte hash of the public key.
+// The EVM only sees the 20-byte hash, so the EVM address of an io1 address is its public key hash, and an EVM address
+// maps back to the io1 address with the version and chain ID of this node. For example, with the default chain ID
+//
+//   io1qyqsyqcyqy352euf40x77qfrg4ncn27dauqjx3t8n66v49 <-> 0x0123456789abcdef0123456789abcdef01234567
+//
+// The mapping is the same one used by the EVM to read the accounts and to emit the contract addresses and logs.
+
+// ToEthAddress returns the EVM address of an io1 address
+func ToEthAddress(rawAddr string) (common.Address, error) {
+	pkHash, err := iotxaddress.GetPubkeyHash(rawAddr)
+	if err != nil {
+		return common.Address{}, errors.Wrapf(err, "invalid address %s", rawAddr)
+	}
+	return common.BytesToAddress(pkHash), nil
+}
+
+// FromEthAddress returns the io1 address of an EVM address on the chain of this node
+func FromEthAddress(addr common.Address) (string, error) {
+	iotxAddr, err := iotxaddress.GetAddressByHash(iotxaddress.IsTestnet, iotxaddress.ChainID, addr.Bytes())
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid EVM address %x", addr.Bytes())
+	}
+	return iotxAddr.RawAddress, nil
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package ethrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/network"
+)
+
+// maxRequestBytes limits the size of a JSON-RPC request or a batch of requests
+const maxRequestBytes = 5 * 1024 * 1024
+
+// JSON-RPC 2.0 error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeServerError    = -32000
+)
+
+type (
+	request struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+	}
+
+	response struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  json.RawMessage `json:"result,omitempty"`
+		Error   *rpcError       `json:"error,omitempty"`
+	}
+
+	rpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+)
+
+// Server serves the Ethereum-compatible JSON-RPC gateway over HTTP
+type Server struct {
+	cfg     config.EthRPC
+	svc     *Service
+	httpSvr http.Server
+	port    int
+}
+
+// NewServer instantiates an Ethereum-compatible JSON-RPC server
+func NewServer(cfg config.EthRPC, chain blockchain.Blockchain, actPool actpool.ActPool, p2p network.Overlay) *Server {
+	return &Server{
+		cfg: cfg,
+		svc: &Service{
+			bc:  chain,
+			ap:  actPool,
+			p2p: p2p,
+			cfg: cfg,
+		},
+	}
+}
+
+// Start starts the JSON-RPC server
+func (s *Server) Start(_ context.Context) error {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(s.cfg.Port))
+	if err != nil {
+		return errors.Wrap(err, "error when creating network listener")
+	}
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		return errors.Wrapf(err, "error when spliting addr %s", listener.Addr().String())
+	}
+	if s.port, err = strconv.Atoi(port); err != nil {
+		return errors.Wrapf(err, "error when converting port %s to int", port)
+	}
+	logger.Info().Msgf("Starting eth JSON-RPC server on %s", listener.Addr().String())
+	s.httpSvr = http.Server{Handler: s}
+	go func() {
+		if err := s.httpSvr.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Panic().Err(err).Msg("error when serving eth JSON-RPC requests")
+		}
+	}()
+	return nil
+}
+
+// Stop stops the JSON-RPC server
+func (s *Server) Stop(ctx context.Context) error {
+	if err := s.httpSvr.Shutdown(ctx); err != nil {
+		return errors.Wrap(err, "error when shutting down eth JSON-RPC http server")
+	}
+	return nil
+}
+
+// Port returns the actually binding port
+func (s *Server) Port() int {
+	return s.port
+}
+
+// ServeHTTP serves a JSON-RPC 2.0 request or a batch of requests posted in the body
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			s.encode(encoder, newErrorResponse(nil, codeParseError, err))
+			return
+		}
+		if len(batch) == 0 {
+			s.encode(encoder, newErrorResponse(nil, codeInvalidRequest, errors.New("empty batch")))
+			return
+		}
+		resps := make([]*response, 0, len(batch))
+		for _, req := range batch {
+			resps = append(resps, s.handle(req))
+		}
+		s.encode(encoder, resps)
+		return
+	}
+	s.encode(encoder, s.handle(body))
+}
+
+func (s *Server) handle(body []byte) *response {
+	var req request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return newErrorResponse(nil, codeParseError, err)
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return newErrorResponse(req.ID, codeInvalidRequest, errors.New("invalid JSON-RPC 2.0 request"))
+	}
+	logger.Debug().Str("method", req.Method).Msg("receive eth JSON-RPC request")
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newErrorResponse(req.ID, codeInvalidParams, errors.New("params should be an array"))
+		}
+	}
+	result, err := s.svc.invoke(req.Method, params)
+	if err != nil {
+		code := codeServerError
+		switch errors.Cause(err) {
+		case ErrMethodNotFound:
+			code = codeMethodNotFound
+		case ErrInvalidParams, ErrUnsupportedTransaction:
+			code = codeInvalidParams
+		}
+		return newErrorResponse(req.ID, code, err)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return newErrorResponse(req.ID, codeServerError, err)
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: data}
+}
+
+func (s *Server) encode(encoder *json.Encoder, v interface{}) {
+	if err := encoder.Encode(v); err != nil {
+		logger.Error().Err(err).Msg("Failed to write eth JSON-RPC response")
+	}
+}
+
+func newErrorResponse(id json.RawMessage, code int, err error) *response {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return &response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: code, Message: err.Error()}}
+}
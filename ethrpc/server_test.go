@@ -0,0 +1,278 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package ethrpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/core/types"
+	"github.com/CoderZhi/go-ethereum/rlp"
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/test/mock/mock_actpool"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	"github.com/iotexproject/iotex-core/test/mock/mock_network"
+	"github.com/iotexproject/iotex-core/test/mock/mock_state"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestAddressMapping(t *testing.T) {
+	require := require.New(t)
+
+	addr, err := ToEthAddress("io1qyqsyqcyqy352euf40x77qfrg4ncn27dauqjx3t8n66v49")
+	require.NoError(err)
+	require.Equal("0x0123456789abcdef0123456789abcdef01234567", strings.ToLower(addr.Hex()))
+	rawAddr, err := FromEthAddress(addr)
+	require.NoError(err)
+	require.Equal("io1qyqsyqcyqy352euf40x77qfrg4ncn27dauqjx3t8n66v49", rawAddr)
+
+	producer := ta.Addrinfo["producer"].RawAddress
+	addr, err = ToEthAddress(producer)
+	require.NoError(err)
+	rawAddr, err = FromEthAddress(addr)
+	require.NoError(err)
+	require.Equal(producer, rawAddr)
+
+	_, err = ToEthAddress("0x0123456789abcdef0123456789abcdef01234567")
+	require.Error(err)
+}
+
+func TestServer(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bc := mock_blockchain.NewMockBlockchain(ctrl)
+	ap := mock_actpool.NewMockActPool(ctrl)
+	p2p := mock_network.NewMockOverlay(ctrl)
+	sf := mock_state.NewMockFactory(ctrl)
+	view := mock_state.NewMockFactory(ctrl)
+	bc.EXPECT().TipHeight().Return(uint64(10)).AnyTimes()
+	bc.EXPECT().GetFactory().Return(sf).AnyTimes()
+	sf.EXPECT().ViewAtHeight(gomock.Any()).Return(view, nil).AnyTimes()
+
+	svr := httptest.NewServer(NewServer(config.Default.EthRPC, bc, ap, p2p))
+	defer svr.Close()
+
+	alfa := ta.Addrinfo["alfa"].RawAddress
+	alfaAddr, err := ToEthAddress(alfa)
+	require.NoError(err)
+	alfaHex := strings.ToLower(alfaAddr.Hex())
+
+	resp := call(t, svr.URL, "eth_blockNumber")
+	require.Nil(resp.Error)
+	require.Equal(`"0xa"`, string(resp.Result))
+
+	// a batch of requests
+	body := `[{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber"},{"jsonrpc":"2.0","id":2,"method":"eth_accounts"}]`
+	httpResp, err := http.Post(svr.URL, "application/json", strings.NewReader(body))
+	require.NoError(err)
+	var resps []response
+	require.NoError(json.NewDecoder(httpResp.Body).Decode(&resps))
+	require.NoError(httpResp.Body.Close())
+	require.Equal(2, len(resps))
+	require.Equal(`"0xa"`, string(resps[0].Result))
+	require.Equal(codeMethodNotFound, resps[1].Error.Code)
+
+	view.EXPECT().Balance(alfa).Return(big.NewInt(100), nil).Times(1)
+	resp = call(t, svr.URL, "eth_getBalance", alfaHex, "latest")
+	require.Nil(resp.Error)
+	require.Equal(`"0x64"`, string(resp.Result))
+	view.EXPECT().Balance(alfa).Return(nil, errors.Wrap(state.ErrAccountNotExist, "alfa")).Times(1)
+	resp = call(t, svr.URL, "eth_getBalance", alfaHex, "0x1")
+	require.Nil(resp.Error)
+	require.Equal(`"0x0"`, string(resp.Result))
+	resp = call(t, svr.URL, "eth_getBalance", alfa, "latest")
+	require.Equal(codeInvalidParams, resp.Error.Code)
+	resp = call(t, svr.URL, "eth_getBalance", alfaHex, "0xb")
+	require.Equal(codeServerError, resp.Error.Code)
+	resp = call(t, svr.URL, "eth_getBalance")
+	require.Equal(codeInvalidParams, resp.Error.Code)
+
+	view.EXPECT().State(alfa).Return(&state.State{Balance: big.NewInt(0), CodeHash: []byte{1}}, nil).Times(1)
+	view.EXPECT().GetCode(addrHash(alfaHex)).Return([]byte{0x60, 0x80}, nil).Times(1)
+	resp = call(t, svr.URL, "eth_getCode", alfaHex, "latest")
+	require.Nil(resp.Error)
+	require.Equal(`"0x6080"`, string(resp.Result))
+	view.EXPECT().State(alfa).Return(&state.State{Balance: big.NewInt(0)}, nil).Times(1)
+	resp = call(t, svr.URL, "eth_getCode", alfaHex, "latest")
+	require.Nil(resp.Error)
+	require.Equal(`"0x"`, string(resp.Result))
+
+	slot := hash.Hash32B{31: 1}
+	value := hash.Hash32B{31: 0xff}
+	view.EXPECT().GetContractState(addrHash(alfaHex), slot).Return(value, nil).Times(1)
+	resp = call(t, svr.URL, "eth_getStorageAt", alfaHex, "0x1", "latest")
+	require.Nil(resp.Error)
+	require.Equal(`"0x`+hex.EncodeToString(value[:])+`"`, string(resp.Result))
+	resp = call(t, svr.URL, "eth_getStorageAt", alfaHex, "0xzz", "latest")
+	require.Equal(codeInvalidParams, resp.Error.Code)
+
+	// call a contract without a sender
+	view.EXPECT().Nonce(gomock.Any()).Return(uint64(0), errors.Wrap(state.ErrAccountNotExist, "zero")).Times(1)
+	bc.EXPECT().ExecuteContractReadAtHeight(gomock.Any(), uint64(10)).
+		Do(func(ex *action.Execution, _ uint64) {
+			require.Equal(alfa, ex.Contract)
+			require.Equal(uint64(1), ex.Nonce)
+			require.Equal([]byte{0x12, 0x34}, ex.Data)
+		}).Return([]byte{0x56}, nil).Times(1)
+	resp = call(t, svr.URL, "eth_call", map[string]string{"to": alfaHex, "data": "0x1234"}, "latest")
+	require.Nil(resp.Error)
+	require.Equal(`"0x56"`, string(resp.Result))
+
+	// a receipt not on the chain is null
+	txHash := hash.Hash32B{1}
+	bc.EXPECT().GetReceiptByActionHash(txHash).Return(nil, errors.Wrap(db.ErrNotExist, "receipt")).Times(1)
+	resp = call(t, svr.URL, "eth_getTransactionReceipt", "0x"+hex.EncodeToString(txHash[:]))
+	require.Nil(resp.Error)
+	require.Equal("null", string(resp.Result))
+
+	tsf, err := action.NewTransfer(1, big.NewInt(1), ta.Addrinfo["producer"].RawAddress, alfa, []byte{}, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	tsf, err = tsf.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	data, err := proto.Marshal(tsf.ConvertToActionPb())
+	require.NoError(err)
+	ap.EXPECT().AddTsf(gomock.Any()).Do(func(act *action.Transfer) {
+		require.Equal(tsf.Hash(), act.Hash())
+	}).Return(nil).Times(1)
+	p2p.EXPECT().Broadcast(gomock.Any()).Return(nil).Times(1)
+	resp = call(t, svr.URL, "eth_sendRawTransaction", "0x"+hex.EncodeToString(data))
+	require.Nil(resp.Error)
+	tsfHash := tsf.Hash()
+	require.Equal(`"0x`+hex.EncodeToString(tsfHash[:])+`"`, string(resp.Result))
+	// a signed Ethereum transaction cannot be converted into an action
+	ethTx, err := rlp.EncodeToBytes(types.NewTransaction(1, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil))
+	require.NoError(err)
+	resp = call(t, svr.URL, "eth_sendRawTransaction", "0x"+hex.EncodeToString(ethTx))
+	require.Equal(codeInvalidParams, resp.Error.Code)
+	require.True(strings.Contains(resp.Error.Message, "signed Ethereum transactions are not accepted"))
+
+	// a receipt on the chain
+	blk := blockchain.NewGenesisBlock(nil)
+	bc.EXPECT().GetReceiptByActionHash(tsfHash).Return(&blockchain.Receipt{
+		Status:      blockchain.SuccessStatus,
+		Hash:        tsfHash,
+		GasConsumed: 10000,
+		BlockHeight: 11,
+		BlockHash:   hash.Hash32B{2},
+	}, nil).Times(1)
+	bc.EXPECT().GetTransferByTransferHash(tsfHash).Return(tsf, nil).Times(1)
+	bc.EXPECT().GetBlockByHash(hash.Hash32B{2}).Return(blk, nil).Times(1)
+	resp = call(t, svr.URL, "eth_getTransactionReceipt", "0x"+hex.EncodeToString(tsfHash[:]))
+	require.Nil(resp.Error)
+	var receipt Receipt
+	require.NoError(json.Unmarshal(resp.Result, &receipt))
+	require.Equal("0xb", receipt.BlockNumber)
+	require.Equal("0x0", receipt.TransactionIndex)
+	require.Equal("0x2710", receipt.CumulativeGasUsed)
+	require.Equal("0x1", receipt.Status)
+	require.Equal(alfaHex, *receipt.To)
+	require.Nil(receipt.ContractAddress)
+}
+
+func TestServer_GetLogs(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bc := mock_blockchain.NewMockBlockchain(ctrl)
+	bc.EXPECT().TipHeight().Return(uint64(10)).AnyTimes()
+	svr := httptest.NewServer(NewServer(config.Default.EthRPC, bc, nil, nil))
+	defer svr.Close()
+
+	contract := ta.Addrinfo["bravo"].RawAddress
+	contractAddr, err := ToEthAddress(contract)
+	require.NoError(err)
+	topic1 := hash.Hash32B{1}
+	topic2 := hash.Hash32B{2}
+	blkHash := hash.Hash32B{4}
+	var tsfs []*action.Transfer
+	for nonce := uint64(1); nonce <= 3; nonce++ {
+		tsf, err := action.NewTransfer(nonce, big.NewInt(1), ta.Addrinfo["producer"].RawAddress, contract, []byte{}, uint64(100000), big.NewInt(0))
+		require.NoError(err)
+		tsfs = append(tsfs, tsf)
+	}
+	txHash := tsfs[2].Hash()
+	logs := []*blockchain.Log{
+		{Address: contract, Topics: []hash.Hash32B{topic2}, BlockNumber: 5, TxnHash: txHash},
+		{Address: contract, Topics: []hash.Hash32B{topic1}, BlockNumber: 5, TxnHash: txHash},
+		{Address: contract, Topics: []hash.Hash32B{topic1, topic2}, BlockNumber: 5, TxnHash: txHash},
+	}
+	filter := &blockchain.LogFilter{
+		FromHeight: 1,
+		ToHeight:   10,
+		Addresses:  []string{contract},
+		Topics:     [][]hash.Hash32B{{topic1}},
+	}
+	bc.EXPECT().GetLogs(filter, uint64(0), config.Default.EthRPC.MaxLogs).Return(logs[1:], nil).Times(1)
+	bc.EXPECT().GetReceiptByActionHash(txHash).Return(&blockchain.Receipt{Logs: logs, Index: 2, BlockHash: blkHash}, nil).Times(2)
+	// the actions before emit 3 logs in the block
+	bc.EXPECT().GetBlockByHash(blkHash).Return(&blockchain.Block{Transfers: tsfs}, nil).Times(1)
+	bc.EXPECT().GetReceiptByActionHash(tsfs[0].Hash()).Return(&blockchain.Receipt{Logs: logs[:1]}, nil).Times(1)
+	bc.EXPECT().GetReceiptByActionHash(tsfs[1].Hash()).Return(&blockchain.Receipt{Logs: logs[:2]}, nil).Times(1)
+
+	resp := call(t, svr.URL, "eth_getLogs", map[string]interface{}{
+		"fromBlock": "0x1",
+		"address":   contractAddr.Hex(),
+		"topics":    []interface{}{"0x" + hex.EncodeToString(topic1[:])},
+	})
+	require.Nil(resp.Error)
+	var res []*Log
+	require.NoError(json.Unmarshal(resp.Result, &res))
+	require.Equal(2, len(res))
+	require.Equal(strings.ToLower(contractAddr.Hex()), res[0].Address)
+	require.Equal("0x5", res[0].BlockNumber)
+	require.Equal("0x2", res[0].TransactionIndex)
+	// the logs are indexed by their positions in the block
+	require.Equal("0x4", res[0].LogIndex)
+	require.Equal("0x5", res[1].LogIndex)
+	require.Equal(2, len(res[1].Topics))
+
+	resp = call(t, svr.URL, "eth_getLogs", map[string]interface{}{"fromBlock": "0x2", "toBlock": "0x1"})
+	require.Equal(codeInvalidParams, resp.Error.Code)
+	resp = call(t, svr.URL, "eth_getLogs", map[string]interface{}{"address": common.Address{}.Hex(), "topics": []interface{}{nil, 1}})
+	require.Equal(codeInvalidParams, resp.Error.Code)
+}
+
+func call(t *testing.T, url string, method string, params ...interface{}) *response {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	require.NoError(t, err)
+	httpResp, err := http.Post(url, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer httpResp.Body.Close()
+	require.Equal(t, http.StatusOK, httpResp.StatusCode)
+	var resp response
+	require.NoError(t, json.NewDecoder(httpResp.Body).Decode(&resp))
+	require.Equal(t, "1", string(resp.ID))
+	return &resp
+}
@@ -0,0 +1,715 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package ethrpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/CoderZhi/go-ethereum/common"
+	"github.com/CoderZhi/go-ethereum/common/hexutil"
+	"github.com/CoderZhi/go-ethereum/core/types"
+	"github.com/CoderZhi/go-ethereum/rlp"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/network"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+var (
+	// ErrInvalidParams indicates the params of a JSON-RPC request are invalid
+	ErrInvalidParams = errors.New("invalid params")
+	// ErrMethodNotFound indicates the method of a JSON-RPC request is not supported
+	ErrMethodNotFound = errors.New("method not found")
+	// ErrUnsupportedTransaction indicates the raw transaction is a signed Ethereum transaction, which cannot be
+	// converted into an action
+	ErrUnsupportedTransaction = errors.New("unsupported transaction")
+)
+
+type (
+	// CallArgs is the message call of eth_call
+	CallArgs struct {
+		From     string `json:"from"`
+		To       string `json:"to"`
+		Gas      string `json:"gas"`
+		GasPrice string `json:"gasPrice"`
+		Value    string `json:"value"`
+		Data     string `json:"data"`
+	}
+
+	// FilterQuery is the log filter of eth_getLogs. Address is an address or an array of addresses, and each of the
+	// Topics is null, a topic or an array of topics
+	FilterQuery struct {
+		BlockHash string            `json:"blockHash"`
+		FromBlock string            `json:"fromBlock"`
+		ToBlock   string            `json:"toBlock"`
+		Address   json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+
+	// Log is a contract log in the Ethereum format. LogIndex is the position of the log in the logs emitted by all the
+	// actions of its block
+	Log struct {
+		Address          string   `json:"address"`
+		Topics           []string `json:"topics"`
+		Data             string   `json:"data"`
+		BlockNumber      string   `json:"blockNumber"`
+		TransactionHash  string   `json:"transactionHash"`
+		TransactionIndex string   `json:"transactionIndex"`
+		BlockHash        string   `json:"blockHash"`
+		LogIndex         string   `json:"logIndex"`
+		Removed          bool     `json:"removed"`
+	}
+
+	// Receipt is the receipt of a transfer, vote or execution in the Ethereum format
+	Receipt struct {
+		TransactionHash   string  `json:"transactionHash"`
+		TransactionIndex  string  `json:"transactionIndex"`
+		BlockHash         string  `json:"blockHash"`
+		BlockNumber       string  `json:"blockNumber"`
+		From              string  `json:"from"`
+		To                *string `json:"to"`
+		CumulativeGasUsed string  `json:"cumulativeGasUsed"`
+		GasUsed           string  `json:"gasUsed"`
+		ContractAddress   *string `json:"contractAddress"`
+		Logs              []*Log  `json:"logs"`
+		Status            string  `json:"status"`
+	}
+)
+
+// Service maps the eth_* JSON-RPC methods onto the blockchain, its state factory and the actpool. The transactions are
+// the transfers, votes and executions, and their hashes are the action hashes
+type Service struct {
+	bc  blockchain.Blockchain
+	ap  actpool.ActPool
+	p2p network.Overlay
+	cfg config.EthRPC
+}
+
+// invoke calls the method with the positional params
+func (s *Service) invoke(method string, params []json.RawMessage) (interface{}, error) {
+	switch method {
+	case "eth_blockNumber":
+		return s.BlockNumber()
+	case "eth_getBalance":
+		var addr, block string
+		if err := parseParams(params, 1, &addr, &block); err != nil {
+			return nil, err
+		}
+		return s.GetBalance(addr, block)
+	case "eth_call":
+		var args CallArgs
+		var block string
+		if err := parseParams(params, 1, &args, &block); err != nil {
+			return nil, err
+		}
+		return s.Call(args, block)
+	case "eth_getTransactionReceipt":
+		var txHash string
+		if err := parseParams(params, 1, &txHash); err != nil {
+			return nil, err
+		}
+		return s.GetTransactionReceipt(txHash)
+	case "eth_getCode":
+		var addr, block string
+		if err := parseParams(params, 1, &addr, &block); err != nil {
+			return nil, err
+		}
+		return s.GetCode(addr, block)
+	case "eth_getStorageAt":
+		var addr, position, block string
+		if err := parseParams(params, 2, &addr, &position, &block); err != nil {
+			return nil, err
+		}
+		return s.GetStorageAt(addr, position, block)
+	case "eth_getLogs":
+		var query FilterQuery
+		if err := parseParams(params, 1, &query); err != nil {
+			return nil, err
+		}
+		return s.GetLogs(query)
+	case "eth_sendRawTransaction":
+		var data string
+		if err := parseParams(params, 1, &data); err != nil {
+			return nil, err
+		}
+		return s.SendRawTransaction(data)
+	default:
+		return nil, errors.Wrapf(ErrMethodNotFound, "method %s", method)
+	}
+}
+
+// BlockNumber returns the tip height
+func (s *Service) BlockNumber() (string, error) {
+	return hexutil.EncodeUint64(s.bc.TipHeight()), nil
+}
+
+// GetBalance returns the balance of an account at a block
+func (s *Service) GetBalance(addr string, block string) (string, error) {
+	rawAddr, err := decodeAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	sf, err := s.stateAt(block)
+	if err != nil {
+		return "", err
+	}
+	balance, err := sf.Balance(rawAddr)
+	if errors.Cause(err) == state.ErrAccountNotExist {
+		return hexutil.EncodeBig(big.NewInt(0)), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return hexutil.EncodeBig(balance), nil
+}
+
+// Call runs a read-only execution at a block and returns its return value. The execution is run off the network, so
+// it costs no gas of the executor, which is the zero address if the call has no sender
+func (s *Service) Call(args CallArgs, block string) (string, error) {
+	height, err := s.height(block)
+	if err != nil {
+		return "", err
+	}
+	executor, err := FromEthAddress(common.Address{})
+	if err != nil {
+		return "", err
+	}
+	if args.From != "" {
+		if executor, err = decodeAddress(args.From); err != nil {
+			return "", err
+		}
+	}
+	contract := action.EmptyAddress
+	if args.To != "" {
+		if contract, err = decodeAddress(args.To); err != nil {
+			return "", err
+		}
+	}
+	gas := blockchain.GasLimit
+	if args.Gas != "" {
+		if gas, err = hexutil.DecodeUint64(args.Gas); err != nil {
+			return "", errors.Wrapf(ErrInvalidParams, "invalid gas %s", args.Gas)
+		}
+	}
+	gasPrice, err := decodeBig(args.GasPrice)
+	if err != nil {
+		return "", err
+	}
+	amount, err := decodeBig(args.Value)
+	if err != nil {
+		return "", err
+	}
+	data, err := hexutil.Decode(orEmptyData(args.Data))
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidParams, "invalid data %s", args.Data)
+	}
+	sf, err := s.bc.GetFactory().ViewAtHeight(height)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get states on height %d", height)
+	}
+	nonce, err := sf.Nonce(executor)
+	if err != nil && errors.Cause(err) != state.ErrAccountNotExist {
+		return "", err
+	}
+	execution, err := action.NewExecution(executor, contract, nonce+1, amount, gas, gasPrice, data)
+	if err != nil {
+		return "", err
+	}
+	res, err := s.bc.ExecuteContractReadAtHeight(execution, height)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(res), nil
+}
+
+// GetTransactionReceipt returns the receipt of a transfer, vote or execution, or nil if it is not on the chain
+func (s *Service) GetTransactionReceipt(txHash string) (*Receipt, error) {
+	h, err := decodeHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := s.bc.GetReceiptByActionHash(h)
+	if errors.Cause(err) == db.ErrNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := s.actionAddresses(h)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := s.bc.GetBlockByHash(receipt.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	// the gas used by the actions before and including this one in the block, and the logs emitted before its logs
+	cumulativeGas := receipt.GasConsumed
+	var logIndex uint
+	for i, actHash := range actionHashes(blk) {
+		if uint(i) >= receipt.Index {
+			break
+		}
+		prev, err := s.bc.GetReceiptByActionHash(actHash)
+		if err != nil {
+			return nil, err
+		}
+		cumulativeGas += prev.GasConsumed
+		logIndex += uint(len(prev.Logs))
+	}
+
+	res := &Receipt{
+		TransactionHash:   hexutil.Encode(receipt.Hash[:]),
+		TransactionIndex:  hexutil.EncodeUint64(uint64(receipt.Index)),
+		BlockHash:         hexutil.Encode(receipt.BlockHash[:]),
+		BlockNumber:       hexutil.EncodeUint64(receipt.BlockHeight),
+		From:              from,
+		To:                to,
+		CumulativeGasUsed: hexutil.EncodeUint64(cumulativeGas),
+		GasUsed:           hexutil.EncodeUint64(receipt.GasConsumed),
+		Logs:              []*Log{},
+		Status:            hexutil.EncodeUint64(receipt.Status),
+	}
+	if receipt.ContractAddress != "" {
+		addr, err := ToEthAddress(receipt.ContractAddress)
+		if err != nil {
+			return nil, err
+		}
+		contractAddr := strings.ToLower(addr.Hex())
+		res.ContractAddress = &contractAddr
+	}
+	for i, log := range receipt.Logs {
+		ethLog, err := convertLog(log, receipt.Index, logIndex+uint(i))
+		if err != nil {
+			return nil, err
+		}
+		res.Logs = append(res.Logs, ethLog)
+	}
+	return res, nil
+}
+
+// GetCode returns the code of a contract at a block, which is empty for an account not being a contract
+func (s *Service) GetCode(addr string, block string) (string, error) {
+	rawAddr, err := decodeAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	sf, err := s.stateAt(block)
+	if err != nil {
+		return "", err
+	}
+	account, err := sf.State(rawAddr)
+	if errors.Cause(err) == state.ErrAccountNotExist {
+		return hexutil.Encode(nil), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(account.CodeHash) == 0 {
+		return hexutil.Encode(nil), nil
+	}
+	code, err := sf.GetCode(addrHash(addr))
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(code), nil
+}
+
+// GetStorageAt returns the value in a storage slot of a contract at a block, which is zero if the slot is never set
+func (s *Service) GetStorageAt(addr string, position string, block string) (string, error) {
+	if _, err := decodeAddress(addr); err != nil {
+		return "", err
+	}
+	key, err := decodeSlot(position)
+	if err != nil {
+		return "", err
+	}
+	sf, err := s.stateAt(block)
+	if err != nil {
+		return "", err
+	}
+	value, err := sf.GetContractState(addrHash(addr), key)
+	switch errors.Cause(err) {
+	case nil:
+	case state.ErrAccountNotExist, trie.ErrNotExist:
+		value = hash.ZeroHash32B
+	default:
+		return "", err
+	}
+	return hexutil.Encode(value[:]), nil
+}
+
+// GetLogs returns the logs matching the filter, up to the max number of logs in the config
+func (s *Service) GetLogs(query FilterQuery) ([]*Log, error) {
+	filter := &blockchain.LogFilter{}
+	if query.BlockHash != "" {
+		h, err := decodeHash(query.BlockHash)
+		if err != nil {
+			return nil, err
+		}
+		blk, err := s.bc.GetBlockByHash(h)
+		if err != nil {
+			return nil, err
+		}
+		filter.FromHeight = blk.Height()
+		filter.ToHeight = blk.Height()
+	} else {
+		var err error
+		if filter.FromHeight, err = s.height(query.FromBlock); err != nil {
+			return nil, err
+		}
+		if filter.ToHeight, err = s.height(query.ToBlock); err != nil {
+			return nil, err
+		}
+	}
+	addresses, err := decodeOneOrMany(query.Address)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addresses {
+		rawAddr, err := decodeAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		filter.Addresses = append(filter.Addresses, rawAddr)
+	}
+	for _, position := range query.Topics {
+		topics, err := decodeOneOrMany(position)
+		if err != nil {
+			return nil, err
+		}
+		var hashes []hash.Hash32B
+		for _, topic := range topics {
+			h, err := decodeHash(topic)
+			if err != nil {
+				return nil, err
+			}
+			hashes = append(hashes, h)
+		}
+		filter.Topics = append(filter.Topics, hashes)
+	}
+	if err := filter.Validate(); err != nil {
+		return nil, errors.Wrap(ErrInvalidParams, err.Error())
+	}
+
+	logs, err := s.bc.GetLogs(filter, 0, s.cfg.MaxLogs)
+	if err != nil {
+		return nil, err
+	}
+	// the receipts of the actions emitting the logs, the positions of the matching logs in their blocks, and the
+	// offsets of the logs of each action in the blocks
+	receipts := make(map[hash.Hash32B]*blockchain.Receipt)
+	positions := make(map[hash.Hash32B][]uint)
+	offsets := make(map[hash.Hash32B][]uint)
+	res := make([]*Log, 0, len(logs))
+	for _, log := range logs {
+		receipt, ok := receipts[log.TxnHash]
+		if !ok {
+			if receipt, err = s.bc.GetReceiptByActionHash(log.TxnHash); err != nil {
+				return nil, err
+			}
+			receipts[log.TxnHash] = receipt
+			blkOffsets, ok := offsets[receipt.BlockHash]
+			if !ok {
+				if blkOffsets, err = s.logOffsets(receipt.BlockHash); err != nil {
+					return nil, err
+				}
+				offsets[receipt.BlockHash] = blkOffsets
+			}
+			if receipt.Index >= uint(len(blkOffsets)) {
+				return nil, errors.Errorf("action %x is not in block %x", log.TxnHash, receipt.BlockHash)
+			}
+			for i, l := range receipt.Logs {
+				if filter.Match(l) {
+					positions[log.TxnHash] = append(positions[log.TxnHash], blkOffsets[receipt.Index]+uint(i))
+				}
+			}
+		}
+		if len(positions[log.TxnHash]) == 0 {
+			return nil, errors.Errorf("log is not found in the receipt of action %x", log.TxnHash)
+		}
+		ethLog, err := convertLog(log, receipt.Index, positions[log.TxnHash][0])
+		if err != nil {
+			return nil, err
+		}
+		positions[log.TxnHash] = positions[log.TxnHash][1:]
+		res = append(res, ethLog)
+	}
+	return res, nil
+}
+
+// SendRawTransaction adds a signed action into the actpool and broadcasts it, and returns its hash. Unlike Ethereum, the
+// raw transaction is the serialized protobuf of the action, since the actions are signed with the keys of IoTeX. An RLP
+// encoded Ethereum transaction is rejected with ErrUnsupportedTransaction, because its secp256k1 signature cannot
+// authorize an action
+func (s *Service) SendRawTransaction(data string) (string, error) {
+	bytes, err := hexutil.Decode(data)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidParams, "invalid raw transaction %s", data)
+	}
+	var tx types.Transaction
+	if err := rlp.DecodeBytes(bytes, &tx); err == nil {
+		return "", errors.Wrap(
+			ErrUnsupportedTransaction,
+			"signed Ethereum transactions are not accepted, send the serialized protobuf of a signed action instead",
+		)
+	}
+	actPb := &iproto.ActionPb{}
+	if err := proto.Unmarshal(bytes, actPb); err != nil {
+		return "", errors.Wrap(ErrInvalidParams, err.Error())
+	}
+	var actHash hash.Hash32B
+	switch {
+	case actPb.GetTransfer() != nil:
+		tsf := &action.Transfer{}
+		tsf.ConvertFromActionPb(actPb)
+		if err := s.ap.AddTsf(tsf); err != nil {
+			return "", err
+		}
+		actHash = tsf.Hash()
+	case actPb.GetVote() != nil:
+		vote := &action.Vote{}
+		vote.ConvertFromActionPb(actPb)
+		if err := s.ap.AddVote(vote); err != nil {
+			return "", err
+		}
+		actHash = vote.Hash()
+	case actPb.GetExecution() != nil:
+		execution := &action.Execution{}
+		execution.ConvertFromActionPb(actPb)
+		if err := s.ap.AddExecution(execution); err != nil {
+			return "", err
+		}
+		actHash = execution.Hash()
+	default:
+		return "", errors.Wrap(ErrInvalidParams, "raw transaction is not a transfer, vote or execution")
+	}
+	if s.p2p != nil {
+		if err := s.p2p.Broadcast(actPb); err != nil {
+			logger.Error().Err(err).Hex("hash", actHash[:]).Msg("Failed to broadcast action")
+		}
+	}
+	return hexutil.Encode(actHash[:]), nil
+}
+
+// height returns the height of a block number, which is a hex number, "earliest", "latest" or "pending". The pending
+// block is the tip, since the pending actions are not executed before being minted into a block
+func (s *Service) height(block string) (uint64, error) {
+	tip := s.bc.TipHeight()
+	switch block {
+	case "", "latest", "pending":
+		return tip, nil
+	case "earliest":
+		return 0, nil
+	}
+	height, err := hexutil.DecodeUint64(block)
+	if err != nil {
+		return 0, errors.Wrapf(ErrInvalidParams, "invalid block number %s", block)
+	}
+	if height > tip {
+		return 0, errors.Errorf("block %d is higher than tip %d", height, tip)
+	}
+	return height, nil
+}
+
+// stateAt returns the read-only view of the states at a block
+func (s *Service) stateAt(block string) (state.Factory, error) {
+	height, err := s.height(block)
+	if err != nil {
+		return nil, err
+	}
+	sf, err := s.bc.GetFactory().ViewAtHeight(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get states on height %d", height)
+	}
+	return sf, nil
+}
+
+// actionAddresses returns the sender and the recipient of an action, where the recipient is nil for a contract
+// creation or an unvote
+func (s *Service) actionAddresses(h hash.Hash32B) (string, *string, error) {
+	var from, to string
+	if tsf, err := s.bc.GetTransferByTransferHash(h); err == nil {
+		from, to = tsf.Sender, tsf.Recipient
+	} else if vote, err := s.bc.GetVoteByVoteHash(h); err == nil {
+		from, to = vote.GetVote().VoterAddress, vote.GetVote().VoteeAddress
+	} else if execution, err := s.bc.GetExecutionByExecutionHash(h); err == nil {
+		from, to = execution.Executor, execution.Contract
+	} else {
+		return "", nil, errors.Wrapf(err, "failed to get action %x", h)
+	}
+	fromAddr, err := ToEthAddress(from)
+	if err != nil {
+		return "", nil, err
+	}
+	if to == "" {
+		return strings.ToLower(fromAddr.Hex()), nil, nil
+	}
+	toAddr, err := ToEthAddress(to)
+	if err != nil {
+		return "", nil, err
+	}
+	toHex := strings.ToLower(toAddr.Hex())
+	return strings.ToLower(fromAddr.Hex()), &toHex, nil
+}
+
+// logOffsets returns the number of the logs emitted by the actions before each action in the block
+func (s *Service) logOffsets(blkHash hash.Hash32B) ([]uint, error) {
+	blk, err := s.bc.GetBlockByHash(blkHash)
+	if err != nil {
+		return nil, err
+	}
+	hashes := actionHashes(blk)
+	offsets := make([]uint, 0, len(hashes))
+	var numLogs uint
+	for _, actHash := range hashes {
+		offsets = append(offsets, numLogs)
+		receipt, err := s.bc.GetReceiptByActionHash(actHash)
+		if err != nil {
+			return nil, err
+		}
+		numLogs += uint(len(receipt.Logs))
+	}
+	return offsets, nil
+}
+
+// actionHashes returns the hashes of the actions in a block, in the order of transfers, votes and executions
+func actionHashes(blk *blockchain.Block) []hash.Hash32B {
+	hashes := make([]hash.Hash32B, 0, len(blk.Transfers)+len(blk.Votes)+len(blk.Executions))
+	for _, tsf := range blk.Transfers {
+		hashes = append(hashes, tsf.Hash())
+	}
+	for _, vote := range blk.Votes {
+		hashes = append(hashes, vote.Hash())
+	}
+	for _, execution := range blk.Executions {
+		hashes = append(hashes, execution.Hash())
+	}
+	return hashes
+}
+
+func convertLog(log *blockchain.Log, txIndex uint, logIndex uint) (*Log, error) {
+	addr, err := ToEthAddress(log.Address)
+	if err != nil {
+		return nil, err
+	}
+	topics := make([]string, 0, len(log.Topics))
+	for _, topic := range log.Topics {
+		topics = append(topics, hexutil.Encode(topic[:]))
+	}
+	return &Log{
+		Address:          strings.ToLower(addr.Hex()),
+		Topics:           topics,
+		Data:             hexutil.Encode(log.Data),
+		BlockNumber:      hexutil.EncodeUint64(log.BlockNumber),
+		TransactionHash:  hexutil.Encode(log.TxnHash[:]),
+		TransactionIndex: hexutil.EncodeUint64(uint64(txIndex)),
+		BlockHash:        hexutil.Encode(log.BlockHash[:]),
+		LogIndex:         hexutil.EncodeUint64(uint64(logIndex)),
+	}, nil
+}
+
+// parseParams decodes the positional params into the args, of which the first required ones must be given
+func parseParams(params []json.RawMessage, required int, args ...interface{}) error {
+	if len(params) < required || len(params) > len(args) {
+		return errors.Wrapf(ErrInvalidParams, "expect %d to %d params but got %d", required, len(args), len(params))
+	}
+	for i, param := range params {
+		if string(param) == "null" {
+			continue
+		}
+		if err := json.Unmarshal(param, args[i]); err != nil {
+			return errors.Wrapf(ErrInvalidParams, "invalid param %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// decodeOneOrMany decodes a string or an array of strings, which is nil if the value is null or absent
+func decodeOneOrMany(value json.RawMessage) ([]string, error) {
+	if len(value) == 0 || string(value) == "null" {
+		return nil, nil
+	}
+	var one string
+	if err := json.Unmarshal(value, &one); err == nil {
+		return []string{one}, nil
+	}
+	var many []string
+	if err := json.Unmarshal(value, &many); err != nil {
+		return nil, errors.Wrapf(ErrInvalidParams, "invalid value %s", value)
+	}
+	return many, nil
+}
+
+// decodeAddress returns the io1 address of a hex EVM address
+func decodeAddress(addr string) (string, error) {
+	if !common.IsHexAddress(addr) {
+		return "", errors.Wrapf(ErrInvalidParams, "invalid address %s", addr)
+	}
+	return FromEthAddress(common.HexToAddress(addr))
+}
+
+// addrHash returns the address hash of a validated hex EVM address, under which the contract is stored in the states
+func addrHash(addr string) hash.AddrHash {
+	return byteutil.BytesTo20B(common.HexToAddress(addr).Bytes())
+}
+
+func decodeHash(h string) (hash.Hash32B, error) {
+	bytes, err := hexutil.Decode(h)
+	if err != nil || len(bytes) != 32 {
+		return hash.ZeroHash32B, errors.Wrapf(ErrInvalidParams, "invalid hash %s", h)
+	}
+	return byteutil.BytesTo32B(bytes), nil
+}
+
+// decodeSlot decodes a storage position, which is a hex number of up to 32 bytes
+func decodeSlot(position string) (hash.Hash32B, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(position, "0x"), "0X")
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	bytes, err := hex.DecodeString(digits)
+	if err != nil || len(digits) == 0 || len(bytes) > 32 {
+		return hash.ZeroHash32B, errors.Wrapf(ErrInvalidParams, "invalid storage position %s", position)
+	}
+	var slot hash.Hash32B
+	copy(slot[32-len(bytes):], bytes)
+	return slot, nil
+}
+
+// decodeBig decodes a hex number, which is zero if absent
+func decodeBig(value string) (*big.Int, error) {
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+	res, err := hexutil.DecodeBig(value)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidParams, "invalid number %s", value)
+	}
+	return res, nil
+}
+
+func orEmptyData(data string) string {
+	if data == "" {
+		return "0x"
+	}
+	return data
+}
@@ -25,7 +25,10 @@ import (
 	"github.com/iotexproject/iotex-core/network"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	pb "github.com/iotexproject/iotex-core/proto"
+	"github.com/iotexproject/iotex-core/state"
+	"github.com/iotexproject/iotex-core/trie"
 )
 
 var (
@@ -39,6 +42,8 @@ var (
 	ErrExecution = errors.New("invalid execution")
 	// ErrReceipt indicates the error of receipt
 	ErrReceipt = errors.New("invalid receipt")
+	// ErrHeight indicates the error of height
+	ErrHeight = errors.New("invalid height")
 )
 
 var (
@@ -71,13 +76,22 @@ func (exp *Service) GetBlockchainHeight() (int64, error) {
 	return int64(tip), nil
 }
 
-// GetAddressBalance returns the balance of an address
-func (exp *Service) GetAddressBalance(address string) (int64, error) {
-	state, err := exp.bc.StateByAddr(address)
+// GetAddressBalance returns the balance of an address at the given height, or at the tip if the height is nil
+func (exp *Service) GetAddressBalance(address string, height *int64) (int64, error) {
+	if err := checkHeight(height); err != nil {
+		return int64(0), err
+	}
+	var st *state.State
+	var err error
+	if height == nil {
+		st, err = exp.bc.StateByAddr(address)
+	} else {
+		st, err = exp.bc.StateByAddrAtHeight(address, uint64(*height))
+	}
 	if err != nil {
 		return int64(0), err
 	}
-	return state.Balance.Int64(), nil
+	return st.Balance.Int64(), nil
 }
 
 // GetAddressDetails returns the properties of an address
@@ -602,6 +616,48 @@ func (exp *Service) GetReceiptByExecutionID(id string) (explorer.Receipt, error)
 	return convertReceiptToExplorerReceipt(receipt)
 }
 
+// GetReceiptByActionID gets receipt with corresponding transfer, vote or execution id
+func (exp *Service) GetReceiptByActionID(id string) (explorer.Receipt, error) {
+	bytes, err := hex.DecodeString(id)
+	if err != nil {
+		return explorer.Receipt{}, err
+	}
+	var actionHash hash.Hash32B
+	copy(actionHash[:], bytes)
+	receipt, err := exp.bc.GetReceiptByActionHash(actionHash)
+	if err != nil {
+		return explorer.Receipt{}, err
+	}
+
+	return convertReceiptToExplorerReceipt(receipt)
+}
+
+// GetLogs gets logs matching the filter, which has up to 4 topics by position, and an empty topic matches any topic.
+// The offset only skips the logs on the from height of the filter, which is the cursor of the next page
+func (exp *Service) GetLogs(filter explorer.LogFilter, offset int64, limit int64) ([]explorer.Log, error) {
+	if filter.FromHeight < 0 || filter.ToHeight < 0 {
+		return []explorer.Log{}, errors.Wrapf(ErrHeight, "height range = [%d, %d]", filter.FromHeight, filter.ToHeight)
+	}
+	if offset < 0 || limit < 0 {
+		return []explorer.Log{}, errors.Errorf("invalid offset %d or limit %d", offset, limit)
+	}
+	logFilter, err := toLogFilter(filter.Addresses, filter.Topics)
+	if err != nil {
+		return []explorer.Log{}, err
+	}
+	logFilter.FromHeight = uint64(filter.FromHeight)
+	logFilter.ToHeight = uint64(filter.ToHeight)
+	logs, err := exp.bc.GetLogs(logFilter, uint64(offset), uint64(limit))
+	if err != nil {
+		return []explorer.Log{}, err
+	}
+	res := make([]explorer.Log, 0, len(logs))
+	for _, log := range logs {
+		res = append(res, convertLogToExplorerLog(log))
+	}
+	return res, nil
+}
+
 // GetLastBlocksByRange get block with height [offset-limit+1, offset]
 func (exp *Service) GetLastBlocksByRange(offset int64, limit int64) ([]explorer.Block, error) {
 	var res []explorer.Block
@@ -739,7 +795,7 @@ func (exp *Service) GetCoinStatistic() (explorer.CoinStatistic, error) {
 
 	explorerCoinStats := explorer.CoinStatistic{
 		Height:     int64(tipHeight),
-		Supply:     int64(blockchain.Gen.TotalSupply),
+		Supply:     int64(exp.bc.Genesis().TotalSupply),
 		Transfers:  int64(totalTransfers),
 		Votes:      int64(totalVotes),
 		Executions: int64(totalExecutions),
@@ -862,6 +918,7 @@ func (exp *Service) SendTransfer(tsfJSON explorer.SendTransferRequest) (resp exp
 		GasLimit:  uint64(tsfJSON.GasLimit),
 		GasPrice:  big.NewInt(tsfJSON.GasPrice).Bytes(),
 		Signature: signature,
+		ChainID:   uint32(tsfJSON.ChainID),
 	}
 	// broadcast to the network
 	if err = exp.p2p.Broadcast(actPb); err != nil {
@@ -909,6 +966,7 @@ func (exp *Service) SendVote(voteJSON explorer.SendVoteRequest) (resp explorer.S
 		GasLimit:  uint64(voteJSON.GasLimit),
 		GasPrice:  big.NewInt(voteJSON.GasPrice).Bytes(),
 		Signature: signature,
+		ChainID:   uint32(voteJSON.ChainID),
 	}
 
 	// broadcast to the network
@@ -977,6 +1035,7 @@ func (exp *Service) SendSmartContract(execution explorer.Execution) (resp explor
 		GasLimit:  uint64(execution.GasLimit),
 		GasPrice:  big.NewInt(execution.GasPrice).Bytes(),
 		Signature: signature,
+		ChainID:   uint32(execution.ChainID),
 	}
 	//
 	// broadcast to the network
@@ -992,44 +1051,67 @@ func (exp *Service) SendSmartContract(execution explorer.Execution) (resp explor
 	return explorer.SendSmartContractResponse{Hash: hex.EncodeToString(h[:])}, nil
 }
 
-// ReadExecutionState reads the state in a contract address specified by the slot
-func (exp *Service) ReadExecutionState(execution explorer.Execution) (string, error) {
+// ReadExecutionState reads the state in a contract address specified by the slot at the given height, or at the tip
+// if the height is nil
+func (exp *Service) ReadExecutionState(execution explorer.Execution, height *int64) (string, error) {
 	logger.Debug().Msg("receive read smart contract request")
 
-	data, err := hex.DecodeString(execution.Data)
-	if err != nil {
+	if err := checkHeight(height); err != nil {
 		return "", err
 	}
-	signature, err := hex.DecodeString(execution.Signature)
+	sc, err := toExecution(execution)
 	if err != nil {
 		return "", err
 	}
-	actPb := &pb.ActionPb{
-		Action: &pb.ActionPb_Execution{
-			Execution: &pb.ExecutionPb{
-				Amount:         big.NewInt(execution.Amount).Bytes(),
-				Executor:       execution.Executor,
-				Contract:       execution.Contract,
-				ExecutorPubKey: nil,
-				Data:           data,
-			},
-		},
-		Version:   uint32(execution.Version),
-		Nonce:     uint64(execution.Nonce),
-		GasLimit:  uint64(execution.GasLimit),
-		GasPrice:  big.NewInt(execution.GasPrice).Bytes(),
-		Signature: signature,
+	var res []byte
+	if height == nil {
+		res, err = exp.bc.ExecuteContractRead(sc)
+	} else {
+		res, err = exp.bc.ExecuteContractReadAtHeight(sc, uint64(*height))
 	}
-
-	sc := &action.Execution{}
-	sc.ConvertFromActionPb(actPb)
-	res, err := exp.bc.ExecuteContractRead(sc)
 	if err != nil {
 		return "", err
 	}
 	return hex.EncodeToString(res), nil
 }
 
+// EstimateGas estimates the minimal gas limit with which the execution succeeds, or returns the reason why it never
+// succeeds
+func (exp *Service) EstimateGas(execution explorer.Execution) (explorer.EstimateGasResponse, error) {
+	logger.Debug().Msg("receive estimate gas request")
+
+	sc, err := toExecution(execution)
+	if err != nil {
+		return explorer.EstimateGasResponse{}, err
+	}
+	gas, reason, err := exp.bc.EstimateGasForExecution(sc)
+	if err != nil {
+		return explorer.EstimateGasResponse{}, err
+	}
+	return explorer.EstimateGasResponse{
+		Gas:     int64(gas),
+		Succeed: reason == "",
+		Reason:  reason,
+	}, nil
+}
+
+// TraceExecution replays an execution on the states of its parent block, and returns its opcode steps and call tree
+func (exp *Service) TraceExecution(id string) (explorer.ExecutionTrace, error) {
+	logger.Debug().Str("id", id).Msg("receive trace execution request")
+
+	bytes, err := hex.DecodeString(id)
+	if err != nil {
+		return explorer.ExecutionTrace{}, err
+	}
+	var executionHash hash.Hash32B
+	copy(executionHash[:], bytes)
+	trace, err := exp.bc.TraceExecution(executionHash)
+	if err != nil {
+		return explorer.ExecutionTrace{}, err
+	}
+	return convertTraceToExplorerTrace(id, trace)
+}
+
 // GetBlockOrActionByHash get block or action by a hash
 func (exp *Service) GetBlockOrActionByHash(hashStr string) (explorer.GetBlkOrActResponse, error) {
 	if blk, err := exp.GetBlockByID(hashStr); err == nil {
@@ -1051,6 +1133,134 @@ func (exp *Service) GetBlockOrActionByHash(hashStr string) (explorer.GetBlkOrAct
 	return explorer.GetBlkOrActResponse{}, nil
 }
 
+// GetStateProof returns the Merkle proof of an account and its storage slots on the given height, which can be
+// verified against the returned state root without accessing any DB
+func (exp *Service) GetStateProof(address string, height int64, storageKeys []string) (explorer.StateProof, error) {
+	if height < 0 {
+		return explorer.StateProof{}, errors.Wrapf(ErrHeight, "height = %d", height)
+	}
+	sf := exp.bc.GetFactory()
+	stateHeight, err := sf.Height()
+	if err != nil {
+		return explorer.StateProof{}, err
+	}
+	if uint64(height) > stateHeight {
+		return explorer.StateProof{}, errors.Wrapf(ErrHeight, "state is only available up to height %d", stateHeight)
+	}
+	if uint64(height) < stateHeight {
+		// open the states on a past height
+		if sf, err = sf.ViewAtHeight(uint64(height)); err != nil {
+			return explorer.StateProof{}, err
+		}
+	}
+	pkHash, err := iotxaddress.GetPubkeyHash(address)
+	if err != nil {
+		return explorer.StateProof{}, err
+	}
+	addrHash := byteutil.BytesTo20B(pkHash)
+	root := sf.RootHash()
+	accountProof, err := sf.AccountProof(addrHash)
+	if err != nil {
+		return explorer.StateProof{}, err
+	}
+	stateProof := explorer.StateProof{
+		Address:       address,
+		Height:        height,
+		StateRoot:     hex.EncodeToString(root[:]),
+		AccountProof:  encodeProof(accountProof),
+		StorageProofs: []explorer.StorageProof{},
+	}
+	// the tries are rehashed with the index of each child committed on the upgrade height
+	indexed := uint64(height) >= exp.bc.Upgrades().IndexedTrieHeight
+	st, err := state.VerifyAccountProof(root, addrHash, accountProof, indexed)
+	if errors.Cause(err) == state.ErrAccountNotExist {
+		// the exclusion of the account also proves the exclusion of its storage
+		return stateProof, nil
+	}
+	if err != nil {
+		return explorer.StateProof{}, err
+	}
+	stateProof.StorageRoot = hex.EncodeToString(st.Root[:])
+	for _, keyStr := range storageKeys {
+		keyBytes, err := hex.DecodeString(keyStr)
+		if err != nil {
+			return explorer.StateProof{}, err
+		}
+		if len(keyBytes) != hash.HashSize {
+			return explorer.StateProof{}, errors.Errorf("invalid storage key length = %d", len(keyBytes))
+		}
+		key := byteutil.BytesTo32B(keyBytes)
+		proof, err := sf.StorageProof(addrHash, key)
+		if err != nil {
+			return explorer.StateProof{}, err
+		}
+		storageProof := explorer.StorageProof{Key: keyStr, Proof: encodeProof(proof)}
+		value, err := state.VerifyStorageProof(st.Root, key, proof, indexed)
+		switch {
+		case errors.Cause(err) == trie.ErrNotExist:
+		case err != nil:
+			return explorer.StateProof{}, err
+		default:
+			storageProof.Value = hex.EncodeToString(value[:])
+		}
+		stateProof.StorageProofs = append(stateProof.StorageProofs, storageProof)
+	}
+	return stateProof, nil
+}
+
+// GetActionProof returns the header of the block including the transfer, vote or execution, and the Merkle path
+// proving the action is in the block, which can be verified against the txRoot in the header
+func (exp *Service) GetActionProof(actionID string) (explorer.ActionProof, error) {
+	bytes, err := hex.DecodeString(actionID)
+	if err != nil {
+		return explorer.ActionProof{}, err
+	}
+	var actHash hash.Hash32B
+	copy(actHash[:], bytes)
+
+	blkHash, err := exp.bc.GetBlockHashByTransferHash(actHash)
+	if err != nil {
+		if blkHash, err = exp.bc.GetBlockHashByVoteHash(actHash); err != nil {
+			if blkHash, err = exp.bc.GetBlockHashByExecutionHash(actHash); err != nil {
+				return explorer.ActionProof{}, errors.Wrapf(err, "failed to get block of action %x", actHash)
+			}
+		}
+	}
+	blk, err := exp.bc.GetBlockByHash(blkHash)
+	if err != nil {
+		return explorer.ActionProof{}, err
+	}
+	index, path, err := blk.TxMerklePath(actHash)
+	if err != nil {
+		return explorer.ActionProof{}, err
+	}
+	merklePath := make([]string, 0, len(path))
+	for _, h := range path {
+		merklePath = append(merklePath, hex.EncodeToString(h[:]))
+	}
+
+	blkHeaderPb := blk.ConvertToBlockHeaderPb()
+	return explorer.ActionProof{
+		ActionID: actionID,
+		BlockHeader: explorer.BlockHeader{
+			ID:             hex.EncodeToString(blkHash[:]),
+			Version:        int64(blkHeaderPb.Version),
+			ChainID:        int64(blkHeaderPb.ChainID),
+			Height:         int64(blkHeaderPb.Height),
+			Timestamp:      int64(blkHeaderPb.Timestamp),
+			PrevBlockID:    hex.EncodeToString(blkHeaderPb.PrevBlockHash),
+			TxRoot:         hex.EncodeToString(blkHeaderPb.TxRoot),
+			StateRoot:      hex.EncodeToString(blkHeaderPb.StateRoot),
+			ReceiptRoot:    hex.EncodeToString(blkHeaderPb.ReceiptRoot),
+			ProducerPubKey: keypair.EncodePublicKey(blk.Header.Pubkey),
+			Signature:      hex.EncodeToString(blkHeaderPb.Signature),
+		},
+		Index:      int64(index),
+		NumActions: int64(blk.NumActions()),
+		MerklePath: merklePath,
+	}, nil
+}
+
 // getTransfer takes in a blockchain and transferHash and returns an Explorer Transfer
 func getTransfer(bc blockchain.Blockchain, ap actpool.ActPool, transferHash hash.Hash32B) (explorer.Transfer, error) {
 	explorerTransfer := explorer.Transfer{}
@@ -1244,19 +1454,7 @@ func convertReceiptToExplorerReceipt(receipt *blockchain.Receipt) (explorer.Rece
 	}
 	logs := []explorer.Log{}
 	for _, log := range receipt.Logs {
-		topics := []string{}
-		for _, topic := range log.Topics {
-			topics = append(topics, hex.EncodeToString(topic[:]))
-		}
-		logs = append(logs, explorer.Log{
-			Address:     log.Address,
-			Topics:      topics,
-			Data:        hex.EncodeToString(log.Data),
-			BlockNumber: int64(log.BlockNumber),
-			TxnHash:     hex.EncodeToString(log.TxnHash[:]),
-			BlockHash:   hex.EncodeToString(log.BlockHash[:]),
-			Index:       int64(log.Index),
-		})
+		logs = append(logs, convertLogToExplorerLog(log))
 	}
 
 	return explorer.Receipt{
@@ -1266,5 +1464,187 @@ func convertReceiptToExplorerReceipt(receipt *blockchain.Receipt) (explorer.Rece
 		GasConsumed:     int64(receipt.GasConsumed),
 		ContractAddress: receipt.ContractAddress,
 		Logs:            logs,
+		BlockHeight:     int64(receipt.BlockHeight),
+		BlockID:         hex.EncodeToString(receipt.BlockHash[:]),
+		Index:           int64(receipt.Index),
 	}, nil
 }
+
+func convertTraceToExplorerTrace(id string, trace *blockchain.ExecutionTrace) (explorer.ExecutionTrace, error) {
+	if trace == nil || trace.Receipt == nil {
+		return explorer.ExecutionTrace{}, errors.Wrap(ErrReceipt, "trace and its receipt cannot be nil")
+	}
+	steps := make([]explorer.TraceStep, 0, len(trace.Steps))
+	for _, step := range trace.Steps {
+		steps = append(steps, convertTraceStepToExplorerTraceStep(step))
+	}
+	res := explorer.ExecutionTrace{
+		ID:          id,
+		Status:      int64(trace.Receipt.Status),
+		GasConsumed: int64(trace.Receipt.GasConsumed),
+		Error:       trace.Error,
+		Truncated:   trace.Truncated,
+		Steps:       steps,
+	}
+	if trace.Call != nil {
+		res.Call = convertCallFrameToExplorerCallFrame(trace.Call)
+	}
+	return res, nil
+}
+
+func convertTraceStepToExplorerTraceStep(step *blockchain.TraceStep) explorer.TraceStep {
+	stack := make([]string, 0, len(step.Stack))
+	for _, value := range step.Stack {
+		stack = append(stack, value.Text(16))
+	}
+	res := explorer.TraceStep{
+		Pc:      int64(step.PC),
+		Op:      step.Op,
+		Gas:     int64(step.Gas),
+		GasCost: int64(step.GasCost),
+		Depth:   int64(step.Depth),
+		Stack:   stack,
+		Error:   step.Error,
+	}
+	if step.MemoryDiff != nil {
+		res.MemoryOffset = int64(step.MemoryDiff.Offset)
+		res.MemoryDiff = hex.EncodeToString(step.MemoryDiff.Data)
+	}
+	if step.StorageWrite != nil {
+		res.StorageKey = hex.EncodeToString(step.StorageWrite.Key[:])
+		res.StorageValue = hex.EncodeToString(step.StorageWrite.Value[:])
+	}
+	return res
+}
+
+func convertCallFrameToExplorerCallFrame(frame *blockchain.CallFrame) explorer.CallFrame {
+	calls := make([]explorer.CallFrame, 0, len(frame.Calls))
+	for _, call := range frame.Calls {
+		calls = append(calls, convertCallFrameToExplorerCallFrame(call))
+	}
+	res := explorer.CallFrame{
+		Type:    frame.Type,
+		From:    frame.From,
+		To:      frame.To,
+		Gas:     int64(frame.Gas),
+		GasUsed: int64(frame.GasUsed),
+		Input:   hex.EncodeToString(frame.Input),
+		Output:  hex.EncodeToString(frame.Output),
+		Error:   frame.Error,
+		Calls:   calls,
+		Value:   "0",
+	}
+	if frame.Value != nil {
+		res.Value = frame.Value.String()
+	}
+	return res
+}
+
+// toLogFilter converts the contract addresses and the hex topics by position to a log filter, where an empty topic
+// matches any topic on the position
+func toLogFilter(addresses []string, topics []string) (*blockchain.LogFilter, error) {
+	filter := &blockchain.LogFilter{Addresses: addresses}
+	for _, topic := range topics {
+		if topic == "" {
+			filter.Topics = append(filter.Topics, nil)
+			continue
+		}
+		bytes, err := hex.DecodeString(topic)
+		if err != nil {
+			return nil, err
+		}
+		var topicHash hash.Hash32B
+		copy(topicHash[:], bytes)
+		filter.Topics = append(filter.Topics, []hash.Hash32B{topicHash})
+	}
+	return filter, nil
+}
+
+func convertBlockToExplorerBlock(blk *blockchain.Block) explorer.Block {
+	blkHash := blk.HashBlock()
+	totalAmount := int64(0)
+	totalSize := uint32(0)
+	for _, transfer := range blk.Transfers {
+		totalAmount += transfer.Amount.Int64()
+		totalSize += transfer.TotalSize()
+	}
+	return explorer.Block{
+		ID:         hex.EncodeToString(blkHash[:]),
+		Height:     int64(blk.Height()),
+		Timestamp:  int64(blk.ConvertToBlockHeaderPb().Timestamp),
+		Transfers:  int64(len(blk.Transfers)),
+		Votes:      int64(len(blk.Votes)),
+		Executions: int64(len(blk.Executions)),
+		Amount:     totalAmount,
+		Size:       int64(totalSize),
+		GenerateBy: explorer.BlockGenerator{
+			Name:    "",
+			Address: keypair.EncodePublicKey(blk.Header.Pubkey),
+		},
+	}
+}
+
+func convertLogToExplorerLog(log *blockchain.Log) explorer.Log {
+	topics := []string{}
+	for _, topic := range log.Topics {
+		topics = append(topics, hex.EncodeToString(topic[:]))
+	}
+	return explorer.Log{
+		Address:     log.Address,
+		Topics:      topics,
+		Data:        hex.EncodeToString(log.Data),
+		BlockNumber: int64(log.BlockNumber),
+		TxnHash:     hex.EncodeToString(log.TxnHash[:]),
+		BlockHash:   hex.EncodeToString(log.BlockHash[:]),
+		Index:       int64(log.Index),
+	}
+}
+
+func encodeProof(proof [][]byte) []string {
+	nodes := make([]string, 0, len(proof))
+	for _, node := range proof {
+		nodes = append(nodes, hex.EncodeToString(node))
+	}
+	return nodes
+}
+
+// checkHeight checks the optional height of a request, which is nil if the request is on the tip
+func checkHeight(height *int64) error {
+	if height != nil && *height < 0 {
+		return errors.Wrapf(ErrHeight, "height = %d", *height)
+	}
+	return nil
+}
+
+// toExecution converts the execution in read request to action.Execution
+func toExecution(execution explorer.Execution) (*action.Execution, error) {
+	data, err := hex.DecodeString(execution.Data)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hex.DecodeString(execution.Signature)
+	if err != nil {
+		return nil, err
+	}
+	actPb := &pb.ActionPb{
+		Action: &pb.ActionPb_Execution{
+			Execution: &pb.ExecutionPb{
+				Amount:         big.NewInt(execution.Amount).Bytes(),
+				Executor:       execution.Executor,
+				Contract:       execution.Contract,
+				ExecutorPubKey: nil,
+				Data:           data,
+			},
+		},
+		Version:   uint32(execution.Version),
+		Nonce:     uint64(execution.Nonce),
+		GasLimit:  uint64(execution.GasLimit),
+		GasPrice:  big.NewInt(execution.GasPrice).Bytes(),
+		Signature: signature,
+		ChainID:   uint32(execution.ChainID),
+	}
+
+	sc := &action.Execution{}
+	sc.ConvertFromActionPb(actPb)
+	return sc, nil
+}
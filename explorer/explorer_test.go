@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"math/big"
 	"net"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -24,9 +25,13 @@ import (
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/consensus/scheme"
+	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/explorer/idl/explorer"
+	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/network/node"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/keypair"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/state"
 	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
 	"github.com/iotexproject/iotex-core/test/mock/mock_consensus"
@@ -53,7 +58,7 @@ func addTestingBlocks(bc blockchain.Blockchain) error {
 	// test --> A, B, C, D, E, F
 	tsf, _ := action.NewTransfer(1, big.NewInt(10), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress, []byte{}, uint64(100000), big.NewInt(10))
 	tsf, _ = tsf.Sign(ta.Addrinfo["producer"])
-	blk, err := bc.MintNewBlock([]*action.Transfer{tsf}, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err := bc.MintNewBlock([]*action.Transfer{tsf}, nil, nil, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -75,7 +80,7 @@ func addTestingBlocks(bc blockchain.Blockchain) error {
 	vote1, _ = vote1.Sign(ta.Addrinfo["charlie"])
 	execution1, _ := action.NewExecution(ta.Addrinfo["charlie"].RawAddress, ta.Addrinfo["delta"].RawAddress, 6, big.NewInt(1), uint64(1000000), big.NewInt(10), []byte{1})
 	execution1, _ = execution1.Sign(ta.Addrinfo["charlie"])
-	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4}, []*action.Vote{vote1}, []*action.Execution{execution1}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock([]*action.Transfer{tsf1, tsf2, tsf3, tsf4}, []*action.Vote{vote1}, []*action.Execution{execution1}, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -84,7 +89,7 @@ func addTestingBlocks(bc blockchain.Blockchain) error {
 	}
 
 	// Add block 3
-	blk, err = bc.MintNewBlock(nil, nil, nil, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, nil, nil, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -101,7 +106,7 @@ func addTestingBlocks(bc blockchain.Blockchain) error {
 	execution2, _ := action.NewExecution(ta.Addrinfo["alfa"].RawAddress, ta.Addrinfo["delta"].RawAddress, 2, big.NewInt(1), 1000000, big.NewInt(10), []byte{1})
 	execution1, _ = execution1.Sign(ta.Addrinfo["charlie"])
 	execution2, _ = execution2.Sign(ta.Addrinfo["alfa"])
-	blk, err = bc.MintNewBlock(nil, []*action.Vote{vote1, vote2}, []*action.Execution{execution1, execution2}, ta.Addrinfo["producer"], "")
+	blk, err = bc.MintNewBlock(nil, []*action.Vote{vote1, vote2}, []*action.Execution{execution1, execution2}, nil, ta.Addrinfo["producer"], "")
 	if err != nil {
 		return err
 	}
@@ -317,12 +322,12 @@ func TestExplorerApi(t *testing.T) {
 	require.Equal(int64(15), stats.Aps)
 
 	// success
-	balance, err := svc.GetAddressBalance(ta.Addrinfo["charlie"].RawAddress)
+	balance, err := svc.GetAddressBalance(ta.Addrinfo["charlie"].RawAddress, nil)
 	require.Nil(err)
 	require.Equal(int64(6), balance)
 
 	// error
-	_, err = svc.GetAddressBalance("")
+	_, err = svc.GetAddressBalance("", nil)
 	require.Error(err)
 
 	// success
@@ -718,7 +723,7 @@ func TestExplorerGetReceiptByExecutionID(t *testing.T) {
 	require.NoError(err)
 	execution, err = execution.Sign(ta.Addrinfo["producer"])
 	require.NoError(err)
-	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, ta.Addrinfo["producer"], "")
+	blk, err := bc.MintNewBlock(nil, nil, []*action.Execution{execution}, nil, ta.Addrinfo["producer"], "")
 	require.NoError(err)
 	require.Nil(bc.CommitBlock(blk))
 
@@ -728,3 +733,409 @@ func TestExplorerGetReceiptByExecutionID(t *testing.T) {
 	require.NoError(err)
 	require.Equal(eHashStr, receipt.Hash)
 }
+
+func TestExplorerGetReceiptByActionID(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+	// the transfers and votes consume their intrinsic gas from the gas fee upgrade
+	cfg.Chain.Upgrades.GasFeeHeight = 1
+
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, blockchain.Gen.TotalSupply)
+	require.NoError(err)
+	// Disable block reward to make bookkeeping easier
+	blockchain.Gen.BlockReward = uint64(0)
+
+	// create chain
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(&cfg, blockchain.PrecreatedStateFactoryOption(sf), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	require.NotNil(bc)
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+
+	svc := Service{
+		bc: bc,
+		cfg: config.Explorer{
+			TpsWindow:               10,
+			MaxTransferPayloadBytes: 1024,
+		},
+	}
+
+	tsf, err := action.NewTransfer(1, big.NewInt(10), ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["charlie"].RawAddress, []byte{}, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	tsf, err = tsf.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	vote, err := action.NewVote(2, ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["producer"].RawAddress, uint64(100000), big.NewInt(1))
+	require.NoError(err)
+	vote, err = vote.Sign(ta.Addrinfo["producer"])
+	require.NoError(err)
+	blk, err := bc.MintNewBlock([]*action.Transfer{tsf}, []*action.Vote{vote}, nil, nil, ta.Addrinfo["producer"], "")
+	require.NoError(err)
+	require.Nil(bc.CommitBlock(blk))
+	blkHash := blk.HashBlock()
+
+	tsfHash := tsf.Hash()
+	receipt, err := svc.GetReceiptByActionID(hex.EncodeToString(tsfHash[:]))
+	require.NoError(err)
+	require.Equal(hex.EncodeToString(tsfHash[:]), receipt.Hash)
+	require.Equal(int64(blockchain.SuccessStatus), receipt.Status)
+	gas, err := tsf.IntrinsicGas()
+	require.NoError(err)
+	require.Equal(int64(gas), receipt.GasConsumed)
+	require.Equal(int64(1), receipt.BlockHeight)
+	require.Equal(hex.EncodeToString(blkHash[:]), receipt.BlockID)
+	require.Equal(int64(0), receipt.Index)
+
+	voteHash := vote.Hash()
+	receipt, err = svc.GetReceiptByActionID(hex.EncodeToString(voteHash[:]))
+	require.NoError(err)
+	require.Equal(int64(blockchain.SuccessStatus), receipt.Status)
+	require.Equal(int64(vote.IntrinsicGas()), receipt.GasConsumed)
+	require.Equal(int64(1), receipt.BlockHeight)
+	// the coinbase transfer is appended to the transfers
+	require.Equal(int64(2), receipt.Index)
+
+	_, err = svc.GetReceiptByActionID(hex.EncodeToString(blkHash[:]))
+	require.Error(err)
+}
+
+func TestExplorerGetLogs(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	topic := byteutil.BytesTo32B(hash.Hash256b([]byte("topic")))
+	contract := ta.Addrinfo["delta"].RawAddress
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	mBc.EXPECT().GetLogs(&blockchain.LogFilter{
+		FromHeight: 1,
+		ToHeight:   5,
+		Addresses:  []string{contract},
+		Topics:     [][]hash.Hash32B{nil, {topic}},
+	}, uint64(2), uint64(3)).Times(1).Return([]*blockchain.Log{
+		{Address: contract, Topics: []hash.Hash32B{topic, topic}, Data: []byte{0x12}, BlockNumber: 3, Index: 1},
+	}, nil)
+	svc := Service{bc: mBc}
+
+	filter := explorer.LogFilter{
+		FromHeight: 1,
+		ToHeight:   5,
+		Addresses:  []string{contract},
+		Topics:     []string{"", hex.EncodeToString(topic[:])},
+	}
+	logs, err := svc.GetLogs(filter, 2, 3)
+	require.NoError(err)
+	require.Equal(1, len(logs))
+	require.Equal(contract, logs[0].Address)
+	require.Equal([]string{hex.EncodeToString(topic[:]), hex.EncodeToString(topic[:])}, logs[0].Topics)
+	require.Equal("12", logs[0].Data)
+	require.Equal(int64(3), logs[0].BlockNumber)
+	require.Equal(int64(1), logs[0].Index)
+
+	_, err = svc.GetLogs(explorer.LogFilter{FromHeight: -1}, 0, 1)
+	require.Equal(ErrHeight, errors.Cause(err))
+	_, err = svc.GetLogs(explorer.LogFilter{Topics: []string{"xyz"}}, 0, 1)
+	require.Error(err)
+}
+
+func TestExplorerEstimateGas(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	svc := Service{bc: mBc}
+	execution := explorer.Execution{
+		Nonce:    1,
+		Executor: ta.Addrinfo["producer"].RawAddress,
+		Contract: ta.Addrinfo["delta"].RawAddress,
+		GasPrice: 10,
+		Data:     "6d4ce63c",
+	}
+
+	mBc.EXPECT().EstimateGasForExecution(gomock.Any()).Times(1).Return(uint64(21000), "", nil)
+	res, err := svc.EstimateGas(execution)
+	require.NoError(err)
+	require.Equal(explorer.EstimateGasResponse{Gas: 21000, Succeed: true}, res)
+
+	mBc.EXPECT().EstimateGasForExecution(gomock.Any()).Times(1).Return(uint64(0), "Execution reverted", nil)
+	res, err = svc.EstimateGas(execution)
+	require.NoError(err)
+	require.Equal(explorer.EstimateGasResponse{Reason: "Execution reverted"}, res)
+
+	mBc.EXPECT().EstimateGasForExecution(gomock.Any()).Times(1).Return(uint64(0), "", errors.New("error"))
+	_, err = svc.EstimateGas(execution)
+	require.Error(err)
+	execution.Data = "xyz"
+	_, err = svc.EstimateGas(execution)
+	require.Error(err)
+}
+
+func TestExplorerTraceExecution(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	svc := Service{bc: mBc}
+	id := strings.Repeat("ab", 32)
+	trace := &blockchain.ExecutionTrace{
+		Receipt: &blockchain.Receipt{Status: blockchain.FailureStatus, GasConsumed: 21100},
+		Error:   "Execution reverted",
+		Steps: []*blockchain.TraceStep{
+			{
+				PC:         4,
+				Op:         "MSTORE",
+				Gas:        100,
+				GasCost:    12,
+				Depth:      1,
+				Stack:      []*big.Int{big.NewInt(0x80), big.NewInt(0x40)},
+				MemoryDiff: &blockchain.MemoryDiff{Offset: 0x5f, Data: []byte{0x80}},
+			},
+			{PC: 5, Op: "SSTORE", Depth: 1, StorageWrite: &blockchain.StorageWrite{Value: byteutil.BytesTo32B([]byte{15})}},
+		},
+		Call: &blockchain.CallFrame{
+			Type:  "CALL",
+			From:  ta.Addrinfo["producer"].RawAddress,
+			To:    ta.Addrinfo["delta"].RawAddress,
+			Value: new(big.Int).Lsh(big.NewInt(1), 70),
+			Input: []byte{0xde, 0xad},
+			Error: "execution reverted",
+			Calls: []*blockchain.CallFrame{{Type: "STATICCALL", Value: big.NewInt(0)}},
+		},
+	}
+
+	mBc.EXPECT().TraceExecution(gomock.Any()).Times(1).Return(trace, nil)
+	res, err := svc.TraceExecution(id)
+	require.NoError(err)
+	require.Equal(id, res.ID)
+	require.Equal(int64(blockchain.FailureStatus), res.Status)
+	require.Equal(int64(21100), res.GasConsumed)
+	require.Equal("Execution reverted", res.Error)
+	require.Equal(2, len(res.Steps))
+	require.Equal(explorer.TraceStep{
+		Pc:           4,
+		Op:           "MSTORE",
+		Gas:          100,
+		GasCost:      12,
+		Depth:        1,
+		Stack:        []string{"80", "40"},
+		MemoryOffset: 0x5f,
+		MemoryDiff:   "80",
+	}, res.Steps[0])
+	require.Equal(strings.Repeat("00", 32), res.Steps[1].StorageKey)
+	require.Equal("0f"+strings.Repeat("00", 31), res.Steps[1].StorageValue)
+	require.Equal("dead", res.Call.Input)
+	require.Equal("1180591620717411303424", res.Call.Value)
+	require.Equal(1, len(res.Call.Calls))
+	require.Equal("STATICCALL", res.Call.Calls[0].Type)
+
+	mBc.EXPECT().TraceExecution(gomock.Any()).Times(1).Return(nil, errors.New("error"))
+	_, err = svc.TraceExecution(id)
+	require.Error(err)
+	_, err = svc.TraceExecution("xyz")
+	require.Error(err)
+}
+
+func TestExplorerGetStateProof(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, blockchain.Gen.TotalSupply)
+	require.NoError(err)
+	// Disable block reward to make bookkeeping easier
+	blockchain.Gen.BlockReward = uint64(0)
+
+	// create chain
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(&cfg, blockchain.PrecreatedStateFactoryOption(sf), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	require.NotNil(bc)
+	require.NoError(addTestingBlocks(bc))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+
+	svc := Service{bc: bc}
+	height := int64(bc.TipHeight())
+	decode := func(proof []string) [][]byte {
+		nodes := [][]byte{}
+		for _, node := range proof {
+			b, err := hex.DecodeString(node)
+			require.NoError(err)
+			nodes = append(nodes, b)
+		}
+		return nodes
+	}
+
+	// inclusion of account
+	proof, err := svc.GetStateProof(ta.Addrinfo["charlie"].RawAddress, height, nil)
+	require.NoError(err)
+	require.Equal(height, proof.Height)
+	rootBytes, err := hex.DecodeString(proof.StateRoot)
+	require.NoError(err)
+	root := byteutil.BytesTo32B(rootBytes)
+	require.Equal(sf.RootHash(), root)
+	pkHash, err := iotxaddress.GetPubkeyHash(ta.Addrinfo["charlie"].RawAddress)
+	require.NoError(err)
+	st, err := state.VerifyAccountProof(root, byteutil.BytesTo20B(pkHash), decode(proof.AccountProof), false)
+	require.NoError(err)
+	balance, err := bc.Balance(ta.Addrinfo["charlie"].RawAddress)
+	require.NoError(err)
+	require.Equal(balance, st.Balance)
+
+	// exclusion of account and its storage
+	addr, err := iotxaddress.NewAddress(iotxaddress.IsTestnet, iotxaddress.ChainID)
+	require.NoError(err)
+	key := hex.EncodeToString(hash.ZeroHash32B[:])
+	proof, err = svc.GetStateProof(addr.RawAddress, height, []string{key})
+	require.NoError(err)
+	pkHash, err = iotxaddress.GetPubkeyHash(addr.RawAddress)
+	require.NoError(err)
+	_, err = state.VerifyAccountProof(root, byteutil.BytesTo20B(pkHash), decode(proof.AccountProof), false)
+	require.Equal(state.ErrAccountNotExist, errors.Cause(err))
+	require.Equal(0, len(proof.StorageProofs))
+
+	// account on a past height
+	proof, err = svc.GetStateProof(ta.Addrinfo["charlie"].RawAddress, 1, nil)
+	require.NoError(err)
+	rootBytes, err = hex.DecodeString(proof.StateRoot)
+	require.NoError(err)
+	root = byteutil.BytesTo32B(rootBytes)
+	root1, err := sf.RootHashByHeight(1)
+	require.NoError(err)
+	require.Equal(root1, root)
+	pkHash, err = iotxaddress.GetPubkeyHash(ta.Addrinfo["charlie"].RawAddress)
+	require.NoError(err)
+	st, err = state.VerifyAccountProof(root, byteutil.BytesTo20B(pkHash), decode(proof.AccountProof), false)
+	require.NoError(err)
+	require.Equal(big.NewInt(10), st.Balance)
+
+	// invalid height and storage key
+	_, err = svc.GetStateProof(ta.Addrinfo["charlie"].RawAddress, -1, nil)
+	require.Equal(ErrHeight, errors.Cause(err))
+	_, err = svc.GetStateProof(ta.Addrinfo["charlie"].RawAddress, height+1, nil)
+	require.Equal(ErrHeight, errors.Cause(err))
+	_, err = svc.GetStateProof(ta.Addrinfo["charlie"].RawAddress, height, []string{"1234"})
+	require.Error(err)
+}
+
+func TestExplorerStateAtHeight(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+	cfg.Explorer.Enabled = true
+
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, blockchain.Gen.TotalSupply)
+	require.NoError(err)
+	// Disable block reward to make bookkeeping easier
+	blockchain.Gen.BlockReward = uint64(0)
+
+	// create chain
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(&cfg, blockchain.PrecreatedStateFactoryOption(sf), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	require.NotNil(bc)
+	require.NoError(addTestingBlocks(bc))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+
+	svc := Service{bc: bc}
+	height := func(h int64) *int64 { return &h }
+	balance, err := svc.GetAddressBalance(ta.Addrinfo["charlie"].RawAddress, height(1))
+	require.NoError(err)
+	require.Equal(int64(10), balance)
+	balance, err = svc.GetAddressBalance(ta.Addrinfo["charlie"].RawAddress, height(int64(bc.TipHeight())))
+	require.NoError(err)
+	tipBalance, err := svc.GetAddressBalance(ta.Addrinfo["charlie"].RawAddress, nil)
+	require.NoError(err)
+	require.Equal(tipBalance, balance)
+	// charlie does not exist on genesis block
+	_, err = svc.GetAddressBalance(ta.Addrinfo["charlie"].RawAddress, height(0))
+	require.Equal(state.ErrAccountNotExist, errors.Cause(err))
+	_, err = svc.GetAddressBalance(ta.Addrinfo["charlie"].RawAddress, height(-1))
+	require.Equal(ErrHeight, errors.Cause(err))
+
+	// read contract on a given height
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mBc := mock_blockchain.NewMockBlockchain(ctrl)
+	mBc.EXPECT().ExecuteContractReadAtHeight(gomock.Any(), uint64(2)).Times(1).Return([]byte{0x12, 0x34}, nil)
+	mBc.EXPECT().ExecuteContractRead(gomock.Any()).Times(1).Return([]byte{0x56}, nil)
+	svc = Service{bc: mBc}
+	execution := explorer.Execution{
+		Executor: ta.Addrinfo["charlie"].RawAddress,
+		Contract: ta.Addrinfo["delta"].RawAddress,
+		GasLimit: 1000000,
+		Data:     "0123",
+	}
+	res, err := svc.ReadExecutionState(execution, height(2))
+	require.NoError(err)
+	require.Equal("1234", res)
+	res, err = svc.ReadExecutionState(execution, nil)
+	require.NoError(err)
+	require.Equal("56", res)
+	_, err = svc.ReadExecutionState(execution, height(-1))
+	require.Equal(ErrHeight, errors.Cause(err))
+}
+
+func TestExplorerGetActionProof(t *testing.T) {
+	require := require.New(t)
+	cfg := config.Default
+
+	sf, err := state.NewFactory(&cfg, state.InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	_, err = sf.LoadOrCreateState(ta.Addrinfo["producer"].RawAddress, blockchain.Gen.TotalSupply)
+	require.NoError(err)
+	// Disable block reward to make bookkeeping easier
+	blockchain.Gen.BlockReward = uint64(0)
+
+	// create chain
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(&cfg, blockchain.PrecreatedStateFactoryOption(sf), blockchain.InMemDaoOption())
+	require.NoError(bc.Start(ctx))
+	require.NotNil(bc)
+	require.NoError(addTestingBlocks(bc))
+	defer func() {
+		require.NoError(bc.Stop(ctx))
+	}()
+
+	svc := Service{bc: bc}
+	blk, err := bc.GetBlockByHeight(2)
+	require.NoError(err)
+	blkHash := blk.HashBlock()
+	txRoot := blk.TxRoot()
+	actHashes := []hash.Hash32B{blk.Transfers[1].Hash(), blk.Votes[0].Hash(), blk.Executions[0].Hash()}
+	for _, actHash := range actHashes {
+		proof, err := svc.GetActionProof(hex.EncodeToString(actHash[:]))
+		require.NoError(err)
+		require.Equal(hex.EncodeToString(blkHash[:]), proof.BlockHeader.ID)
+		require.Equal(hex.EncodeToString(txRoot[:]), proof.BlockHeader.TxRoot)
+		require.Equal(int64(2), proof.BlockHeader.Height)
+		path := []hash.Hash32B{}
+		for _, h := range proof.MerklePath {
+			b, err := hex.DecodeString(h)
+			require.NoError(err)
+			path = append(path, byteutil.BytesTo32B(b))
+		}
+		require.Equal(int64(blk.NumActions()), proof.NumActions)
+		require.True(crypto.VerifyAuditPath(txRoot, actHash, int(proof.Index), int(proof.NumActions), path))
+	}
+
+	// action not in the chain
+	_, err = svc.GetActionProof(hex.EncodeToString(hash.ZeroHash32B[:]))
+	require.Error(err)
+}
@@ -8,8 +8,8 @@ import (
 )
 
 const BarristerVersion string = "0.1.6"
-const BarristerChecksum string = "5bd957cffa724fec63f9d979bf845b7a"
-const BarristerDateGenerated int64 = 1536941047139000000
+const BarristerChecksum string = "7451763507d03639f60b5ebd883e7c6e"
+const BarristerDateGenerated int64 = 1792200132923000000
 
 type CoinStatistic struct {
 	Height     int64 `json:"height"`
@@ -55,6 +55,7 @@ type Transfer struct {
 	Timestamp    int64  `json:"timestamp"`
 	BlockID      string `json:"blockID"`
 	IsPending    bool   `json:"isPending"`
+	ChainID      int64  `json:"chainID"`
 }
 
 type Execution struct {
@@ -72,6 +73,7 @@ type Execution struct {
 	Data           string `json:"data"`
 	BlockID        string `json:"blockID"`
 	IsPending      bool   `json:"isPending"`
+	ChainID        int64  `json:"chainID"`
 }
 
 type Log struct {
@@ -84,6 +86,13 @@ type Log struct {
 	Index       int64    `json:"index"`
 }
 
+type LogFilter struct {
+	FromHeight int64    `json:"fromHeight"`
+	ToHeight   int64    `json:"toHeight"`
+	Addresses  []string `json:"addresses"`
+	Topics     []string `json:"topics"`
+}
+
 type Receipt struct {
 	ReturnValue     string `json:"returnValue"`
 	Status          int64  `json:"status"`
@@ -91,6 +100,9 @@ type Receipt struct {
 	GasConsumed     int64  `json:"gasConsumed"`
 	ContractAddress string `json:"contractAddress"`
 	Logs            []Log  `json:"logs"`
+	BlockHeight     int64  `json:"blockHeight"`
+	BlockID         string `json:"blockID"`
+	Index           int64  `json:"index"`
 }
 
 type SendExecutionResponse struct {
@@ -110,6 +122,7 @@ type Vote struct {
 	Signature   string `json:"signature"`
 	BlockID     string `json:"blockID"`
 	IsPending   bool   `json:"isPending"`
+	ChainID     int64  `json:"chainID"`
 }
 
 type AddressDetails struct {
@@ -154,6 +167,7 @@ type SendTransferRequest struct {
 	GasLimit     int64  `json:"gasLimit"`
 	GasPrice     int64  `json:"gasPrice"`
 	IsCoinbase   bool   `json:"isCoinbase"`
+	ChainID      int64  `json:"chainID"`
 }
 
 type SendTransferResponse struct {
@@ -169,6 +183,7 @@ type SendVoteRequest struct {
 	GasLimit    int64  `json:"gasLimit"`
 	GasPrice    int64  `json:"gasPrice"`
 	Signature   string `json:"signature"`
+	ChainID     int64  `json:"chainID"`
 }
 
 type SendVoteResponse struct {
@@ -188,6 +203,49 @@ type SendSmartContractResponse struct {
 	Hash string `json:"hash"`
 }
 
+type EstimateGasResponse struct {
+	Gas     int64  `json:"gas"`
+	Succeed bool   `json:"succeed"`
+	Reason  string `json:"reason"`
+}
+
+type TraceStep struct {
+	Pc           int64    `json:"pc"`
+	Op           string   `json:"op"`
+	Gas          int64    `json:"gas"`
+	GasCost      int64    `json:"gasCost"`
+	Depth        int64    `json:"depth"`
+	Stack        []string `json:"stack"`
+	MemoryOffset int64    `json:"memoryOffset"`
+	MemoryDiff   string   `json:"memoryDiff"`
+	StorageKey   string   `json:"storageKey"`
+	StorageValue string   `json:"storageValue"`
+	Error        string   `json:"error"`
+}
+
+type CallFrame struct {
+	Type    string      `json:"type"`
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Value   string      `json:"value"`
+	Gas     int64       `json:"gas"`
+	GasUsed int64       `json:"gasUsed"`
+	Input   string      `json:"input"`
+	Output  string      `json:"output"`
+	Error   string      `json:"error"`
+	Calls   []CallFrame `json:"calls"`
+}
+
+type ExecutionTrace struct {
+	ID          string      `json:"ID"`
+	Status      int64       `json:"status"`
+	GasConsumed int64       `json:"gasConsumed"`
+	Error       string      `json:"error"`
+	Truncated   bool        `json:"truncated"`
+	Steps       []TraceStep `json:"steps"`
+	Call        CallFrame   `json:"call"`
+}
+
 type GetBlkOrActResponse struct {
 	Block     *Block     `json:"block,omitempty"`
 	Transfer  *Transfer  `json:"transfer,omitempty"`
@@ -195,9 +253,46 @@ type GetBlkOrActResponse struct {
 	Execution *Execution `json:"execution,omitempty"`
 }
 
+type StorageProof struct {
+	Key   string   `json:"key"`
+	Value string   `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+type StateProof struct {
+	Address       string         `json:"address"`
+	Height        int64          `json:"height"`
+	StateRoot     string         `json:"stateRoot"`
+	AccountProof  []string       `json:"accountProof"`
+	StorageRoot   string         `json:"storageRoot"`
+	StorageProofs []StorageProof `json:"storageProofs"`
+}
+
+type BlockHeader struct {
+	ID             string `json:"ID"`
+	Version        int64  `json:"version"`
+	ChainID        int64  `json:"chainID"`
+	Height         int64  `json:"height"`
+	Timestamp      int64  `json:"timestamp"`
+	PrevBlockID    string `json:"prevBlockID"`
+	TxRoot         string `json:"txRoot"`
+	StateRoot      string `json:"stateRoot"`
+	ReceiptRoot    string `json:"receiptRoot"`
+	ProducerPubKey string `json:"producerPubKey"`
+	Signature      string `json:"signature"`
+}
+
+type ActionProof struct {
+	ActionID    string      `json:"actionID"`
+	BlockHeader BlockHeader `json:"blockHeader"`
+	Index       int64       `json:"index"`
+	NumActions  int64       `json:"numActions"`
+	MerklePath  []string    `json:"merklePath"`
+}
+
 type Explorer interface {
 	GetBlockchainHeight() (int64, error)
-	GetAddressBalance(address string) (int64, error)
+	GetAddressBalance(address string, height *int64) (int64, error)
 	GetAddressDetails(address string) (AddressDetails, error)
 	GetLastTransfersByRange(startBlockHeight int64, offset int64, limit int64, showCoinBase bool) ([]Transfer, error)
 	GetTransferByID(transferID string) (Transfer, error)
@@ -224,8 +319,14 @@ type Explorer interface {
 	SendSmartContract(request Execution) (SendSmartContractResponse, error)
 	GetPeers() (GetPeersResponse, error)
 	GetReceiptByExecutionID(id string) (Receipt, error)
-	ReadExecutionState(request Execution) (string, error)
+	GetReceiptByActionID(id string) (Receipt, error)
+	GetLogs(filter LogFilter, offset int64, limit int64) ([]Log, error)
+	ReadExecutionState(request Execution, height *int64) (string, error)
+	EstimateGas(request Execution) (EstimateGasResponse, error)
+	TraceExecution(id string) (ExecutionTrace, error)
 	GetBlockOrActionByHash(hashStr string) (GetBlkOrActResponse, error)
+	GetStateProof(address string, height int64, storageKeys []string) (StateProof, error)
+	GetActionProof(actionID string) (ActionProof, error)
 }
 
 func NewExplorerProxy(c barrister.Client) Explorer {
@@ -255,8 +356,8 @@ func (_p ExplorerProxy) GetBlockchainHeight() (int64, error) {
 	return int64(0), _err
 }
 
-func (_p ExplorerProxy) GetAddressBalance(address string) (int64, error) {
-	_res, _err := _p.client.Call("Explorer.getAddressBalance", address)
+func (_p ExplorerProxy) GetAddressBalance(address string, height *int64) (int64, error) {
+	_res, _err := _p.client.Call("Explorer.getAddressBalance", address, height)
 	if _err == nil {
 		_retType := _p.idl.Method("Explorer.getAddressBalance").Returns
 		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(int64(0)), _res, "")
@@ -741,8 +842,44 @@ func (_p ExplorerProxy) GetReceiptByExecutionID(id string) (Receipt, error) {
 	return Receipt{}, _err
 }
 
-func (_p ExplorerProxy) ReadExecutionState(request Execution) (string, error) {
-	_res, _err := _p.client.Call("Explorer.readExecutionState", request)
+func (_p ExplorerProxy) GetReceiptByActionID(id string) (Receipt, error) {
+	_res, _err := _p.client.Call("Explorer.getReceiptByActionID", id)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getReceiptByActionID").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(Receipt{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(Receipt)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getReceiptByActionID returned invalid type: %v", _t)
+			return Receipt{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return Receipt{}, _err
+}
+
+func (_p ExplorerProxy) GetLogs(filter LogFilter, offset int64, limit int64) ([]Log, error) {
+	_res, _err := _p.client.Call("Explorer.getLogs", filter, offset, limit)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getLogs").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf([]Log{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.([]Log)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getLogs returned invalid type: %v", _t)
+			return []Log{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return []Log{}, _err
+}
+
+func (_p ExplorerProxy) ReadExecutionState(request Execution, height *int64) (string, error) {
+	_res, _err := _p.client.Call("Explorer.readExecutionState", request, height)
 	if _err == nil {
 		_retType := _p.idl.Method("Explorer.readExecutionState").Returns
 		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(""), _res, "")
@@ -759,6 +896,42 @@ func (_p ExplorerProxy) ReadExecutionState(request Execution) (string, error) {
 	return "", _err
 }
 
+func (_p ExplorerProxy) EstimateGas(request Execution) (EstimateGasResponse, error) {
+	_res, _err := _p.client.Call("Explorer.estimateGas", request)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.estimateGas").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(EstimateGasResponse{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(EstimateGasResponse)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.estimateGas returned invalid type: %v", _t)
+			return EstimateGasResponse{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return EstimateGasResponse{}, _err
+}
+
+func (_p ExplorerProxy) TraceExecution(id string) (ExecutionTrace, error) {
+	_res, _err := _p.client.Call("Explorer.traceExecution", id)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.traceExecution").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(ExecutionTrace{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(ExecutionTrace)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.traceExecution returned invalid type: %v", _t)
+			return ExecutionTrace{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return ExecutionTrace{}, _err
+}
+
 func (_p ExplorerProxy) GetBlockOrActionByHash(hashStr string) (GetBlkOrActResponse, error) {
 	_res, _err := _p.client.Call("Explorer.getBlockOrActionByHash", hashStr)
 	if _err == nil {
@@ -777,6 +950,42 @@ func (_p ExplorerProxy) GetBlockOrActionByHash(hashStr string) (GetBlkOrActRespo
 	return GetBlkOrActResponse{}, _err
 }
 
+func (_p ExplorerProxy) GetStateProof(address string, height int64, storageKeys []string) (StateProof, error) {
+	_res, _err := _p.client.Call("Explorer.getStateProof", address, height, storageKeys)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getStateProof").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(StateProof{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(StateProof)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getStateProof returned invalid type: %v", _t)
+			return StateProof{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return StateProof{}, _err
+}
+
+func (_p ExplorerProxy) GetActionProof(actionID string) (ActionProof, error) {
+	_res, _err := _p.client.Call("Explorer.getActionProof", actionID)
+	if _err == nil {
+		_retType := _p.idl.Method("Explorer.getActionProof").Returns
+		_res, _err = barrister.Convert(_p.idl, &_retType, reflect.TypeOf(ActionProof{}), _res, "")
+	}
+	if _err == nil {
+		_cast, _ok := _res.(ActionProof)
+		if !_ok {
+			_t := reflect.TypeOf(_res)
+			_msg := fmt.Sprintf("Explorer.getActionProof returned invalid type: %v", _t)
+			return ActionProof{}, &barrister.JsonRpcError{Code: -32000, Message: _msg}
+		}
+		return _cast, nil
+	}
+	return ActionProof{}, _err
+}
+
 func NewJSONServer(idl *barrister.Idl, forceASCII bool, explorer Explorer) barrister.Server {
 	return NewServer(idl, &barrister.JsonSerializer{forceASCII}, explorer)
 }
@@ -831,7 +1040,7 @@ var IdlJsonRaw = `[
         "type": "comment",
         "name": "",
         "comment": "",
-        "value": "3. barrister explorer.idl | $GOPATH/bin/idl2go -i -p explorer",
+        "value": "3. barrister explorer.idl | $GOPATH/bin/idl2go -i -n -p explorer",
         "extends": "",
         "fields": null,
         "values": null,
@@ -1126,6 +1335,13 @@ var IdlJsonRaw = `[
                 "optional": false,
                 "is_array": false,
                 "comment": ""
+            },
+            {
+                "name": "chainID",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             }
         ],
         "values": null,
@@ -1238,6 +1454,13 @@ var IdlJsonRaw = `[
                 "optional": false,
                 "is_array": false,
                 "comment": ""
+            },
+            {
+                "name": "chainID",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             }
         ],
         "values": null,
@@ -1309,6 +1532,48 @@ var IdlJsonRaw = `[
         "date_generated": 0,
         "checksum": ""
     },
+    {
+        "type": "struct",
+        "name": "LogFilter",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "fromHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "toHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "addresses",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "topics",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
     {
         "type": "struct",
         "name": "Receipt",
@@ -1357,6 +1622,27 @@ var IdlJsonRaw = `[
                 "optional": false,
                 "is_array": true,
                 "comment": ""
+            },
+            {
+                "name": "blockHeight",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blockID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "index",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             }
         ],
         "values": null,
@@ -1476,6 +1762,13 @@ var IdlJsonRaw = `[
                 "optional": false,
                 "is_array": false,
                 "comment": ""
+            },
+            {
+                "name": "chainID",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             }
         ],
         "values": null,
@@ -1749,6 +2042,13 @@ var IdlJsonRaw = `[
                 "optional": false,
                 "is_array": false,
                 "comment": ""
+            },
+            {
+                "name": "chainID",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             }
         ],
         "values": null,
@@ -1840,6 +2140,13 @@ var IdlJsonRaw = `[
                 "optional": false,
                 "is_array": false,
                 "comment": ""
+            },
+            {
+                "name": "chainID",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             }
         ],
         "values": null,
@@ -1941,36 +2248,29 @@ var IdlJsonRaw = `[
     },
     {
         "type": "struct",
-        "name": "GetBlkOrActResponse",
+        "name": "EstimateGasResponse",
         "comment": "",
         "value": "",
         "extends": "",
         "fields": [
             {
-                "name": "block",
-                "type": "Block",
-                "optional": true,
-                "is_array": false,
-                "comment": ""
-            },
-            {
-                "name": "transfer",
-                "type": "Transfer",
-                "optional": true,
+                "name": "gas",
+                "type": "int",
+                "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "vote",
-                "type": "Vote",
-                "optional": true,
+                "name": "succeed",
+                "type": "bool",
+                "optional": false,
                 "is_array": false,
                 "comment": ""
             },
             {
-                "name": "execution",
-                "type": "Execution",
-                "optional": true,
+                "name": "reason",
+                "type": "string",
+                "optional": false,
                 "is_array": false,
                 "comment": ""
             }
@@ -1982,64 +2282,582 @@ var IdlJsonRaw = `[
         "checksum": ""
     },
     {
-        "type": "interface",
-        "name": "Explorer",
+        "type": "struct",
+        "name": "TraceStep",
         "comment": "",
         "value": "",
         "extends": "",
-        "fields": null,
-        "values": null,
-        "functions": [
+        "fields": [
             {
-                "name": "getBlockchainHeight",
-                "comment": "get the blockchain tip height",
-                "params": [],
-                "returns": {
-                    "name": "",
-                    "type": "int",
-                    "optional": false,
-                    "is_array": false,
-                    "comment": ""
-                }
+                "name": "pc",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             },
             {
-                "name": "getAddressBalance",
-                "comment": "get the balance of an address",
-                "params": [
-                    {
-                        "name": "address",
-                        "type": "string",
-                        "optional": false,
-                        "is_array": false,
-                        "comment": ""
-                    }
-                ],
-                "returns": {
-                    "name": "",
-                    "type": "int",
-                    "optional": false,
-                    "is_array": false,
-                    "comment": ""
-                }
+                "name": "op",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
             },
             {
-                "name": "getAddressDetails",
-                "comment": "get the address detail of an iotex address",
-                "params": [
-                    {
-                        "name": "address",
-                        "type": "string",
-                        "optional": false,
-                        "is_array": false,
-                        "comment": ""
-                    }
-                ],
-                "returns": {
-                    "name": "",
-                    "type": "AddressDetails",
-                    "optional": false,
-                    "is_array": false,
-                    "comment": ""
+                "name": "gas",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasCost",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "depth",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "stack",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "memoryOffset",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "memoryDiff",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "storageKey",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "storageValue",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "error",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "CallFrame",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "type",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "from",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "to",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "value",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": "the value transferred in decimal, which may exceed int64"
+            },
+            {
+                "name": "gas",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasUsed",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "input",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "output",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "error",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "calls",
+                "type": "CallFrame",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "ExecutionTrace",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "ID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "status",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "gasConsumed",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "error",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "truncated",
+                "type": "bool",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "steps",
+                "type": "TraceStep",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "call",
+                "type": "CallFrame",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "GetBlkOrActResponse",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "block",
+                "type": "Block",
+                "optional": true,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "transfer",
+                "type": "Transfer",
+                "optional": true,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "vote",
+                "type": "Vote",
+                "optional": true,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "execution",
+                "type": "Execution",
+                "optional": true,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "StorageProof",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "key",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "value",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "proof",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "StateProof",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "address",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "height",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "stateRoot",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "accountProof",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            },
+            {
+                "name": "storageRoot",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "storageProofs",
+                "type": "StorageProof",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "BlockHeader",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "ID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "version",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "chainID",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "height",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "timestamp",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "prevBlockID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "txRoot",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "stateRoot",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "receiptRoot",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "producerPubKey",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "signature",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "struct",
+        "name": "ActionProof",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": [
+            {
+                "name": "actionID",
+                "type": "string",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "blockHeader",
+                "type": "BlockHeader",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "index",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "numActions",
+                "type": "int",
+                "optional": false,
+                "is_array": false,
+                "comment": ""
+            },
+            {
+                "name": "merklePath",
+                "type": "string",
+                "optional": false,
+                "is_array": true,
+                "comment": ""
+            }
+        ],
+        "values": null,
+        "functions": null,
+        "barrister_version": "",
+        "date_generated": 0,
+        "checksum": ""
+    },
+    {
+        "type": "interface",
+        "name": "Explorer",
+        "comment": "",
+        "value": "",
+        "extends": "",
+        "fields": null,
+        "values": null,
+        "functions": [
+            {
+                "name": "getBlockchainHeight",
+                "comment": "get the blockchain tip height",
+                "params": [],
+                "returns": {
+                    "name": "",
+                    "type": "int",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getAddressBalance",
+                "comment": "get the balance of an address at the given height, or at the tip if the height is null",
+                "params": [
+                    {
+                        "name": "address",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "height",
+                        "type": "int",
+                        "optional": true,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "int",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getAddressDetails",
+                "comment": "get the address detail of an iotex address",
+                "params": [
+                    {
+                        "name": "address",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "AddressDetails",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
                 }
             },
             {
@@ -2692,9 +3510,63 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "getReceiptByActionID",
+                "comment": "get receipt by the id of a transfer, vote or execution",
+                "params": [
+                    {
+                        "name": "id",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "Receipt",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getLogs",
+                "comment": "get list of logs emitted by the contract addresses with the topics in the height range of the filter, which\nspans at most 10000 heights. The offset skips the logs on the from height, so the next page starts from the\nheight of the last log with the number of logs returned on that height as the offset",
+                "params": [
+                    {
+                        "name": "filter",
+                        "type": "LogFilter",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "offset",
+                        "type": "int",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "limit",
+                        "type": "int",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "Log",
+                    "optional": false,
+                    "is_array": true,
+                    "comment": ""
+                }
+            },
             {
                 "name": "readExecutionState",
-                "comment": "read execution state",
+                "comment": "read execution state at the given height, or at the tip if the height is null",
                 "params": [
                     {
                         "name": "request",
@@ -2702,6 +3574,13 @@ var IdlJsonRaw = `[
                         "optional": false,
                         "is_array": false,
                         "comment": ""
+                    },
+                    {
+                        "name": "height",
+                        "type": "int",
+                        "optional": true,
+                        "is_array": false,
+                        "comment": ""
                     }
                 ],
                 "returns": {
@@ -2712,6 +3591,46 @@ var IdlJsonRaw = `[
                     "comment": ""
                 }
             },
+            {
+                "name": "estimateGas",
+                "comment": "estimate the minimal gas limit with which the execution succeeds, or the reason why it never succeeds",
+                "params": [
+                    {
+                        "name": "request",
+                        "type": "Execution",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "EstimateGasResponse",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "traceExecution",
+                "comment": "replay an execution on the states of its parent block, and get its opcode steps and call tree",
+                "params": [
+                    {
+                        "name": "id",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "ExecutionTrace",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
             {
                 "name": "getBlockOrActionByHash",
                 "comment": "get block or action by a hash",
@@ -2731,6 +3650,60 @@ var IdlJsonRaw = `[
                     "is_array": false,
                     "comment": ""
                 }
+            },
+            {
+                "name": "getStateProof",
+                "comment": "get the Merkle proof of an account and its storage slots on the given height",
+                "params": [
+                    {
+                        "name": "address",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "height",
+                        "type": "int",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    },
+                    {
+                        "name": "storageKeys",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": true,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "StateProof",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
+            },
+            {
+                "name": "getActionProof",
+                "comment": "get the block header and the Merkle path proving a transfer, vote or execution is included in the block",
+                "params": [
+                    {
+                        "name": "actionID",
+                        "type": "string",
+                        "optional": false,
+                        "is_array": false,
+                        "comment": ""
+                    }
+                ],
+                "returns": {
+                    "name": "",
+                    "type": "ActionProof",
+                    "optional": false,
+                    "is_array": false,
+                    "comment": ""
+                }
             }
         ],
         "barrister_version": "",
@@ -2747,7 +3720,7 @@ var IdlJsonRaw = `[
         "values": null,
         "functions": null,
         "barrister_version": "0.1.6",
-        "date_generated": 1536941047139,
-        "checksum": "5bd957cffa724fec63f9d979bf845b7a"
+        "date_generated": 1792200132923,
+        "checksum": "7451763507d03639f60b5ebd883e7c6e"
     }
 ]`
@@ -24,7 +24,7 @@ func (exp *MockExplorer) GetBlockchainHeight() (int64, error) {
 }
 
 // GetAddressBalance returns the balance of an address
-func (exp *MockExplorer) GetAddressBalance(address string) (int64, error) {
+func (exp *MockExplorer) GetAddressBalance(address string, height *int64) (int64, error) {
 	return randInt64(), nil
 }
 
@@ -101,6 +101,16 @@ func (exp *MockExplorer) GetReceiptByExecutionID(id string) (explorer.Receipt, e
 	return explorer.Receipt{}, nil
 }
 
+// GetReceiptByActionID gets receipt with corresponding transfer, vote or execution id
+func (exp *MockExplorer) GetReceiptByActionID(id string) (explorer.Receipt, error) {
+	return explorer.Receipt{}, nil
+}
+
+// GetLogs gets logs matching the filter
+func (exp *MockExplorer) GetLogs(filter explorer.LogFilter, offset int64, limit int64) ([]explorer.Log, error) {
+	return []explorer.Log{}, nil
+}
+
 // GetLastExecutionsByRange return executions in [-(offset+limit-1), -offset] from block
 // with height startBlockHeight
 func (exp *MockExplorer) GetLastExecutionsByRange(startBlockHeight int64, offset int64, limit int64) ([]explorer.Execution, error) {
@@ -204,15 +214,35 @@ func (exp *MockExplorer) SendSmartContract(request explorer.Execution) (explorer
 }
 
 // ReadExecutionState sends a smart contract
-func (exp *MockExplorer) ReadExecutionState(request explorer.Execution) (string, error) {
+func (exp *MockExplorer) ReadExecutionState(request explorer.Execution, height *int64) (string, error) {
 	return "100", nil
 }
 
+// EstimateGas estimates the gas of an execution
+func (exp *MockExplorer) EstimateGas(request explorer.Execution) (explorer.EstimateGasResponse, error) {
+	return explorer.EstimateGasResponse{Gas: 100, Succeed: true}, nil
+}
+
+// TraceExecution traces an execution
+func (exp *MockExplorer) TraceExecution(id string) (explorer.ExecutionTrace, error) {
+	return explorer.ExecutionTrace{}, nil
+}
+
 // GetBlockOrActionByHash get block or action by a hash
 func (exp *MockExplorer) GetBlockOrActionByHash(hash string) (explorer.GetBlkOrActResponse, error) {
 	return explorer.GetBlkOrActResponse{}, nil
 }
 
+// GetStateProof returns the Merkle proof of an account and its storage slots
+func (exp *MockExplorer) GetStateProof(address string, height int64, storageKeys []string) (explorer.StateProof, error) {
+	return explorer.StateProof{}, nil
+}
+
+// GetActionProof returns the Merkle path proving an action is in a block
+func (exp *MockExplorer) GetActionProof(actionID string) (explorer.ActionProof, error) {
+	return explorer.ActionProof{}, nil
+}
+
 func randInt64() int64 {
 	rand.Seed(time.Now().UnixNano())
 	amount := int64(0)
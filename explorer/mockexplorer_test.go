@@ -20,7 +20,7 @@ func TestMockExplorerApi(t *testing.T) {
 	_, err := svc.GetBlockchainHeight()
 	require.Nil(err)
 
-	_, err = svc.GetAddressBalance("")
+	_, err = svc.GetAddressBalance("", nil)
 	require.Nil(err)
 
 	_, err = svc.GetAddressDetails("")
@@ -77,6 +77,12 @@ func TestMockExplorerApi(t *testing.T) {
 	_, err = svc.GetPeers()
 	require.Nil(err)
 
+	_, err = svc.GetStateProof("", 0, nil)
+	require.Nil(err)
+
+	_, err = svc.GetActionProof("")
+	require.Nil(err)
+
 	randInt64 := randInt64()
 	require.NotNil(randInt64)
 
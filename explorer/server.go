@@ -31,6 +31,7 @@ type Server struct {
 	exp     explorer.Explorer
 	jrpcSvr barrister.Server
 	httpSvr http.Server
+	hub     *subscriptionHub
 	port    int
 }
 
@@ -53,6 +54,7 @@ func NewServer(
 			p2p: p2p,
 			cfg: cfg,
 		},
+		hub: newSubscriptionHub(chain, actPool),
 	}
 }
 
@@ -66,13 +68,21 @@ func NewTestSever(cfg config.Explorer) *Server {
 
 // Start starts the explorer server
 func (s *Server) Start(_ context.Context) error {
+	mux := http.NewServeMux()
+	if s.hub != nil {
+		if err := s.hub.start(); err != nil {
+			return errors.Wrap(err, "error when starting subscription hub")
+		}
+		mux.Handle(SubscribePath, s.hub)
+	}
 	portStr := strconv.Itoa(s.cfg.Port)
 	started := make(chan bool)
 	go func(started chan bool) {
 		idl := barrister.MustParseIdlJson([]byte(explorer.IdlJsonRaw))
 		s.jrpcSvr = explorer.NewJSONServer(idl, true, s.exp)
 		s.jrpcSvr.AddFilter(logFilter{})
-		s.httpSvr = http.Server{Handler: &s.jrpcSvr}
+		mux.Handle("/", &s.jrpcSvr)
+		s.httpSvr = http.Server{Handler: mux}
 		listener, err := net.Listen("tcp", ":"+portStr)
 		if err != nil {
 			logger.Panic().Err(err).Msg("error when creating network listener")
@@ -97,6 +107,12 @@ func (s *Server) Start(_ context.Context) error {
 
 // Stop stops the explorer server
 func (s *Server) Stop(ctx context.Context) error {
+	// the subscriptions are streamed until the subscribers are dropped, so drop them before shutting down
+	if s.hub != nil {
+		if err := s.hub.stop(); err != nil {
+			return errors.Wrap(err, "error when stopping subscription hub")
+		}
+	}
 	if err := s.httpSvr.Shutdown(ctx); err != nil {
 		return errors.Wrap(err, "error when shutting down explorer http server")
 	}
@@ -0,0 +1,297 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package explorer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/actpool"
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/proto"
+)
+
+const (
+	// SubscribePath is the path of the explorer port serving the subscriptions as server-sent events
+	SubscribePath = "/subscribe"
+
+	// subscriberBufferSize is the number of events buffered for a subscriber, which is dropped once the buffer is full
+	subscriberBufferSize = 64
+	// hubBufferSize is the number of blocks or actions buffered for the hub
+	hubBufferSize = 256
+)
+
+// types of subscriptions
+const (
+	subscribeBlocks  = "blocks"
+	subscribeActions = "actions"
+	subscribeLogs    = "logs"
+)
+
+// event is pushed to a subscriber as a server-sent event, whose data is encoded in JSON
+type event struct {
+	name string
+	data interface{}
+}
+
+// subscriber receives the events of a type of subscription until it is dropped
+type subscriber struct {
+	kind    string
+	filter  *blockchain.LogFilter
+	events  chan event
+	dropped chan struct{}
+}
+
+// subscriptionHub receives the blocks committed to the chain and the actions accepted into the actpool, and pushes them
+// to the subscribers. A subscriber not keeping up with the events is dropped, so that neither committing blocks nor
+// adding actions is stalled by a slow subscriber
+type subscriptionHub struct {
+	bc          blockchain.Blockchain
+	ap          actpool.ActPool
+	blocks      chan *blockchain.Block
+	actions     chan *iproto.ActionPb
+	quit        chan struct{}
+	mutex       sync.Mutex
+	subscribers map[*subscriber]bool
+}
+
+// newSubscriptionHub creates a hub of the subscriptions to the chain and the actpool
+func newSubscriptionHub(bc blockchain.Blockchain, ap actpool.ActPool) *subscriptionHub {
+	return &subscriptionHub{
+		bc:          bc,
+		ap:          ap,
+		blocks:      make(chan *blockchain.Block, hubBufferSize),
+		actions:     make(chan *iproto.ActionPb, hubBufferSize),
+		quit:        make(chan struct{}),
+		subscribers: make(map[*subscriber]bool),
+	}
+}
+
+// start subscribes the chain and the actpool, and starts pushing events to the subscribers
+func (h *subscriptionHub) start() error {
+	if err := h.bc.SubscribeBlockCreation(h.blocks); err != nil {
+		return errors.Wrap(err, "failed to subscribe block creation")
+	}
+	if h.ap != nil {
+		if err := h.ap.SubscribeNewAction(h.actions); err != nil {
+			return errors.Wrap(err, "failed to subscribe new actions")
+		}
+	}
+	go h.run()
+	return nil
+}
+
+// stop unsubscribes the chain and the actpool, and drops all the subscribers
+func (h *subscriptionHub) stop() error {
+	if err := h.bc.UnsubscribeBlockCreation(h.blocks); err != nil {
+		return errors.Wrap(err, "failed to unsubscribe block creation")
+	}
+	if h.ap != nil {
+		if err := h.ap.UnsubscribeNewAction(h.actions); err != nil {
+			return errors.Wrap(err, "failed to unsubscribe new actions")
+		}
+	}
+	close(h.quit)
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for s := range h.subscribers {
+		h.drop(s)
+	}
+	return nil
+}
+
+func (h *subscriptionHub) run() {
+	for {
+		select {
+		case blk := <-h.blocks:
+			h.onBlock(blk)
+		case act := <-h.actions:
+			h.onAction(act)
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// subscribe adds a subscriber of the type of subscription, the filter only applies to the subscription of logs
+func (h *subscriptionHub) subscribe(kind string, filter *blockchain.LogFilter) *subscriber {
+	s := &subscriber{
+		kind:    kind,
+		filter:  filter,
+		events:  make(chan event, subscriberBufferSize),
+		dropped: make(chan struct{}),
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.subscribers[s] = true
+	return s
+}
+
+// unsubscribe removes the subscriber if it has not been dropped
+func (h *subscriptionHub) unsubscribe(s *subscriber) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.subscribers[s] {
+		h.drop(s)
+	}
+}
+
+// drop removes the subscriber and notifies it, the caller must hold the mutex
+func (h *subscriptionHub) drop(s *subscriber) {
+	delete(h.subscribers, s)
+	close(s.dropped)
+}
+
+// publish pushes the events to the subscribers of the type of subscription, if the i-th event passes the match for the
+// subscriber when the match is given
+func (h *subscriptionHub) publish(kind string, events []event, match func(s *subscriber, i int) bool) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for s := range h.subscribers {
+		if s.kind != kind {
+			continue
+		}
+		for i, e := range events {
+			if match != nil && !match(s, i) {
+				continue
+			}
+			select {
+			case s.events <- e:
+			default:
+				logger.Warn().Str("subscription", kind).Msg("Drop a slow subscriber")
+				h.drop(s)
+			}
+			if !h.subscribers[s] {
+				break
+			}
+		}
+	}
+}
+
+func (h *subscriptionHub) onBlock(blk *blockchain.Block) {
+	h.publish(subscribeBlocks, []event{{name: "block", data: convertBlockToExplorerBlock(blk)}}, nil)
+
+	// only executions emit logs
+	var logs []*blockchain.Log
+	for _, execution := range blk.Executions {
+		receipt, err := h.bc.GetReceiptByActionHash(execution.Hash())
+		if err != nil {
+			logger.Error().Err(err).Uint64("height", blk.Height()).Msg("Failed to get receipt of execution")
+			continue
+		}
+		logs = append(logs, receipt.Logs...)
+	}
+	if len(logs) == 0 {
+		return
+	}
+	events := make([]event, 0, len(logs))
+	for _, log := range logs {
+		events = append(events, event{name: "log", data: convertLogToExplorerLog(log)})
+	}
+	h.publish(subscribeLogs, events, func(s *subscriber, i int) bool {
+		return s.filter.Match(logs[i])
+	})
+}
+
+func (h *subscriptionHub) onAction(act *iproto.ActionPb) {
+	var e event
+	var err error
+	switch {
+	case act.GetTransfer() != nil:
+		transfer := &action.Transfer{}
+		transfer.ConvertFromActionPb(act)
+		e.name = "transfer"
+		e.data, err = convertTsfToExplorerTsf(transfer, true)
+	case act.GetVote() != nil:
+		vote := &action.Vote{}
+		vote.ConvertFromActionPb(act)
+		e.name = "vote"
+		e.data, err = convertVoteToExplorerVote(vote, true)
+	case act.GetExecution() != nil:
+		execution := &action.Execution{}
+		execution.ConvertFromActionPb(act)
+		e.name = "execution"
+		e.data, err = convertExecutionToExplorerExecution(execution, true)
+	default:
+		return
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to convert action")
+		return
+	}
+	h.publish(subscribeActions, []event{e}, nil)
+}
+
+// ServeHTTP streams a subscription as server-sent events. The type of subscription is given by the query parameter
+// "type", which is "blocks", "actions" or "logs". The logs can be filtered by the comma-separated contract addresses in
+// "addresses" and the comma-separated hex topics by position in "topics", where an empty topic matches any topic
+func (h *subscriptionHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	query := r.URL.Query()
+	kind := query.Get("type")
+	var filter *blockchain.LogFilter
+	switch kind {
+	case subscribeBlocks, subscribeActions:
+	case subscribeLogs:
+		var addresses, topics []string
+		if value := query.Get("addresses"); value != "" {
+			addresses = strings.Split(value, ",")
+		}
+		if value := query.Get("topics"); value != "" {
+			topics = strings.Split(value, ",")
+		}
+		var err error
+		if filter, err = toLogFilter(addresses, topics); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := filter.Validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unknown subscription type %s", kind), http.StatusBadRequest)
+		return
+	}
+
+	s := h.subscribe(kind, filter)
+	defer h.unsubscribe(s)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case e := <-s.events:
+			data, err := json.Marshal(e.data)
+			if err != nil {
+				logger.Error().Err(err).Str("event", e.name).Msg("Failed to encode event")
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-s.dropped:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package explorer
+
+import (
+	"bufio"
+	"encoding/hex"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/clock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/blockchain/action"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/test/mock/mock_blockchain"
+	ta "github.com/iotexproject/iotex-core/test/testaddress"
+)
+
+func TestSubscriptionHub(t *testing.T) {
+	require := require.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	topic := hash.Hash32B{1}
+	execution, err := action.NewExecution(ta.Addrinfo["producer"].RawAddress, ta.Addrinfo["alfa"].RawAddress, 1, big.NewInt(0), uint64(100000), big.NewInt(0), []byte{})
+	require.NoError(err)
+	blk := blockchain.NewBlock(0, 1, hash.ZeroHash32B, clock.New(), nil, nil, []*action.Execution{execution})
+	receipt := &blockchain.Receipt{Logs: []*blockchain.Log{
+		{Address: "contract1", Topics: []hash.Hash32B{topic}, BlockNumber: 1},
+		{Address: "contract2", BlockNumber: 1},
+	}}
+
+	bc := mock_blockchain.NewMockBlockchain(ctrl)
+	hub := newSubscriptionHub(bc, nil)
+	bc.EXPECT().SubscribeBlockCreation(hub.blocks).Return(nil).Times(1)
+	bc.EXPECT().UnsubscribeBlockCreation(hub.blocks).Return(nil).Times(1)
+	bc.EXPECT().GetReceiptByActionHash(execution.Hash()).Return(receipt, nil).AnyTimes()
+	require.NoError(hub.start())
+	svr := httptest.NewServer(hub)
+	defer svr.Close()
+
+	resp, err := http.Get(svr.URL + "?type=unknown")
+	require.NoError(err)
+	require.NoError(resp.Body.Close())
+	require.Equal(http.StatusBadRequest, resp.StatusCode)
+	resp, err = http.Get(svr.URL + "?type=logs&topics=" + strings.Repeat(",", 4))
+	require.NoError(err)
+	require.NoError(resp.Body.Close())
+	require.Equal(http.StatusBadRequest, resp.StatusCode)
+
+	blocksResp, err := http.Get(svr.URL + "?type=blocks")
+	require.NoError(err)
+	defer blocksResp.Body.Close()
+	require.Equal(http.StatusOK, blocksResp.StatusCode)
+	require.Equal("text/event-stream", blocksResp.Header.Get("Content-Type"))
+	logsResp, err := http.Get(svr.URL + "?type=logs&addresses=contract1&topics=" + hex.EncodeToString(topic[:]))
+	require.NoError(err)
+	defer logsResp.Body.Close()
+	require.Equal(http.StatusOK, logsResp.StatusCode)
+	waitSubscribers(t, hub, 2)
+
+	hub.blocks <- blk
+	blocks := bufio.NewReader(blocksResp.Body)
+	line, err := blocks.ReadString('\n')
+	require.NoError(err)
+	require.Equal("event: block\n", line)
+	line, err = blocks.ReadString('\n')
+	require.NoError(err)
+	require.True(strings.HasPrefix(line, "data: "))
+	require.Contains(line, `"height":1`)
+
+	// only the log of contract1 passes the filter
+	logs := bufio.NewReader(logsResp.Body)
+	line, err = logs.ReadString('\n')
+	require.NoError(err)
+	require.Equal("event: log\n", line)
+	line, err = logs.ReadString('\n')
+	require.NoError(err)
+	require.Contains(line, `"address":"contract1"`)
+
+	// a subscriber not reading the events is dropped once its buffer is full
+	s := hub.subscribe(subscribeBlocks, nil)
+	for i := 0; i <= subscriberBufferSize; i++ {
+		hub.publish(subscribeBlocks, []event{{name: "block"}}, nil)
+	}
+	select {
+	case <-s.dropped:
+	case <-time.After(time.Second):
+		require.Fail("slow subscriber is not dropped")
+	}
+	hub.unsubscribe(s)
+
+	require.NoError(hub.stop())
+	hub.mutex.Lock()
+	require.Equal(0, len(hub.subscribers))
+	hub.mutex.Unlock()
+}
+
+func waitSubscribers(t *testing.T, hub *subscriptionHub, n int) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		hub.mutex.Lock()
+		count := len(hub.subscribers)
+		hub.mutex.Unlock()
+		if count == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Fail(t, "subscribers are not added")
+}
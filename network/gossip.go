@@ -13,6 +13,8 @@ import (
 
 	"encoding/hex"
 
+	"github.com/pkg/errors"
+
 	"github.com/iotexproject/iotex-core/dispatch/dispatcher"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network/proto"
@@ -88,7 +90,10 @@ func (g *Gossip) processMsg(msgType uint32, msgBody []byte) error {
 		return err
 	}
 	if g.Dispatcher != nil {
-		g.Dispatcher.HandleBroadcast(protoMsg, nil)
+		// Only an invalid message stops the relay, while the other handling errors are local to this node
+		if err := g.Dispatcher.HandleBroadcast(protoMsg, nil); errors.Cause(err) == dispatcher.ErrInvalidMsg {
+			return err
+		}
 	}
 	return nil
 }
@@ -26,8 +26,12 @@ import (
 	"github.com/iotexproject/iotex-core/proto"
 )
 
-// ErrPeerNotFound means the peer is not found
-var ErrPeerNotFound = errors.New("Peer not found")
+var (
+	// ErrPeerNotFound means the peer is not found
+	ErrPeerNotFound = errors.New("Peer not found")
+	// ErrGenesisHashMismatch means the peer is in the network of another genesis
+	ErrGenesisHashMismatch = errors.New("Genesis hash mismatch")
+)
 
 // Overlay represents the peer-to-peer network
 type Overlay interface {
@@ -46,6 +50,9 @@ type IotxOverlay struct {
 	Tasks      []*routine.RecurringTask
 	Config     *config.Network
 	Dispatcher dispatcher.Dispatcher
+	// GenesisHash is the hash of the genesis of the chain, which the peers exchange in ping and pong to refuse to peer
+	// with the nodes of another network
+	GenesisHash []byte
 
 	lifecycle lifecycle.Lifecycle
 }
@@ -85,7 +85,8 @@ func (d *MockDispatcher) Stop(_ context.Context) error {
 	return nil
 }
 
-func (d *MockDispatcher) HandleBroadcast(proto.Message, chan bool) {
+func (d *MockDispatcher) HandleBroadcast(proto.Message, chan bool) error {
+	return nil
 }
 
 func (d *MockDispatcher) HandleTell(net.Addr, proto.Message, chan bool) {
@@ -96,8 +97,9 @@ type MockDispatcher1 struct {
 	Count uint32
 }
 
-func (d1 *MockDispatcher1) HandleBroadcast(proto.Message, chan bool) {
+func (d1 *MockDispatcher1) HandleBroadcast(proto.Message, chan bool) error {
 	d1.Count++
+	return nil
 }
 
 func TestOverlay(t *testing.T) {
@@ -380,8 +382,9 @@ func (d3 *MockDispatcher3) HandleTell(net.Addr, proto.Message, chan bool) {
 	d3.C <- true
 }
 
-func (d3 *MockDispatcher3) HandleBroadcast(proto.Message, chan bool) {
+func (d3 *MockDispatcher3) HandleBroadcast(proto.Message, chan bool) error {
 	d3.C <- true
+	return nil
 }
 
 func runBenchmarkOp(tell bool, size int, parallel bool, tls bool, b *testing.B) {
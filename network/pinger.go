@@ -7,6 +7,7 @@
 package network
 
 import (
+	"bytes"
 	"math/rand"
 
 	"github.com/iotexproject/iotex-core/logger"
@@ -33,7 +34,7 @@ func (h *Pinger) Ping() {
 				logger.Error().Msg("value is not an instance of Peer")
 				return
 			}
-			pong, err := p.Ping(&pb.Ping{Nonce: n, Addr: h.Overlay.RPC.String()})
+			pong, err := p.Ping(&pb.Ping{Nonce: n, Addr: h.Overlay.RPC.String(), GenesisHash: h.Overlay.GenesisHash})
 			if err != nil {
 				logger.Error().Err(err).Str("dst", p.String()).Msg("error when getting pong")
 				return
@@ -49,6 +50,13 @@ func (h *Pinger) Ping() {
 					Uint64("in-nonce", pong.AckNonce).
 					Msg("pong carries an unmatched nonce")
 			}
+			if !bytes.Equal(pong.GenesisHash, h.Overlay.GenesisHash) {
+				logger.Error().
+					Str("dst", p.String()).
+					Hex("genesis-hash", pong.GenesisHash).
+					Msg("pong carries an unmatched genesis hash")
+				h.Overlay.PM.RemovePeer(p.String())
+			}
 		}()
 		return true
 	})
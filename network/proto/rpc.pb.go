@@ -27,7 +27,9 @@ type Ping struct {
 	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
 	// Every one who participates into the network needs to tell others its address
 	// TODO: Seperate it as a standalone protocol
-	Addr                 string   `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Addr string `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	// The hash of the genesis of the network the node is in, which has to match to peer
+	GenesisHash          []byte   `protobuf:"bytes,3,opt,name=genesis_hash,json=genesisHash,proto3" json:"genesis_hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -71,8 +73,16 @@ func (m *Ping) GetAddr() string {
 	return ""
 }
 
+func (m *Ping) GetGenesisHash() []byte {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return nil
+}
+
 type Pong struct {
 	AckNonce             uint64   `protobuf:"varint,1,opt,name=ack_nonce,json=ackNonce,proto3" json:"ack_nonce,omitempty"`
+	GenesisHash          []byte   `protobuf:"bytes,2,opt,name=genesis_hash,json=genesisHash,proto3" json:"genesis_hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -109,6 +119,13 @@ func (m *Pong) GetAckNonce() uint64 {
 	return 0
 }
 
+func (m *Pong) GetGenesisHash() []byte {
+	if m != nil {
+		return m.GenesisHash
+	}
+	return nil
+}
+
 type GetPeersReq struct {
 	Count                uint32   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -578,29 +595,30 @@ var _Peer_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("network/proto/rpc.proto", fileDescriptor_rpc_66b4c8a5b08bed28) }
 
 var fileDescriptor_rpc_66b4c8a5b08bed28 = []byte{
-	// 375 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0xcb, 0x6e, 0xe2, 0x30,
-	0x14, 0xc5, 0x13, 0xf3, 0xba, 0x80, 0x84, 0x2c, 0x66, 0x26, 0x93, 0xd9, 0x04, 0x23, 0xa1, 0x2c,
-	0x2a, 0xa8, 0xda, 0x4d, 0xa5, 0xee, 0xe8, 0xa2, 0xbb, 0x0a, 0x45, 0xec, 0x51, 0x70, 0xac, 0x50,
-	0xe5, 0xe1, 0x34, 0x36, 0xaa, 0xf2, 0x19, 0xfd, 0xb6, 0xfe, 0x50, 0xe5, 0x60, 0xa2, 0x50, 0x41,
-	0x77, 0xf7, 0xdc, 0x73, 0x1f, 0xe7, 0x3e, 0xe0, 0x6f, 0xc6, 0xd5, 0xbb, 0x28, 0xe2, 0x65, 0x5e,
-	0x08, 0x25, 0x96, 0x45, 0xce, 0x16, 0x95, 0x45, 0xba, 0x86, 0xa0, 0xb7, 0x80, 0xd7, 0xaf, 0x59,
-	0x44, 0x26, 0xd0, 0xce, 0x44, 0xc6, 0xb8, 0x8d, 0x5c, 0xe4, 0x61, 0xff, 0x08, 0x08, 0x01, 0x1c,
-	0x84, 0x61, 0x61, 0xff, 0x72, 0x91, 0xd7, 0xf7, 0x2b, 0x9b, 0xce, 0x00, 0xaf, 0x45, 0x16, 0x91,
-	0xff, 0xd0, 0x0f, 0x58, 0xbc, 0x6d, 0x66, 0xf5, 0x02, 0x16, 0xbf, 0x68, 0x4c, 0x67, 0x30, 0x78,
-	0xe6, 0x6a, 0xcd, 0x79, 0x21, 0x7d, 0xfe, 0xa6, 0xab, 0x33, 0x71, 0xc8, 0x54, 0x15, 0x37, 0xf2,
-	0x8f, 0x80, 0x4e, 0x9b, 0x41, 0xb2, 0x6e, 0x86, 0x5c, 0xab, 0x6e, 0xf6, 0x81, 0x60, 0xb8, 0x2a,
-	0x44, 0x10, 0xb2, 0x40, 0x2a, 0x5d, 0xe9, 0x0f, 0x74, 0xf6, 0x3c, 0x08, 0x79, 0x61, 0x4a, 0x19,
-	0x44, 0xfe, 0x41, 0x2f, 0x95, 0xd1, 0x56, 0x95, 0x39, 0xaf, 0xd4, 0x8e, 0xfc, 0x6e, 0x2a, 0xa3,
-	0x4d, 0x99, 0xf3, 0x13, 0xb5, 0x13, 0x61, 0x69, 0x5b, 0x2e, 0xf2, 0x86, 0x15, 0xb5, 0x12, 0x61,
-	0x49, 0xa6, 0x30, 0xd4, 0x14, 0xdb, 0x73, 0x16, 0xcb, 0x43, 0x6a, 0xe3, 0x8a, 0x1e, 0xa4, 0x32,
-	0x7a, 0x32, 0x2e, 0x32, 0x06, 0x4b, 0xa9, 0xc4, 0x6e, 0xbb, 0xc8, 0x6b, 0xfb, 0xda, 0xa4, 0xf3,
-	0x33, 0x49, 0xf2, 0x9a, 0x24, 0x1a, 0x43, 0x77, 0xc3, 0x93, 0xe4, 0x27, 0xd5, 0x17, 0xf6, 0x7b,
-	0x36, 0x89, 0x75, 0x7d, 0x12, 0x7c, 0x36, 0x09, 0x9d, 0x9e, 0x9a, 0x5d, 0xd5, 0x73, 0xf7, 0x89,
-	0x00, 0xeb, 0x65, 0x93, 0x39, 0xe0, 0x5c, 0xdf, 0x7c, 0xb4, 0x30, 0x5f, 0xb0, 0xd0, 0x2f, 0xe0,
-	0x34, 0xa0, 0xc8, 0x22, 0xda, 0x22, 0x0f, 0xd0, 0x8b, 0xcc, 0x7d, 0xc8, 0xa4, 0x26, 0x1b, 0x77,
-	0x75, 0x2e, 0x79, 0x25, 0x6d, 0x91, 0x47, 0xe8, 0xef, 0x4e, 0x2b, 0x22, 0xbf, 0xeb, 0xa0, 0xe6,
-	0x25, 0x9d, 0x8b, 0x6e, 0x9d, 0x7c, 0x03, 0x58, 0xf1, 0x24, 0x21, 0xe3, 0x3a, 0xc0, 0xac, 0xd1,
-	0xf9, 0xee, 0x91, 0xb4, 0xb5, 0xeb, 0x54, 0x0f, 0x7d, 0xff, 0x15, 0x00, 0x00, 0xff, 0xff, 0x2b,
-	0xd6, 0x92, 0xa8, 0xeb, 0x02, 0x00, 0x00,
+	// 387 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7d, 0x53, 0x4d, 0x4f, 0xc2, 0x40,
+	0x10, 0xb5, 0xb4, 0x7c, 0x0d, 0x90, 0x90, 0x0d, 0x6a, 0xad, 0x17, 0xa8, 0x09, 0xf1, 0x60, 0x4a,
+	0xa2, 0x17, 0x13, 0x6f, 0x98, 0xa8, 0x27, 0x43, 0x2a, 0x77, 0x52, 0xda, 0x4d, 0x4b, 0x0a, 0xdd,
+	0xda, 0x5d, 0x62, 0xf8, 0x19, 0xfe, 0x36, 0xff, 0x90, 0xb3, 0x65, 0x69, 0x0a, 0x82, 0xb7, 0x99,
+	0xf7, 0x66, 0x5f, 0xdf, 0x7c, 0x14, 0x2e, 0x13, 0x2a, 0xbe, 0x58, 0x16, 0x8f, 0xd2, 0x8c, 0x09,
+	0x36, 0xca, 0x52, 0xdf, 0xc9, 0x23, 0x52, 0x57, 0x84, 0xfd, 0x01, 0xc6, 0x64, 0x91, 0x84, 0xa4,
+	0x07, 0xd5, 0x84, 0x25, 0x3e, 0x35, 0xb5, 0xbe, 0x76, 0x6b, 0xb8, 0xdb, 0x84, 0x10, 0x30, 0xbc,
+	0x20, 0xc8, 0xcc, 0x0a, 0x82, 0x4d, 0x37, 0x8f, 0xc9, 0x00, 0xda, 0x21, 0x4d, 0x28, 0x5f, 0xf0,
+	0x59, 0xe4, 0xf1, 0xc8, 0xd4, 0x91, 0x6b, 0xbb, 0x2d, 0x85, 0xbd, 0x21, 0x64, 0xbf, 0xa0, 0x28,
+	0x43, 0xd1, 0x6b, 0x68, 0x7a, 0x7e, 0x3c, 0x2b, 0x0b, 0x37, 0x10, 0x78, 0xcf, 0xb5, 0x0f, 0x75,
+	0x2a, 0x7f, 0x75, 0x6e, 0xa0, 0xf5, 0x4a, 0xc5, 0x84, 0xd2, 0x8c, 0xbb, 0xf4, 0x53, 0x7a, 0xf4,
+	0xd9, 0x3a, 0x11, 0xb9, 0x54, 0xc7, 0xdd, 0x26, 0xf6, 0xa0, 0x5c, 0xc4, 0x0b, 0xcb, 0x5a, 0x5f,
+	0xdf, 0x59, 0xb6, 0xbf, 0x35, 0x68, 0x8f, 0x33, 0xe6, 0x05, 0xbe, 0xc7, 0x85, 0x54, 0xba, 0x80,
+	0x5a, 0x44, 0xbd, 0x80, 0x66, 0x4a, 0x4a, 0x65, 0xe4, 0x0a, 0x1a, 0x2b, 0x1e, 0xce, 0xc4, 0x26,
+	0xa5, 0xb9, 0x9f, 0x8e, 0x5b, 0xc7, 0x7c, 0x8a, 0xe9, 0x8e, 0x9a, 0xb3, 0x60, 0xa3, 0x5a, 0x96,
+	0xd4, 0x18, 0x53, 0xd9, 0x89, 0xa4, 0xfc, 0x88, 0xfa, 0x31, 0x5f, 0xaf, 0x4c, 0x63, 0xdb, 0x09,
+	0x62, 0xcf, 0x0a, 0x22, 0x5d, 0xd0, 0x85, 0x58, 0x9a, 0x55, 0x64, 0xaa, 0xae, 0x0c, 0xed, 0xe1,
+	0x9e, 0x25, 0x7e, 0xca, 0x92, 0x1d, 0x43, 0x7d, 0x4a, 0x97, 0xcb, 0xff, 0x5c, 0x1f, 0xdb, 0x52,
+	0xb9, 0x13, 0xfd, 0x74, 0x27, 0xc6, 0x5e, 0x27, 0x38, 0x4b, 0xf5, 0xb1, 0x93, 0x7e, 0xee, 0x7f,
+	0x34, 0x5c, 0x2e, 0x0e, 0x9b, 0x0c, 0xc1, 0x48, 0xe5, 0xe5, 0x74, 0x1c, 0x75, 0x4b, 0x8e, 0x3c,
+	0x24, 0xab, 0x94, 0xe2, 0x09, 0xd8, 0x67, 0xe4, 0x11, 0x1a, 0xa1, 0xda, 0x0f, 0xe9, 0x15, 0x64,
+	0x69, 0xaf, 0xd6, 0x31, 0x94, 0xe3, 0xcb, 0x27, 0x68, 0xce, 0x77, 0x23, 0x22, 0xe7, 0x45, 0x51,
+	0x79, 0x93, 0xd6, 0x51, 0x58, 0x3e, 0xbe, 0x03, 0x43, 0x60, 0x2b, 0xa4, 0x5b, 0x14, 0xa8, 0x31,
+	0x5a, 0x87, 0x08, 0x56, 0xcf, 0x6b, 0xf9, 0x6f, 0xf1, 0xf0, 0x0b, 0x32, 0x43, 0x30, 0x02, 0x31,
+	0x03, 0x00, 0x00,
 }
@@ -7,18 +7,21 @@
 package network
 
 import (
+	"bytes"
 	"fmt"
 	"net"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/iotexproject/iotex-core/dispatch/dispatcher"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network/node"
 	pb "github.com/iotexproject/iotex-core/network/proto"
@@ -52,6 +55,7 @@ type RPCServer struct {
 
 	listenPort  string
 	counters    *sync.Map
+	penalties   *sync.Map
 	rateLimit   uint64
 	lastReqTime time.Time
 }
@@ -68,6 +72,7 @@ func NewRPCServer(o *IotxOverlay) *RPCServer {
 		listenPort: listenPort,
 		rateLimit:  o.Config.RateLimitPerSec * uint64(o.Config.RateLimitWindowSize) / uint64(time.Second),
 		counters:   &sync.Map{},
+		penalties:  &sync.Map{},
 	}
 }
 
@@ -82,8 +87,11 @@ func (s *RPCServer) Ping(ctx context.Context, ping *pb.Ping) (*pb.Pong, error) {
 		return nil, fmt.Errorf("sended requests too frequently")
 	}
 	sRequestMtc.WithLabelValues("Ping", "false").Inc()
+	if !bytes.Equal(ping.GenesisHash, s.Overlay.GenesisHash) {
+		return nil, errors.Wrapf(ErrGenesisHashMismatch, "peer %s has genesis hash %x", ping.Addr, ping.GenesisHash)
+	}
 	s.Overlay.PM.AddPeer(ping.Addr)
-	return &pb.Pong{AckNonce: ping.Nonce}, nil
+	return &pb.Pong{AckNonce: ping.Nonce, GenesisHash: s.Overlay.GenesisHash}, nil
 }
 
 // GetPeers implements the server side RPC logic
@@ -129,6 +137,9 @@ func (s *RPCServer) Broadcast(ctx context.Context, req *pb.BroadcastReq) (*pb.Br
 	if err == nil {
 		return &pb.BroadcastRes{Header: iproto.MagicBroadcastMsgHeader}, nil
 	}
+	if errors.Cause(err) == dispatcher.ErrInvalidMsg {
+		s.penalize(ctx)
+	}
 	return nil, err
 }
 
@@ -215,13 +226,22 @@ func (s *RPCServer) LastReqTime() time.Time {
 }
 
 func (s *RPCServer) shouldDropRequest(ctx context.Context) (bool, error) {
-	if !s.Overlay.Config.RateLimitEnabled {
+	if !s.Overlay.Config.RateLimitEnabled && s.Overlay.Config.InvalidMsgPenalty == 0 {
 		return false, nil
 	}
 	addr, err := s.getClientAddr(ctx)
 	if err != nil {
 		return false, err
 	}
+	if until, ok := s.penalties.Load(addr); ok {
+		if time.Now().Before(until.(time.Time)) {
+			return true, nil
+		}
+		s.penalties.Delete(addr)
+	}
+	if !s.Overlay.Config.RateLimitEnabled {
+		return false, nil
+	}
 	c, _ := s.counters.LoadOrStore(
 		addr,
 		counter.NewSlidingWindowCounterWithSecondSlot(s.Overlay.Config.RateLimitWindowSize))
@@ -232,6 +252,20 @@ func (s *RPCServer) shouldDropRequest(ctx context.Context) (bool, error) {
 	return false, nil
 }
 
+// penalize drops the requests from the peer in the context for the configured period
+func (s *RPCServer) penalize(ctx context.Context) {
+	if s.Overlay.Config.InvalidMsgPenalty == 0 {
+		return
+	}
+	addr, err := s.getClientAddr(ctx)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to penalize the peer sending an invalid message")
+		return
+	}
+	logger.Warn().Str("addr", addr).Msg("penalize the peer sending an invalid message")
+	s.penalties.Store(addr, time.Now().Add(s.Overlay.Config.InvalidMsgPenalty))
+}
+
 func (s *RPCServer) getClientAddr(ctx context.Context) (string, error) {
 	p, ok := peer.FromContext(ctx)
 	if !ok {
@@ -13,10 +13,12 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 
+	"github.com/iotexproject/iotex-core/dispatch/dispatcher"
 	pb "github.com/iotexproject/iotex-core/network/proto"
 	"github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/test/mock/mock_dispatcher"
@@ -52,6 +54,40 @@ func TestRpcPingPong(t *testing.T) {
 	assert.True(t, "127.0.0.1:10001" == value.(*Peer).String())
 }
 
+func TestRpcPingPongGenesisHash(t *testing.T) {
+	ctx := context.Background()
+	config := LoadTestConfig("", true)
+	o := &IotxOverlay{Config: config, GenesisHash: []byte{1, 2, 3}}
+	o.PM = NewPeerManager(o, 1, 1)
+	s := NewRPCServer(o)
+	o.RPC = s
+	err := s.Start(ctx)
+	require.NoError(t, err)
+	p := NewPeer(s.Network(), s.String())
+	err = p.Connect(config)
+	require.NoError(t, err)
+
+	defer func() {
+		err := p.Close()
+		require.NoError(t, err)
+		err = s.Stop(ctx)
+		require.NoError(t, err)
+	}()
+
+	// refuse to peer with the node of another network
+	_, err = p.Ping(&pb.Ping{Nonce: uint64(4689), Addr: "127.0.0.1:10001", GenesisHash: []byte{3, 2, 1}})
+	require.Error(t, err)
+	require.True(t, strings.Contains(err.Error(), ErrGenesisHashMismatch.Error()))
+	_, ok := o.PM.Peers.Load("127.0.0.1:10001")
+	require.False(t, ok)
+
+	pong, err := p.Ping(&pb.Ping{Nonce: uint64(4689), Addr: "127.0.0.1:10001", GenesisHash: []byte{1, 2, 3}})
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, pong.GenesisHash)
+	_, ok = o.PM.Peers.Load("127.0.0.1:10001")
+	require.True(t, ok)
+}
+
 func TestGetPeers(t *testing.T) {
 	ctx := context.Background()
 	config := LoadTestConfig("", true)
@@ -129,6 +165,53 @@ func TestBroadcast(t *testing.T) {
 	assert.Equal(t, iproto.MagicBroadcastMsgHeader, res.Header)
 }
 
+func TestBroadcastInvalidMsgPenalty(t *testing.T) {
+	ctx := context.Background()
+	mctrl := gomock.NewController(t)
+	dp := mock_dispatcher.NewMockDispatcher(mctrl)
+	dp.EXPECT().HandleBroadcast(gomock.Any(), gomock.Any()).
+		Return(errors.Wrap(dispatcher.ErrInvalidMsg, "forged message")).Times(1)
+
+	config := LoadTestConfig("", true)
+	config.InvalidMsgPenalty = time.Minute
+	o := &IotxOverlay{Config: config}
+	o.PM = NewPeerManager(o, 0, 0)
+	o.Gossip = NewGossip(o)
+	o.Gossip.AttachDispatcher(dp)
+	s := NewRPCServer(o)
+	o.RPC = s
+	err := s.Start(ctx)
+	require.NoError(t, err)
+	p := NewPeer(s.Network(), s.String())
+	err = p.Connect(config)
+	require.NoError(t, err)
+
+	defer func() {
+		err := p.Close()
+		assert.NoError(t, err)
+		err = s.Stop(ctx)
+		assert.NoError(t, err)
+		mctrl.Finish()
+	}()
+
+	b, _ := proto.Marshal(&iproto.ViewChangeMsg{})
+	_, err = p.BroadcastMsg(&pb.BroadcastReq{
+		Header:      iproto.MagicBroadcastMsgHeader,
+		MsgType:     iproto.ViewChangeMsgType,
+		MsgBody:     b,
+		MsgChecksum: []byte{1},
+	})
+	assert.Error(t, err)
+	// The following requests from the penalized peer are dropped without reaching the dispatcher
+	_, err = p.BroadcastMsg(&pb.BroadcastReq{
+		Header:      iproto.MagicBroadcastMsgHeader,
+		MsgType:     iproto.ViewChangeMsgType,
+		MsgBody:     b,
+		MsgChecksum: []byte{2},
+	})
+	assert.Error(t, err)
+}
+
 func TestRPCTell(t *testing.T) {
 	ctx := context.Background()
 	mctrl := gomock.NewController(t)
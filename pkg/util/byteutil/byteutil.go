@@ -25,6 +25,11 @@ func Uint64ToBytes(value uint64) []byte {
 	return bytes
 }
 
+// BytesToUint32 converts 4 bytes with the machine endian to uint32
+func BytesToUint32(value []byte) uint32 {
+	return enc.MachineEndian.Uint32(value)
+}
+
 // BytesToUint64 converts 8 bytes with the machine endian to uint64
 func BytesToUint64(value []byte) uint64 {
 	return enc.MachineEndian.Uint64(value)
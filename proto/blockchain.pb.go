@@ -351,6 +351,9 @@ type ReceiptPb struct {
 	GasConsumed          uint64   `protobuf:"varint,4,opt,name=gasConsumed" json:"gasConsumed,omitempty"`
 	ContractAddress      string   `protobuf:"bytes,5,opt,name=contractAddress" json:"contractAddress,omitempty"`
 	Logs                 []*LogPb `protobuf:"bytes,6,rep,name=logs" json:"logs,omitempty"`
+	BlockHeight          uint64   `protobuf:"varint,7,opt,name=blockHeight" json:"blockHeight,omitempty"`
+	BlockHash            []byte   `protobuf:"bytes,8,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Index                uint32   `protobuf:"varint,9,opt,name=index" json:"index,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -422,16 +425,39 @@ func (m *ReceiptPb) GetLogs() []*LogPb {
 	return nil
 }
 
+func (m *ReceiptPb) GetBlockHeight() uint64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *ReceiptPb) GetBlockHash() []byte {
+	if m != nil {
+		return m.BlockHash
+	}
+	return nil
+}
+
+func (m *ReceiptPb) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
 type ActionPb struct {
 	Version   uint32 `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
 	Nonce     uint64 `protobuf:"varint,2,opt,name=nonce" json:"nonce,omitempty"`
 	GasLimit  uint64 `protobuf:"varint,3,opt,name=gasLimit" json:"gasLimit,omitempty"`
 	GasPrice  []byte `protobuf:"bytes,4,opt,name=gasPrice,proto3" json:"gasPrice,omitempty"`
 	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	ChainID   uint32 `protobuf:"varint,6,opt,name=chainID" json:"chainID,omitempty"`
 	// Types that are valid to be assigned to Action:
 	//	*ActionPb_Transfer
 	//	*ActionPb_Vote
 	//	*ActionPb_Execution
+	//	*ActionPb_Evidence
 	Action               isActionPb_Action `protobuf_oneof:"action"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
@@ -475,10 +501,14 @@ type ActionPb_Vote struct {
 type ActionPb_Execution struct {
 	Execution *ExecutionPb `protobuf:"bytes,12,opt,name=execution,oneof"`
 }
+type ActionPb_Evidence struct {
+	Evidence *EvidencePb `protobuf:"bytes,13,opt,name=evidence,oneof"`
+}
 
 func (*ActionPb_Transfer) isActionPb_Action()  {}
 func (*ActionPb_Vote) isActionPb_Action()      {}
 func (*ActionPb_Execution) isActionPb_Action() {}
+func (*ActionPb_Evidence) isActionPb_Action()  {}
 
 func (m *ActionPb) GetAction() isActionPb_Action {
 	if m != nil {
@@ -522,6 +552,13 @@ func (m *ActionPb) GetSignature() []byte {
 	return nil
 }
 
+func (m *ActionPb) GetChainID() uint32 {
+	if m != nil {
+		return m.ChainID
+	}
+	return 0
+}
+
 func (m *ActionPb) GetTransfer() *TransferPb {
 	if x, ok := m.GetAction().(*ActionPb_Transfer); ok {
 		return x.Transfer
@@ -543,12 +580,20 @@ func (m *ActionPb) GetExecution() *ExecutionPb {
 	return nil
 }
 
+func (m *ActionPb) GetEvidence() *EvidencePb {
+	if x, ok := m.GetAction().(*ActionPb_Evidence); ok {
+		return x.Evidence
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*ActionPb) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _ActionPb_OneofMarshaler, _ActionPb_OneofUnmarshaler, _ActionPb_OneofSizer, []interface{}{
 		(*ActionPb_Transfer)(nil),
 		(*ActionPb_Vote)(nil),
 		(*ActionPb_Execution)(nil),
+		(*ActionPb_Evidence)(nil),
 	}
 }
 
@@ -571,6 +616,11 @@ func _ActionPb_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.Execution); err != nil {
 			return err
 		}
+	case *ActionPb_Evidence:
+		b.EncodeVarint(13<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Evidence); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("ActionPb.Action has unexpected type %T", x)
@@ -605,6 +655,14 @@ func _ActionPb_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffe
 		err := b.DecodeMessage(msg)
 		m.Action = &ActionPb_Execution{msg}
 		return true, err
+	case 13: // action.evidence
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(EvidencePb)
+		err := b.DecodeMessage(msg)
+		m.Action = &ActionPb_Evidence{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -629,6 +687,11 @@ func _ActionPb_OneofSizer(msg proto.Message) (n int) {
 		n += 1 // tag and wire
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *ActionPb_Evidence:
+		s := proto.Size(x.Evidence)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -784,6 +847,7 @@ func (m *BlockHeaderPb) GetDkgSignature() []byte {
 type BlockPb struct {
 	Header               *BlockHeaderPb `protobuf:"bytes,1,opt,name=header" json:"header,omitempty"`
 	Actions              []*ActionPb    `protobuf:"bytes,2,rep,name=actions" json:"actions,omitempty"`
+	CommitCert           *CommitCertPb  `protobuf:"bytes,3,opt,name=commit_cert,json=commitCert" json:"commit_cert,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -827,6 +891,13 @@ func (m *BlockPb) GetActions() []*ActionPb {
 	return nil
 }
 
+func (m *BlockPb) GetCommitCert() *CommitCertPb {
+	if m != nil {
+		return m.CommitCert
+	}
+	return nil
+}
+
 // index of block raw data file
 type BlockIndex struct {
 	Start                uint64   `protobuf:"varint,1,opt,name=start" json:"start,omitempty"`
@@ -974,6 +1045,9 @@ type ViewChangeMsg struct {
 	BlockHash            []byte                       `protobuf:"bytes,3,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
 	SenderAddr           string                       `protobuf:"bytes,4,opt,name=senderAddr" json:"senderAddr,omitempty"`
 	Decision             bool                         `protobuf:"varint,5,opt,name=decision" json:"decision,omitempty"`
+	Height               uint64                       `protobuf:"varint,6,opt,name=height" json:"height,omitempty"`
+	Round                uint32                       `protobuf:"varint,7,opt,name=round" json:"round,omitempty"`
+	Signature            []byte                       `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
 	XXX_unrecognized     []byte                       `json:"-"`
 	XXX_sizecache        int32                        `json:"-"`
@@ -1038,13 +1112,36 @@ func (m *ViewChangeMsg) GetDecision() bool {
 	return false
 }
 
+func (m *ViewChangeMsg) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *ViewChangeMsg) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *ViewChangeMsg) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 // Candidates and list of candidates
 type Candidate struct {
-	Address              string   `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
-	Votes                []byte   `protobuf:"bytes,2,opt,name=votes,proto3" json:"votes,omitempty"`
-	PubKey               []byte   `protobuf:"bytes,3,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
-	CreationHeight       uint64   `protobuf:"varint,4,opt,name=creationHeight" json:"creationHeight,omitempty"`
-	LastUpdateHeight     uint64   `protobuf:"varint,5,opt,name=lastUpdateHeight" json:"lastUpdateHeight,omitempty"`
+	Address          string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
+	Votes            []byte `protobuf:"bytes,2,opt,name=votes,proto3" json:"votes,omitempty"`
+	PubKey           []byte `protobuf:"bytes,3,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	CreationHeight   uint64 `protobuf:"varint,4,opt,name=creationHeight" json:"creationHeight,omitempty"`
+	LastUpdateHeight uint64 `protobuf:"varint,5,opt,name=lastUpdateHeight" json:"lastUpdateHeight,omitempty"`
+	// height until which the candidate is jailed for its equivocation, exclusive
+	JailedUntil          uint64   `protobuf:"varint,6,opt,name=jailedUntil" json:"jailedUntil,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1109,6 +1206,13 @@ func (m *Candidate) GetLastUpdateHeight() uint64 {
 	return 0
 }
 
+func (m *Candidate) GetJailedUntil() uint64 {
+	if m != nil {
+		return m.JailedUntil
+	}
+	return 0
+}
+
 type CandidateList struct {
 	Candidates           []*Candidate `protobuf:"bytes,1,rep,name=candidates" json:"candidates,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
@@ -1147,6 +1251,156 @@ func (m *CandidateList) GetCandidates() []*Candidate {
 	return nil
 }
 
+// endorsement of a block by a delegate, which is the signature of the delegate's vote for the block
+type EndorsementPb struct {
+	Endorser             string   `protobuf:"bytes,1,opt,name=endorser" json:"endorser,omitempty"`
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EndorsementPb) Reset()         { *m = EndorsementPb{} }
+func (m *EndorsementPb) String() string { return proto.CompactTextString(m) }
+func (*EndorsementPb) ProtoMessage()    {}
+func (*EndorsementPb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_e0462512d733363d, []int{14}
+}
+func (m *EndorsementPb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EndorsementPb.Unmarshal(m, b)
+}
+func (m *EndorsementPb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EndorsementPb.Marshal(b, m, deterministic)
+}
+func (dst *EndorsementPb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EndorsementPb.Merge(dst, src)
+}
+func (m *EndorsementPb) XXX_Size() int {
+	return xxx_messageInfo_EndorsementPb.Size(m)
+}
+func (m *EndorsementPb) XXX_DiscardUnknown() {
+	xxx_messageInfo_EndorsementPb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EndorsementPb proto.InternalMessageInfo
+
+func (m *EndorsementPb) GetEndorser() string {
+	if m != nil {
+		return m.Endorser
+	}
+	return ""
+}
+
+func (m *EndorsementPb) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// commit certificate of a block, which consists of the endorsements of more than 2/3 of the delegates
+type CommitCertPb struct {
+	Height               uint64           `protobuf:"varint,1,opt,name=height" json:"height,omitempty"`
+	Round                uint32           `protobuf:"varint,2,opt,name=round" json:"round,omitempty"`
+	Endorsements         []*EndorsementPb `protobuf:"bytes,3,rep,name=endorsements" json:"endorsements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *CommitCertPb) Reset()         { *m = CommitCertPb{} }
+func (m *CommitCertPb) String() string { return proto.CompactTextString(m) }
+func (*CommitCertPb) ProtoMessage()    {}
+func (*CommitCertPb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_e0462512d733363d, []int{15}
+}
+func (m *CommitCertPb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitCertPb.Unmarshal(m, b)
+}
+func (m *CommitCertPb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitCertPb.Marshal(b, m, deterministic)
+}
+func (dst *CommitCertPb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitCertPb.Merge(dst, src)
+}
+func (m *CommitCertPb) XXX_Size() int {
+	return xxx_messageInfo_CommitCertPb.Size(m)
+}
+func (m *CommitCertPb) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitCertPb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitCertPb proto.InternalMessageInfo
+
+func (m *CommitCertPb) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *CommitCertPb) GetRound() uint32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *CommitCertPb) GetEndorsements() []*EndorsementPb {
+	if m != nil {
+		return m.Endorsements
+	}
+	return nil
+}
+
+// evidence of the equivocation of a delegate, which consists of two conflicting view change messages signed by the
+// delegate at the same height and round
+type EvidencePb struct {
+	First                *ViewChangeMsg `protobuf:"bytes,1,opt,name=first" json:"first,omitempty"`
+	Second               *ViewChangeMsg `protobuf:"bytes,2,opt,name=second" json:"second,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *EvidencePb) Reset()         { *m = EvidencePb{} }
+func (m *EvidencePb) String() string { return proto.CompactTextString(m) }
+func (*EvidencePb) ProtoMessage()    {}
+func (*EvidencePb) Descriptor() ([]byte, []int) {
+	return fileDescriptor_blockchain_e0462512d733363d, []int{16}
+}
+func (m *EvidencePb) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EvidencePb.Unmarshal(m, b)
+}
+func (m *EvidencePb) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EvidencePb.Marshal(b, m, deterministic)
+}
+func (dst *EvidencePb) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EvidencePb.Merge(dst, src)
+}
+func (m *EvidencePb) XXX_Size() int {
+	return xxx_messageInfo_EvidencePb.Size(m)
+}
+func (m *EvidencePb) XXX_DiscardUnknown() {
+	xxx_messageInfo_EvidencePb.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EvidencePb proto.InternalMessageInfo
+
+func (m *EvidencePb) GetFirst() *ViewChangeMsg {
+	if m != nil {
+		return m.First
+	}
+	return nil
+}
+
+func (m *EvidencePb) GetSecond() *ViewChangeMsg {
+	if m != nil {
+		return m.Second
+	}
+	return nil
+}
+
 // //////////////////////////////////////////////////////////////////////////////////////////////////
 // BELOW ARE DEFINITIONS FOR TEST-ONLY MESSAGES!
 // //////////////////////////////////////////////////////////////////////////////////////////////////
@@ -1161,7 +1415,7 @@ func (m *TestPayload) Reset()         { *m = TestPayload{} }
 func (m *TestPayload) String() string { return proto.CompactTextString(m) }
 func (*TestPayload) ProtoMessage()    {}
 func (*TestPayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_blockchain_e0462512d733363d, []int{14}
+	return fileDescriptor_blockchain_e0462512d733363d, []int{17}
 }
 func (m *TestPayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TestPayload.Unmarshal(m, b)
@@ -1203,6 +1457,9 @@ func init() {
 	proto.RegisterType((*ViewChangeMsg)(nil), "iproto.ViewChangeMsg")
 	proto.RegisterType((*Candidate)(nil), "iproto.Candidate")
 	proto.RegisterType((*CandidateList)(nil), "iproto.CandidateList")
+	proto.RegisterType((*EndorsementPb)(nil), "iproto.EndorsementPb")
+	proto.RegisterType((*CommitCertPb)(nil), "iproto.CommitCertPb")
+	proto.RegisterType((*EvidencePb)(nil), "iproto.EvidencePb")
 	proto.RegisterType((*TestPayload)(nil), "iproto.TestPayload")
 	proto.RegisterEnum("iproto.ViewChangeMsg_ViewChangeType", ViewChangeMsg_ViewChangeType_name, ViewChangeMsg_ViewChangeType_value)
 }
@@ -1210,76 +1467,87 @@ func init() {
 func init() { proto.RegisterFile("blockchain.proto", fileDescriptor_blockchain_e0462512d733363d) }
 
 var fileDescriptor_blockchain_e0462512d733363d = []byte{
-	// 1133 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x56, 0x4d, 0x6f, 0x23, 0x45,
-	0x13, 0xce, 0xf8, 0xdb, 0xe5, 0xd8, 0xeb, 0xb7, 0xdf, 0x65, 0x35, 0xa0, 0x15, 0x32, 0xa3, 0x65,
-	0x65, 0xad, 0xc4, 0x0a, 0x92, 0x03, 0x17, 0x2e, 0x89, 0x63, 0x61, 0x8b, 0x90, 0x8c, 0x3a, 0xc1,
-	0x88, 0x53, 0xd4, 0x33, 0xd3, 0x19, 0x8f, 0x62, 0x4f, 0x5b, 0xd3, 0xed, 0x10, 0xff, 0x09, 0xee,
-	0x1c, 0xe1, 0xb2, 0x7f, 0x81, 0x0b, 0x77, 0x7e, 0x16, 0xea, 0xea, 0xee, 0xf1, 0x07, 0x90, 0x53,
-	0xe6, 0x79, 0xaa, 0x5c, 0x5d, 0xf5, 0x54, 0x75, 0x75, 0xa0, 0x1f, 0x2d, 0x44, 0xfc, 0x10, 0xcf,
-	0x59, 0x96, 0xbf, 0x5f, 0x15, 0x42, 0x09, 0xd2, 0xc8, 0xf0, 0x6f, 0xf0, 0x87, 0x07, 0x70, 0x5b,
-	0xb0, 0x5c, 0xde, 0xf3, 0x22, 0x8c, 0xc8, 0x2b, 0x68, 0xb0, 0xa5, 0x58, 0xe7, 0xca, 0xf7, 0x06,
-	0xde, 0xf0, 0x98, 0x5a, 0xa4, 0x79, 0xc9, 0xf3, 0x84, 0x17, 0x7e, 0x65, 0xe0, 0x0d, 0xdb, 0xd4,
-	0x22, 0xf2, 0x1a, 0xda, 0x05, 0x8f, 0xb3, 0x55, 0xc6, 0x73, 0xe5, 0x57, 0xd1, 0xb4, 0x25, 0x88,
-	0x0f, 0xcd, 0x15, 0xdb, 0x2c, 0x04, 0x4b, 0xfc, 0x1a, 0x86, 0x73, 0x90, 0x04, 0x70, 0x6c, 0x22,
-	0x84, 0xeb, 0xe8, 0x3b, 0xbe, 0xf1, 0xeb, 0x68, 0xde, 0xe3, 0xc8, 0xa7, 0x00, 0x99, 0x1c, 0x89,
-	0x2c, 0x8f, 0x98, 0xe4, 0x7e, 0x63, 0xe0, 0x0d, 0x5b, 0x74, 0x87, 0x09, 0x7e, 0xf1, 0xa0, 0x31,
-	0x13, 0x8a, 0x87, 0x91, 0x4e, 0x43, 0x65, 0x4b, 0x2e, 0x15, 0x5b, 0xae, 0x30, 0xf3, 0x1a, 0xdd,
-	0x12, 0x3a, 0x90, 0xe4, 0x8b, 0xfb, 0x70, 0x1d, 0x3d, 0xf0, 0x0d, 0x16, 0x70, 0x4c, 0x77, 0x18,
-	0x9d, 0xcc, 0xa3, 0x50, 0xbc, 0x38, 0x4b, 0x92, 0x82, 0x4b, 0x69, 0xeb, 0xd8, 0xe3, 0x9c, 0x0f,
-	0x77, 0x3e, 0xb5, 0xad, 0x8f, 0xe3, 0x82, 0x5f, 0x3d, 0xe8, 0x8c, 0x9f, 0x78, 0xbc, 0x56, 0x99,
-	0xc8, 0x9f, 0x11, 0xf3, 0x13, 0x68, 0x71, 0x74, 0x13, 0x4e, 0xce, 0x12, 0x6b, 0x5b, 0x2c, 0x72,
-	0x55, 0xb0, 0xd8, 0xe9, 0x59, 0x62, 0xf2, 0x16, 0x7a, 0xce, 0xcf, 0xca, 0x66, 0x54, 0x3d, 0x60,
-	0x09, 0x81, 0x5a, 0xc2, 0x14, 0xb3, 0xa2, 0xe2, 0x77, 0xf0, 0xa7, 0x07, 0xf5, 0x4b, 0x91, 0x86,
-	0x91, 0x6e, 0x0a, 0xb3, 0x45, 0x78, 0x78, 0x80, 0x83, 0x3a, 0x5f, 0x25, 0x56, 0x59, 0x2c, 0xfd,
-	0xca, 0xa0, 0xaa, 0xf3, 0x35, 0xa8, 0x8c, 0x57, 0xdd, 0xc6, 0x23, 0x03, 0xe8, 0xe0, 0x4c, 0x5d,
-	0xad, 0x97, 0x11, 0x2f, 0x30, 0x91, 0x1a, 0xdd, 0xa5, 0xf4, 0x39, 0xea, 0x29, 0x9f, 0x30, 0x39,
-	0xb7, 0x89, 0x38, 0xa8, 0xbb, 0x85, 0x8e, 0x68, 0x6b, 0xa0, 0x6d, 0x4b, 0x90, 0x97, 0x50, 0xcf,
-	0xf2, 0x84, 0x3f, 0xf9, 0xcd, 0x81, 0x37, 0xec, 0x52, 0x03, 0x82, 0xbf, 0x3c, 0x68, 0x53, 0x1e,
-	0xf3, 0x6c, 0xa5, 0xc2, 0x48, 0x9f, 0x5e, 0x70, 0xb5, 0x2e, 0xf2, 0x19, 0x5b, 0xac, 0xb9, 0x95,
-	0x77, 0x97, 0xc2, 0x81, 0x55, 0x4c, 0xad, 0x25, 0x2a, 0x5c, 0xa3, 0x16, 0xe9, 0x5a, 0xe6, 0xfa,
-	0x58, 0x5b, 0x8b, 0xfe, 0xd6, 0xd1, 0x52, 0x26, 0x47, 0x22, 0x97, 0xeb, 0x25, 0x4f, 0x5c, 0x2d,
-	0x3b, 0x14, 0x19, 0xc2, 0x0b, 0xd7, 0x05, 0x37, 0x00, 0x75, 0xd4, 0xee, 0x90, 0x26, 0x9f, 0x41,
-	0x6d, 0x21, 0x52, 0xe9, 0x37, 0x06, 0xd5, 0x61, 0xe7, 0xa4, 0xfb, 0xde, 0x5c, 0xb3, 0xf7, 0x28,
-	0x3d, 0x45, 0x53, 0xf0, 0xa1, 0x02, 0xad, 0xb3, 0xd8, 0xce, 0x88, 0x0f, 0xcd, 0x47, 0x5e, 0xc8,
-	0x4c, 0xe4, 0x58, 0x45, 0x97, 0x3a, 0xa8, 0x75, 0xc8, 0x45, 0x1e, 0x73, 0x5b, 0x80, 0x01, 0x7a,
-	0x3e, 0x52, 0x26, 0x2f, 0xb3, 0x65, 0x66, 0xe6, 0xa3, 0x46, 0x4b, 0x6c, 0x6d, 0x61, 0x91, 0xc5,
-	0xdc, 0x4e, 0x46, 0x89, 0xb5, 0xe6, 0x32, 0x4b, 0x73, 0xa6, 0xd6, 0x05, 0xb7, 0xfd, 0xd8, 0x12,
-	0xe4, 0x4b, 0x68, 0x29, 0xbb, 0x04, 0x7c, 0x18, 0x78, 0xc3, 0xce, 0x09, 0x71, 0x99, 0x6f, 0x97,
-	0xc3, 0xe4, 0x88, 0x96, 0x5e, 0xe4, 0x0d, 0xd4, 0xf4, 0xec, 0xfb, 0x1d, 0xf4, 0xee, 0x39, 0x6f,
-	0x73, 0x1f, 0x27, 0x47, 0x14, 0xad, 0xe4, 0x14, 0xda, 0xdc, 0x5d, 0x08, 0xff, 0x18, 0x5d, 0xff,
-	0xef, 0x5c, 0x77, 0x6e, 0xca, 0xe4, 0x88, 0x6e, 0xfd, 0xce, 0x5b, 0xd0, 0x60, 0x28, 0x4f, 0xf0,
-	0x5b, 0x15, 0xba, 0xe7, 0x38, 0x18, 0x9c, 0x25, 0xb8, 0x9f, 0xfe, 0x5b, 0x2e, 0x1f, 0x9a, 0xb8,
-	0xdf, 0xa6, 0x17, 0x28, 0x58, 0x97, 0x3a, 0xa8, 0x47, 0x61, 0xce, 0xb3, 0x74, 0xee, 0x04, 0xb3,
-	0x68, 0x7f, 0x69, 0xd4, 0x0e, 0x97, 0xc6, 0x1b, 0xe8, 0xae, 0x0a, 0xfe, 0x78, 0x5e, 0x0e, 0xaa,
-	0x11, 0x6d, 0x9f, 0xc4, 0x2b, 0xf3, 0x44, 0x85, 0x50, 0x76, 0x8e, 0x2d, 0x42, 0xb9, 0x15, 0x53,
-	0x1c, 0x4d, 0x4d, 0x2b, 0xb7, 0x23, 0xcc, 0xf8, 0xe2, 0x2c, 0xa3, 0xbd, 0xe5, 0xc6, 0xb7, 0xa4,
-	0x74, 0x2b, 0x0b, 0x2e, 0x79, 0xf1, 0xc8, 0x13, 0xbf, 0x6d, 0x5a, 0xe9, 0xf0, 0x7e, 0x2b, 0xe1,
-	0xb0, 0x95, 0xaf, 0xa0, 0xb1, 0x32, 0x8b, 0xae, 0x63, 0x32, 0x32, 0x48, 0x8f, 0x53, 0xf2, 0x90,
-	0x4e, 0x2f, 0xb0, 0x0d, 0xc7, 0xd4, 0x00, 0x1d, 0x2b, 0x79, 0x48, 0xed, 0x66, 0xec, 0x9a, 0x58,
-	0x25, 0xa1, 0x97, 0x5e, 0xf2, 0x90, 0xde, 0x94, 0x87, 0xf5, 0xcc, 0x96, 0xde, 0xe5, 0x82, 0x04,
-	0x9a, 0x28, 0x47, 0x18, 0x91, 0x2f, 0xb4, 0xd0, 0xba, 0x51, 0xd8, 0x9b, 0xce, 0xc9, 0x47, 0xae,
-	0xd5, 0x7b, 0x3d, 0xa4, 0xd6, 0x89, 0xbc, 0x83, 0xa6, 0xe9, 0xb3, 0xd9, 0x37, 0x9d, 0x93, 0xbe,
-	0xf3, 0x77, 0xb7, 0x83, 0x3a, 0x87, 0xe0, 0x12, 0x00, 0x83, 0x4c, 0xf5, 0x32, 0xd0, 0xb5, 0x48,
-	0xc5, 0x0a, 0x65, 0x57, 0xbd, 0x01, 0xa4, 0x0f, 0x55, 0x9e, 0x27, 0xf6, 0xba, 0xe8, 0x4f, 0xad,
-	0x85, 0xb8, 0xbf, 0x97, 0x5c, 0x77, 0xbe, 0x3a, 0xec, 0x52, 0x8b, 0x82, 0x53, 0x68, 0x63, 0xb4,
-	0x9b, 0x4d, 0x1e, 0x6f, 0x83, 0x55, 0xfe, 0x25, 0x58, 0xb5, 0x0c, 0x16, 0x7c, 0x0d, 0x3d, 0xfc,
-	0xd1, 0x48, 0xe4, 0x8a, 0x65, 0x39, 0x2f, 0xc8, 0xe7, 0x50, 0xc7, 0xb5, 0x65, 0xcb, 0x7d, 0xb1,
-	0x57, 0x6e, 0x18, 0x51, 0x63, 0x0d, 0x7e, 0xaf, 0x40, 0x77, 0x96, 0xf1, 0x9f, 0x47, 0x73, 0x96,
-	0xa7, 0xfc, 0x7b, 0x99, 0x92, 0x6f, 0xa0, 0xf1, 0x18, 0xab, 0xcd, 0xca, 0x6c, 0xae, 0xde, 0xc9,
-	0x9b, 0xf2, 0xfa, 0xec, 0xba, 0xed, 0xa0, 0xdb, 0xcd, 0x8a, 0x53, 0xfb, 0x9b, 0xed, 0xb1, 0x95,
-	0xe7, 0x8e, 0xdd, 0xdf, 0xb2, 0xd5, 0xc3, 0x2d, 0x8b, 0x6f, 0xa2, 0x7e, 0x6c, 0xf5, 0xe2, 0xb2,
-	0xaf, 0xd9, 0x0e, 0xa3, 0x07, 0x30, 0xe1, 0x71, 0x86, 0x37, 0xad, 0x8e, 0x4f, 0x6f, 0x89, 0x03,
-	0x0a, 0xbd, 0xfd, 0xd4, 0xc8, 0x6b, 0xf0, 0xa7, 0x57, 0xb3, 0xb3, 0xcb, 0xe9, 0xc5, 0xdd, 0x6c,
-	0x3a, 0xfe, 0xf1, 0x6e, 0x34, 0x39, 0xbb, 0xfa, 0x76, 0x7c, 0x77, 0xfb, 0x53, 0x38, 0xee, 0x1f,
-	0x91, 0x0e, 0x34, 0x43, 0x7a, 0x1d, 0x5e, 0xdf, 0x8c, 0xfb, 0x9e, 0x01, 0xe3, 0xd9, 0xf5, 0xed,
-	0xb8, 0x5f, 0x21, 0x2d, 0xa8, 0xe1, 0x57, 0x35, 0xf8, 0xe0, 0x41, 0x7b, 0xc4, 0xf2, 0x24, 0x4b,
-	0x98, 0xe2, 0xcf, 0xbc, 0x51, 0x2f, 0xa1, 0xae, 0x37, 0x8b, 0xb4, 0xcf, 0xb8, 0x01, 0x76, 0xe8,
-	0xf5, 0x8b, 0x58, 0x2d, 0x87, 0x5e, 0xbf, 0x84, 0x6f, 0xa1, 0x17, 0x17, 0x9c, 0xe9, 0x19, 0x9a,
-	0x98, 0x15, 0x60, 0xee, 0xf9, 0x01, 0x4b, 0xde, 0x41, 0x7f, 0xc1, 0xa4, 0xfa, 0x61, 0xa5, 0x4f,
-	0xb7, 0x9e, 0x75, 0xf4, 0xfc, 0x07, 0x1f, 0x9c, 0x43, 0xb7, 0x4c, 0xf4, 0x32, 0x93, 0x8a, 0x7c,
-	0x05, 0x10, 0x3b, 0x42, 0xe7, 0xab, 0x47, 0xf9, 0x7f, 0xae, 0x29, 0xa5, 0x2b, 0xdd, 0x71, 0x0a,
-	0x86, 0xd0, 0xb9, 0xe5, 0x52, 0x85, 0xf6, 0xbf, 0xa1, 0x8f, 0xa1, 0xb5, 0x94, 0xe9, 0x5d, 0x24,
-	0x92, 0x8d, 0x7d, 0xcb, 0x9a, 0x4b, 0x99, 0x9e, 0x8b, 0x64, 0x13, 0x35, 0x30, 0xcc, 0xe9, 0xdf,
-	0x01, 0x00, 0x00, 0xff, 0xff, 0xd0, 0x04, 0xb6, 0xc8, 0xc2, 0x09, 0x00, 0x00,
+	// 1312 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7d, 0x56, 0xdb, 0x6e, 0x23, 0x45,
+	0x10, 0x5d, 0xdf, 0xed, 0xf2, 0x65, 0x4d, 0xb3, 0x20, 0x83, 0x10, 0x5a, 0x46, 0x01, 0x45, 0x20,
+	0x22, 0xf0, 0x0a, 0x21, 0x24, 0x5e, 0x12, 0xaf, 0x45, 0x2c, 0x42, 0x62, 0x4d, 0xb2, 0x46, 0x3c,
+	0x45, 0xe3, 0x99, 0x8e, 0x3d, 0xc4, 0x9e, 0xb1, 0xa6, 0xdb, 0xde, 0xe4, 0x27, 0x78, 0x47, 0x48,
+	0x48, 0xfc, 0x05, 0x2f, 0x7c, 0x00, 0x3f, 0xc3, 0x0b, 0x3f, 0x40, 0x75, 0x75, 0xf7, 0x5c, 0xcc,
+	0x26, 0x4f, 0xf6, 0xa9, 0xaa, 0xe9, 0xae, 0x3e, 0x55, 0xa7, 0xba, 0xa1, 0x3f, 0x5f, 0xc5, 0xfe,
+	0xad, 0xbf, 0xf4, 0xc2, 0xe8, 0x68, 0x93, 0xc4, 0x32, 0x66, 0xf5, 0x90, 0x7e, 0x9d, 0x3f, 0x4b,
+	0x00, 0x57, 0x89, 0x17, 0x89, 0x1b, 0x9e, 0x4c, 0xe7, 0xec, 0x5d, 0xa8, 0x7b, 0xeb, 0x78, 0x1b,
+	0xc9, 0x41, 0xe9, 0x79, 0xe9, 0xb0, 0xe3, 0x1a, 0xa4, 0xec, 0x82, 0x47, 0x01, 0x4f, 0x06, 0x65,
+	0xb4, 0xb7, 0x5c, 0x83, 0xd8, 0x07, 0xd0, 0x4a, 0xb8, 0x1f, 0x6e, 0x42, 0x8e, 0x9f, 0x54, 0xc8,
+	0x95, 0x19, 0xd8, 0x00, 0x1a, 0x1b, 0xef, 0x7e, 0x15, 0x7b, 0xc1, 0xa0, 0x4a, 0xcb, 0x59, 0xc8,
+	0x1c, 0xe8, 0xe8, 0x15, 0xa6, 0xdb, 0xf9, 0xf7, 0xfc, 0x7e, 0x50, 0x23, 0x77, 0xc1, 0xc6, 0x3e,
+	0x04, 0x08, 0xc5, 0x28, 0x0e, 0xa3, 0xb9, 0x27, 0xf8, 0xa0, 0x8e, 0x11, 0x4d, 0x37, 0x67, 0x71,
+	0x7e, 0x29, 0x41, 0x7d, 0x16, 0x4b, 0x8e, 0x69, 0x63, 0x1a, 0x32, 0x5c, 0x73, 0x21, 0xbd, 0xf5,
+	0x86, 0x32, 0xaf, 0xba, 0x99, 0x41, 0x2d, 0x24, 0xf8, 0xea, 0x06, 0x97, 0xbd, 0xc5, 0xad, 0xca,
+	0xb4, 0x55, 0xce, 0xa2, 0x92, 0xd9, 0xe1, 0x3a, 0xc9, 0x71, 0x10, 0x24, 0x5c, 0x08, 0x73, 0x8e,
+	0x82, 0xcd, 0xc6, 0x70, 0x1b, 0x53, 0xcd, 0x62, 0xac, 0xcd, 0xf9, 0xb5, 0x04, 0xed, 0xf1, 0x1d,
+	0xf7, 0xb7, 0x32, 0x8c, 0xa3, 0x47, 0xc8, 0x7c, 0x1f, 0x9a, 0x9c, 0xc2, 0x62, 0x4b, 0x67, 0x8a,
+	0x95, 0xcf, 0x8f, 0x23, 0x99, 0x78, 0xbe, 0xe5, 0x33, 0xc5, 0xec, 0x13, 0xe8, 0xd9, 0x38, 0x43,
+	0x9b, 0x66, 0x75, 0xcf, 0xca, 0x18, 0x54, 0x03, 0x4f, 0x7a, 0x86, 0x54, 0xfa, 0xef, 0xfc, 0x55,
+	0x82, 0xda, 0x59, 0xbc, 0xc0, 0xac, 0xb0, 0x28, 0x9e, 0x39, 0x44, 0x89, 0x36, 0xb0, 0x50, 0xe5,
+	0x2b, 0xe3, 0x4d, 0xe8, 0x0b, 0xcc, 0xaa, 0xa2, 0xf2, 0xd5, 0x28, 0x5d, 0xaf, 0x92, 0xad, 0xc7,
+	0x9e, 0x43, 0x9b, 0x7a, 0xea, 0x7c, 0xbb, 0x9e, 0x63, 0x57, 0x54, 0x89, 0xf3, 0xbc, 0x49, 0xed,
+	0x23, 0xef, 0xa2, 0x53, 0x4f, 0x2c, 0x4d, 0x22, 0x16, 0xaa, 0x6a, 0x51, 0x20, 0xf9, 0xea, 0xe4,
+	0xcb, 0x0c, 0xec, 0x19, 0xd4, 0x42, 0xec, 0x82, 0xbb, 0x41, 0x03, 0x3d, 0x5d, 0x57, 0x03, 0xe7,
+	0xf7, 0x32, 0xb4, 0x5c, 0xee, 0xf3, 0x70, 0x23, 0xf1, 0x0c, 0xb8, 0x7b, 0xc2, 0xe5, 0x36, 0x89,
+	0x66, 0xde, 0x6a, 0xcb, 0x0d, 0xbd, 0x79, 0x13, 0x35, 0xac, 0xf4, 0xe4, 0x56, 0x10, 0xc3, 0x55,
+	0xd7, 0x20, 0x75, 0x96, 0xa5, 0xda, 0xd6, 0x9c, 0x45, 0xfd, 0x57, 0xab, 0x2d, 0x3c, 0xec, 0xab,
+	0x48, 0x6c, 0xd7, 0x3c, 0xb0, 0x67, 0xc9, 0x99, 0xd8, 0x21, 0x3c, 0xb5, 0x55, 0xb0, 0x0d, 0x50,
+	0x23, 0xee, 0xf6, 0xcd, 0xec, 0x23, 0xa8, 0xae, 0xe2, 0x85, 0xc0, 0x63, 0x55, 0x0e, 0xdb, 0xc3,
+	0xee, 0x91, 0x96, 0xd9, 0x11, 0x51, 0xef, 0x92, 0x2b, 0xa5, 0xee, 0x94, 0x87, 0x8b, 0xa5, 0xa4,
+	0x63, 0x5a, 0xea, 0xb4, 0xa9, 0x48, 0x50, 0xf3, 0x41, 0x82, 0x5a, 0x79, 0x82, 0xfe, 0x2d, 0x43,
+	0xf3, 0xd8, 0x37, 0x9d, 0x87, 0xdc, 0xef, 0x78, 0x22, 0x10, 0x10, 0x37, 0x5d, 0xd7, 0x42, 0xf5,
+	0x71, 0x14, 0x47, 0x3e, 0x37, 0xb4, 0x68, 0xa0, 0xba, 0x0e, 0x8f, 0x7b, 0x16, 0xae, 0x43, 0xdd,
+	0x75, 0x55, 0x37, 0xc5, 0xc6, 0x37, 0x4d, 0x42, 0xfc, 0x48, 0xf7, 0x5b, 0x8a, 0x55, 0xa2, 0x22,
+	0x5c, 0x44, 0xc8, 0x6c, 0xc2, 0x4d, 0x95, 0x33, 0x03, 0xfb, 0x02, 0x9a, 0xd2, 0x8c, 0x96, 0x01,
+	0xa0, 0xb3, 0x3d, 0x64, 0x96, 0x8f, 0x6c, 0xe4, 0x9c, 0x3e, 0x71, 0xd3, 0x28, 0x76, 0x00, 0x55,
+	0xa5, 0xa8, 0x41, 0x9b, 0xa2, 0x7b, 0x36, 0x5a, 0xab, 0x1c, 0x23, 0xc9, 0xcb, 0x5e, 0x40, 0x8b,
+	0x5b, 0x99, 0x0d, 0x3a, 0x14, 0xfa, 0xb6, 0x0d, 0xcd, 0xe9, 0x0f, 0xe3, 0xb3, 0x38, 0x45, 0x09,
+	0xcd, 0xbf, 0xc9, 0x4b, 0x6a, 0x39, 0xa4, 0xc4, 0x40, 0x95, 0x26, 0xdf, 0x85, 0x01, 0x57, 0xac,
+	0x74, 0x8b, 0x69, 0x8e, 0x8d, 0x5d, 0xa7, 0x69, 0xa3, 0x4e, 0x9a, 0x28, 0x6c, 0xa2, 0xda, 0xf9,
+	0xa3, 0x02, 0xdd, 0x13, 0x5d, 0x39, 0x2f, 0xa0, 0x09, 0xfa, 0x30, 0xf5, 0xb9, 0x0c, 0xca, 0xc5,
+	0x0c, 0xb0, 0x59, 0x97, 0xba, 0x19, 0x34, 0xf9, 0x06, 0x15, 0xc7, 0x5a, 0x75, 0x7f, 0xac, 0x1d,
+	0x40, 0x77, 0x93, 0xf0, 0xdd, 0x49, 0xda, 0x29, 0xba, 0x00, 0x45, 0x23, 0x89, 0xfa, 0xce, 0x8d,
+	0x63, 0x69, 0x94, 0x66, 0x10, 0x95, 0x0e, 0x25, 0xc1, 0xc9, 0xd5, 0x30, 0xa5, 0xb3, 0x06, 0x2d,
+	0x30, 0x52, 0x1b, 0xf9, 0x9b, 0x56, 0x60, 0xa9, 0x49, 0xb5, 0x05, 0x36, 0x3c, 0x4f, 0x76, 0xa8,
+	0x98, 0x96, 0x6e, 0x0b, 0x8b, 0x8b, 0x6d, 0x01, 0xfb, 0x6d, 0x81, 0x19, 0x6d, 0xf4, 0x28, 0x6e,
+	0xeb, 0x8c, 0x34, 0x52, 0xad, 0x19, 0xdc, 0x2e, 0x90, 0x9d, 0x0e, 0x99, 0x35, 0x50, 0x6b, 0xe1,
+	0x1f, 0x33, 0xbb, 0xbb, 0x7a, 0xad, 0xd4, 0xa0, 0xc6, 0x32, 0x82, 0xcb, 0x74, 0xb3, 0x9e, 0xbe,
+	0x47, 0xf2, 0x36, 0xe7, 0xb7, 0x12, 0x34, 0x88, 0x0f, 0xac, 0xce, 0xe7, 0x8a, 0x69, 0x55, 0x29,
+	0x2a, 0x4e, 0x7b, 0xf8, 0x8e, 0xad, 0x74, 0xa1, 0x88, 0xae, 0x09, 0x62, 0x9f, 0xe2, 0xac, 0xa4,
+	0x42, 0xeb, 0x91, 0xd8, 0x1e, 0xf6, 0x6d, 0xbc, 0x95, 0x9a, 0x6b, 0x03, 0xd8, 0x57, 0xd0, 0xf6,
+	0xe3, 0x35, 0x2a, 0xe6, 0xda, 0xe7, 0x89, 0xae, 0x64, 0x7b, 0xf8, 0xcc, 0xc6, 0x8f, 0xc8, 0x35,
+	0x42, 0x0f, 0x7e, 0x03, 0x7e, 0x8a, 0x9c, 0x33, 0x00, 0xda, 0x7b, 0xa2, 0x54, 0xac, 0x38, 0xc0,
+	0x22, 0x24, 0xd2, 0x5c, 0x62, 0x1a, 0xb0, 0x3e, 0x54, 0xf0, 0x62, 0x34, 0x92, 0x55, 0x7f, 0x15,
+	0x87, 0xf1, 0xcd, 0x8d, 0xe0, 0x6a, 0x9f, 0x0a, 0xb6, 0x92, 0x41, 0x0e, 0x4a, 0x83, 0x56, 0xbb,
+	0xbc, 0x8f, 0xfc, 0x6c, 0xb1, 0xf2, 0x1b, 0x16, 0xab, 0xa4, 0x8b, 0x39, 0x5f, 0x43, 0x8f, 0x3e,
+	0xc2, 0x71, 0x27, 0xb1, 0x21, 0xf1, 0xdc, 0x1f, 0x43, 0x8d, 0xe6, 0x8d, 0x61, 0xe9, 0x69, 0x81,
+	0x25, 0x3c, 0x80, 0xf6, 0x3a, 0xff, 0x94, 0xa1, 0x3b, 0x0b, 0xf9, 0xeb, 0xd1, 0xd2, 0x8b, 0x16,
+	0xfc, 0x07, 0xb1, 0x60, 0xdf, 0x42, 0x7d, 0xe7, 0xcb, 0xfb, 0x8d, 0x9e, 0xc9, 0xbd, 0xe1, 0x41,
+	0x2a, 0xe1, 0x7c, 0x58, 0x0e, 0x5d, 0x61, 0xac, 0x6b, 0xbe, 0xc9, 0xb6, 0x2d, 0x3f, 0xb6, 0x6d,
+	0x71, 0x3c, 0x56, 0xf6, 0xc7, 0x23, 0xdd, 0xf6, 0xea, 0x19, 0xa1, 0x46, 0xb2, 0xb9, 0xa7, 0x73,
+	0x16, 0xd5, 0xb8, 0x01, 0xbe, 0x50, 0x48, 0xa1, 0x35, 0x7a, 0x54, 0xa4, 0x38, 0x27, 0xc4, 0x7a,
+	0x41, 0x88, 0xc8, 0x64, 0x82, 0x57, 0x77, 0x60, 0xef, 0x24, 0x02, 0xc5, 0x36, 0x6f, 0xee, 0xb5,
+	0xb9, 0xe3, 0x42, 0xaf, 0x78, 0x4c, 0x8c, 0x1f, 0x4c, 0xce, 0x67, 0xc7, 0x67, 0x93, 0x97, 0xd7,
+	0xb3, 0xc9, 0xf8, 0xc7, 0xeb, 0xd1, 0xe9, 0xf1, 0xf9, 0x77, 0xe3, 0xeb, 0xab, 0x9f, 0xa6, 0xe3,
+	0xfe, 0x13, 0xd6, 0x86, 0xc6, 0xd4, 0xbd, 0x98, 0x5e, 0x5c, 0x8e, 0xfb, 0x25, 0x0d, 0xc6, 0xb3,
+	0x8b, 0xab, 0x71, 0xbf, 0xcc, 0x9a, 0x50, 0xa5, 0x7f, 0x15, 0xe7, 0xef, 0x12, 0xb4, 0x46, 0x5e,
+	0x14, 0x84, 0x78, 0x07, 0xf3, 0x47, 0x6e, 0x72, 0xcc, 0x57, 0x4d, 0x4a, 0x61, 0x1e, 0x3b, 0x1a,
+	0x18, 0xe1, 0xa9, 0x77, 0x43, 0x25, 0x15, 0x9e, 0x7a, 0x2f, 0xe0, 0xbb, 0xc2, 0x4f, 0xb8, 0xa7,
+	0xda, 0xd8, 0xdc, 0x49, 0x7a, 0xd6, 0xec, 0x59, 0x51, 0x0d, 0xfd, 0x95, 0x27, 0xe4, 0xab, 0x8d,
+	0xda, 0xdd, 0x44, 0xd6, 0x28, 0xf2, 0x7f, 0x76, 0x35, 0x40, 0x7e, 0xf6, 0xc2, 0x15, 0x0f, 0x5e,
+	0x45, 0x32, 0x5c, 0x19, 0x3a, 0xf3, 0x26, 0xe7, 0x04, 0xba, 0xe9, 0x51, 0xce, 0x42, 0x21, 0xd9,
+	0x97, 0x00, 0xbe, 0x35, 0xa8, 0x13, 0x29, 0xbd, 0xbd, 0x95, 0xea, 0xc7, 0x7a, 0xdc, 0x5c, 0x90,
+	0x33, 0x81, 0xee, 0x38, 0x0a, 0xe2, 0x44, 0xf0, 0x35, 0xbe, 0x37, 0x51, 0xdf, 0xea, 0x69, 0xa5,
+	0x0d, 0x89, 0xe1, 0x24, 0xc5, 0xc5, 0x72, 0x95, 0xf7, 0xcb, 0xf5, 0x1a, 0x3a, 0x79, 0x8d, 0xe6,
+	0x5a, 0xa1, 0xf4, 0xe6, 0x56, 0x28, 0xe7, 0x5b, 0xe1, 0x1b, 0xe8, 0xf0, 0x2c, 0x11, 0x41, 0xaa,
+	0xcc, 0x4d, 0x97, 0x42, 0x92, 0x6e, 0x21, 0xd4, 0x59, 0x02, 0x64, 0xd7, 0x0c, 0xfb, 0x0c, 0x6a,
+	0x37, 0x61, 0x22, 0xe4, 0xfe, 0x7c, 0x2a, 0xe8, 0xc7, 0xd5, 0x31, 0x6a, 0x9a, 0x09, 0x8e, 0x2f,
+	0x90, 0xc0, 0x08, 0xe6, 0x81, 0x68, 0x13, 0xe4, 0x1c, 0x42, 0xfb, 0x0a, 0xef, 0x8e, 0xa9, 0x79,
+	0x83, 0xbf, 0x07, 0xcd, 0xb5, 0x58, 0x5c, 0xcf, 0xe3, 0xe0, 0xde, 0xbc, 0xa0, 0x1a, 0x88, 0x4f,
+	0x10, 0xce, 0xeb, 0xb4, 0xcc, 0x8b, 0xff, 0x00, 0x34, 0x97, 0xb1, 0x33, 0x38, 0x0c, 0x00, 0x00,
 }
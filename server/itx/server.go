@@ -17,6 +17,7 @@ import (
 	"github.com/iotexproject/iotex-core/consensus"
 	"github.com/iotexproject/iotex-core/dispatch"
 	"github.com/iotexproject/iotex-core/dispatch/dispatcher"
+	"github.com/iotexproject/iotex-core/ethrpc"
 	"github.com/iotexproject/iotex-core/explorer"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/network"
@@ -33,6 +34,7 @@ type Server struct {
 	blocksync  blocksync.BlockSync
 	dispatcher dispatcher.Dispatcher
 	explorer   *explorer.Server
+	ethRPC     *ethrpc.Server
 }
 
 // NewServer creates a new server
@@ -81,11 +83,21 @@ func (s *Server) Start(ctx context.Context) error {
 	if err := s.explorer.Start(ctx); err != nil {
 		return errors.Wrap(err, "error when starting explorer")
 	}
+	if s.ethRPC != nil {
+		if err := s.ethRPC.Start(ctx); err != nil {
+			return errors.Wrap(err, "error when starting eth JSON-RPC gateway")
+		}
+	}
 	return nil
 }
 
 // Stop stops the server
 func (s *Server) Stop(ctx context.Context) error {
+	if s.ethRPC != nil {
+		if err := s.ethRPC.Stop(ctx); err != nil {
+			return errors.Wrap(err, "error when stopping eth JSON-RPC gateway")
+		}
+	}
 	if err := s.explorer.Stop(ctx); err != nil {
 		return errors.Wrap(err, "error when stopping explorer")
 	}
@@ -142,9 +154,24 @@ func (s *Server) Explorer() *explorer.Server {
 	return s.explorer
 }
 
+// EthRPC returns the Ethereum-compatible JSON-RPC gateway, which is nil if it is not enabled
+func (s *Server) EthRPC() *ethrpc.Server {
+	return s.ethRPC
+}
+
 func newServer(cfg *config.Config, chain blockchain.Blockchain) *Server {
+	if chain == nil {
+		logger.Fatal().Msg("Fail to create blockchain")
+	}
+	genesisHash, err := chain.Genesis().Hash()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Fail to hash genesis")
+	}
+	// the consensus parameters of the genesis override the ones in the config
+	chain.Genesis().OverrideConsensus(&cfg.Consensus)
 	// create P2P network and BlockSync
 	p2p := network.NewOverlay(&cfg.Network)
+	p2p.GenesisHash = genesisHash[:]
 	// Create ActPool
 	actPool, err := actpool.NewActPool(chain, cfg.ActPool)
 	if err != nil {
@@ -171,6 +198,10 @@ func newServer(cfg *config.Config, chain blockchain.Blockchain) *Server {
 	} else {
 		exp = explorer.NewServer(cfg.Explorer, chain, consensus, dispatcher, actPool, p2p)
 	}
+	var ethRPC *ethrpc.Server
+	if cfg.EthRPC.Enabled {
+		ethRPC = ethrpc.NewServer(cfg.EthRPC, chain, actPool, p2p)
+	}
 
 	return &Server{
 		cfg:        cfg,
@@ -181,5 +212,6 @@ func newServer(cfg *config.Config, chain blockchain.Blockchain) *Server {
 		blocksync:  bs,
 		dispatcher: dispatcher,
 		explorer:   exp,
+		ethRPC:     ethRPC,
 	}
 }
@@ -36,6 +36,9 @@ type Candidate struct {
 	PubKey           []byte
 	CreationHeight   uint64
 	LastUpdateHeight uint64
+	// JailedUntil is the height until which the candidate is jailed for its equivocation, exclusive. A jailed
+	// candidate is not returned as a candidate, so that it can't be chosen as a delegate
+	JailedUntil uint64
 }
 
 // CandidateList indicates the list of candidates which is sortable
@@ -58,6 +61,7 @@ func candidateToPb(cand *Candidate) (*iproto.Candidate, error) {
 		PubKey:           cand.PubKey,
 		CreationHeight:   cand.CreationHeight,
 		LastUpdateHeight: cand.LastUpdateHeight,
+		JailedUntil:      cand.JailedUntil,
 	}
 	if cand.Votes != nil && len(cand.Votes.Bytes()) > 0 {
 		candidatePb.Votes = cand.Votes.Bytes()
@@ -76,6 +80,7 @@ func pbToCandidate(candPb *iproto.Candidate) (*Candidate, error) {
 		PubKey:           candPb.PubKey,
 		CreationHeight:   candPb.CreationHeight,
 		LastUpdateHeight: candPb.LastUpdateHeight,
+		JailedUntil:      candPb.JailedUntil,
 	}
 	return candidate, nil
 }
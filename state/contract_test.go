@@ -55,7 +55,7 @@ func TestCreateContract(t *testing.T) {
 	v, err = sf.GetCode(addr1)
 	require.Error(err)
 	require.Equal([]byte(nil), v)
-	require.Nil(sf.CommitStateChanges(0, nil, nil, nil))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
 	// reload same contract
 	contract1, err := sf.LoadOrCreateState(addr.RawAddress, 0)
 	require.Nil(err)
@@ -132,7 +132,7 @@ func TestLoadStoreContract(t *testing.T) {
 	require.Nil(sf.SetContractState(contract1, k3, v3))
 	require.Nil(sf.SetContractState(contract1, k4, v4))
 
-	require.Nil(sf.CommitStateChanges(0, nil, nil, nil))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
 	root := sf.RootHash()
 	require.Nil(sf.Stop(context.Background()))
 
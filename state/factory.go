@@ -8,6 +8,7 @@ package state
 
 import (
 	"context"
+	"io"
 	"math/big"
 	"sort"
 
@@ -20,7 +21,9 @@ import (
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/keypair"
 	"github.com/iotexproject/iotex-core/pkg/lifecycle"
+	"github.com/iotexproject/iotex-core/pkg/routine"
 	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 	"github.com/iotexproject/iotex-core/trie"
 )
@@ -40,6 +43,9 @@ var (
 
 	// ErrFailedToUnmarshalState is the error that the state un-marshaling is failed
 	ErrFailedToUnmarshalState = errors.New("failed to unmarshal state")
+
+	// ErrReadOnlyView is the error that the state changes are committed to a read-only view of the factory
+	ErrReadOnlyView = errors.New("cannot commit to read-only view")
 )
 
 const (
@@ -60,17 +66,27 @@ type (
 		State(string) (*State, error)
 		CachedState(string) (*State, error)
 		RootHash() hash.Hash32B
+		RootHashByHeight(uint64) (hash.Hash32B, error)
 		Height() (uint64, error)
-		CommitStateChanges(uint64, []*action.Transfer, []*action.Vote, []*action.Execution) error
+		ViewAtHeight(uint64) (Factory, error)
+		Fork(uint64) (Factory, error)
+		CommitStateChanges(uint64, []*action.Transfer, []*action.Vote, []*action.Execution, []*action.Evidence) error
 		// Contracts
 		GetCodeHash(hash.AddrHash) (hash.Hash32B, error)
 		GetCode(hash.AddrHash) ([]byte, error)
 		SetCode(hash.AddrHash, []byte) error
 		GetContractState(hash.AddrHash, hash.Hash32B) (hash.Hash32B, error)
 		SetContractState(hash.AddrHash, hash.Hash32B, hash.Hash32B) error
+		DeleteContract(hash.AddrHash) error
+		// Proofs
+		AccountProof(hash.AddrHash) ([][]byte, error)
+		StorageProof(hash.AddrHash, hash.Hash32B) ([][]byte, error)
 		// Candidate pool
 		Candidates() (uint64, []*Candidate)
 		CandidatesByHeight(uint64) ([]*Candidate, error)
+		// Snapshots
+		Snapshot(uint64, io.Writer) error
+		LoadSnapshot(io.Reader, uint64, hash.Hash32B) error
 	}
 
 	// factory implements StateFactory interface, tracks changes to account/contract and batch-commits to DB
@@ -80,11 +96,19 @@ type (
 		currentChainHeight uint64
 		numCandidates      uint
 		cachedCandidates   map[hash.AddrHash]*Candidate
+		// upgrades are the heights the protocol upgrades are activated on
+		upgrades config.Upgrades
+		// slashing is the penalty of the offenders of the evidences, who are jailed for the epochs of epochHeights
+		slashing     config.Slashing
+		epochHeights uint64
 		// accounts
 		cachedAccount  map[hash.AddrHash]*State   // accounts being modified in this block
 		cachedContract map[hash.AddrHash]Contract // contracts being modified in this block
 		accountTrie    trie.Trie                  // global state trie
 		dao            db.CachedKVStore           // the underlying DB for account/contract storage
+		readOnly       bool                       // true if the factory is a read-only view at a past height
+		scratch        bool                       // true if the factory is a scratch copy never written into DB
+		pruner         *pruner                    // prunes the states of old heights, nil in archive mode
 	}
 )
 
@@ -155,6 +179,9 @@ func NewFactory(cfg *config.Config, opts ...FactoryOption) (Factory, error) {
 	sf := &factory{
 		currentChainHeight: 0,
 		numCandidates:      cfg.Chain.NumCandidates,
+		upgrades:           cfg.Chain.Upgrades,
+		slashing:           cfg.Chain.Slashing,
+		epochHeights:       epochHeights(&cfg.Consensus.RollDPoS),
 		cachedCandidates:   make(map[hash.AddrHash]*Candidate),
 		cachedAccount:      make(map[hash.AddrHash]*State),
 		cachedContract:     make(map[hash.AddrHash]Contract),
@@ -169,6 +196,10 @@ func NewFactory(cfg *config.Config, opts ...FactoryOption) (Factory, error) {
 	if sf.accountTrie != nil {
 		sf.lifecycle.Add(sf.accountTrie)
 	}
+	if cfg.Chain.PruningMode == config.PruneMode && sf.dao != nil {
+		sf.pruner = newPruner(sf.dao.KVStore(), cfg.Chain.NumRetainedHeights)
+		sf.lifecycle.Add(routine.NewRecurringTask(sf.pruner.Run, cfg.Chain.PruningInterval))
+	}
 	return sf, nil
 }
 
@@ -256,8 +287,20 @@ func (sf *factory) RootHash() hash.Hash32B {
 	return sf.accountTrie.RootHash()
 }
 
+// RootHashByHeight returns the hash of the root node of the accountTrie at a given height
+func (sf *factory) RootHashByHeight(height uint64) (hash.Hash32B, error) {
+	root, err := sf.dao.Get(trie.AccountRootKVNameSpace, byteutil.Uint64ToBytes(height))
+	if err != nil {
+		return hash.ZeroHash32B, errors.Wrapf(err, "failed to get accountTrie's root hash on height %d", height)
+	}
+	return byteutil.BytesTo32B(root), nil
+}
+
 // Height returns factory's height
 func (sf *factory) Height() (uint64, error) {
+	if sf.readOnly {
+		return sf.currentChainHeight, nil
+	}
 	height, err := sf.dao.Get(trie.AccountKVNameSpace, []byte(CurrentHeightKey))
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to get factory's height from underlying DB")
@@ -265,8 +308,39 @@ func (sf *factory) Height() (uint64, error) {
 	return byteutil.BytesToUint64(height), nil
 }
 
+// ViewAtHeight returns a read-only view of the states at a given height. The view opens the accountTrie at the root
+// hash of that height, and keeps the changes made to it in its own cache, which is never written into DB
+func (sf *factory) ViewAtHeight(height uint64) (Factory, error) {
+	view, err := sf.copyAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	view.readOnly = true
+	return view, nil
+}
+
+// Fork returns a scratch copy of the states at a given height. Unlike a read-only view, state changes can be committed
+// to the copy to get the resulting root hash, but they are only kept in its own cache and never written into DB
+func (sf *factory) Fork(height uint64) (Factory, error) {
+	fork, err := sf.copyAtHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	fork.scratch = true
+	return fork, nil
+}
+
 // CommitStateChanges updates a State from the given actions
-func (sf *factory) CommitStateChanges(blockHeight uint64, tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution) error {
+func (sf *factory) CommitStateChanges(
+	blockHeight uint64,
+	tsf []*action.Transfer,
+	vote []*action.Vote,
+	executions []*action.Execution,
+	evidences []*action.Evidence,
+) error {
+	if sf.readOnly {
+		return errors.Wrapf(ErrReadOnlyView, "view on height %d", sf.currentChainHeight)
+	}
 	// Recover cachedCandidates after restart factory
 	if blockHeight > 0 && len(sf.cachedCandidates) == 0 {
 		candidates, err := sf.getCandidates(blockHeight - 1)
@@ -279,12 +353,23 @@ func (sf *factory) CommitStateChanges(blockHeight uint64, tsf []*action.Transfer
 	}
 
 	defer sf.clearCache()
-	if err := sf.handleTsf(tsf); err != nil {
+	// gas fees are credited to the block producer, who is the recipient of the coinbase transfer
+	producer := ""
+	for _, tx := range tsf {
+		if tx.IsCoinbase {
+			producer = tx.Recipient
+			break
+		}
+	}
+	if err := sf.handleTsf(blockHeight, producer, tsf); err != nil {
 		return errors.Wrap(err, "failed to handle transfers")
 	}
-	if err := sf.handleVote(blockHeight, vote); err != nil {
+	if err := sf.handleVote(blockHeight, producer, vote); err != nil {
 		return errors.Wrap(err, "failed to handle votes")
 	}
+	if err := sf.handleEvidence(blockHeight, evidences); err != nil {
+		return errors.Wrap(err, "failed to handle evidences")
+	}
 
 	// update pending state changes to trie
 	for addr, state := range sf.cachedAccount {
@@ -305,7 +390,7 @@ func (sf *factory) CommitStateChanges(blockHeight uint64, tsf []*action.Transfer
 		if addr == byteutil.BytesTo20B(voteeAddr) {
 			totalWeight.Add(totalWeight, state.Balance)
 		}
-		sf.updateCandidate(addr, totalWeight, blockHeight)
+		sf.updateCandidate(addr, totalWeight, state.JailedUntil, blockHeight)
 	}
 	// update pending contract changes
 	for addr, contract := range sf.cachedContract {
@@ -332,11 +417,20 @@ func (sf *factory) CommitStateChanges(blockHeight uint64, tsf []*action.Transfer
 			return errors.Wrap(err, "failed to update pending state changes to trie")
 		}
 	}
+	if blockHeight == sf.upgrades.IndexedTrieHeight && !sf.accountTrie.Indexed() {
+		if err := sf.reindex(); err != nil {
+			return errors.Wrapf(err, "failed to rehash the states on height %d", blockHeight)
+		}
+	}
 	// Persist accountTrie's root hash
 	accountRootHash := sf.RootHash()
 	if err := sf.dao.Put(trie.AccountKVNameSpace, []byte(AccountTrieRootKey), accountRootHash[:]); err != nil {
 		return errors.Wrap(err, "failed to store accountTrie's root hash")
 	}
+	// Persist accountTrie's root hash on this height, so the states can be read at this height later
+	if err := sf.dao.Put(trie.AccountRootKVNameSpace, byteutil.Uint64ToBytes(blockHeight), accountRootHash[:]); err != nil {
+		return errors.Wrapf(err, "failed to store accountTrie's root hash on height %d", blockHeight)
+	}
 	// Persist new list of candidates
 	candidates, err := MapToCandidates(sf.cachedCandidates)
 	if err != nil {
@@ -355,6 +449,15 @@ func (sf *factory) CommitStateChanges(blockHeight uint64, tsf []*action.Transfer
 	if err := sf.dao.Put(trie.AccountKVNameSpace, []byte(CurrentHeightKey), byteutil.Uint64ToBytes(blockHeight)); err != nil {
 		return errors.Wrap(err, "failed to store accountTrie's current height")
 	}
+	if sf.scratch {
+		// the changes of a scratch copy are discarded along with its cache
+		return nil
+	}
+	if sf.pruner != nil {
+		// the pruner must not sweep while new nodes are being written to DB
+		sf.pruner.mutex.Lock()
+		defer sf.pruner.mutex.Unlock()
+	}
 	// commit all changes in a batch
 	if err := sf.accountTrie.Commit(); err != nil {
 		return errors.Wrap(err, "failed to commit all changes to underlying DB in a batch")
@@ -429,12 +532,70 @@ func (sf *factory) SetContractState(addr hash.AddrHash, key, value hash.Hash32B)
 	return contract.SetState(key, value[:])
 }
 
+// DeleteContract removes contract's code hash and storage root, the account trie is updated on CommitStateChanges
+func (sf *factory) DeleteContract(addr hash.AddrHash) error {
+	var state *State
+	if contract, ok := sf.cachedContract[addr]; ok {
+		// drop the pending storage changes of the contract
+		state = contract.SelfState()
+		delete(sf.cachedContract, addr)
+	} else {
+		s, err := sf.cachedState(addr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to DeleteContract for contract %x", addr)
+		}
+		state = s
+	}
+	state.CodeHash = nil
+	state.Root = hash.ZeroHash32B
+	sf.cachedAccount[addr] = state
+	return nil
+}
+
+//======================================
+// Proof functions
+//======================================
+// AccountProof returns the Merkle proof of an account in the accountTrie, which can be verified against RootHash()
+func (sf *factory) AccountProof(addr hash.AddrHash) ([][]byte, error) {
+	proof, err := sf.accountTrie.Proof(addr[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get proof of account %x", addr)
+	}
+	return proof, nil
+}
+
+// StorageProof returns the Merkle proof of a contract's storage slot, which can be verified against the storage
+// trie root in the contract's confirmed state
+func (sf *factory) StorageProof(addr hash.AddrHash, key hash.Hash32B) ([][]byte, error) {
+	state, err := sf.getState(addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get state of contract %x", addr)
+	}
+	if state.Root == hash.ZeroHash32B {
+		// the account has no storage, VerifyStorageProof() proves the exclusion with an empty proof
+		return [][]byte{}, nil
+	}
+	tr, err := trie.NewTrieSharedDB(sf.dao, trie.ContractKVNameSpace, state.Root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create storage trie of contract %x", addr)
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, errors.Wrapf(err, "failed to load storage trie of contract %x", addr)
+	}
+	proof, err := tr.Proof(key[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get proof of storage %x in contract %x", key, addr)
+	}
+	return proof, nil
+}
+
 //======================================
 // Candidate functions
 //======================================
-// Candidates returns array of candidates in candidate pool
+// Candidates returns array of candidates in candidate pool, except the jailed ones
 func (sf *factory) Candidates() (uint64, []*Candidate) {
 	candidates, _ := MapToCandidates(sf.cachedCandidates)
+	candidates = unjailedCandidates(candidates, sf.currentChainHeight)
 	if len(candidates) <= int(sf.numCandidates) {
 		return sf.currentChainHeight, candidates
 	}
@@ -442,13 +603,14 @@ func (sf *factory) Candidates() (uint64, []*Candidate) {
 	return sf.currentChainHeight, candidates[:sf.numCandidates]
 }
 
-// CandidatesByHeight returns array of candidates in candidate pool of a given height
+// CandidatesByHeight returns array of candidates in candidate pool of a given height, except the ones jailed on it
 func (sf *factory) CandidatesByHeight(height uint64) ([]*Candidate, error) {
 	// Load candidates on the given height from underlying db
 	candidates, err := sf.getCandidates(height)
 	if err != nil {
 		return []*Candidate{}, errors.Wrapf(err, "failed to get candidates on height %d", height)
 	}
+	candidates = unjailedCandidates(candidates, height)
 	if len(candidates) > int(sf.numCandidates) {
 		candidates = candidates[:sf.numCandidates]
 	}
@@ -501,7 +663,12 @@ func (sf *factory) getContract(addr hash.AddrHash) (Contract, error) {
 	if state.Root == hash.ZeroHash32B {
 		state.Root = trie.EmptyRoot
 	}
-	tr, err := trie.NewTrieSharedDB(sf.dao, trie.ContractKVNameSpace, state.Root)
+	var opts []trie.Option
+	if sf.accountTrie.Indexed() {
+		// the storage trie of a new contract is hashed by the same scheme as the accountTrie
+		opts = append(opts, trie.IndexedOption())
+	}
+	tr, err := trie.NewTrieSharedDB(sf.dao, trie.ContractKVNameSpace, state.Root, opts...)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create storage trie for new contract %x", addr)
 	}
@@ -511,6 +678,69 @@ func (sf *factory) getContract(addr hash.AddrHash) (Contract, error) {
 	return contract, nil
 }
 
+// copyAtHeight opens the accountTrie at the root hash of a given height on a new cache of the underlying DB
+func (sf *factory) copyAtHeight(height uint64) (*factory, error) {
+	root, err := sf.RootHashByHeight(height)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := sf.getCandidates(height)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get candidates on height %d", height)
+	}
+	cachedCandidates, err := CandidatesToMap(candidates)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert candidate list to map of cached candidates")
+	}
+	dao := db.NewCachedKVStore(sf.dao.KVStore())
+	tr, err := trie.NewTrieSharedDB(dao, trie.AccountKVNameSpace, root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create accountTrie on height %d", height)
+	}
+	// the copy is not added into lifecycle, since stopping the trie would stop the DB shared with the factory
+	if err := tr.Start(context.Background()); err != nil {
+		return nil, errors.Wrapf(err, "failed to load accountTrie on height %d", height)
+	}
+	return &factory{
+		currentChainHeight: height,
+		numCandidates:      sf.numCandidates,
+		upgrades:           sf.upgrades,
+		slashing:           sf.slashing,
+		epochHeights:       sf.epochHeights,
+		cachedCandidates:   cachedCandidates,
+		cachedAccount:      make(map[hash.AddrHash]*State),
+		cachedContract:     make(map[hash.AddrHash]Contract),
+		accountTrie:        tr,
+		dao:                dao,
+	}, nil
+}
+
+// reindex rehashes the accountTrie and the storage tries of all contracts, so the hashes commit to the index of each
+// child of a branch
+func (sf *factory) reindex() error {
+	return sf.accountTrie.Reindex(func(value []byte) ([]byte, error) {
+		state, err := bytesToState(value)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert bytes to state")
+		}
+		if state.Root == hash.ZeroHash32B || state.Root == trie.EmptyRoot {
+			return value, nil
+		}
+		tr, err := trie.NewTrieSharedDB(sf.dao, trie.ContractKVNameSpace, state.Root)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create storage trie on root %x", state.Root)
+		}
+		if err := tr.Start(context.Background()); err != nil {
+			return nil, errors.Wrapf(err, "failed to load storage trie on root %x", state.Root)
+		}
+		if err := tr.Reindex(nil); err != nil {
+			return nil, errors.Wrapf(err, "failed to rehash storage trie on root %x", state.Root)
+		}
+		state.Root = tr.RootHash()
+		return stateToBytes(state)
+	})
+}
+
 // clearCache removes all local changes after committing to trie
 func (sf *factory) clearCache() {
 	sf.cachedAccount = nil
@@ -522,10 +752,11 @@ func (sf *factory) clearCache() {
 //======================================
 // private candidate functions
 //======================================
-func (sf *factory) updateCandidate(pkHash hash.AddrHash, totalWeight *big.Int, blockHeight uint64) {
+func (sf *factory) updateCandidate(pkHash hash.AddrHash, totalWeight *big.Int, jailedUntil uint64, blockHeight uint64) {
 	// Candidate was added when self-nomination, always exist in cachedCandidates
 	candidate, _ := sf.cachedCandidates[pkHash]
 	candidate.Votes = totalWeight
+	candidate.JailedUntil = jailedUntil
 	candidate.LastUpdateHeight = blockHeight
 }
 
@@ -540,7 +771,7 @@ func (sf *factory) getCandidates(height uint64) (CandidateList, error) {
 //======================================
 // private transfer/vote functions
 //======================================
-func (sf *factory) handleTsf(tsf []*action.Transfer) error {
+func (sf *factory) handleTsf(blockHeight uint64, producer string, tsf []*action.Transfer) error {
 	for _, tx := range tsf {
 		if tx.IsContract() {
 			continue
@@ -551,9 +782,18 @@ func (sf *factory) handleTsf(tsf []*action.Transfer) error {
 			if err != nil {
 				return errors.Wrapf(err, "failed to load or create the state of sender %s", tx.Sender)
 			}
-			if tx.Amount.Cmp(sender.Balance) == 1 {
+			gas, err := tx.IntrinsicGas()
+			if err != nil {
+				return errors.Wrapf(err, "failed to get the intrinsic gas of transfer from %s", tx.Sender)
+			}
+			fee := sf.intrinsicGasFee(blockHeight, gas, tx.GasPrice)
+			if new(big.Int).Add(tx.Amount, fee).Cmp(sender.Balance) == 1 {
 				return errors.Wrapf(ErrNotEnoughBalance, "failed to verify the balance of sender %s", tx.Sender)
 			}
+			// charge gas fee
+			if err := sf.payGasFee(tx.Sender, sender, producer, fee); err != nil {
+				return errors.Wrapf(err, "failed to charge gas fee to sender %s", tx.Sender)
+			}
 			// update sender balance
 			if err := sender.SubBalance(tx.Amount); err != nil {
 				return errors.Wrapf(err, "failed to update the balance of sender %s", tx.Sender)
@@ -594,7 +834,7 @@ func (sf *factory) handleTsf(tsf []*action.Transfer) error {
 	return nil
 }
 
-func (sf *factory) handleVote(blockHeight uint64, vote []*action.Vote) error {
+func (sf *factory) handleVote(blockHeight uint64, producer string, vote []*action.Vote) error {
 	for _, v := range vote {
 		pbVote := v.GetVote()
 		voterAddress := pbVote.VoterAddress
@@ -602,6 +842,11 @@ func (sf *factory) handleVote(blockHeight uint64, vote []*action.Vote) error {
 		if err != nil {
 			return errors.Wrapf(err, "failed to load or create the state of voter %s", voterAddress)
 		}
+		// charge gas fee
+		fee := sf.intrinsicGasFee(blockHeight, v.IntrinsicGas(), new(big.Int).SetBytes(v.GasPrice))
+		if err := sf.payGasFee(voterAddress, voteFrom, producer, fee); err != nil {
+			return errors.Wrapf(err, "failed to charge gas fee to voter %s", voterAddress)
+		}
 
 		// update voteFrom nonce
 		if v.Nonce > voteFrom.Nonce {
@@ -655,6 +900,128 @@ func (sf *factory) handleVote(blockHeight uint64, vote []*action.Vote) error {
 	return nil
 }
 
+// handleEvidence slashes the offender of each evidence, and jails it for the configured number of epochs. An evidence
+// is rejected if its offender is not a candidate, its messages don't prove the equivocation, or the offender has been
+// jailed since the height of the equivocation, which prevents an offender from being slashed twice for it
+func (sf *factory) handleEvidence(blockHeight uint64, evidences []*action.Evidence) error {
+	for _, e := range evidences {
+		offender := e.Offender()
+		pkHash, err := iotxaddress.GetPubkeyHash(offender)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the hash of offender's address %s", offender)
+		}
+		candidate, ok := sf.cachedCandidates[byteutil.BytesTo20B(pkHash)]
+		if !ok {
+			return errors.Wrapf(action.ErrEvidence, "offender %s is not a candidate", offender)
+		}
+		pubKey, err := keypair.BytesToPublicKey(candidate.PubKey)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the public key of offender %s", offender)
+		}
+		if err := e.Verify(pubKey); err != nil {
+			return errors.Wrapf(err, "failed to verify the evidence against offender %s", offender)
+		}
+		state, err := sf.LoadOrCreateState(offender, 0)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load or create the state of offender %s", offender)
+		}
+		if e.Height() > blockHeight || e.Height() < state.JailedUntil {
+			return errors.Wrapf(
+				action.ErrEvidence,
+				"equivocation of offender %s at height %d, which is jailed until height %d",
+				offender,
+				e.Height(),
+				state.JailedUntil,
+			)
+		}
+		// the slashed balance is burnt
+		slashed := new(big.Int).Mul(state.Balance, new(big.Int).SetUint64(sf.slashing.RatePercent))
+		slashed.Div(slashed, big.NewInt(100))
+		if err := state.SubBalance(slashed); err != nil {
+			return errors.Wrapf(err, "failed to slash the balance of offender %s", offender)
+		}
+		if err := sf.updateVoteeWeight(offender, state, new(big.Int).Neg(slashed)); err != nil {
+			return err
+		}
+		state.JailedUntil = blockHeight + sf.slashing.JailEpochs*sf.epochHeights
+	}
+	return nil
+}
+
+// intrinsicGasFee returns the fee of the intrinsic gas of a transfer or vote, which is free before the gas fee upgrade
+func (sf *factory) intrinsicGasFee(blockHeight uint64, gas uint64, gasPrice *big.Int) *big.Int {
+	if blockHeight < sf.upgrades.GasFeeHeight {
+		return big.NewInt(0)
+	}
+	return gasFee(gas, gasPrice)
+}
+
+// payGasFee moves the gas fee from payer to the block producer, and updates the voting weights of their votees
+// The fee is burnt if there is no block producer
+func (sf *factory) payGasFee(payerAddr string, payer *State, producer string, fee *big.Int) error {
+	if fee.Sign() == 0 {
+		return nil
+	}
+	if err := payer.SubBalance(fee); err != nil {
+		return errors.Wrapf(err, "failed to update the balance of payer %s", payerAddr)
+	}
+	if err := sf.updateVoteeWeight(payerAddr, payer, new(big.Int).Neg(fee)); err != nil {
+		return err
+	}
+	if producer == "" {
+		return nil
+	}
+	beneficiary, err := sf.LoadOrCreateState(producer, 0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load or create the state of producer %s", producer)
+	}
+	if err := beneficiary.AddBalance(fee); err != nil {
+		return errors.Wrapf(err, "failed to update the balance of producer %s", producer)
+	}
+	return sf.updateVoteeWeight(producer, beneficiary, fee)
+}
+
+// updateVoteeWeight adds delta to the voting weight of the account's votee, if the account votes to someone else
+func (sf *factory) updateVoteeWeight(addr string, state *State, delta *big.Int) error {
+	if len(state.Votee) == 0 || state.Votee == addr {
+		return nil
+	}
+	votee, err := sf.LoadOrCreateState(state.Votee, 0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load or create the state of votee %s", state.Votee)
+	}
+	votee.VotingWeight.Add(votee.VotingWeight, delta)
+	return nil
+}
+
+// unjailedCandidates returns the candidates not jailed on the height, in the same order
+func unjailedCandidates(candidates CandidateList, height uint64) CandidateList {
+	unjailed := make(CandidateList, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.JailedUntil <= height {
+			unjailed = append(unjailed, candidate)
+		}
+	}
+	return unjailed
+}
+
+// epochHeights returns the number of heights in an epoch of a Roll-DPoS chain
+func epochHeights(cfg *config.RollDPoS) uint64 {
+	numSubEpochs := cfg.NumSubEpochs
+	if numSubEpochs == 0 {
+		numSubEpochs = 1
+	}
+	return uint64(cfg.NumDelegates) * uint64(numSubEpochs)
+}
+
+// gasFee returns the fee of the gas at the given price
+func gasFee(gas uint64, gasPrice *big.Int) *big.Int {
+	if gasPrice == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+}
+
 //======================================
 // private trie constructor functions
 //======================================
@@ -20,10 +20,12 @@ import (
 
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/crypto"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/iotxaddress"
 	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/proto"
 	"github.com/iotexproject/iotex-core/test/mock/mock_trie"
 	"github.com/iotexproject/iotex-core/testutil"
 	"github.com/iotexproject/iotex-core/trie"
@@ -71,7 +73,7 @@ func TestCreateState(t *testing.T) {
 	addr, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
 	require.Nil(err)
 	state, _ := sf.LoadOrCreateState(addr.RawAddress, 5)
-	require.Nil(sf.CommitStateChanges(0, nil, nil, nil))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
 	require.Equal(uint64(0x0), state.Nonce)
 	require.Equal(big.NewInt(5), state.Balance)
 	ss, err := sf.State(addr.RawAddress)
@@ -119,6 +121,93 @@ func TestNonce(t *testing.T) {
 	require.Equal(ErrFailedToUnmarshalState, errors.Cause(err))
 }
 
+func TestGasFee(t *testing.T) {
+	require := require.New(t)
+
+	feeCfg := *cfg
+	feeCfg.Chain.Upgrades.GasFeeHeight = 0
+	accountTrie, err := trie.NewTrie(db.NewMemKVStore(), "account", trie.EmptyRoot)
+	require.Nil(err)
+	sf, err := NewFactory(&feeCfg, PrecreatedTrieOption(accountTrie))
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	b, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	producer, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(a.RawAddress, 1000000)
+	require.Nil(err)
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+
+	// transfer and vote fees are credited to the producer of coinbase transfer
+	tsf, err := action.NewTransfer(1, big.NewInt(100), a.RawAddress, b.RawAddress, []byte("payload"), uint64(100000), big.NewInt(2))
+	require.Nil(err)
+	vote, err := action.NewVote(2, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(3))
+	require.Nil(err)
+	coinbase := action.NewCoinBaseTransfer(big.NewInt(5), producer.RawAddress)
+	require.Nil(sf.CommitStateChanges(1, []*action.Transfer{tsf, coinbase}, []*action.Vote{vote}, nil, nil))
+	tsfFee := (action.TransferBaseIntrinsicGas + 7*action.TransferPayloadGas) * 2
+	voteFee := action.VoteIntrinsicGas * 3
+	balance, err := sf.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(int64(1000000-100-tsfFee-voteFee)), balance)
+	balance, err = sf.Balance(b.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(100), balance)
+	balance, err = sf.Balance(producer.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(int64(5+tsfFee+voteFee)), balance)
+
+	// reject transfer which cannot afford the gas fee
+	balance, err = sf.Balance(a.RawAddress)
+	require.Nil(err)
+	tsf, err = action.NewTransfer(3, balance, a.RawAddress, b.RawAddress, []byte{}, uint64(100000), big.NewInt(1))
+	require.Nil(err)
+	err = sf.CommitStateChanges(2, []*action.Transfer{tsf, coinbase}, nil, nil, nil)
+	require.Equal(ErrNotEnoughBalance, errors.Cause(err))
+}
+
+func TestGasFeeUpgrade(t *testing.T) {
+	require := require.New(t)
+
+	upgradeCfg := *cfg
+	upgradeCfg.Chain.Upgrades.GasFeeHeight = 2
+	accountTrie, err := trie.NewTrie(db.NewMemKVStore(), "account", trie.EmptyRoot)
+	require.Nil(err)
+	sf, err := NewFactory(&upgradeCfg, PrecreatedTrieOption(accountTrie))
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	producer, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(a.RawAddress, 1000000)
+	require.Nil(err)
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+	coinbase := action.NewCoinBaseTransfer(big.NewInt(5), producer.RawAddress)
+
+	// the vote is free before the gas fee upgrade
+	vote, err := action.NewVote(1, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(3))
+	require.Nil(err)
+	require.Nil(sf.CommitStateChanges(1, []*action.Transfer{coinbase}, []*action.Vote{vote}, nil, nil))
+	balance, err := sf.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(1000000), balance)
+
+	// and is charged the fee from the height of the upgrade
+	vote, err = action.NewVote(2, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(3))
+	require.Nil(err)
+	require.Nil(sf.CommitStateChanges(2, []*action.Transfer{coinbase}, []*action.Vote{vote}, nil, nil))
+	balance, err = sf.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(int64(1000000-action.VoteIntrinsicGas*3)), balance)
+	balance, err = sf.Balance(producer.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(int64(10+action.VoteIntrinsicGas*3)), balance)
+}
+
 func voteForm(height uint64, cs []*Candidate) []string {
 	r := make([]string, len(cs))
 	for i := 0; i < len(cs); i++ {
@@ -266,7 +355,7 @@ func TestCandidates(t *testing.T) {
 	// a:100(0) b:200(0) c:300(0)
 	tx1 := action.Transfer{Sender: a.RawAddress, Recipient: b.RawAddress, Nonce: uint64(1), Amount: big.NewInt(10)}
 	tx2 := action.Transfer{Sender: a.RawAddress, Recipient: c.RawAddress, Nonce: uint64(2), Amount: big.NewInt(20)}
-	err = sf.CommitStateChanges(0, []*action.Transfer{&tx1, &tx2}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{&tx1, &tx2}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	balanceB, err := sf.Balance(b.RawAddress)
 	require.Nil(t, err)
@@ -280,7 +369,7 @@ func TestCandidates(t *testing.T) {
 	vote, err := action.NewVote(0, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
 	vote.GetVote().SelfPubkey = a.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":70"}))
 	// a(a):70(+0=70) b:210 c:320
@@ -288,7 +377,7 @@ func TestCandidates(t *testing.T) {
 	vote2, err := action.NewVote(0, b.RawAddress, b.RawAddress, uint64(100000), big.NewInt(10))
 	vote2.GetVote().SelfPubkey = b.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote2}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote2}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":70", b.RawAddress + ":210"}))
 	// a(a):70(+0=70) b(b):210(+0=210) !c:320
@@ -296,19 +385,19 @@ func TestCandidates(t *testing.T) {
 	vote3, err := action.NewVote(1, a.RawAddress, b.RawAddress, uint64(100000), big.NewInt(10))
 	vote3.GetVote().SelfPubkey = a.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote3}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote3}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":0", b.RawAddress + ":280"}))
 	// a(b):70(0) b(b):210(+70=280) !c:320
 
 	tx3 := action.Transfer{Sender: b.RawAddress, Recipient: a.RawAddress, Nonce: uint64(2), Amount: big.NewInt(20)}
-	err = sf.CommitStateChanges(0, []*action.Transfer{&tx3}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{&tx3}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":0", b.RawAddress + ":280"}))
 	// a(b):90(0) b(b):190(+90=280) !c:320
 
 	tx4 := action.Transfer{Sender: a.RawAddress, Recipient: b.RawAddress, Nonce: uint64(2), Amount: big.NewInt(20)}
-	err = sf.CommitStateChanges(0, []*action.Transfer{&tx4}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{&tx4}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":0", b.RawAddress + ":280"}))
 	// a(b):70(0) b(b):210(+70=280) !c:320
@@ -316,7 +405,7 @@ func TestCandidates(t *testing.T) {
 	vote4, err := action.NewVote(1, b.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
 	vote4.GetVote().SelfPubkey = b.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote4}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote4}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":210", b.RawAddress + ":70"}))
 	// a(b):70(210) b(a):210(70) !c:320
@@ -324,7 +413,7 @@ func TestCandidates(t *testing.T) {
 	vote5, err := action.NewVote(2, b.RawAddress, b.RawAddress, uint64(100000), big.NewInt(10))
 	vote5.GetVote().SelfPubkey = b.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote5}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote5}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":0", b.RawAddress + ":280"}))
 	// a(b):70(0) b(b):210(+70=280) !c:320
@@ -332,13 +421,13 @@ func TestCandidates(t *testing.T) {
 	vote6, err := action.NewVote(3, b.RawAddress, b.RawAddress, uint64(100000), big.NewInt(10))
 	vote6.GetVote().SelfPubkey = b.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote6}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote6}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":0", b.RawAddress + ":280"}))
 	// a(b):70(0) b(b):210(+70=280) !c:320
 
 	tx5 := action.Transfer{Sender: c.RawAddress, Recipient: a.RawAddress, Nonce: uint64(2), Amount: big.NewInt(20)}
-	err = sf.CommitStateChanges(0, []*action.Transfer{&tx5}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{&tx5}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":0", b.RawAddress + ":300"}))
 	// a(b):90(0) b(b):210(+90=300) !c:300
@@ -346,7 +435,7 @@ func TestCandidates(t *testing.T) {
 	vote7, err := action.NewVote(0, c.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
 	vote7.GetVote().SelfPubkey = c.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote7}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote7}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":300", b.RawAddress + ":300"}))
 	// a(b):90(300) b(b):210(+90=300) !c(a):300
@@ -354,7 +443,7 @@ func TestCandidates(t *testing.T) {
 	vote8, err := action.NewVote(4, b.RawAddress, c.RawAddress, uint64(100000), big.NewInt(10))
 	vote8.GetVote().SelfPubkey = b.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote8}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote8}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":300", b.RawAddress + ":90"}))
 	// a(b):90(300) b(c):210(90) !c(a):300
@@ -362,7 +451,7 @@ func TestCandidates(t *testing.T) {
 	vote9, err := action.NewVote(1, c.RawAddress, c.RawAddress, uint64(100000), big.NewInt(10))
 	vote9.GetVote().SelfPubkey = c.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote9}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote9}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":510", b.RawAddress + ":90"}))
 	// a(b):90(0) b(c):210(90) c(c):300(+210=510)
@@ -370,7 +459,7 @@ func TestCandidates(t *testing.T) {
 	vote10, err := action.NewVote(0, d.RawAddress, e.RawAddress, uint64(100000), big.NewInt(10))
 	vote10.GetVote().SelfPubkey = d.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote10}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote10}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":510", b.RawAddress + ":90"}))
 	// a(b):90(0) b(c):210(90) c(c):300(+210=510)
@@ -378,7 +467,7 @@ func TestCandidates(t *testing.T) {
 	vote11, err := action.NewVote(1, d.RawAddress, d.RawAddress, uint64(100000), big.NewInt(10))
 	vote11.GetVote().SelfPubkey = d.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote11}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote11}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":510", d.RawAddress + ":100"}))
 	// a(b):90(0) b(c):210(90) c(c):300(+210=510) d(d): 100(100)
@@ -386,7 +475,7 @@ func TestCandidates(t *testing.T) {
 	vote12, err := action.NewVote(2, d.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
 	vote12.GetVote().SelfPubkey = d.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote12}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote12}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":510", a.RawAddress + ":100"}))
 	// a(b):90(100) b(c):210(90) c(c):300(+210=510) d(a): 100(0)
@@ -394,7 +483,7 @@ func TestCandidates(t *testing.T) {
 	vote13, err := action.NewVote(2, c.RawAddress, d.RawAddress, uint64(100000), big.NewInt(10))
 	vote13.GetVote().SelfPubkey = c.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote13}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote13}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":210", d.RawAddress + ":300"}))
 	// a(b):90(100) b(c):210(90) c(d):300(210) d(a): 100(300)
@@ -402,14 +491,14 @@ func TestCandidates(t *testing.T) {
 	vote14, err := action.NewVote(3, c.RawAddress, c.RawAddress, uint64(100000), big.NewInt(10))
 	vote14.GetVote().SelfPubkey = c.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote14}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote14}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":510", a.RawAddress + ":100"}))
 	// a(b):90(100) b(c):210(90) c(c):300(+210=510) d(a): 100(0)
 
 	tx6 := action.Transfer{Sender: c.RawAddress, Recipient: e.RawAddress, Nonce: uint64(1), Amount: big.NewInt(200)}
 	tx7 := action.Transfer{Sender: b.RawAddress, Recipient: e.RawAddress, Nonce: uint64(2), Amount: big.NewInt(200)}
-	err = sf.CommitStateChanges(0, []*action.Transfer{&tx6, &tx7}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{&tx6, &tx7}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":110", a.RawAddress + ":100"}))
 	// a(b):90(100) b(c):10(90) c(c):100(+10=110) d(a): 100(0) !e:500
@@ -417,7 +506,7 @@ func TestCandidates(t *testing.T) {
 	vote15, err := action.NewVote(0, e.RawAddress, e.RawAddress, uint64(100000), big.NewInt(10))
 	vote15.GetVote().SelfPubkey = e.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote15}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote15}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":110", e.RawAddress + ":500"}))
 	// a(b):90(100) b(c):10(90) c(c):100(+10=110) d(a): 100(0) e(e):500(+0=500)
@@ -425,7 +514,7 @@ func TestCandidates(t *testing.T) {
 	vote16, err := action.NewVote(0, f.RawAddress, f.RawAddress, uint64(100000), big.NewInt(10))
 	vote16.GetVote().SelfPubkey = f.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote16}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote16}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{f.RawAddress + ":300", e.RawAddress + ":500"}))
 	// a(b):90(100) b(c):10(90) c(c):100(+10=110) d(a): 100(0) e(e):500(+0=500) f(f):300(+0=300)
@@ -436,26 +525,26 @@ func TestCandidates(t *testing.T) {
 	vote18, err := action.NewVote(1, f.RawAddress, d.RawAddress, uint64(100000), big.NewInt(10))
 	vote18.GetVote().SelfPubkey = f.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote17, vote18}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote17, vote18}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{d.RawAddress + ":300", e.RawAddress + ":500"}))
 	// a(b):90(100) b(c):10(90) c(c):100(+10=110) d(a): 100(300) e(e):500(+0=500) f(d):300(0)
 
 	tx8 := action.Transfer{Sender: f.RawAddress, Recipient: b.RawAddress, Nonce: uint64(1), Amount: big.NewInt(200)}
-	err = sf.CommitStateChanges(0, []*action.Transfer{&tx8}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{&tx8}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":310", e.RawAddress + ":500"}))
 	// a(b):90(100) b(c):210(90) c(c):100(+210=310) d(a): 100(100) e(e):500(+0=500) f(d):100(0)
 	//fmt.Printf("%v \n", voteForm(sf.candidatesBuffer()))
 
 	tx9 := action.Transfer{Sender: b.RawAddress, Recipient: a.RawAddress, Nonce: uint64(1), Amount: big.NewInt(10)}
-	err = sf.CommitStateChanges(0, []*action.Transfer{&tx9}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{&tx9}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{c.RawAddress + ":300", e.RawAddress + ":500"}))
 	// a(b):100(100) b(c):200(100) c(c):100(+200=300) d(a): 100(100) e(e):500(+0=500) f(d):100(0)
 
 	tx10 := action.Transfer{Sender: e.RawAddress, Recipient: d.RawAddress, Nonce: uint64(1), Amount: big.NewInt(300)}
-	err = sf.CommitStateChanges(1, []*action.Transfer{&tx10}, []*action.Vote{}, []*action.Execution{})
+	err = sf.CommitStateChanges(1, []*action.Transfer{&tx10}, []*action.Vote{}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	height, _ := sf.Candidates()
 	require.True(t, height == 1)
@@ -468,7 +557,7 @@ func TestCandidates(t *testing.T) {
 	vote20, err := action.NewVote(3, d.RawAddress, b.RawAddress, uint64(100000), big.NewInt(10))
 	vote20.GetVote().SelfPubkey = d.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(2, []*action.Transfer{}, []*action.Vote{vote19, vote20}, []*action.Execution{})
+	err = sf.CommitStateChanges(2, []*action.Transfer{}, []*action.Vote{vote19, vote20}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	height, _ = sf.Candidates()
 	require.True(t, height == 2)
@@ -478,7 +567,7 @@ func TestCandidates(t *testing.T) {
 	vote21, err := action.NewVote(4, c.RawAddress, "", uint64(100000), big.NewInt(10))
 	vote21.GetVote().SelfPubkey = c.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(3, []*action.Transfer{}, []*action.Vote{vote21}, []*action.Execution{})
+	err = sf.CommitStateChanges(3, []*action.Transfer{}, []*action.Vote{vote21}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	height, _ = sf.Candidates()
 	require.True(t, height == 3)
@@ -488,7 +577,7 @@ func TestCandidates(t *testing.T) {
 	vote22, err := action.NewVote(4, f.RawAddress, "", uint64(100000), big.NewInt(10))
 	vote22.GetVote().SelfPubkey = f.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(3, []*action.Transfer{}, []*action.Vote{vote22}, []*action.Execution{})
+	err = sf.CommitStateChanges(3, []*action.Transfer{}, []*action.Vote{vote22}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	height, _ = sf.Candidates()
 	require.True(t, height == 3)
@@ -583,21 +672,21 @@ func TestUnvote(t *testing.T) {
 	vote1, err := action.NewVote(0, a.RawAddress, "", uint64(100000), big.NewInt(10))
 	vote1.GetVote().SelfPubkey = a.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote1}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote1}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{}))
 
 	vote2, err := action.NewVote(0, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(10))
 	vote2.GetVote().SelfPubkey = a.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote2}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote2}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":100"}))
 
 	vote3, err := action.NewVote(0, a.RawAddress, "", uint64(100000), big.NewInt(10))
 	vote3.GetVote().SelfPubkey = a.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote3}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote3}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{}))
 
@@ -610,11 +699,80 @@ func TestUnvote(t *testing.T) {
 	vote6, err := action.NewVote(0, a.RawAddress, "", uint64(100000), big.NewInt(10))
 	vote6.GetVote().SelfPubkey = a.PublicKey[:]
 	require.NoError(t, err)
-	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote4, vote5, vote6}, []*action.Execution{})
+	err = sf.CommitStateChanges(0, []*action.Transfer{}, []*action.Vote{vote4, vote5, vote6}, []*action.Execution{}, nil)
 	require.Nil(t, err)
 	require.True(t, compareStrings(voteForm(sf.Candidates()), []string{b.RawAddress + ":200"}))
 }
 
+func TestSlashing(t *testing.T) {
+	require := require.New(t)
+	a, _ := iotxaddress.NewAddress(iotxaddress.IsTestnet, iotxaddress.ChainID)
+	b, _ := iotxaddress.NewAddress(iotxaddress.IsTestnet, iotxaddress.ChainID)
+
+	testutil.CleanupPath(t, testTriePath)
+	defer testutil.CleanupPath(t, testTriePath)
+
+	accountTr, _ := trie.NewTrie(db.NewBoltDB(testTriePath, &cfg.DB), "account", trie.EmptyRoot)
+	require.NoError(accountTr.Start(context.Background()))
+	sf := &factory{
+		accountTrie:      accountTr,
+		numCandidates:    uint(2),
+		cachedCandidates: make(map[hash.AddrHash]*Candidate),
+		cachedAccount:    make(map[hash.AddrHash]*State),
+		slashing:         config.Slashing{RatePercent: 10, JailEpochs: 2},
+		epochHeights:     4,
+	}
+	sf.dao = db.NewCachedKVStore(sf.accountTrie.TrieDB())
+	_, err := sf.LoadOrCreateState(a.RawAddress, uint64(100))
+	require.NoError(err)
+	_, err = sf.LoadOrCreateState(b.RawAddress, uint64(200))
+	require.NoError(err)
+
+	vote, err := action.NewVote(0, a.RawAddress, a.RawAddress, uint64(100000), big.NewInt(0))
+	require.NoError(err)
+	vote.GetVote().SelfPubkey = a.PublicKey[:]
+	require.NoError(sf.CommitStateChanges(0, nil, []*action.Vote{vote}, nil, nil))
+	require.True(compareStrings(voteForm(sf.Candidates()), []string{a.RawAddress + ":100"}))
+
+	equivocate := func(signer *iotxaddress.Address) *action.Evidence {
+		msgs := make([]*iproto.ViewChangeMsg, 0, 2)
+		blkHashes := []hash.Hash32B{{1}, {2}}
+		for i := range blkHashes {
+			h := action.ViewChangeHash(1, 0, iproto.ViewChangeMsg_VOTE, blkHashes[i], true)
+			msgs = append(msgs, &iproto.ViewChangeMsg{
+				Vctype:     iproto.ViewChangeMsg_VOTE,
+				BlockHash:  blkHashes[i][:],
+				SenderAddr: signer.RawAddress,
+				Decision:   true,
+				Height:     1,
+				Signature:  crypto.EC283.Sign(signer.PrivateKey, h[:]),
+			})
+		}
+		e, err := action.NewEvidence(msgs[0], msgs[1])
+		require.NoError(err)
+		return e
+	}
+
+	// The offender is slashed and jailed
+	evidence := equivocate(a)
+	require.NoError(sf.CommitStateChanges(1, nil, nil, nil, []*action.Evidence{evidence}))
+	balance, err := sf.Balance(a.RawAddress)
+	require.NoError(err)
+	require.Equal(big.NewInt(90), balance)
+	state, err := sf.LoadOrCreateState(a.RawAddress, 0)
+	require.NoError(err)
+	require.Equal(uint64(9), state.JailedUntil)
+	require.True(compareStrings(voteForm(sf.Candidates()), []string{}))
+
+	// The offender cannot be slashed twice for the same equivocation
+	err = sf.CommitStateChanges(2, nil, nil, nil, []*action.Evidence{evidence})
+	require.Equal(action.ErrEvidence, errors.Cause(err))
+
+	// The evidence against a non-candidate is rejected
+	err = sf.CommitStateChanges(2, nil, nil, nil, []*action.Evidence{equivocate(b)})
+	require.Equal(action.ErrEvidence, errors.Cause(err))
+}
+
 func TestLoadStoreHeight(t *testing.T) {
 	require := require.New(t)
 
@@ -664,6 +822,283 @@ func TestLoadStoreHeightInMem(t *testing.T) {
 	require.Equal(uint64(10), height)
 }
 
+func TestStateProof(t *testing.T) {
+	require := require.New(t)
+
+	accountTrie, err := trie.NewTrie(db.NewMemKVStore(), "account", trie.EmptyRoot)
+	require.Nil(err)
+	sf, err := NewFactory(cfg, PrecreatedTrieOption(accountTrie))
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	b, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(a.RawAddress, 100)
+	require.Nil(err)
+	c, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(c.RawAddress, 0)
+	require.Nil(err)
+	cHash, err := iotxaddress.GetPubkeyHash(c.RawAddress)
+	require.Nil(err)
+	contract := byteutil.BytesTo20B(cHash)
+	k1 := byteutil.BytesTo32B(hash.Hash256b([]byte("key1")))
+	k2 := byteutil.BytesTo32B(hash.Hash256b([]byte("key2")))
+	v1 := byteutil.BytesTo32B(hash.Hash256b([]byte("value1")))
+	require.Nil(sf.SetContractState(contract, k1, v1))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+	root := sf.RootHash()
+
+	// inclusion of account
+	aHash, err := iotxaddress.GetPubkeyHash(a.RawAddress)
+	require.Nil(err)
+	proof, err := sf.AccountProof(byteutil.BytesTo20B(aHash))
+	require.Nil(err)
+	state, err := VerifyAccountProof(root, byteutil.BytesTo20B(aHash), proof, false)
+	require.Nil(err)
+	require.Equal(big.NewInt(100), state.Balance)
+	_, err = VerifyAccountProof(trie.EmptyRoot, byteutil.BytesTo20B(aHash), proof, false)
+	require.Equal(trie.ErrInvalidProof, errors.Cause(err))
+	// exclusion of account
+	bHash, err := iotxaddress.GetPubkeyHash(b.RawAddress)
+	require.Nil(err)
+	proof, err = sf.AccountProof(byteutil.BytesTo20B(bHash))
+	require.Nil(err)
+	_, err = VerifyAccountProof(root, byteutil.BytesTo20B(bHash), proof, false)
+	require.Equal(ErrAccountNotExist, errors.Cause(err))
+
+	// storage of contract
+	proof, err = sf.AccountProof(contract)
+	require.Nil(err)
+	state, err = VerifyAccountProof(root, contract, proof, false)
+	require.Nil(err)
+	proof, err = sf.StorageProof(contract, k1)
+	require.Nil(err)
+	v, err := VerifyStorageProof(state.Root, k1, proof, false)
+	require.Nil(err)
+	require.Equal(v1, v)
+	proof, err = sf.StorageProof(contract, k2)
+	require.Nil(err)
+	_, err = VerifyStorageProof(state.Root, k2, proof, false)
+	require.Equal(trie.ErrNotExist, errors.Cause(err))
+	// account without storage
+	proof, err = sf.StorageProof(byteutil.BytesTo20B(aHash), k1)
+	require.Nil(err)
+	_, err = VerifyStorageProof(hash.ZeroHash32B, k1, proof, false)
+	require.Equal(trie.ErrNotExist, errors.Cause(err))
+	_, err = sf.StorageProof(byteutil.BytesTo20B(bHash), k1)
+	require.Equal(ErrAccountNotExist, errors.Cause(err))
+}
+
+func TestIndexedTrieUpgrade(t *testing.T) {
+	require := require.New(t)
+
+	upgradeCfg := *cfg
+	upgradeCfg.Chain.Upgrades.IndexedTrieHeight = 1
+	sf, err := NewFactory(&upgradeCfg, InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	aHash, err := iotxaddress.GetPubkeyHash(a.RawAddress)
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(a.RawAddress, 100)
+	require.Nil(err)
+	k1 := byteutil.BytesTo32B(hash.Hash256b([]byte("key1")))
+	v1 := byteutil.BytesTo32B(hash.Hash256b([]byte("value1")))
+	require.Nil(sf.SetContractState(byteutil.BytesTo20B(aHash), k1, v1))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+	root0 := sf.RootHash()
+	proof, err := sf.AccountProof(byteutil.BytesTo20B(aHash))
+	require.Nil(err)
+	_, err = VerifyAccountProof(root0, byteutil.BytesTo20B(aHash), proof, false)
+	require.Nil(err)
+	_, err = VerifyAccountProof(root0, byteutil.BytesTo20B(aHash), proof, true)
+	require.Equal(trie.ErrInvalidProof, errors.Cause(err))
+
+	// the states are rehashed on the upgrade height without changes
+	require.Nil(sf.CommitStateChanges(1, nil, nil, nil, nil))
+	root1 := sf.RootHash()
+	require.NotEqual(root0, root1)
+	balance, err := sf.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(100), balance)
+	v, err := sf.GetContractState(byteutil.BytesTo20B(aHash), k1)
+	require.Nil(err)
+	require.Equal(v1, v)
+	proof, err = sf.AccountProof(byteutil.BytesTo20B(aHash))
+	require.Nil(err)
+	state, err := VerifyAccountProof(root1, byteutil.BytesTo20B(aHash), proof, true)
+	require.Nil(err)
+	proof, err = sf.StorageProof(byteutil.BytesTo20B(aHash), k1)
+	require.Nil(err)
+	v, err = VerifyStorageProof(state.Root, k1, proof, true)
+	require.Nil(err)
+	require.Equal(v1, v)
+
+	// the storage trie of a new contract is hashed by the new scheme
+	b, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	bHash, err := iotxaddress.GetPubkeyHash(b.RawAddress)
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(b.RawAddress, 0)
+	require.Nil(err)
+	require.Nil(sf.SetContractState(byteutil.BytesTo20B(bHash), k1, v1))
+	require.Nil(sf.CommitStateChanges(2, nil, nil, nil, nil))
+	proof, err = sf.AccountProof(byteutil.BytesTo20B(bHash))
+	require.Nil(err)
+	state, err = VerifyAccountProof(sf.RootHash(), byteutil.BytesTo20B(bHash), proof, true)
+	require.Nil(err)
+	proof, err = sf.StorageProof(byteutil.BytesTo20B(bHash), k1)
+	require.Nil(err)
+	v, err = VerifyStorageProof(state.Root, k1, proof, true)
+	require.Nil(err)
+	require.Equal(v1, v)
+
+	// the states before the upgrade height are kept
+	view, err := sf.ViewAtHeight(0)
+	require.Nil(err)
+	require.Equal(root0, view.RootHash())
+	balance, err = view.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(100), balance)
+	require.Nil(sf.Stop(context.Background()))
+}
+
+func TestViewAtHeight(t *testing.T) {
+	require := require.New(t)
+
+	sf, err := NewFactory(cfg, InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(a.RawAddress, 100)
+	require.Nil(err)
+	cHash, err := iotxaddress.GetPubkeyHash(a.RawAddress)
+	require.Nil(err)
+	contract := byteutil.BytesTo20B(cHash)
+	k := byteutil.BytesTo32B(hash.Hash256b([]byte("key")))
+	v1 := byteutil.BytesTo32B(hash.Hash256b([]byte("value1")))
+	v2 := byteutil.BytesTo32B(hash.Hash256b([]byte("value2")))
+	require.Nil(sf.SetContractState(contract, k, v1))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+	root0 := sf.RootHash()
+
+	// change the balance and storage on height 1
+	state, err := sf.LoadOrCreateState(a.RawAddress, 0)
+	require.Nil(err)
+	state.Balance = big.NewInt(50)
+	require.Nil(sf.SetContractState(contract, k, v2))
+	require.Nil(sf.CommitStateChanges(1, nil, nil, nil, nil))
+	root1 := sf.RootHash()
+	require.NotEqual(root0, root1)
+
+	root, err := sf.RootHashByHeight(0)
+	require.Nil(err)
+	require.Equal(root0, root)
+	root, err = sf.RootHashByHeight(1)
+	require.Nil(err)
+	require.Equal(root1, root)
+	_, err = sf.RootHashByHeight(2)
+	require.NotNil(err)
+
+	// read the states on height 0
+	view, err := sf.ViewAtHeight(0)
+	require.Nil(err)
+	require.Equal(root0, view.RootHash())
+	height, err := view.Height()
+	require.Nil(err)
+	require.Equal(uint64(0), height)
+	balance, err := view.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(100), balance)
+	v, err := view.GetContractState(contract, k)
+	require.Nil(err)
+	require.Equal(v1, v)
+
+	// changes made to the view are not visible to the factory, and cannot be committed
+	require.Nil(view.SetContractState(contract, k, v2))
+	require.Equal(ErrReadOnlyView, errors.Cause(view.CommitStateChanges(1, nil, nil, nil, nil)))
+	balance, err = sf.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(50), balance)
+	v, err = sf.GetContractState(contract, k)
+	require.Nil(err)
+	require.Equal(v2, v)
+	height, err = sf.Height()
+	require.Nil(err)
+	require.Equal(uint64(1), height)
+
+	_, err = sf.ViewAtHeight(2)
+	require.NotNil(err)
+}
+
+func TestFork(t *testing.T) {
+	require := require.New(t)
+
+	sf, err := NewFactory(cfg, InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(a.RawAddress, 100)
+	require.Nil(err)
+	cHash, err := iotxaddress.GetPubkeyHash(a.RawAddress)
+	require.Nil(err)
+	contract := byteutil.BytesTo20B(cHash)
+	k := byteutil.BytesTo32B(hash.Hash256b([]byte("key")))
+	v1 := byteutil.BytesTo32B(hash.Hash256b([]byte("value1")))
+	v2 := byteutil.BytesTo32B(hash.Hash256b([]byte("value2")))
+	require.Nil(sf.SetContractState(contract, k, v1))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+	root0 := sf.RootHash()
+
+	// commit the changes of height 1 to a fork of height 0
+	fork, err := sf.Fork(0)
+	require.Nil(err)
+	require.Equal(root0, fork.RootHash())
+	state, err := fork.LoadOrCreateState(a.RawAddress, 0)
+	require.Nil(err)
+	state.Balance = big.NewInt(50)
+	require.Nil(fork.SetContractState(contract, k, v2))
+	require.Nil(fork.CommitStateChanges(1, nil, nil, nil, nil))
+	forkRoot := fork.RootHash()
+	require.NotEqual(root0, forkRoot)
+	balance, err := fork.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(50), balance)
+
+	// the changes committed to the fork are not written into the factory
+	require.Equal(root0, sf.RootHash())
+	height, err := sf.Height()
+	require.Nil(err)
+	require.Equal(uint64(0), height)
+	_, err = sf.RootHashByHeight(1)
+	require.NotNil(err)
+	balance, err = sf.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(100), balance)
+	// read the storage on a view, since reading it on the factory would cache the contract with the state of height 0
+	view, err := sf.ViewAtHeight(0)
+	require.Nil(err)
+	v, err := view.GetContractState(contract, k)
+	require.Nil(err)
+	require.Equal(v1, v)
+
+	// the factory reaches the same root by committing the same changes
+	state, err = sf.LoadOrCreateState(a.RawAddress, 0)
+	require.Nil(err)
+	state.Balance = big.NewInt(50)
+	require.Nil(sf.SetContractState(contract, k, v2))
+	require.Nil(sf.CommitStateChanges(1, nil, nil, nil, nil))
+	require.Equal(forkRoot, sf.RootHash())
+
+	_, err = sf.Fork(2)
+	require.NotNil(err)
+}
+
 func compareStrings(actual []string, expected []string) bool {
 	act := make(map[string]bool)
 	for i := 0; i < len(actual); i++ {
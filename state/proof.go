@@ -0,0 +1,47 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+// VerifyAccountProof checks the proof returned by Factory.AccountProof() against the root hash of the account trie,
+// and returns the proven state of the account. ErrAccountNotExist is returned if the proof shows the account does
+// not exist. indexed tells if the states are on or after the height the tries are rehashed on
+func VerifyAccountProof(root hash.Hash32B, addr hash.AddrHash, proof [][]byte, indexed bool) (*State, error) {
+	value, err := trie.VerifyProof(root, addr[:], proof, indexed)
+	if errors.Cause(err) == trie.ErrNotExist {
+		return nil, errors.Wrapf(ErrAccountNotExist, "addrHash = %x", addr[:])
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to verify proof of account %x", addr)
+	}
+	return bytesToState(value)
+}
+
+// VerifyStorageProof checks the proof returned by Factory.StorageProof() against the storage trie root of a
+// contract, and returns the proven value of the storage slot. trie.ErrNotExist is returned if the proof shows the
+// slot does not exist. indexed tells if the states are on or after the height the tries are rehashed on
+func VerifyStorageProof(root hash.Hash32B, key hash.Hash32B, proof [][]byte, indexed bool) (hash.Hash32B, error) {
+	if root == hash.ZeroHash32B {
+		// account without storage trie
+		if len(proof) != 0 {
+			return hash.ZeroHash32B, errors.Wrap(trie.ErrInvalidProof, "account without storage has non-empty proof")
+		}
+		return hash.ZeroHash32B, errors.Wrapf(trie.ErrNotExist, "key = %x", key)
+	}
+	value, err := trie.VerifyProof(root, key[:], proof, indexed)
+	if err != nil {
+		return hash.ZeroHash32B, err
+	}
+	return byteutil.BytesTo32B(value), nil
+}
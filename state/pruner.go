@@ -0,0 +1,217 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"sync"
+
+	"github.com/boltdb/bolt"
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+// PrunedHeightKey indicates the key of the oldest height whose states are kept in underlying DB
+const PrunedHeightKey = "prunedHeight"
+
+// pruner removes the patricia nodes that are no longer reachable from the account trie roots of the latest heights.
+// It runs a mark-and-sweep in background: the reachable nodes are marked without blocking block commit, and only the
+// sweep is serialized with the commit of state changes
+type pruner struct {
+	mutex       sync.Mutex // held by factory when committing state changes to DB
+	kv          db.KVStore // the DB underlying the cached KV store of factory
+	numRetained uint64     // number of latest heights to keep
+}
+
+// nodeMarkSet holds the marked nodes of account trie and storage tries
+type nodeMarkSet struct {
+	account  map[hash.Hash32B]bool
+	contract map[hash.Hash32B]bool
+}
+
+func newPruner(kv db.KVStore, numRetained uint64) *pruner {
+	return &pruner{
+		kv:          kv,
+		numRetained: numRetained,
+	}
+}
+
+// Run prunes the trie DB, it is called periodically by the recurring task
+func (p *pruner) Run() {
+	if err := p.prune(); err != nil {
+		logger.Error().Err(err).Msg("Failed to prune state trie")
+	}
+}
+
+// prune deletes the nodes not reachable from the roots of the latest numRetained heights
+func (p *pruner) prune() error {
+	height, err := p.height()
+	switch errors.Cause(err) {
+	case nil:
+	case db.ErrNotExist, bolt.ErrBucketNotFound:
+		// no state has been committed yet
+		return nil
+	default:
+		return err
+	}
+	if height < p.numRetained {
+		return nil
+	}
+	oldest := height - p.numRetained + 1
+	pruned, err := p.prunedHeight()
+	if err != nil {
+		return err
+	}
+	if oldest <= pruned {
+		return nil
+	}
+
+	// mark the nodes reachable from retained heights
+	marked := nodeMarkSet{
+		account:  map[hash.Hash32B]bool{trie.EmptyRoot: true},
+		contract: map[hash.Hash32B]bool{trie.EmptyRoot: true},
+	}
+	for h := oldest; h <= height; h++ {
+		if err := p.mark(marked, h); err != nil {
+			return err
+		}
+	}
+	// nodes of the account trie use 32-byte hash as key, other records in the namespace are skipped
+	accountNodes, err := p.unmarked(trie.AccountKVNameSpace, marked.account)
+	if err != nil {
+		return err
+	}
+	contractNodes, err := p.unmarked(trie.ContractKVNameSpace, marked.contract)
+	if err != nil {
+		return err
+	}
+
+	// sweep, blocks are not committed during the sweep
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// mark the heights committed since marking started, which may have re-created unmarked nodes
+	latest, err := p.height()
+	if err != nil {
+		return err
+	}
+	for h := height + 1; h <= latest; h++ {
+		if err := p.mark(marked, h); err != nil {
+			return err
+		}
+	}
+	batch := p.kv.Batch()
+	numDeleted := 0
+	for i := range accountNodes {
+		// the batch keeps the key slice until it's committed, so the key must not be sliced from the loop variable
+		key := accountNodes[i][:]
+		if marked.account[accountNodes[i]] {
+			continue
+		}
+		numDeleted++
+		if err := batch.Delete(trie.AccountKVNameSpace, key, "failed to delete account node %x", key); err != nil {
+			return err
+		}
+	}
+	for i := range contractNodes {
+		key := contractNodes[i][:]
+		if marked.contract[contractNodes[i]] {
+			continue
+		}
+		numDeleted++
+		if err := batch.Delete(trie.ContractKVNameSpace, key, "failed to delete contract node %x", key); err != nil {
+			return err
+		}
+	}
+	for h := pruned; h < oldest; h++ {
+		if err := batch.Delete(trie.AccountRootKVNameSpace, byteutil.Uint64ToBytes(h), "failed to delete root of height %d", h); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(trie.AccountKVNameSpace, []byte(PrunedHeightKey), byteutil.Uint64ToBytes(oldest), "failed to store pruned height"); err != nil {
+		return err
+	}
+	if err := batch.Commit(); err != nil {
+		return errors.Wrapf(err, "failed to prune states before height %d", oldest)
+	}
+	logger.Info().Uint64("height", oldest).Int("nodes", numDeleted).Msg("Pruned state trie")
+	return nil
+}
+
+// mark marks the nodes of account trie and storage tries reachable from the root of the height
+func (p *pruner) mark(marked nodeMarkSet, height uint64) error {
+	value, err := p.kv.Get(trie.AccountRootKVNameSpace, byteutil.Uint64ToBytes(height))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get accountTrie's root hash on height %d", height)
+	}
+	root := byteutil.BytesTo32B(value)
+	return trie.Walk(p.kv, trie.AccountKVNameSpace, root, func(h hash.Hash32B) bool {
+		if marked.account[h] {
+			return false
+		}
+		marked.account[h] = true
+		return true
+	}, func(value []byte) error {
+		state, err := bytesToState(value)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode account state")
+		}
+		if state.Root == hash.ZeroHash32B {
+			return nil
+		}
+		return trie.Walk(p.kv, trie.ContractKVNameSpace, state.Root, func(h hash.Hash32B) bool {
+			if marked.contract[h] {
+				return false
+			}
+			marked.contract[h] = true
+			return true
+		}, nil)
+	})
+}
+
+// unmarked returns the keys of nodes in the namespace which are not marked
+func (p *pruner) unmarked(namespace string, marked map[hash.Hash32B]bool) ([]hash.Hash32B, error) {
+	keys := []hash.Hash32B{}
+	err := p.kv.ForEach(namespace, func(k, _ []byte) error {
+		if len(k) != len(hash.ZeroHash32B) {
+			return nil
+		}
+		key := byteutil.BytesTo32B(k)
+		if !marked[key] {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to iterate over namespace %s", namespace)
+	}
+	return keys, nil
+}
+
+func (p *pruner) height() (uint64, error) {
+	value, err := p.kv.Get(trie.AccountKVNameSpace, []byte(CurrentHeightKey))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get current height")
+	}
+	return byteutil.BytesToUint64(value), nil
+}
+
+func (p *pruner) prunedHeight() (uint64, error) {
+	switch value, err := p.kv.Get(trie.AccountKVNameSpace, []byte(PrunedHeightKey)); errors.Cause(err) {
+	case nil:
+		return byteutil.BytesToUint64(value), nil
+	case db.ErrNotExist:
+		// never pruned
+		return 0, nil
+	default:
+		return 0, errors.Wrap(err, "failed to get pruned height")
+	}
+}
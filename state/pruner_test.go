@@ -0,0 +1,108 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+func TestPruner(t *testing.T) {
+	require := require.New(t)
+
+	cfg := config.Default
+	cfg.Chain.PruningMode = config.PruneMode
+	cfg.Chain.NumRetainedHeights = 2
+	cfg.Chain.PruningInterval = time.Hour
+	f, err := NewFactory(&cfg, InMemTrieOption())
+	require.Nil(err)
+	require.Nil(f.Start(context.Background()))
+	defer func() { require.Nil(f.Stop(context.Background())) }()
+	sf := f.(*factory)
+	require.NotNil(sf.pruner)
+
+	// nothing to prune before any commit
+	require.Nil(sf.pruner.prune())
+
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	cHash, err := iotxaddress.GetPubkeyHash(a.RawAddress)
+	require.Nil(err)
+	contract := byteutil.BytesTo20B(cHash)
+	k := byteutil.BytesTo32B(hash.Hash256b([]byte("key")))
+	countNodes := func(namespace string) int {
+		nodes, err := sf.pruner.unmarked(namespace, map[hash.Hash32B]bool{})
+		require.Nil(err)
+		return len(nodes)
+	}
+
+	// change the balance and storage on every height
+	for h := uint64(0); h < 5; h++ {
+		state, err := sf.LoadOrCreateState(a.RawAddress, 0)
+		require.Nil(err)
+		state.Balance = big.NewInt(int64(h))
+		v := byteutil.BytesTo32B(hash.Hash256b(byteutil.Uint64ToBytes(h)))
+		require.Nil(sf.SetContractState(contract, k, v))
+		require.Nil(sf.CommitStateChanges(h, nil, nil, nil, nil))
+	}
+	numAccountNodes := countNodes(trie.AccountKVNameSpace)
+	numContractNodes := countNodes(trie.ContractKVNameSpace)
+
+	require.Nil(sf.pruner.prune())
+	require.True(countNodes(trie.AccountKVNameSpace) < numAccountNodes)
+	require.True(countNodes(trie.ContractKVNameSpace) < numContractNodes)
+	for h := uint64(0); h < 3; h++ {
+		_, err := sf.RootHashByHeight(h)
+		require.NotNil(err)
+		_, err = sf.ViewAtHeight(h)
+		require.NotNil(err)
+	}
+	// states of the retained heights are still readable
+	for h := uint64(3); h < 5; h++ {
+		view, err := sf.ViewAtHeight(h)
+		require.Nil(err)
+		balance, err := view.Balance(a.RawAddress)
+		require.Nil(err)
+		require.Equal(big.NewInt(int64(h)), balance)
+		v, err := view.GetContractState(contract, k)
+		require.Nil(err)
+		require.Equal(byteutil.BytesTo32B(hash.Hash256b(byteutil.Uint64ToBytes(h))), v)
+	}
+	pruned, err := sf.pruner.prunedHeight()
+	require.Nil(err)
+	require.Equal(uint64(3), pruned)
+
+	// pruning again without new heights does nothing
+	numAccountNodes = countNodes(trie.AccountKVNameSpace)
+	require.Nil(sf.pruner.prune())
+	require.Equal(numAccountNodes, countNodes(trie.AccountKVNameSpace))
+
+	// the factory keeps committing on top of the pruned trie
+	state, err := sf.LoadOrCreateState(a.RawAddress, 0)
+	require.Nil(err)
+	state.Balance = big.NewInt(5)
+	require.Nil(sf.CommitStateChanges(5, nil, nil, nil, nil))
+	require.Nil(sf.pruner.prune())
+	_, err = sf.RootHashByHeight(3)
+	require.NotNil(err)
+	balance, err := sf.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(5), balance)
+	v, err := sf.GetContractState(contract, k)
+	require.Nil(err)
+	require.Equal(byteutil.BytesTo32B(hash.Hash256b(byteutil.Uint64ToBytes(4))), v)
+}
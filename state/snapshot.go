@@ -0,0 +1,283 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+// ErrInvalidSnapshot is the error that the state snapshot is corrupted or does not match the expected root
+var ErrInvalidSnapshot = errors.New("invalid state snapshot")
+
+// types of records in state snapshot
+const (
+	snapshotHeader byte = iota + 1
+	snapshotAccountNode
+	snapshotContractNode
+	snapshotCode
+	snapshotCandidates
+)
+
+// maxSnapshotRecordSize is the maximum size of a record in state snapshot
+const maxSnapshotRecordSize = 1 << 26
+
+// Snapshot writes the states on the height into w, including the height and root hash of account trie, the nodes of
+// account trie and storage tries, the contract code and the candidates. Every record is a 1-byte type followed by
+// the length of payload in 4-byte big endian and the payload
+func (sf *factory) Snapshot(height uint64, w io.Writer) error {
+	root, err := sf.RootHashByHeight(height)
+	if err != nil {
+		return err
+	}
+	candidates, err := sf.dao.Get(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(height))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get candidates on height %d", height)
+	}
+	kv := sf.dao.KVStore()
+	bw := bufio.NewWriter(w)
+	if err := writeSnapshotRecord(bw, snapshotHeader, append(byteutil.Uint64ToBytes(height), root[:]...)); err != nil {
+		return err
+	}
+	// write the nodes reachable from the root, a node shared by multiple roots is written only once
+	var walkErr error
+	writeNodes := func(namespace string, kind byte, written map[hash.Hash32B]bool) func(hash.Hash32B) bool {
+		return func(h hash.Hash32B) bool {
+			if walkErr != nil || written[h] || h == trie.EmptyRoot {
+				return false
+			}
+			written[h] = true
+			node, err := kv.Get(namespace, h[:])
+			if err != nil {
+				walkErr = errors.Wrapf(err, "failed to get node %x", h)
+				return false
+			}
+			walkErr = writeSnapshotRecord(bw, kind, node)
+			return walkErr == nil
+		}
+	}
+	contractNodes := make(map[hash.Hash32B]bool)
+	codes := make(map[hash.Hash32B]bool)
+	err = trie.Walk(kv, trie.AccountKVNameSpace, root, writeNodes(trie.AccountKVNameSpace, snapshotAccountNode,
+		make(map[hash.Hash32B]bool)), func(value []byte) error {
+		state, err := bytesToState(value)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode account state")
+		}
+		if state.CodeHash != nil && !codes[byteutil.BytesTo32B(state.CodeHash)] {
+			codes[byteutil.BytesTo32B(state.CodeHash)] = true
+			code, err := kv.Get(trie.CodeKVNameSpace, state.CodeHash)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get code %x", state.CodeHash)
+			}
+			if err := writeSnapshotRecord(bw, snapshotCode, code); err != nil {
+				return err
+			}
+		}
+		if state.Root == hash.ZeroHash32B {
+			return nil
+		}
+		if err := trie.Walk(kv, trie.ContractKVNameSpace, state.Root, writeNodes(trie.ContractKVNameSpace,
+			snapshotContractNode, contractNodes), nil); err != nil {
+			return err
+		}
+		return walkErr
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to write states on height %d", height)
+	}
+	if walkErr != nil {
+		return errors.Wrapf(walkErr, "failed to write states on height %d", height)
+	}
+	if err := writeSnapshotRecord(bw, snapshotCandidates, candidates); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// LoadSnapshot loads the states written by Snapshot into the factory, which must not have committed any state. The
+// snapshot is rejected if it is not on the given height with the given root hash, or any node, code or candidate
+// list is missing. The records are staged in memory and written to DB only after the states are verified, so a
+// rejected snapshot leaves nothing behind
+func (sf *factory) LoadSnapshot(r io.Reader, height uint64, root hash.Hash32B) error {
+	if sf.readOnly {
+		return errors.Wrapf(ErrReadOnlyView, "view on height %d", sf.currentChainHeight)
+	}
+	if _, err := sf.Height(); err == nil {
+		return errors.New("cannot load state snapshot into a factory with committed states")
+	}
+	br := bufio.NewReader(r)
+	kind, header, err := readSnapshotRecord(br)
+	if err != nil {
+		return err
+	}
+	if kind != snapshotHeader || len(header) != 8+len(root) {
+		return errors.Wrap(ErrInvalidSnapshot, "snapshot does not start with header")
+	}
+	if byteutil.BytesToUint64(header[:8]) != height || byteutil.BytesTo32B(header[8:]) != root {
+		return errors.Wrapf(
+			ErrInvalidSnapshot,
+			"snapshot of root %x on height %d does not match root %x on height %d",
+			header[8:],
+			byteutil.BytesToUint64(header[:8]),
+			root,
+			height,
+		)
+	}
+
+	staged := db.NewCachedKVStore(sf.dao.KVStore())
+	var candidatesBytes []byte
+	for {
+		kind, payload, err := readSnapshotRecord(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case snapshotAccountNode, snapshotContractNode:
+			namespace := trie.AccountKVNameSpace
+			if kind == snapshotContractNode {
+				namespace = trie.ContractKVNameSpace
+			}
+			// the node is stored by its hash, so any change of the node is caught when verifying the states
+			key, err := trie.NodeHash(payload)
+			if err != nil {
+				return errors.Wrapf(ErrInvalidSnapshot, "invalid node: %v", err)
+			}
+			if err := staged.Put(namespace, key[:], payload); err != nil {
+				return errors.Wrapf(err, "failed to put node %x", key)
+			}
+		case snapshotCode:
+			// the code is stored by its hash as well
+			codeHash := hash.Hash256b(payload)
+			if err := staged.Put(trie.CodeKVNameSpace, codeHash, payload); err != nil {
+				return errors.Wrapf(err, "failed to put code %x", codeHash)
+			}
+		case snapshotCandidates:
+			candidatesBytes = payload
+		default:
+			return errors.Wrapf(ErrInvalidSnapshot, "unknown record type %d", kind)
+		}
+	}
+	if candidatesBytes == nil {
+		return errors.Wrap(ErrInvalidSnapshot, "missing candidates")
+	}
+	candidates, err := Deserialize(candidatesBytes)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSnapshot, "invalid candidates: %v", err)
+	}
+	if err := verifySnapshot(staged, root); err != nil {
+		return errors.Wrapf(ErrInvalidSnapshot, "incomplete states: %v", err)
+	}
+
+	// the states are complete, persist them together with the root hash and height
+	if err := staged.Put(trie.AccountKVNameSpace, []byte(AccountTrieRootKey), root[:]); err != nil {
+		return errors.Wrap(err, "failed to store accountTrie's root hash")
+	}
+	if err := staged.Put(trie.AccountRootKVNameSpace, byteutil.Uint64ToBytes(height), root[:]); err != nil {
+		return errors.Wrapf(err, "failed to store accountTrie's root hash on height %d", height)
+	}
+	if err := staged.Put(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(height), candidatesBytes); err != nil {
+		return errors.Wrapf(err, "failed to store candidates on height %d", height)
+	}
+	if err := staged.Put(trie.AccountKVNameSpace, []byte(CurrentHeightKey), byteutil.Uint64ToBytes(height)); err != nil {
+		return errors.Wrap(err, "failed to store accountTrie's current height")
+	}
+	if err := staged.Commit(); err != nil {
+		return errors.Wrap(err, "failed to write snapshot to DB")
+	}
+	tr, err := trie.NewTrieSharedDB(sf.dao, trie.AccountKVNameSpace, root)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate accountTrie from snapshot")
+	}
+	if err := tr.Start(context.Background()); err != nil {
+		return errors.Wrap(err, "failed to load accountTrie from snapshot")
+	}
+	if sf.cachedCandidates, err = CandidatesToMap(candidates); err != nil {
+		return errors.Wrap(err, "failed to convert candidate list to map of cached candidates")
+	}
+	sf.accountTrie = tr
+	sf.currentChainHeight = height
+	return nil
+}
+
+// verifySnapshot checks all the nodes and code reachable from the root exist in DB
+func verifySnapshot(kv db.KVStore, root hash.Hash32B) error {
+	accountNodes := make(map[hash.Hash32B]bool)
+	contractNodes := make(map[hash.Hash32B]bool)
+	return trie.Walk(kv, trie.AccountKVNameSpace, root, func(h hash.Hash32B) bool {
+		if accountNodes[h] {
+			return false
+		}
+		accountNodes[h] = true
+		return true
+	}, func(value []byte) error {
+		state, err := bytesToState(value)
+		if err != nil {
+			return errors.Wrap(err, "failed to decode account state")
+		}
+		if state.CodeHash != nil {
+			if _, err := kv.Get(trie.CodeKVNameSpace, state.CodeHash); err != nil {
+				return errors.Wrapf(err, "failed to get code %x", state.CodeHash)
+			}
+		}
+		if state.Root == hash.ZeroHash32B || state.Root == trie.EmptyRoot {
+			return nil
+		}
+		return trie.Walk(kv, trie.ContractKVNameSpace, state.Root, func(h hash.Hash32B) bool {
+			if contractNodes[h] {
+				return false
+			}
+			contractNodes[h] = true
+			return true
+		}, nil)
+	})
+}
+
+func writeSnapshotRecord(w io.Writer, kind byte, payload []byte) error {
+	header := make([]byte, 5)
+	header[0] = kind
+	binary.BigEndian.PutUint32(header[1:], uint32(len(payload)))
+	if _, err := w.Write(header); err != nil {
+		return errors.Wrap(err, "failed to write snapshot record")
+	}
+	if _, err := w.Write(payload); err != nil {
+		return errors.Wrap(err, "failed to write snapshot record")
+	}
+	return nil
+}
+
+// readSnapshotRecord returns io.EOF if there is no more record
+func readSnapshotRecord(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.EOF {
+			return 0, nil, err
+		}
+		return 0, nil, errors.Wrap(ErrInvalidSnapshot, "failed to read record header")
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > maxSnapshotRecordSize {
+		return 0, nil, errors.Wrapf(ErrInvalidSnapshot, "record size %d exceeds limit", size)
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, errors.Wrap(ErrInvalidSnapshot, "failed to read record")
+	}
+	return header[0], payload, nil
+}
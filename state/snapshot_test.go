@@ -0,0 +1,127 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package state
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iotexproject/iotex-core/iotxaddress"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
+	"github.com/iotexproject/iotex-core/trie"
+)
+
+func TestSnapshot(t *testing.T) {
+	require := require.New(t)
+
+	sf, err := NewFactory(cfg, InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf.Start(context.Background()))
+	a, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(a.RawAddress, 100)
+	require.Nil(err)
+	c, err := iotxaddress.NewAddress(true, []byte{0xa4, 0x00, 0x00, 0x00})
+	require.Nil(err)
+	_, err = sf.LoadOrCreateState(c.RawAddress, 0)
+	require.Nil(err)
+	cHash, err := iotxaddress.GetPubkeyHash(c.RawAddress)
+	require.Nil(err)
+	contract := byteutil.BytesTo20B(cHash)
+	code := []byte("contract code")
+	codeHash := byteutil.BytesTo32B(hash.Hash256b(code))
+	k := byteutil.BytesTo32B(hash.Hash256b([]byte("key")))
+	v1 := byteutil.BytesTo32B(hash.Hash256b([]byte("value1")))
+	v2 := byteutil.BytesTo32B(hash.Hash256b([]byte("value2")))
+	require.Nil(sf.SetCode(contract, code))
+	require.Nil(sf.SetContractState(contract, k, v1))
+	require.Nil(sf.CommitStateChanges(0, nil, nil, nil, nil))
+	root0 := sf.RootHash()
+	state, err := sf.LoadOrCreateState(a.RawAddress, 0)
+	require.Nil(err)
+	state.Balance = big.NewInt(50)
+	require.Nil(sf.SetContractState(contract, k, v2))
+	require.Nil(sf.CommitStateChanges(1, nil, nil, nil, nil))
+
+	// snapshot on height 0
+	var buf bytes.Buffer
+	require.Nil(sf.Snapshot(0, &buf))
+	_, err = sf.RootHashByHeight(2)
+	require.NotNil(err)
+	require.NotNil(sf.Snapshot(2, &bytes.Buffer{}))
+	// the factory has committed states
+	require.NotNil(sf.LoadSnapshot(bytes.NewReader(buf.Bytes()), 0, root0))
+
+	sf2, err := NewFactory(cfg, InMemTrieOption())
+	require.Nil(err)
+	require.Nil(sf2.Start(context.Background()))
+	// mismatched height or root
+	err = sf2.LoadSnapshot(bytes.NewReader(buf.Bytes()), 1, root0)
+	require.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	err = sf2.LoadSnapshot(bytes.NewReader(buf.Bytes()), 0, sf.RootHash())
+	require.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	// truncated snapshot
+	err = sf2.LoadSnapshot(bytes.NewReader(buf.Bytes()[:buf.Len()-1]), 0, root0)
+	require.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	_, err = sf2.Height()
+	require.NotNil(err)
+	// snapshot missing nodes, nothing of it is written to DB
+	rootNode, err := sf.(*factory).dao.Get(trie.AccountKVNameSpace, root0[:])
+	require.Nil(err)
+	candidatesBytes, err := sf.(*factory).dao.Get(trie.CandidateKVNameSpace, byteutil.Uint64ToBytes(0))
+	require.Nil(err)
+	var incomplete bytes.Buffer
+	require.Nil(writeSnapshotRecord(&incomplete, snapshotHeader, append(byteutil.Uint64ToBytes(0), root0[:]...)))
+	require.Nil(writeSnapshotRecord(&incomplete, snapshotAccountNode, rootNode))
+	require.Nil(writeSnapshotRecord(&incomplete, snapshotCandidates, candidatesBytes))
+	err = sf2.LoadSnapshot(bytes.NewReader(incomplete.Bytes()), 0, root0)
+	require.Equal(ErrInvalidSnapshot, errors.Cause(err))
+	_, err = sf2.(*factory).dao.Get(trie.AccountKVNameSpace, root0[:])
+	require.NotNil(err)
+
+	require.Nil(sf2.LoadSnapshot(bytes.NewReader(buf.Bytes()), 0, root0))
+	require.Equal(root0, sf2.RootHash())
+	height, err := sf2.Height()
+	require.Nil(err)
+	require.Equal(uint64(0), height)
+	root, err := sf2.RootHashByHeight(0)
+	require.Nil(err)
+	require.Equal(root0, root)
+	balance, err := sf2.Balance(a.RawAddress)
+	require.Nil(err)
+	require.Equal(big.NewInt(100), balance)
+	v, err := sf2.GetContractState(contract, k)
+	require.Nil(err)
+	require.Equal(v1, v)
+	h, err := sf2.GetCodeHash(contract)
+	require.Nil(err)
+	require.Equal(codeHash, h)
+	c2, err := sf2.GetCode(contract)
+	require.Nil(err)
+	require.Equal(code, c2)
+	_, candidates := sf.Candidates()
+	candidates2, err := sf2.CandidatesByHeight(0)
+	require.Nil(err)
+	require.Equal(len(candidates), len(candidates2))
+
+	// the factory keeps committing on top of the snapshot, and reaches the same root
+	state, err = sf2.LoadOrCreateState(a.RawAddress, 0)
+	require.Nil(err)
+	state.Balance = big.NewInt(50)
+	require.Nil(sf2.SetContractState(contract, k, v2))
+	require.Nil(sf2.CommitStateChanges(1, nil, nil, nil, nil))
+	require.Equal(sf.RootHash(), sf2.RootHash())
+
+	require.Nil(sf.Stop(context.Background()))
+	require.Nil(sf2.Stop(context.Background()))
+}
@@ -26,6 +26,8 @@ type State struct {
 	VotingWeight *big.Int
 	Votee        string
 	Voters       map[string]*big.Int
+	// JailedUntil is the height until which the account, as a candidate, is jailed for its equivocation, exclusive
+	JailedUntil uint64
 }
 
 func stateToBytes(s *State) ([]byte, error) {
@@ -95,6 +95,30 @@ func (mr *MockActPoolMockRecorder) AddExecution(execution interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddExecution", reflect.TypeOf((*MockActPool)(nil).AddExecution), execution)
 }
 
+// AddEvidence mocks base method
+func (m *MockActPool) AddEvidence(evidence *action.Evidence) error {
+	ret := m.ctrl.Call(m, "AddEvidence", evidence)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvidence indicates an expected call of AddEvidence
+func (mr *MockActPoolMockRecorder) AddEvidence(evidence interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvidence", reflect.TypeOf((*MockActPool)(nil).AddEvidence), evidence)
+}
+
+// PickEvidences mocks base method
+func (m *MockActPool) PickEvidences() []*action.Evidence {
+	ret := m.ctrl.Call(m, "PickEvidences")
+	ret0, _ := ret[0].([]*action.Evidence)
+	return ret0
+}
+
+// PickEvidences indicates an expected call of PickEvidences
+func (mr *MockActPoolMockRecorder) PickEvidences() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PickEvidences", reflect.TypeOf((*MockActPool)(nil).PickEvidences))
+}
+
 // GetPendingNonce mocks base method
 func (m *MockActPool) GetPendingNonce(addr string) (uint64, error) {
 	ret := m.ctrl.Call(m, "GetPendingNonce", addr)
@@ -156,3 +180,27 @@ func (m *MockActPool) GetCapacity() uint64 {
 func (mr *MockActPoolMockRecorder) GetCapacity() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapacity", reflect.TypeOf((*MockActPool)(nil).GetCapacity))
 }
+
+// SubscribeNewAction mocks base method
+func (m *MockActPool) SubscribeNewAction(ch chan *proto.ActionPb) error {
+	ret := m.ctrl.Call(m, "SubscribeNewAction", ch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeNewAction indicates an expected call of SubscribeNewAction
+func (mr *MockActPoolMockRecorder) SubscribeNewAction(ch interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeNewAction", reflect.TypeOf((*MockActPool)(nil).SubscribeNewAction), ch)
+}
+
+// UnsubscribeNewAction mocks base method
+func (m *MockActPool) UnsubscribeNewAction(ch chan *proto.ActionPb) error {
+	ret := m.ctrl.Call(m, "UnsubscribeNewAction", ch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeNewAction indicates an expected call of UnsubscribeNewAction
+func (mr *MockActPoolMockRecorder) UnsubscribeNewAction(ch interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeNewAction", reflect.TypeOf((*MockActPool)(nil).UnsubscribeNewAction), ch)
+}
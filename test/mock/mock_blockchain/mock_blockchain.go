@@ -9,6 +9,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	blockchain "github.com/iotexproject/iotex-core/blockchain"
 	action "github.com/iotexproject/iotex-core/blockchain/action"
+	config "github.com/iotexproject/iotex-core/config"
 	iotxaddress "github.com/iotexproject/iotex-core/iotxaddress"
 	hash "github.com/iotexproject/iotex-core/pkg/hash"
 	state "github.com/iotexproject/iotex-core/state"
@@ -103,15 +104,15 @@ func (mr *MockBlockchainMockRecorder) CreateState(addr, init interface{}) *gomoc
 }
 
 // CommitStateChanges mocks base method
-func (m *MockBlockchain) CommitStateChanges(chainHeight uint64, tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution) error {
-	ret := m.ctrl.Call(m, "CommitStateChanges", chainHeight, tsf, vote, executions)
+func (m *MockBlockchain) CommitStateChanges(chainHeight uint64, tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution, evidences []*action.Evidence) error {
+	ret := m.ctrl.Call(m, "CommitStateChanges", chainHeight, tsf, vote, executions, evidences)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CommitStateChanges indicates an expected call of CommitStateChanges
-func (mr *MockBlockchainMockRecorder) CommitStateChanges(chainHeight, tsf, vote, executions interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitStateChanges", reflect.TypeOf((*MockBlockchain)(nil).CommitStateChanges), chainHeight, tsf, vote, executions)
+func (mr *MockBlockchainMockRecorder) CommitStateChanges(chainHeight, tsf, vote, executions, evidences interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitStateChanges", reflect.TypeOf((*MockBlockchain)(nil).CommitStateChanges), chainHeight, tsf, vote, executions, evidences)
 }
 
 // Candidates mocks base method
@@ -400,6 +401,32 @@ func (mr *MockBlockchainMockRecorder) GetReceiptByExecutionHash(h interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptByExecutionHash", reflect.TypeOf((*MockBlockchain)(nil).GetReceiptByExecutionHash), h)
 }
 
+// GetReceiptByActionHash mocks base method
+func (m *MockBlockchain) GetReceiptByActionHash(h hash.Hash32B) (*blockchain.Receipt, error) {
+	ret := m.ctrl.Call(m, "GetReceiptByActionHash", h)
+	ret0, _ := ret[0].(*blockchain.Receipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiptByActionHash indicates an expected call of GetReceiptByActionHash
+func (mr *MockBlockchainMockRecorder) GetReceiptByActionHash(h interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptByActionHash", reflect.TypeOf((*MockBlockchain)(nil).GetReceiptByActionHash), h)
+}
+
+// GetLogs mocks base method
+func (m *MockBlockchain) GetLogs(filter *blockchain.LogFilter, offset uint64, limit uint64) ([]*blockchain.Log, error) {
+	ret := m.ctrl.Call(m, "GetLogs", filter, offset, limit)
+	ret0, _ := ret[0].([]*blockchain.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogs indicates an expected call of GetLogs
+func (mr *MockBlockchainMockRecorder) GetLogs(filter, offset, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogs", reflect.TypeOf((*MockBlockchain)(nil).GetLogs), filter, offset, limit)
+}
+
 // GetFactory mocks base method
 func (m *MockBlockchain) GetFactory() state.Factory {
 	ret := m.ctrl.Call(m, "GetFactory")
@@ -449,17 +476,54 @@ func (mr *MockBlockchainMockRecorder) StateByAddr(address interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateByAddr", reflect.TypeOf((*MockBlockchain)(nil).StateByAddr), address)
 }
 
+// StateByAddrAtHeight mocks base method
+func (m *MockBlockchain) StateByAddrAtHeight(address string, height uint64) (*state.State, error) {
+	ret := m.ctrl.Call(m, "StateByAddrAtHeight", address, height)
+	ret0, _ := ret[0].(*state.State)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StateByAddrAtHeight indicates an expected call of StateByAddrAtHeight
+func (mr *MockBlockchainMockRecorder) StateByAddrAtHeight(address, height interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StateByAddrAtHeight", reflect.TypeOf((*MockBlockchain)(nil).StateByAddrAtHeight), address, height)
+}
+
+// Upgrades mocks base method
+func (m *MockBlockchain) Upgrades() config.Upgrades {
+	ret := m.ctrl.Call(m, "Upgrades")
+	ret0, _ := ret[0].(config.Upgrades)
+	return ret0
+}
+
+// Upgrades indicates an expected call of Upgrades
+func (mr *MockBlockchainMockRecorder) Upgrades() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upgrades", reflect.TypeOf((*MockBlockchain)(nil).Upgrades))
+}
+
+// Genesis mocks base method
+func (m *MockBlockchain) Genesis() *blockchain.Genesis {
+	ret := m.ctrl.Call(m, "Genesis")
+	ret0, _ := ret[0].(*blockchain.Genesis)
+	return ret0
+}
+
+// Genesis indicates an expected call of Genesis
+func (mr *MockBlockchainMockRecorder) Genesis() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Genesis", reflect.TypeOf((*MockBlockchain)(nil).Genesis))
+}
+
 // MintNewBlock mocks base method
-func (m *MockBlockchain) MintNewBlock(tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution, address *iotxaddress.Address, data string) (*blockchain.Block, error) {
-	ret := m.ctrl.Call(m, "MintNewBlock", tsf, vote, executions, address, data)
+func (m *MockBlockchain) MintNewBlock(tsf []*action.Transfer, vote []*action.Vote, executions []*action.Execution, evidences []*action.Evidence, address *iotxaddress.Address, data string) (*blockchain.Block, error) {
+	ret := m.ctrl.Call(m, "MintNewBlock", tsf, vote, executions, evidences, address, data)
 	ret0, _ := ret[0].(*blockchain.Block)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // MintNewBlock indicates an expected call of MintNewBlock
-func (mr *MockBlockchainMockRecorder) MintNewBlock(tsf, vote, executions, address, data interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MintNewBlock", reflect.TypeOf((*MockBlockchain)(nil).MintNewBlock), tsf, vote, executions, address, data)
+func (mr *MockBlockchainMockRecorder) MintNewBlock(tsf, vote, executions, evidences, address, data interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MintNewBlock", reflect.TypeOf((*MockBlockchain)(nil).MintNewBlock), tsf, vote, executions, evidences, address, data)
 }
 
 // MintNewDKGBlock mocks base method
@@ -511,6 +575,18 @@ func (mr *MockBlockchainMockRecorder) ValidateBlock(blk interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateBlock", reflect.TypeOf((*MockBlockchain)(nil).ValidateBlock), blk)
 }
 
+// ValidateProposal mocks base method
+func (m *MockBlockchain) ValidateProposal(blk *blockchain.Block) error {
+	ret := m.ctrl.Call(m, "ValidateProposal", blk)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateProposal indicates an expected call of ValidateProposal
+func (mr *MockBlockchainMockRecorder) ValidateProposal(blk interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateProposal", reflect.TypeOf((*MockBlockchain)(nil).ValidateProposal), blk)
+}
+
 // Validator mocks base method
 func (m *MockBlockchain) Validator() blockchain.Validator {
 	ret := m.ctrl.Call(m, "Validator")
@@ -545,3 +621,67 @@ func (m *MockBlockchain) ExecuteContractRead(arg0 *action.Execution) ([]byte, er
 func (mr *MockBlockchainMockRecorder) ExecuteContractRead(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteContractRead", reflect.TypeOf((*MockBlockchain)(nil).ExecuteContractRead), arg0)
 }
+
+// ExecuteContractReadAtHeight mocks base method
+func (m *MockBlockchain) ExecuteContractReadAtHeight(arg0 *action.Execution, arg1 uint64) ([]byte, error) {
+	ret := m.ctrl.Call(m, "ExecuteContractReadAtHeight", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteContractReadAtHeight indicates an expected call of ExecuteContractReadAtHeight
+func (mr *MockBlockchainMockRecorder) ExecuteContractReadAtHeight(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteContractReadAtHeight", reflect.TypeOf((*MockBlockchain)(nil).ExecuteContractReadAtHeight), arg0, arg1)
+}
+
+// EstimateGasForExecution mocks base method
+func (m *MockBlockchain) EstimateGasForExecution(arg0 *action.Execution) (uint64, string, error) {
+	ret := m.ctrl.Call(m, "EstimateGasForExecution", arg0)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// EstimateGasForExecution indicates an expected call of EstimateGasForExecution
+func (mr *MockBlockchainMockRecorder) EstimateGasForExecution(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstimateGasForExecution", reflect.TypeOf((*MockBlockchain)(nil).EstimateGasForExecution), arg0)
+}
+
+// TraceExecution mocks base method
+func (m *MockBlockchain) TraceExecution(h hash.Hash32B) (*blockchain.ExecutionTrace, error) {
+	ret := m.ctrl.Call(m, "TraceExecution", h)
+	ret0, _ := ret[0].(*blockchain.ExecutionTrace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TraceExecution indicates an expected call of TraceExecution
+func (mr *MockBlockchainMockRecorder) TraceExecution(h interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TraceExecution", reflect.TypeOf((*MockBlockchain)(nil).TraceExecution), h)
+}
+
+// SubscribeBlockCreation mocks base method
+func (m *MockBlockchain) SubscribeBlockCreation(ch chan *blockchain.Block) error {
+	ret := m.ctrl.Call(m, "SubscribeBlockCreation", ch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeBlockCreation indicates an expected call of SubscribeBlockCreation
+func (mr *MockBlockchainMockRecorder) SubscribeBlockCreation(ch interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeBlockCreation", reflect.TypeOf((*MockBlockchain)(nil).SubscribeBlockCreation), ch)
+}
+
+// UnsubscribeBlockCreation mocks base method
+func (m *MockBlockchain) UnsubscribeBlockCreation(ch chan *blockchain.Block) error {
+	ret := m.ctrl.Call(m, "UnsubscribeBlockCreation", ch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeBlockCreation indicates an expected call of UnsubscribeBlockCreation
+func (mr *MockBlockchainMockRecorder) UnsubscribeBlockCreation(ch interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeBlockCreation", reflect.TypeOf((*MockBlockchain)(nil).UnsubscribeBlockCreation), ch)
+}
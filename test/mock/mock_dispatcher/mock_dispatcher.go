@@ -60,8 +60,10 @@ func (mr *MockDispatcherMockRecorder) Stop(arg0 interface{}) *gomock.Call {
 }
 
 // HandleBroadcast mocks base method
-func (m *MockDispatcher) HandleBroadcast(arg0 proto.Message, arg1 chan bool) {
-	m.ctrl.Call(m, "HandleBroadcast", arg0, arg1)
+func (m *MockDispatcher) HandleBroadcast(arg0 proto.Message, arg1 chan bool) error {
+	ret := m.ctrl.Call(m, "HandleBroadcast", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // HandleBroadcast indicates an expected call of HandleBroadcast
@@ -10,6 +10,7 @@ import (
 	action "github.com/iotexproject/iotex-core/blockchain/action"
 	hash "github.com/iotexproject/iotex-core/pkg/hash"
 	state "github.com/iotexproject/iotex-core/state"
+	io "io"
 	big "math/big"
 	reflect "reflect"
 )
@@ -138,6 +139,19 @@ func (mr *MockFactoryMockRecorder) RootHash() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RootHash", reflect.TypeOf((*MockFactory)(nil).RootHash))
 }
 
+// RootHashByHeight mocks base method
+func (m *MockFactory) RootHashByHeight(arg0 uint64) (hash.Hash32B, error) {
+	ret := m.ctrl.Call(m, "RootHashByHeight", arg0)
+	ret0, _ := ret[0].(hash.Hash32B)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RootHashByHeight indicates an expected call of RootHashByHeight
+func (mr *MockFactoryMockRecorder) RootHashByHeight(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RootHashByHeight", reflect.TypeOf((*MockFactory)(nil).RootHashByHeight), arg0)
+}
+
 // Height mocks base method
 func (m *MockFactory) Height() (uint64, error) {
 	ret := m.ctrl.Call(m, "Height")
@@ -151,16 +165,42 @@ func (mr *MockFactoryMockRecorder) Height() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Height", reflect.TypeOf((*MockFactory)(nil).Height))
 }
 
+// ViewAtHeight mocks base method
+func (m *MockFactory) ViewAtHeight(arg0 uint64) (state.Factory, error) {
+	ret := m.ctrl.Call(m, "ViewAtHeight", arg0)
+	ret0, _ := ret[0].(state.Factory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewAtHeight indicates an expected call of ViewAtHeight
+func (mr *MockFactoryMockRecorder) ViewAtHeight(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewAtHeight", reflect.TypeOf((*MockFactory)(nil).ViewAtHeight), arg0)
+}
+
+// Fork mocks base method
+func (m *MockFactory) Fork(arg0 uint64) (state.Factory, error) {
+	ret := m.ctrl.Call(m, "Fork", arg0)
+	ret0, _ := ret[0].(state.Factory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fork indicates an expected call of Fork
+func (mr *MockFactoryMockRecorder) Fork(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fork", reflect.TypeOf((*MockFactory)(nil).Fork), arg0)
+}
+
 // CommitStateChanges mocks base method
-func (m *MockFactory) CommitStateChanges(arg0 uint64, arg1 []*action.Transfer, arg2 []*action.Vote, arg3 []*action.Execution) error {
-	ret := m.ctrl.Call(m, "CommitStateChanges", arg0, arg1, arg2, arg3)
+func (m *MockFactory) CommitStateChanges(arg0 uint64, arg1 []*action.Transfer, arg2 []*action.Vote, arg3 []*action.Execution, arg4 []*action.Evidence) error {
+	ret := m.ctrl.Call(m, "CommitStateChanges", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CommitStateChanges indicates an expected call of CommitStateChanges
-func (mr *MockFactoryMockRecorder) CommitStateChanges(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitStateChanges", reflect.TypeOf((*MockFactory)(nil).CommitStateChanges), arg0, arg1, arg2, arg3)
+func (mr *MockFactoryMockRecorder) CommitStateChanges(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitStateChanges", reflect.TypeOf((*MockFactory)(nil).CommitStateChanges), arg0, arg1, arg2, arg3, arg4)
 }
 
 // GetCodeHash mocks base method
@@ -226,6 +266,44 @@ func (mr *MockFactoryMockRecorder) SetContractState(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetContractState", reflect.TypeOf((*MockFactory)(nil).SetContractState), arg0, arg1, arg2)
 }
 
+// DeleteContract mocks base method
+func (m *MockFactory) DeleteContract(arg0 hash.AddrHash) error {
+	ret := m.ctrl.Call(m, "DeleteContract", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteContract indicates an expected call of DeleteContract
+func (mr *MockFactoryMockRecorder) DeleteContract(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteContract", reflect.TypeOf((*MockFactory)(nil).DeleteContract), arg0)
+}
+
+// AccountProof mocks base method
+func (m *MockFactory) AccountProof(arg0 hash.AddrHash) ([][]byte, error) {
+	ret := m.ctrl.Call(m, "AccountProof", arg0)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountProof indicates an expected call of AccountProof
+func (mr *MockFactoryMockRecorder) AccountProof(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountProof", reflect.TypeOf((*MockFactory)(nil).AccountProof), arg0)
+}
+
+// StorageProof mocks base method
+func (m *MockFactory) StorageProof(arg0 hash.AddrHash, arg1 hash.Hash32B) ([][]byte, error) {
+	ret := m.ctrl.Call(m, "StorageProof", arg0, arg1)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageProof indicates an expected call of StorageProof
+func (mr *MockFactoryMockRecorder) StorageProof(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageProof", reflect.TypeOf((*MockFactory)(nil).StorageProof), arg0, arg1)
+}
+
 // Candidates mocks base method
 func (m *MockFactory) Candidates() (uint64, []*state.Candidate) {
 	ret := m.ctrl.Call(m, "Candidates")
@@ -251,3 +329,27 @@ func (m *MockFactory) CandidatesByHeight(arg0 uint64) ([]*state.Candidate, error
 func (mr *MockFactoryMockRecorder) CandidatesByHeight(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CandidatesByHeight", reflect.TypeOf((*MockFactory)(nil).CandidatesByHeight), arg0)
 }
+
+// Snapshot mocks base method
+func (m *MockFactory) Snapshot(arg0 uint64, arg1 io.Writer) error {
+	ret := m.ctrl.Call(m, "Snapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot
+func (mr *MockFactoryMockRecorder) Snapshot(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockFactory)(nil).Snapshot), arg0, arg1)
+}
+
+// LoadSnapshot mocks base method
+func (m *MockFactory) LoadSnapshot(arg0 io.Reader, arg1 uint64, arg2 hash.Hash32B) error {
+	ret := m.ctrl.Call(m, "LoadSnapshot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadSnapshot indicates an expected call of LoadSnapshot
+func (mr *MockFactoryMockRecorder) LoadSnapshot(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSnapshot", reflect.TypeOf((*MockFactory)(nil).LoadSnapshot), arg0, arg1, arg2)
+}
@@ -131,3 +131,40 @@ func (m *MockTrie) RootHash() hash.Hash32B {
 func (mr *MockTrieMockRecorder) RootHash() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RootHash", reflect.TypeOf((*MockTrie)(nil).RootHash))
 }
+
+// Proof mocks base method
+func (m *MockTrie) Proof(arg0 []byte) ([][]byte, error) {
+	ret := m.ctrl.Call(m, "Proof", arg0)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Proof indicates an expected call of Proof
+func (mr *MockTrieMockRecorder) Proof(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Proof", reflect.TypeOf((*MockTrie)(nil).Proof), arg0)
+}
+
+// Indexed mocks base method
+func (m *MockTrie) Indexed() bool {
+	ret := m.ctrl.Call(m, "Indexed")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Indexed indicates an expected call of Indexed
+func (mr *MockTrieMockRecorder) Indexed() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Indexed", reflect.TypeOf((*MockTrie)(nil).Indexed))
+}
+
+// Reindex mocks base method
+func (m *MockTrie) Reindex(arg0 func([]byte) ([]byte, error)) error {
+	ret := m.ctrl.Call(m, "Reindex", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reindex indicates an expected call of Reindex
+func (mr *MockTrieMockRecorder) Reindex(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reindex", reflect.TypeOf((*MockTrie)(nil).Reindex), arg0)
+}
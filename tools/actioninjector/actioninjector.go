@@ -22,6 +22,7 @@ import (
 	_ "go.uber.org/automaxprocs"
 	"gopkg.in/yaml.v2"
 
+	"github.com/iotexproject/iotex-core/blockchain"
 	"github.com/iotexproject/iotex-core/blockchain/action"
 	"github.com/iotexproject/iotex-core/explorer"
 	exp "github.com/iotexproject/iotex-core/explorer/idl/explorer"
@@ -48,6 +49,8 @@ type PKPair struct {
 func main() {
 	// path of config file containing all the public/private key paris of addresses getting transfers from Creator in genesis block
 	var configPath string
+	// path of the genesis file of the chain, whose chain ID signs the actions. Default is "", the default genesis
+	var genesisPath string
 	// target address for jrpc connection. Default is "127.0.0.1:14004"
 	var addr string
 	// number of transfer injections. Default is 50
@@ -90,6 +93,7 @@ func main() {
 	var resetInterval int
 
 	flag.StringVar(&configPath, "injector-config-path", "./tools/actioninjector/gentsfaddrs.yaml", "path of config file of genesis transfer addresses")
+	flag.StringVar(&genesisPath, "genesis-path", "", "path of the genesis file of the chain")
 	flag.StringVar(&addr, "addr", "127.0.0.1:14004", "target ip:port for jrpc connection")
 	flag.IntVar(&transferNum, "transfer-num", 50, "number of transfer injections")
 	flag.IntVar(&transferGasLimit, "transfer-gas-limit", 1000000, "transfer gas limit")
@@ -114,6 +118,16 @@ func main() {
 
 	proxy := explorer.NewExplorerProxy("http://" + addr)
 
+	// Sign the actions with the chain ID of the genesis
+	chainID := blockchain.Gen.ChainID
+	if genesisPath != "" {
+		genesis, err := blockchain.LoadGenesis(genesisPath)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to start injecting actions")
+		}
+		chainID = genesis.ChainID
+	}
+
 	// Load Senders' public/private key pairs
 	addrBytes, err := ioutil.ReadFile(configPath)
 	if err != nil {
@@ -150,14 +164,14 @@ func main() {
 	if aps > 0 {
 		d := time.Duration(duration) * time.Second
 		wg := &sync.WaitGroup{}
-		injectByAps(wg, aps, counter, transferGasLimit, transferGasPrice, transferPayload, voteGasLimit, voteGasPrice,
-			contract, executionAmount, executionGasLimit, executionGasPrice, executionData, proxy, admins, delegates, d,
-			retryNum, retryInterval, resetInterval)
+		injectByAps(wg, aps, counter, chainID, transferGasLimit, transferGasPrice, transferPayload, voteGasLimit,
+			voteGasPrice, contract, executionAmount, executionGasLimit, executionGasPrice, executionData, proxy, admins,
+			delegates, d, retryNum, retryInterval, resetInterval)
 		wg.Wait()
 	} else {
 		injectByInterval(transferNum, transferGasLimit, transferGasPrice, transferPayload, voteNum, voteGasLimit,
 			voteGasPrice, executionNum, contract, executionAmount, executionGasLimit, executionGasPrice, executionData,
-			interval, counter, proxy, admins, delegates, retryNum, retryInterval)
+			interval, counter, chainID, proxy, admins, delegates, retryNum, retryInterval)
 	}
 }
 
@@ -166,6 +180,7 @@ func injectByAps(
 	wg *sync.WaitGroup,
 	aps int,
 	counter map[string]uint64,
+	chainID uint32,
 	transferGasLimit int,
 	transferGasPrice int,
 	transferPayload string,
@@ -214,15 +229,15 @@ loop:
 			switch rand := rand.Intn(3); rand {
 			case 0:
 				sender, recipient, nonce := createTransferInjection(counter, delegates)
-				go injectTransfer(wg, client, sender, recipient, nonce, uint64(transferGasLimit),
+				go injectTransfer(wg, client, chainID, sender, recipient, nonce, uint64(transferGasLimit),
 					big.NewInt(int64(transferGasPrice)), transferPayload, retryNum, retryInterval)
 			case 1:
 				sender, recipient, nonce := createVoteInjection(counter, admins, delegates)
-				go injectVote(wg, client, sender, recipient, nonce, uint64(voteGasLimit),
+				go injectVote(wg, client, chainID, sender, recipient, nonce, uint64(voteGasLimit),
 					big.NewInt(int64(voteGasPrice)), retryNum, retryInterval)
 			case 2:
 				executor, nonce := createExecutionInjection(counter, delegates)
-				go injectExecution(wg, client, executor, contract, nonce, big.NewInt(int64(executionAmount)),
+				go injectExecution(wg, client, chainID, executor, contract, nonce, big.NewInt(int64(executionAmount)),
 					uint64(executionGasLimit), big.NewInt(int64(executionGasPrice)), executionData, retryNum, retryInterval)
 			}
 		}
@@ -246,6 +261,7 @@ func injectByInterval(
 	executionData string,
 	interval int,
 	counter map[string]uint64,
+	chainID uint32,
 	client exp.Explorer,
 	admins []*iotxaddress.Address,
 	delegates []*iotxaddress.Address,
@@ -254,17 +270,17 @@ func injectByInterval(
 ) {
 	for transferNum > 0 && voteNum > 0 && executionNum > 0 {
 		sender, recipient, nonce := createTransferInjection(counter, delegates)
-		injectTransfer(nil, client, sender, recipient, nonce, uint64(transferGasLimit),
+		injectTransfer(nil, client, chainID, sender, recipient, nonce, uint64(transferGasLimit),
 			big.NewInt(int64(transferGasPrice)), transferPayload, retryNum, retryInterval)
 		time.Sleep(time.Second * time.Duration(interval))
 
 		sender, recipient, nonce = createVoteInjection(counter, admins, delegates)
-		injectVote(nil, client, sender, recipient, nonce, uint64(voteGasLimit),
+		injectVote(nil, client, chainID, sender, recipient, nonce, uint64(voteGasLimit),
 			big.NewInt(int64(voteGasPrice)), retryNum, retryInterval)
 		time.Sleep(time.Second * time.Duration(interval))
 
 		executor, nonce := createExecutionInjection(counter, delegates)
-		injectExecution(nil, client, executor, contract, nonce, big.NewInt(int64(executionAmount)),
+		injectExecution(nil, client, chainID, executor, contract, nonce, big.NewInt(int64(executionAmount)),
 			uint64(executionGasLimit), big.NewInt(int64(executionGasPrice)), executionData, retryNum, retryInterval)
 		time.Sleep(time.Second * time.Duration(interval))
 
@@ -276,12 +292,12 @@ func injectByInterval(
 	case transferNum > 0 && voteNum > 0:
 		for transferNum > 0 && voteNum > 0 {
 			sender, recipient, nonce := createTransferInjection(counter, delegates)
-			injectTransfer(nil, client, sender, recipient, nonce, uint64(transferGasLimit),
+			injectTransfer(nil, client, chainID, sender, recipient, nonce, uint64(transferGasLimit),
 				big.NewInt(int64(transferGasPrice)), transferPayload, retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 
 			sender, recipient, nonce = createVoteInjection(counter, admins, delegates)
-			injectVote(nil, client, sender, recipient, nonce, uint64(voteGasLimit),
+			injectVote(nil, client, chainID, sender, recipient, nonce, uint64(voteGasLimit),
 				big.NewInt(int64(voteGasPrice)), retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 
@@ -291,12 +307,12 @@ func injectByInterval(
 	case transferNum > 0 && executionNum > 0:
 		for transferNum > 0 && executionNum > 0 {
 			sender, recipient, nonce := createTransferInjection(counter, delegates)
-			injectTransfer(nil, client, sender, recipient, nonce, uint64(transferGasLimit),
+			injectTransfer(nil, client, chainID, sender, recipient, nonce, uint64(transferGasLimit),
 				big.NewInt(int64(transferGasPrice)), transferPayload, retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 
 			executor, nonce := createExecutionInjection(counter, delegates)
-			injectExecution(nil, client, executor, contract, nonce, big.NewInt(int64(executionAmount)),
+			injectExecution(nil, client, chainID, executor, contract, nonce, big.NewInt(int64(executionAmount)),
 				uint64(executionGasLimit), big.NewInt(int64(executionGasPrice)), executionData, retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 
@@ -306,12 +322,12 @@ func injectByInterval(
 	case voteNum > 0 && executionNum > 0:
 		for voteNum > 0 && executionNum > 0 {
 			sender, recipient, nonce := createVoteInjection(counter, admins, delegates)
-			injectVote(nil, client, sender, recipient, nonce, uint64(voteGasLimit),
+			injectVote(nil, client, chainID, sender, recipient, nonce, uint64(voteGasLimit),
 				big.NewInt(int64(voteGasPrice)), retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 
 			executor, nonce := createExecutionInjection(counter, delegates)
-			injectExecution(nil, client, executor, contract, nonce, big.NewInt(int64(executionAmount)),
+			injectExecution(nil, client, chainID, executor, contract, nonce, big.NewInt(int64(executionAmount)),
 				uint64(executionGasLimit), big.NewInt(int64(executionGasPrice)), executionData, retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 
@@ -323,7 +339,7 @@ func injectByInterval(
 	case transferNum > 0:
 		for transferNum > 0 {
 			sender, recipient, nonce := createTransferInjection(counter, delegates)
-			injectTransfer(nil, client, sender, recipient, nonce, uint64(transferGasLimit),
+			injectTransfer(nil, client, chainID, sender, recipient, nonce, uint64(transferGasLimit),
 				big.NewInt(int64(transferGasPrice)), transferPayload, retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 			transferNum--
@@ -331,7 +347,7 @@ func injectByInterval(
 	case voteNum > 0:
 		for voteNum > 0 {
 			sender, recipient, nonce := createVoteInjection(counter, admins, delegates)
-			injectVote(nil, client, sender, recipient, nonce, uint64(voteGasLimit),
+			injectVote(nil, client, chainID, sender, recipient, nonce, uint64(voteGasLimit),
 				big.NewInt(int64(voteGasPrice)), retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 			voteNum--
@@ -339,7 +355,7 @@ func injectByInterval(
 	case executionNum > 0:
 		for executionNum > 0 {
 			executor, nonce := createExecutionInjection(counter, delegates)
-			injectExecution(nil, client, executor, contract, nonce, big.NewInt(int64(executionAmount)),
+			injectExecution(nil, client, chainID, executor, contract, nonce, big.NewInt(int64(executionAmount)),
 				uint64(executionGasLimit), big.NewInt(int64(executionGasPrice)), executionData, retryNum, retryInterval)
 			time.Sleep(time.Second * time.Duration(interval))
 			executionNum--
@@ -350,6 +366,7 @@ func injectByInterval(
 func injectTransfer(
 	wg *sync.WaitGroup,
 	c exp.Explorer,
+	chainID uint32,
 	sender *iotxaddress.Address,
 	recipient *iotxaddress.Address,
 	nonce uint64,
@@ -364,7 +381,8 @@ func injectTransfer(
 		amount = int64(rand.Intn(5))
 	}
 
-	transfer, err := createSignedTransfer(sender, recipient, big.NewInt(amount), nonce, gasLimit, gasPrice, payload)
+	transfer, err := createSignedTransfer(sender, recipient, big.NewInt(amount), nonce, gasLimit, gasPrice, payload,
+		chainID)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to inject transfer")
 	}
@@ -383,6 +401,7 @@ func injectTransfer(
 		GasPrice:     tsf.GasPrice,
 		Signature:    tsf.Signature,
 		Payload:      tsf.Payload,
+		ChainID:      tsf.ChainID,
 	}
 	for i := 0; i < retryNum; i++ {
 		if _, err = c.SendTransfer(request); err == nil {
@@ -415,6 +434,7 @@ func injectTransfer(
 func injectVote(
 	wg *sync.WaitGroup,
 	c exp.Explorer,
+	chainID uint32,
 	sender *iotxaddress.Address,
 	recipient *iotxaddress.Address,
 	nonce uint64,
@@ -423,7 +443,7 @@ func injectVote(
 	retryNum int,
 	retryInterval int,
 ) {
-	vote, err := createSignedVote(sender, recipient, nonce, gasLimit, gasPrice)
+	vote, err := createSignedVote(sender, recipient, nonce, gasLimit, gasPrice, chainID)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to inject vote")
 	}
@@ -443,6 +463,7 @@ func injectVote(
 		GasLimit:    jsonVote.GasLimit,
 		GasPrice:    jsonVote.GasPrice,
 		Signature:   jsonVote.Signature,
+		ChainID:     jsonVote.ChainID,
 	}
 	for i := 0; i < retryNum; i++ {
 		if _, err = c.SendVote(request); err == nil {
@@ -471,6 +492,7 @@ func injectVote(
 func injectExecution(
 	wg *sync.WaitGroup,
 	c exp.Explorer,
+	chainID uint32,
 	executor *iotxaddress.Address,
 	contract string,
 	nonce uint64,
@@ -481,7 +503,7 @@ func injectExecution(
 	retryNum int,
 	retryInterval int,
 ) {
-	execution, err := createSignedExecution(executor, contract, nonce, amount, gasLimit, gasPrice, data)
+	execution, err := createSignedExecution(executor, contract, nonce, amount, gasLimit, gasPrice, data, chainID)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to inject execution")
 	}
@@ -557,6 +579,7 @@ func createSignedTransfer(
 	gasLimit uint64,
 	gasPrice *big.Int,
 	payload string,
+	chainID uint32,
 ) (*action.Transfer, error) {
 	transferPayload, err := hex.DecodeString(payload)
 	if err != nil {
@@ -566,6 +589,7 @@ func createSignedTransfer(
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create raw transfer")
 	}
+	rawTransfer.ChainID = chainID
 	signedTransfer, err := rawTransfer.Sign(sender)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to sign transfer %v", rawTransfer)
@@ -580,11 +604,13 @@ func createSignedVote(
 	nonce uint64,
 	gasLimit uint64,
 	gasPrice *big.Int,
+	chainID uint32,
 ) (*action.Vote, error) {
 	rawVote, err := action.NewVote(nonce, voter.RawAddress, votee.RawAddress, gasLimit, gasPrice)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create raw vote")
 	}
+	rawVote.ChainID = chainID
 	signedVote, err := rawVote.Sign(voter)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to sign vote %v", rawVote)
@@ -601,6 +627,7 @@ func createSignedExecution(
 	gasLimit uint64,
 	gasPrice *big.Int,
 	data string,
+	chainID uint32,
 ) (*action.Execution, error) {
 	executionData, err := hex.DecodeString(data)
 	if err != nil {
@@ -610,6 +637,7 @@ func createSignedExecution(
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create raw execution")
 	}
+	rawExecution.ChainID = chainID
 	signedExecution, err := rawExecution.Sign(executor)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to sign execution %v", rawExecution)
@@ -82,6 +82,7 @@ func TestActionInjector(t *testing.T) {
 	}
 
 	rand.Seed(time.Now().UnixNano())
+	chainID := svr.Blockchain().Genesis().ChainID
 
 	// Test injectByAps
 	aps := 50
@@ -100,9 +101,9 @@ func TestActionInjector(t *testing.T) {
 	executionGasLimit := 1200000
 	executionGasPrice := 10
 	executionData := "2885ad2c"
-	injectByAps(wg, aps, counter, transferGasLimit, transferGasPrice, transferPayload, voteGasLimit, voteGasPrice,
-		contract, executionAmount, executionGasLimit, executionGasPrice, executionData, client, admins, delegates, d,
-		retryNum, retryInterval, resetInterval)
+	injectByAps(wg, aps, counter, chainID, transferGasLimit, transferGasPrice, transferPayload, voteGasLimit,
+		voteGasPrice, contract, executionAmount, executionGasLimit, executionGasPrice, executionData, client, admins,
+		delegates, d, retryNum, retryInterval, resetInterval)
 	wg.Wait()
 
 	// Wait until the injected actions in APS Mode gets into the action pool
@@ -121,7 +122,7 @@ func TestActionInjector(t *testing.T) {
 	interval := 1
 	injectByInterval(transferNum, transferGasLimit, transferGasPrice, transferPayload, voteNum, voteGasLimit,
 		voteGasPrice, executionNum, contract, executionAmount, executionGasLimit, executionGasPrice, executionData,
-		interval, counter, client, admins, delegates, retryNum, retryInterval)
+		interval, counter, chainID, client, admins, delegates, retryNum, retryInterval)
 
 	// Wait until all the injected actions in Interval Mode gets into the action pool
 	err = testutil.WaitUntil(100*time.Millisecond, 5*time.Second, func() (bool, error) {
@@ -0,0 +1,136 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+// This is a tool to export the blocks of a node into an archive file, or import the blocks from an archive file, or
+// export the state snapshot of a node, which can be loaded by setting chain.snapshotPath on a new node
+// To use, run "make build" and
+//   ./bin/chainarchive -config-path=./config.yaml -mode=export -file=./blocks.arc -start=1 -end=100 -compress
+//   ./bin/chainarchive -config-path=./config.yaml -mode=import -file=./blocks.arc
+//   ./bin/chainarchive -config-path=./config.yaml -mode=snapshot -file=./state.snap -height=100
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/blockchain"
+	"github.com/iotexproject/iotex-core/config"
+	"github.com/iotexproject/iotex-core/logger"
+)
+
+func main() {
+	// export, import or snapshot
+	var mode string
+	// path of the archive file
+	var file string
+	// first height to export. Default is 1, since genesis block is created from config by every node
+	var start uint64
+	// last height to export. Default is 0, which means the tip height
+	var end uint64
+	// compress the archive with gzip when exporting
+	var compress bool
+	// height of the state snapshot. Default is 0, which means the tip height
+	var height uint64
+
+	flag.StringVar(&mode, "mode", "", "export, import or snapshot")
+	flag.StringVar(&file, "file", "", "path of the archive file")
+	flag.Uint64Var(&start, "start", 1, "first height to export")
+	flag.Uint64Var(&end, "end", 0, "last height to export, 0 means the tip height")
+	flag.BoolVar(&compress, "compress", false, "compress the archive with gzip when exporting")
+	flag.Uint64Var(&height, "height", 0, "height of the state snapshot, 0 means the tip height")
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(os.Stderr,
+			"usage: chainarchive -config-path=[string] -mode=[export|import|snapshot] -file=[string] -start=[int] -end=[int] -compress -height=[int]\n")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+	flag.Parse()
+	if len(file) == 0 {
+		flag.Usage()
+	}
+	switch mode {
+	case "export", "import", "snapshot":
+	default:
+		flag.Usage()
+	}
+
+	// run returns the error instead of exiting, so the blockchain is stopped and the files are closed by its defers
+	if err := run(mode, file, start, end, compress, height); err != nil {
+		logger.Error().Err(err).Str("mode", mode).Msg("Failed to run chainarchive.")
+		os.Exit(1)
+	}
+}
+
+func run(mode string, file string, start uint64, end uint64, compress bool, height uint64) (err error) {
+	cfg, err := config.New()
+	if err != nil {
+		return errors.Wrap(err, "failed to new config")
+	}
+	ctx := context.Background()
+	bc := blockchain.NewBlockchain(cfg, blockchain.DefaultStateFactoryOption(), blockchain.BoltDBDaoOption())
+	if bc == nil {
+		return errors.New("failed to create blockchain")
+	}
+	if err := bc.Start(ctx); err != nil {
+		return errors.Wrap(err, "failed to start blockchain")
+	}
+	defer func() {
+		if stopErr := bc.Stop(ctx); stopErr != nil {
+			logger.Error().Err(stopErr).Msg("Failed to stop blockchain.")
+		}
+	}()
+
+	var f *os.File
+	switch mode {
+	case "export":
+		if end == 0 {
+			end = bc.TipHeight()
+		}
+		if f, err = os.Create(file); err != nil {
+			return errors.Wrapf(err, "failed to create archive file %s", file)
+		}
+		defer closeFile(f, &err)
+		if err := blockchain.ExportBlocks(bc, f, start, end, compress); err != nil {
+			return errors.Wrap(err, "failed to export blocks")
+		}
+		logger.Info().Uint64("start", start).Uint64("end", end).Str("file", file).Msg("Exported blocks")
+	case "import":
+		if f, err = os.Open(file); err != nil {
+			return errors.Wrapf(err, "failed to open archive file %s", file)
+		}
+		defer closeFile(f, &err)
+		var imported uint64
+		if imported, err = blockchain.ImportBlocks(bc, f); err != nil {
+			return errors.Wrapf(err, "failed to import blocks after %d imported", imported)
+		}
+		logger.Info().Uint64("imported", imported).Uint64("height", bc.TipHeight()).Msg("Imported blocks")
+	case "snapshot":
+		if height == 0 {
+			height = bc.TipHeight()
+		}
+		if f, err = os.Create(file); err != nil {
+			return errors.Wrapf(err, "failed to create snapshot file %s", file)
+		}
+		defer closeFile(f, &err)
+		if err := blockchain.ExportSnapshot(bc, f, height); err != nil {
+			return errors.Wrap(err, "failed to export state snapshot")
+		}
+		logger.Info().Uint64("height", height).Str("file", file).Msg("Exported state snapshot")
+	}
+	return nil
+}
+
+// closeFile closes the file, and sets the error returned by run if closing fails, since the data written may be lost
+func closeFile(f *os.File, err *error) {
+	if closeErr := f.Close(); closeErr != nil && *err == nil {
+		*err = errors.Wrapf(closeErr, "failed to close file %s", f.Name())
+	}
+}
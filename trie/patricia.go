@@ -16,6 +16,7 @@ import (
 
 	"github.com/iotexproject/iotex-core/logger"
 	"github.com/iotexproject/iotex-core/pkg/hash"
+	"github.com/iotexproject/iotex-core/pkg/util/byteutil"
 )
 
 // RADIX specifies the number of unique digits in patricia
@@ -46,15 +47,17 @@ type (
 	ptrcKey []byte
 	// branch is the full node having 256 hashes for next level patricia node + hash of leaf node
 	branch struct {
-		Split bool
-		Path  [RADIX]ptrcKey
-		Value []byte
+		Split   bool
+		Path    [RADIX]ptrcKey
+		Value   []byte
+		Indexed bool // the hash commits to the index of each path
 	}
 	// leaf is squashed path + actual value (or hash of next patricia node for extension)
 	leaf struct {
-		Ext   byte // this is an extension node
-		Path  ptrcKey
-		Value []byte
+		Ext     byte // this is an extension node
+		Path    ptrcKey
+		Value   []byte
+		Indexed bool // the hash commits to the length of path and value
 	}
 )
 
@@ -88,7 +91,7 @@ func (b *branch) insert(k, v []byte, stack *list.List) error {
 		return errors.Wrapf(ErrInvalidPatricia, "branch already has path = %d", k[0])
 	}
 	// create a new leaf
-	l := leaf{0, k[1:], v}
+	l := leaf{0, k[1:], v, b.Indexed}
 	stack.PushBack(&l)
 	b.Split = true
 	return nil
@@ -152,10 +155,28 @@ func (b *branch) blob() ([]byte, []byte, error) {
 // hash return the hash of this node
 func (b *branch) hash() hash.Hash32B {
 	stream := []byte{}
+	if !b.Indexed {
+		// legacy hash of the paths concatenated without their index, which is ambiguous
+		for i := 0; i < RADIX; i++ {
+			stream = append(stream, b.Path[i]...)
+		}
+		stream = append(stream, b.Value...)
+		return blake2b.Sum256(stream)
+	}
+	var nb uint16
 	for i := 0; i < RADIX; i++ {
-		stream = append(stream, b.Path[i]...)
+		if len(b.Path[i]) > 0 {
+			nb++
+			stream = append(stream, byte(i))
+			stream = appendWithLen(stream, b.Path[i])
+		}
 	}
-	stream = append(stream, b.Value...)
+	if nb == 0 && len(b.Value) == 0 {
+		// the empty branch hashes to EmptyRoot either way
+		return blake2b.Sum256(stream)
+	}
+	stream = append([]byte{2, byte(nb >> 8), byte(nb)}, stream...)
+	stream = appendWithLen(stream, b.Value)
 	return blake2b.Sum256(stream)
 }
 
@@ -238,7 +259,7 @@ func (l *leaf) insert(k, v []byte, stack *list.List) error {
 		// new E <P[:match]> -> top of split
 		//======================================
 		if match > 0 {
-			e := leaf{1, l.Path[:match], hash[:]}
+			e := leaf{1, l.Path[:match], hash[:], l.Indexed}
 			stack.PushFront(&e)
 			hashe := e.hash()
 			logger.Debug().Hex("topE", hashe[:8]).Hex("path", l.Path[:match]).Msg("splitE")
@@ -248,14 +269,14 @@ func (l *leaf) insert(k, v []byte, stack *list.List) error {
 		return nil
 	}
 	// add 2 leaf, l1 is current node, l2 for new <key, value>
-	l1 := leaf{0, l.Path[match+1:], l.Value}
+	l1 := leaf{0, l.Path[match+1:], l.Value, l.Indexed}
 	hashl1 := l1.hash()
 	logger.Debug().Hex("currL", hashl1[:8]).Hex("path", l.Path[match+1:]).Msg("splitL")
-	l2 := leaf{0, k[match+1:], v}
+	l2 := leaf{0, k[match+1:], v, l.Indexed}
 	hashl2 := l2.hash()
 	logger.Debug().Hex("newL", hashl2[:8]).Hex("path", k[match+1:]).Msg("splitL")
 	// add 1 branch to link 2 new leaf
-	b := branch{}
+	b := branch{Indexed: l.Indexed}
 	b.Path[l.Path[match]] = hashl1[:]
 	b.Path[k[match]] = hashl2[:]
 	stack.PushBack(&b)
@@ -264,7 +285,7 @@ func (l *leaf) insert(k, v []byte, stack *list.List) error {
 	// if there's matching part, add 1 ext leading to new branch
 	if match > 0 {
 		hashb := b.hash()
-		e := leaf{1, k[:match], hashb[:]}
+		e := leaf{1, k[:match], hashb[:], l.Indexed}
 		stack.PushFront(&e)
 		hashe := e.hash()
 		logger.Debug().Hex("topE", hashe[:8]).Hex("path", l.Path[:match]).Msg("splitL")
@@ -333,8 +354,14 @@ func (l *leaf) blob() ([]byte, []byte, error) {
 
 // hash return the hash of this node
 func (l *leaf) hash() hash.Hash32B {
-	stream := append([]byte{l.Ext}, l.Path...)
-	stream = append(stream, l.Value...)
+	if !l.Indexed {
+		// legacy hash of the path and value concatenated, which is ambiguous
+		stream := append([]byte{l.Ext}, l.Path...)
+		stream = append(stream, l.Value...)
+		return blake2b.Sum256(stream)
+	}
+	stream := appendWithLen([]byte{l.Ext}, l.Path)
+	stream = appendWithLen(stream, l.Value)
 	return blake2b.Sum256(stream)
 }
 
@@ -371,11 +398,11 @@ func (l *leaf) split(match int, k, v []byte, stack *list.List) error {
 	divPath := l.Path[match:]
 	logger.Debug().Hex("curr key", divPath).Msg("diverge")
 	// add leaf for new <k, v>
-	l1 := leaf{0, k[1:], v}
+	l1 := leaf{0, k[1:], v, l.Indexed}
 	hashl := l1.hash()
 	logger.Debug().Hex("newL", hashl[:8]).Hex("path", k[1:]).Msg("splitE")
 	// add 1 branch to link new leaf and current ext (which may split as below)
-	b := branch{}
+	b := branch{Indexed: l.Indexed}
 	b.Path[k[0]] = hashl[:]
 	switch len(divPath) {
 	case 1:
@@ -383,7 +410,7 @@ func (l *leaf) split(match int, k, v []byte, stack *list.List) error {
 		logger.Debug().Hex("currL", hashl[:8]).Hex("path", divPath[0:1]).Msg("splitE")
 	default:
 		// add 1 ext to split current ext
-		e := leaf{1, divPath[1:], l.Value}
+		e := leaf{1, divPath[1:], l.Value, l.Indexed}
 		hashe := e.hash()
 		logger.Debug().Hex("currE", hashe[:8]).Hex("k", divPath[1:]).Hex("v", l.Value).Msg("splitE")
 		// link new leaf and current ext (which becomes e)
@@ -399,3 +426,9 @@ func (l *leaf) split(match int, k, v []byte, stack *list.List) error {
 	stack.PushBack(&l1)
 	return nil
 }
+
+// appendWithLen appends the length of b followed by b to the stream, so the boundary of b is committed in the hash
+func appendWithLen(stream, b []byte) []byte {
+	stream = append(stream, byteutil.Uint32ToBytes(uint32(len(b)))...)
+	return append(stream, b...)
+}
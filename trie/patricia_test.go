@@ -57,7 +57,7 @@ func TestPatricia(t *testing.T) {
 	assert.Equal(byte(6), b1.Value[1])
 	assert.Equal(454, len(stream))
 
-	e := leaf{1, nil, make([]byte, hash.HashSize)}
+	e := leaf{1, nil, make([]byte, hash.HashSize), false}
 	e.Path = []byte{2, 3, 5, 7}
 	copy(e.Value, hash1)
 	stream, err = e.serialize()
@@ -111,7 +111,7 @@ func TestDescend(t *testing.T) {
 	assert.Nil(err)
 
 	// testing ext
-	e := leaf{1, nil, make([]byte, hash.HashSize)}
+	e := leaf{1, nil, make([]byte, hash.HashSize), false}
 	e.Path = []byte{1, 2, 3, 5, 6}
 	copy(e.Value, hash1)
 	b, match, err = e.descend(ant)
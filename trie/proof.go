@@ -0,0 +1,70 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package trie
+
+import (
+	"bytes"
+
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// VerifyProof checks the proof generated by Trie.Proof() against the root hash without accessing any DB. It returns
+// the value of the key if the proof shows the entry is included in the trie, or ErrNotExist if the proof shows the
+// entry does not exist. ErrInvalidProof is returned if the proof does not match the root hash or the key. indexed
+// tells if the trie hashes its nodes with the index of each child committed, in which case the nodes hashed by the
+// legacy scheme are rejected, since a legacy branch can be forged into any other node of the same hash
+func VerifyProof(rootHash hash.Hash32B, key []byte, proof [][]byte, indexed bool) ([]byte, error) {
+	expected := rootHash[:]
+	for i, node := range proof {
+		ptr, err := decodePatricia(node)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidProof, "failed to decode node %d: %v", i, err)
+		}
+		if isIndexed(ptr) != indexed {
+			return nil, errors.Wrapf(ErrInvalidProof, "node %d is not hashed by the scheme of the trie", i)
+		}
+		h := ptr.hash()
+		if !bytes.Equal(h[:], expected) {
+			return nil, errors.Wrapf(ErrInvalidProof, "hash of node %d = %x does not match %x", i, h, expected)
+		}
+		last := i == len(proof)-1
+		switch n := ptr.(type) {
+		case *branch:
+			if len(key) == 0 {
+				return nil, errors.Wrapf(ErrInvalidProof, "branch node %d does not store value", i)
+			}
+			expected = n.Path[key[0]]
+			if len(expected) == 0 {
+				// no path on the key, the key does not exist
+				if !last {
+					return nil, errors.Wrap(ErrInvalidProof, "proof continues after the path diverges")
+				}
+				return nil, errors.Wrapf(ErrNotExist, "key = %x", key)
+			}
+			key = key[1:]
+		case *leaf:
+			if len(n.Path) > len(key) || !bytes.Equal(n.Path, key[:len(n.Path)]) {
+				// path diverges, the key does not exist
+				if !last {
+					return nil, errors.Wrap(ErrInvalidProof, "proof continues after the path diverges")
+				}
+				return nil, errors.Wrapf(ErrNotExist, "key = %x", key)
+			}
+			key = key[len(n.Path):]
+			if n.Ext == 0 {
+				if len(key) != 0 || !last {
+					return nil, errors.Wrapf(ErrInvalidProof, "leaf node %d does not match the key", i)
+				}
+				return n.Value, nil
+			}
+			expected = n.Value
+		}
+	}
+	return nil, errors.Wrap(ErrInvalidProof, "proof ends before reaching the key")
+}
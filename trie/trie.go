@@ -32,12 +32,18 @@ var (
 	// CandidateKVNameSpace is the bucket name for candidate data storage
 	CandidateKVNameSpace = "Candidate"
 
+	// AccountRootKVNameSpace is the bucket name for account trie root hash at each height
+	AccountRootKVNameSpace = "AccountRoot"
+
 	// ErrInvalidTrie indicates something wrong causing invalid operation
 	ErrInvalidTrie = errors.New("invalid trie operation")
 
 	// ErrNotExist indicates entry does not exist
 	ErrNotExist = errors.New("not exist in trie")
 
+	// ErrInvalidProof indicates the proof does not match the root hash or the key
+	ErrInvalidProof = errors.New("invalid trie proof")
+
 	// EmptyRoot is the root hash of an empty trie
 	EmptyRoot = hash.Hash32B{0xe, 0x57, 0x51, 0xc0, 0x26, 0xe5, 0x43, 0xb2, 0xe8, 0xab, 0x2e, 0xb0, 0x60, 0x99,
 		0xda, 0xa1, 0xd1, 0xe5, 0xdf, 0x47, 0x77, 0x8f, 0x77, 0x87, 0xfa, 0xab, 0x45, 0xcd, 0xf1, 0x2f, 0xe3, 0xa8}
@@ -47,12 +53,17 @@ type (
 	// Trie is the interface of Merkle Patricia Trie
 	Trie interface {
 		lifecycle.StartStopper
-		TrieDB() db.KVStore          // return the underlying DB instance
-		Upsert([]byte, []byte) error // insert a new entry
-		Get([]byte) ([]byte, error)  // retrieve an existing entry
-		Delete([]byte) error         // delete an entry
-		Commit() error               // commit the state changes in a batch
-		RootHash() hash.Hash32B      // returns trie's root hash
+		TrieDB() db.KVStore             // return the underlying DB instance
+		Upsert([]byte, []byte) error    // insert a new entry
+		Get([]byte) ([]byte, error)     // retrieve an existing entry
+		Delete([]byte) error            // delete an entry
+		Commit() error                  // commit the state changes in a batch
+		RootHash() hash.Hash32B         // returns trie's root hash
+		Proof([]byte) ([][]byte, error) // returns the Merkle proof of an entry
+		Indexed() bool                  // returns if the node hashes commit to the index of each child
+		// Reindex rehashes every node so the hashes commit to the index of each child, the value of each leaf is
+		// replaced by the one returned by the function
+		Reindex(func([]byte) ([]byte, error)) error
 	}
 
 	// trie implements the Trie interface
@@ -69,24 +80,44 @@ type (
 		numBranch uint64
 		numExt    uint64
 		numLeaf   uint64
+		indexed   bool // the node hashes commit to the index of each child
 		dao       db.CachedKVStore
 	}
+
+	// Option sets trie construction parameter
+	Option func(*trie)
 )
 
+// IndexedOption makes an empty trie hash its nodes with the index of each child committed, the hash scheme of a
+// non-empty trie is the one of its root node
+func IndexedOption() Option {
+	return func(t *trie) {
+		t.indexed = true
+	}
+}
+
 // NewTrie creates a trie with DB filename
-func NewTrie(kvStore db.KVStore, name string, root hash.Hash32B) (Trie, error) {
+func NewTrie(kvStore db.KVStore, name string, root hash.Hash32B, opts ...Option) (Trie, error) {
 	if kvStore == nil {
 		return nil, errors.New("Failed to create KV store for Trie")
 	}
-	return newTrie(kvStore, name, root), nil
+	t := newTrie(kvStore, name, root)
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t, nil
 }
 
 // NewTrieSharedDB creates a trie with the shared DB instance
-func NewTrieSharedDB(kvStore db.CachedKVStore, name string, root hash.Hash32B) (Trie, error) {
+func NewTrieSharedDB(kvStore db.CachedKVStore, name string, root hash.Hash32B, opts ...Option) (Trie, error) {
 	if kvStore == nil {
 		return nil, errors.New("Failed to create KV store for Trie")
 	}
-	return newTrieSharedDB(kvStore, name, root), nil
+	t := newTrieSharedDB(kvStore, name, root)
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t, nil
 }
 
 func (t *trie) Start(ctx context.Context) error {
@@ -189,6 +220,67 @@ func (t *trie) RootHash() hash.Hash32B {
 	return t.rootHash
 }
 
+// Proof returns the serialized patricia nodes on the path from root to the key, which proves the inclusion of
+// the entry, or its exclusion if the path diverges before reaching the key. The proof can be checked by VerifyProof
+func (t *trie) Proof(key []byte) ([][]byte, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	ptr := t.root
+	if ptr == nil {
+		return nil, errors.Wrap(ErrNotExist, "failed to load root")
+	}
+	proof := [][]byte{}
+	for {
+		node, err := ptr.serialize()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to encode patricia node")
+		}
+		proof = append(proof, node)
+		if len(key) == 0 {
+			return proof, nil
+		}
+		hashn, match, err := ptr.descend(key)
+		if err != nil {
+			// path diverges, the last node proves the key does not exist
+			return proof, nil
+		}
+		if _, isLeaf := ptr.(*leaf); isLeaf && match == len(key) {
+			return proof, nil
+		}
+		if ptr, err = t.getPatricia(hashn); err != nil {
+			return nil, err
+		}
+		key = key[match:]
+	}
+}
+
+// Indexed returns if the node hashes commit to the index of each child of a branch
+func (t *trie) Indexed() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.indexed
+}
+
+// Reindex rehashes every node of the trie so the hashes commit to the index of each child of a branch, and the
+// nodes added afterwards are hashed the same way. onLeaf is called on the value of every leaf, and the value it
+// returns is stored instead. The nodes of the old root are kept in DB, so the trie can still be opened on it
+func (t *trie) Reindex(onLeaf func([]byte) ([]byte, error)) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.root == nil {
+		return errors.Wrap(ErrNotExist, "failed to load root")
+	}
+	if err := t.reindex(t.root, onLeaf); err != nil {
+		return errors.Wrap(err, "failed to reindex the trie")
+	}
+	t.indexed = true
+	t.rootHash = t.root.hash()
+	return nil
+}
+
 //======================================
 // private functions
 //======================================
@@ -213,11 +305,14 @@ func (t *trie) loadRoot() error {
 
 	if t.rootHash != EmptyRoot {
 		var err error
-		t.root, err = t.getPatricia(t.rootHash[:])
-		return err
+		if t.root, err = t.getPatricia(t.rootHash[:]); err != nil {
+			return err
+		}
+		t.indexed = isIndexed(t.root)
+		return nil
 	}
 	// initial empty trie
-	t.root = &branch{}
+	t.root = &branch{Indexed: t.indexed}
 	return t.putPatricia(t.root)
 }
 
@@ -334,10 +429,7 @@ func (t *trie) query(key []byte) (patricia, int, error) {
 func (t *trie) delete(ptr patricia, index byte) (bool, byte, error) {
 	var childClps bool
 	var clpsType byte
-	// delete the node from DB
-	if err := t.delPatricia(ptr); err != nil {
-		return childClps, clpsType, err
-	}
+	// the node is NOT deleted from DB, so the trie can still be opened at any historical root hash
 	// by default assuming collapse to leaf node
 	switch ptr.(type) {
 	case *branch:
@@ -401,9 +493,6 @@ func (t *trie) updateDelete(curr patricia, currClps bool, clpsType byte) error {
 		if next == nil {
 			return errors.Wrap(ErrInvalidPatricia, "patricia pushed on stack is not valid")
 		}
-		if err := t.delPatricia(next); err != nil {
-			return errors.Wrap(err, "failed to delete patricia")
-		}
 		// we attempt to collapse in 2 cases:
 		// 1. the current node is not root
 		// 2. the current node is root, but <v> is nil meaning no more entries exist on the incoming path
@@ -424,7 +513,7 @@ func (t *trie) updateDelete(curr patricia, currClps bool, clpsType byte) error {
 		}
 		logger.Debug().Bool("cont", contClps).Msg("clps")
 		if contClps && !noEntry {
-			curr = &leaf{clpsType, t.clpsK, t.clpsV}
+			curr = &leaf{clpsType, t.clpsK, t.clpsV, t.indexed}
 			logger.Info().Hex("k", t.clpsK).Hex("v", t.clpsV).Msg("clps")
 			// after collapsing, the trie might rollback to an earlier state in the history (before adding the deleted entry)
 			// so the node we try to put may already exist in DB
@@ -461,6 +550,14 @@ func (t *trie) getPatricia(key []byte) (patricia, error) {
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get key %x", key[:8])
 	}
+	return decodePatricia(node)
+}
+
+// decodePatricia converts the serialized bytes to patricia node
+func decodePatricia(node []byte) (patricia, error) {
+	if len(node) == 0 {
+		return nil, errors.Wrap(ErrInvalidPatricia, "empty node")
+	}
 	var ptr patricia
 	// first byte of serialized data is type
 	switch node[0] {
@@ -503,13 +600,6 @@ func (t *trie) putPatriciaNew(ptr patricia) error {
 	return t.dao.PutIfNotExists(t.bucket, key[:], value)
 }
 
-// delPatricia deletes the patricia node from DB
-func (t *trie) delPatricia(ptr patricia) error {
-	key := ptr.hash()
-	logger.Debug().Hex("key", key[:8]).Msg("del")
-	return t.dao.Delete(t.bucket, key[:])
-}
-
 // getValue returns the actual value stored in patricia node
 func (t *trie) getValue(ptr patricia, index byte) ([]byte, error) {
 	br, isBranch := ptr.(*branch)
@@ -523,6 +613,59 @@ func (t *trie) getValue(ptr patricia, index byte) ([]byte, error) {
 	return v, e
 }
 
+// reindex rehashes the node and all its descendants in post-order, and stores them into DB
+func (t *trie) reindex(ptr patricia, onLeaf func([]byte) ([]byte, error)) error {
+	switch n := ptr.(type) {
+	case *branch:
+		for i, key := range n.Path {
+			if len(key) == 0 {
+				continue
+			}
+			child, err := t.getPatricia(key)
+			if err != nil {
+				return err
+			}
+			if err := t.reindex(child, onLeaf); err != nil {
+				return err
+			}
+			hashChild := child.hash()
+			n.Path[i] = hashChild[:]
+		}
+		n.Indexed = true
+	case *leaf:
+		if n.Ext == 1 {
+			child, err := t.getPatricia(n.Value)
+			if err != nil {
+				return err
+			}
+			if err := t.reindex(child, onLeaf); err != nil {
+				return err
+			}
+			hashChild := child.hash()
+			n.Value = hashChild[:]
+		} else if onLeaf != nil {
+			value, err := onLeaf(n.Value)
+			if err != nil {
+				return err
+			}
+			n.Value = value
+		}
+		n.Indexed = true
+	}
+	return t.putPatricia(ptr)
+}
+
+// isIndexed returns if the hash of the node commits to the index of each child
+func isIndexed(ptr patricia) bool {
+	switch n := ptr.(type) {
+	case *branch:
+		return n.Indexed
+	case *leaf:
+		return n.Indexed
+	}
+	return false
+}
+
 // clear the stack
 func (t *trie) clear() {
 	for t.toRoot.Len() > 0 {
@@ -18,6 +18,7 @@ import (
 	"github.com/iotexproject/iotex-core/config"
 	"github.com/iotexproject/iotex-core/db"
 	"github.com/iotexproject/iotex-core/logger"
+	"github.com/iotexproject/iotex-core/pkg/hash"
 	"github.com/iotexproject/iotex-core/testutil"
 )
 
@@ -531,3 +532,225 @@ func TestQuery(t *testing.T) {
 	require.Nil(err)
 	require.Nil(tr.Stop(context.Background()))
 }
+
+func TestProof(t *testing.T) {
+	require := require.New(t)
+
+	tr, err := NewTrie(db.NewMemKVStore(), "test", EmptyRoot)
+	require.Nil(err)
+	require.Nil(tr.Start(context.Background()))
+
+	// exclusion proof on empty trie
+	proof, err := tr.Proof(cat)
+	require.Nil(err)
+	require.Equal(1, len(proof))
+	_, err = VerifyProof(EmptyRoot, cat, proof, false)
+	require.Equal(ErrNotExist, errors.Cause(err))
+
+	keys := [][]byte{ham, car, cat, dog, egg, fox, cow}
+	for i, k := range keys {
+		require.Nil(tr.Upsert(k, testV[i]))
+	}
+	root := tr.RootHash()
+	// inclusion proof
+	for i, k := range keys {
+		proof, err := tr.Proof(k)
+		require.Nil(err)
+		v, err := VerifyProof(root, k, proof, false)
+		require.Nil(err)
+		require.Equal(testV[i], v)
+	}
+	// exclusion proof, rat diverges at a branch and ant diverges at the root
+	for _, k := range [][]byte{rat, ant} {
+		proof, err := tr.Proof(k)
+		require.Nil(err)
+		_, err = VerifyProof(root, k, proof, false)
+		require.Equal(ErrNotExist, errors.Cause(err))
+	}
+
+	// proof does not match the root or the key
+	proof, err = tr.Proof(cat)
+	require.Nil(err)
+	_, err = VerifyProof(EmptyRoot, cat, proof, false)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	_, err = VerifyProof(root, car, proof, false)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	_, err = VerifyProof(root, cat, proof[:len(proof)-1], false)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	// tampered node
+	tampered := make([][]byte, len(proof))
+	copy(tampered, proof)
+	last := append([]byte{}, proof[len(proof)-1]...)
+	last[len(last)-1] ^= 1
+	tampered[len(tampered)-1] = last
+	_, err = VerifyProof(root, cat, tampered, false)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+
+	// value is updated
+	require.Nil(tr.Upsert(cat, testV[7]))
+	newRoot := tr.RootHash()
+	proof, err = tr.Proof(cat)
+	require.Nil(err)
+	v, err := VerifyProof(newRoot, cat, proof, false)
+	require.Nil(err)
+	require.Equal(testV[7], v)
+	_, err = VerifyProof(root, cat, proof, false)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	require.Nil(tr.Stop(context.Background()))
+}
+
+func TestReindex(t *testing.T) {
+	require := require.New(t)
+
+	tr, err := NewTrie(db.NewMemKVStore(), "test", EmptyRoot)
+	require.Nil(err)
+	require.Nil(tr.Start(context.Background()))
+	keys := [][]byte{ham, car, cat, dog, egg, fox, cow}
+	for i, k := range keys {
+		require.Nil(tr.Upsert(k, testV[i]))
+	}
+	require.False(tr.Indexed())
+	legacyRoot := tr.RootHash()
+
+	// moving the only child of the root from index 1 to 2 keeps the legacy hash, which forges a proof of another key
+	forge := func(proof [][]byte) [][]byte {
+		ptr, err := decodePatricia(proof[0])
+		require.Nil(err)
+		b, ok := ptr.(*branch)
+		require.True(ok)
+		b.Path[2], b.Path[1] = b.Path[1], nil
+		node, err := b.serialize()
+		require.Nil(err)
+		return append([][]byte{node}, proof[1:]...)
+	}
+	forgedKey := append([]byte{2}, cat[1:]...)
+	legacyProof, err := tr.Proof(cat)
+	require.Nil(err)
+	v, err := VerifyProof(legacyRoot, forgedKey, forge(legacyProof), false)
+	require.Nil(err)
+	require.Equal(testV[2], v)
+
+	// the entries are kept after reindexing
+	require.Nil(tr.Reindex(nil))
+	require.True(tr.Indexed())
+	root := tr.RootHash()
+	require.NotEqual(legacyRoot, root)
+	for i, k := range keys {
+		v, err := tr.Get(k)
+		require.Nil(err)
+		require.Equal(testV[i], v)
+	}
+	proof, err := tr.Proof(cat)
+	require.Nil(err)
+	v, err = VerifyProof(root, cat, proof, true)
+	require.Nil(err)
+	require.Equal(testV[2], v)
+	_, err = VerifyProof(root, forgedKey, forge(proof), true)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	// legacy nodes are rejected by the indexed scheme, and the other way around
+	_, err = VerifyProof(legacyRoot, cat, legacyProof, true)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+	_, err = VerifyProof(root, cat, proof, false)
+	require.Equal(ErrInvalidProof, errors.Cause(err))
+
+	// the reindexed trie is the same as the one built by the indexed scheme from scratch
+	require.Nil(tr.Upsert(ant, testV[7]))
+	require.Nil(tr.Delete(ham))
+	tr1, err := NewTrie(db.NewMemKVStore(), "test", EmptyRoot, IndexedOption())
+	require.Nil(err)
+	require.Nil(tr1.Start(context.Background()))
+	require.True(tr1.Indexed())
+	for i, k := range keys {
+		require.Nil(tr1.Upsert(k, testV[i]))
+	}
+	require.Nil(tr1.Upsert(ant, testV[7]))
+	require.Nil(tr1.Delete(ham))
+	require.Equal(tr.RootHash(), tr1.RootHash())
+
+	// the value of leaf is replaced on reindexing
+	tr2, err := NewTrie(db.NewMemKVStore(), "test", EmptyRoot)
+	require.Nil(err)
+	require.Nil(tr2.Start(context.Background()))
+	require.Nil(tr2.Upsert(cat, testV[2]))
+	require.Nil(tr2.Upsert(car, testV[1]))
+	require.Nil(tr2.Reindex(func(v []byte) ([]byte, error) {
+		return append(v, '!'), nil
+	}))
+	v, err = tr2.Get(cat)
+	require.Nil(err)
+	require.Equal([]byte("cat!"), v)
+	require.Nil(tr.Stop(context.Background()))
+	require.Nil(tr1.Stop(context.Background()))
+	require.Nil(tr2.Stop(context.Background()))
+}
+
+func TestWalk(t *testing.T) {
+	require := require.New(t)
+
+	tr, err := NewTrie(db.NewMemKVStore(), "test", EmptyRoot)
+	require.Nil(err)
+	require.Nil(tr.Start(context.Background()))
+	keys := [][]byte{ham, car, cat, egg, dog}
+	for i, k := range keys {
+		require.Nil(tr.Upsert(k, testV[i]))
+	}
+	require.Nil(tr.Commit())
+	root := tr.RootHash()
+
+	walk := func(root hash.Hash32B) (map[hash.Hash32B]bool, map[string]bool) {
+		nodes := make(map[hash.Hash32B]bool)
+		values := make(map[string]bool)
+		require.Nil(Walk(tr.TrieDB(), "test", root, func(h hash.Hash32B) bool {
+			require.False(nodes[h])
+			nodes[h] = true
+			// the key of node in DB is the hash of serialized node
+			node, err := tr.TrieDB().Get("test", h[:])
+			require.Nil(err)
+			nodeHash, err := NodeHash(node)
+			require.Nil(err)
+			require.Equal(h, nodeHash)
+			return true
+		}, func(v []byte) error {
+			values[string(v)] = true
+			return nil
+		}))
+		return nodes, values
+	}
+	nodes, values := walk(root)
+	require.True(nodes[root])
+	require.Equal(len(keys), len(values))
+	for i := range keys {
+		require.True(values[string(testV[i])])
+	}
+
+	// nodes of the old root are still in DB after updating the trie
+	require.Nil(tr.Upsert(cat, testV[5]))
+	require.Nil(tr.Delete(dog))
+	require.Nil(tr.Commit())
+	newNodes, newValues := walk(tr.RootHash())
+	require.Equal(len(keys)-1, len(newValues))
+	require.True(newValues[string(testV[5])])
+	require.False(newValues[string(testV[2])])
+	_, values = walk(root)
+	require.Equal(len(keys), len(values))
+	// unchanged subtrees are shared by both roots
+	shared := 0
+	for h := range newNodes {
+		if nodes[h] {
+			shared++
+		}
+	}
+	require.True(shared > 0)
+	require.True(shared < len(newNodes))
+
+	// skip the children of the root
+	values = make(map[string]bool)
+	require.Nil(Walk(tr.TrieDB(), "test", root, func(h hash.Hash32B) bool {
+		return h != root
+	}, func(v []byte) error {
+		values[string(v)] = true
+		return nil
+	}))
+	require.Equal(0, len(values))
+	require.Nil(tr.Stop(context.Background()))
+}
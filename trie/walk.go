@@ -0,0 +1,66 @@
+// Copyright (c) 2018 IoTeX
+// This is an alpha (internal) release and is not suitable for production. This source code is provided 'as is' and no
+// warranties are given as to title or non-infringement, merchantability or fitness for purpose and, to the extent
+// permitted by law, all liability for your use of the code is disclaimed. This source code is governed by Apache
+// License 2.0 that can be found in the LICENSE file.
+
+package trie
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/iotexproject/iotex-core/db"
+	"github.com/iotexproject/iotex-core/pkg/hash"
+)
+
+// Walk traverses the patricia nodes reachable from the root hash in a bucket of the DB. visit is called on the hash
+// of every node, and the children of the node are skipped if visit returns false. onLeaf is called on the value stored
+// in every leaf node that is visited
+func Walk(kvStore db.KVStore, bucket string, root hash.Hash32B, visit func(hash.Hash32B) bool, onLeaf func([]byte) error) error {
+	stack := [][]byte{root[:]}
+	for len(stack) > 0 {
+		key := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		var h hash.Hash32B
+		copy(h[:], key)
+		if !visit(h) || h == EmptyRoot {
+			continue
+		}
+		node, err := kvStore.Get(bucket, key)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get node %x", key)
+		}
+		ptr, err := decodePatricia(node)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode node %x", key)
+		}
+		switch n := ptr.(type) {
+		case *branch:
+			for _, child := range n.Path {
+				if len(child) > 0 {
+					stack = append(stack, child)
+				}
+			}
+		case *leaf:
+			if n.Ext == 1 {
+				stack = append(stack, n.Value)
+				continue
+			}
+			if onLeaf != nil {
+				if err := onLeaf(n.Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// NodeHash returns the hash of a serialized patricia node, which is the key of the node in DB
+func NodeHash(node []byte) (hash.Hash32B, error) {
+	ptr, err := decodePatricia(node)
+	if err != nil {
+		return hash.ZeroHash32B, errors.Wrap(err, "failed to decode node")
+	}
+	return ptr.hash(), nil
+}